@@ -0,0 +1,83 @@
+package api_gateway
+
+import (
+	"errors"
+
+	"github.com/it-chain/engine/blockchain"
+)
+
+var ErrInvalidArgument = errors.New("invalid argument")
+
+// this is an api for querying committed blocks and proving transaction inclusion
+type BlockQueryApi struct {
+	blockRepository BlockRepository
+	validator       *blockchain.DefaultValidator
+}
+
+func NewBlockQueryApi(blockRepository BlockRepository) BlockQueryApi {
+	return BlockQueryApi{
+		blockRepository: blockRepository,
+		validator:       &blockchain.DefaultValidator{},
+	}
+}
+
+// this repository is a committed blockchain
+// in pruned mode, blockchain.ErrBlockPruned is returned for transactions of pruned blocks
+type BlockRepository interface {
+	GetBlockByTxID(txID string) (blockchain.Block, error)
+	GetHeaderByHeight(height blockchain.BlockHeight) (blockchain.SignedHeader, error)
+	RetentionStats() (blockchain.RetentionStats, error)
+}
+
+// merkle proof of a transaction with the block which contains it
+// client can verify the proof only with TxSealRoot and the version of the block header
+type TransactionProof struct {
+	BlockHeight uint64
+	BlockSeal   []byte
+	TxSealRoot  []byte
+	Proof       blockchain.MerkleProof
+}
+
+// find a block containing the transaction and extract merkle proof of it
+func (b BlockQueryApi) GetTransactionProof(txID string) (TransactionProof, error) {
+	if txID == "" {
+		return TransactionProof{}, ErrInvalidArgument
+	}
+
+	block, err := b.blockRepository.GetBlockByTxID(txID)
+	if err != nil {
+		return TransactionProof{}, err
+	}
+
+	if len(block.GetTxSeal()) == 0 {
+		return TransactionProof{}, blockchain.ErrInvalidMerkleTree
+	}
+
+	proof, err := b.validator.BuildTxProof(block, txID)
+	if err != nil {
+		return TransactionProof{}, err
+	}
+
+	return TransactionProof{
+		BlockHeight: block.GetHeight(),
+		BlockSeal:   block.GetSeal(),
+		TxSealRoot:  block.GetTxSeal()[0],
+		Proof:       proof,
+	}, nil
+}
+
+// verify merkle proof against the header of the committed block at the height without the transactions
+// the root and the merkle tree come from the header, not from the proof
+func (b BlockQueryApi) VerifyTransactionProof(proof blockchain.MerkleProof, blockHeight uint64) (bool, error) {
+	header, err := b.blockRepository.GetHeaderByHeight(blockHeight)
+	if err != nil {
+		return false, err
+	}
+
+	return b.validator.VerifyTxProof(proof, header.BlockHeader), nil
+}
+
+// block retention mode and how many blocks, transactions and bytes are pruned
+func (b BlockQueryApi) GetRetentionStats() (blockchain.RetentionStats, error) {
+	return b.blockRepository.RetentionStats()
+}
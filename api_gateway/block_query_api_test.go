@@ -0,0 +1,135 @@
+package api_gateway
+
+import (
+	"testing"
+
+	"github.com/it-chain/engine/blockchain"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockBlockRepository struct {
+	block     blockchain.Block
+	retention blockchain.RetentionStats
+	err       error
+}
+
+func (m mockBlockRepository) GetBlockByTxID(txID string) (blockchain.Block, error) {
+	return m.block, m.err
+}
+
+func (m mockBlockRepository) GetHeaderByHeight(height blockchain.BlockHeight) (blockchain.SignedHeader, error) {
+	if m.block == nil || m.block.GetHeight() != height {
+		return blockchain.SignedHeader{}, blockchain.ErrBlockNotFound
+	}
+
+	return blockchain.NewSignedHeader(m.block), m.err
+}
+
+func (m mockBlockRepository) RetentionStats() (blockchain.RetentionStats, error) {
+	return m.retention, m.err
+}
+
+func TestBlockQueryApi_GetTransactionProof(t *testing.T) {
+	// given
+	validator := blockchain.DefaultValidator{}
+
+	tx1 := &blockchain.DefaultTransaction{ID: "tx1"}
+	tx2 := &blockchain.DefaultTransaction{ID: "tx2"}
+	txSeal, _ := validator.BuildTxSeal([]blockchain.Transaction{tx1, tx2})
+
+	block := &blockchain.DefaultBlock{
+		Seal:    []byte("seal"),
+		Height:  7,
+		TxList:  []*blockchain.DefaultTransaction{tx1, tx2},
+		TxSeal:  txSeal,
+		Version: blockchain.CurrentBlockVersion,
+	}
+
+	tests := map[string]struct {
+		input struct {
+			txID       string
+			repository mockBlockRepository
+		}
+		err error
+	}{
+		"success": {
+			input: struct {
+				txID       string
+				repository mockBlockRepository
+			}{txID: "tx2", repository: mockBlockRepository{block: block}},
+			err: nil,
+		},
+		"empty tx id": {
+			input: struct {
+				txID       string
+				repository mockBlockRepository
+			}{txID: "", repository: mockBlockRepository{block: block}},
+			err: ErrInvalidArgument,
+		},
+		"block not found": {
+			input: struct {
+				txID       string
+				repository mockBlockRepository
+			}{txID: "tx3", repository: mockBlockRepository{err: blockchain.ErrBlockNotFound}},
+			err: blockchain.ErrBlockNotFound,
+		},
+		"pruned transaction": {
+			input: struct {
+				txID       string
+				repository mockBlockRepository
+			}{txID: "tx1", repository: mockBlockRepository{err: blockchain.ErrBlockPruned}},
+			err: blockchain.ErrBlockPruned,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+
+		// when
+		blockQueryApi := NewBlockQueryApi(test.input.repository)
+		proof, err := blockQueryApi.GetTransactionProof(test.input.txID)
+
+		// then
+		assert.Equal(t, test.err, err)
+		if err != nil {
+			continue
+		}
+
+		assert.Equal(t, uint64(7), proof.BlockHeight)
+		assert.Equal(t, txSeal[0], proof.TxSealRoot)
+		valid, err := blockQueryApi.VerifyTransactionProof(proof.Proof, proof.BlockHeight)
+		assert.NoError(t, err)
+		assert.True(t, valid)
+
+		// a proof claiming the legacy version is still verified with the tree of the header version
+		legacyProof := proof.Proof
+		legacyProof.Version = blockchain.LegacyBlockVersion
+		valid, err = blockQueryApi.VerifyTransactionProof(legacyProof, proof.BlockHeight)
+		assert.NoError(t, err)
+		assert.False(t, valid)
+
+		_, err = blockQueryApi.VerifyTransactionProof(proof.Proof, 8)
+		assert.Equal(t, blockchain.ErrBlockNotFound, err)
+	}
+}
+
+func TestBlockQueryApi_GetRetentionStats(t *testing.T) {
+	// given
+	retention := blockchain.RetentionStats{
+		Mode:         blockchain.PrunedMode,
+		KeepBlocks:   10,
+		LastHeight:   30,
+		PrunedHeight: 20,
+		PrunedBlocks: 20,
+		PrunedTxs:    40,
+		PrunedBytes:  4096,
+	}
+	blockQueryApi := NewBlockQueryApi(mockBlockRepository{retention: retention})
+
+	// when
+	stats, err := blockQueryApi.GetRetentionStats()
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, retention, stats)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/go-kit/kit/endpoint"
+	"github.com/it-chain/engine/blockchain"
 )
 
 //This file is based on the following sample.
@@ -19,3 +20,102 @@ func makeFindUncommittedTransactionsEndpoint(t TransactionQueryApi) endpoint.End
 		return txs, nil
 	}
 }
+
+type getTransactionProofRequest struct {
+	TxID string
+}
+
+// responses with an error are written by encodeError as {"error": "<message>"}
+// so the error value itself is never serialized
+type getTransactionProofResponse struct {
+	TransactionProof
+	Err error `json:"-"`
+}
+
+func (r getTransactionProofResponse) error() error { return r.Err }
+
+func makeGetTransactionProofEndpoint(b BlockQueryApi) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(getTransactionProofRequest)
+		proof, err := b.GetTransactionProof(req.TxID)
+
+		return getTransactionProofResponse{TransactionProof: proof, Err: err}, nil
+	}
+}
+
+type verifyTransactionProofRequest struct {
+	Proof       blockchain.MerkleProof
+	BlockHeight uint64
+}
+
+type verifyTransactionProofResponse struct {
+	Valid bool
+	Err   error `json:"-"`
+}
+
+func (r verifyTransactionProofResponse) error() error { return r.Err }
+
+func makeVerifyTransactionProofEndpoint(b BlockQueryApi) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(verifyTransactionProofRequest)
+
+		valid, err := b.VerifyTransactionProof(req.Proof, req.BlockHeight)
+
+		return verifyTransactionProofResponse{Valid: valid, Err: err}, nil
+	}
+}
+
+type getRetentionStatsResponse struct {
+	blockchain.RetentionStats
+	Err error `json:"-"`
+}
+
+func (r getRetentionStatsResponse) error() error { return r.Err }
+
+func makeGetRetentionStatsEndpoint(b BlockQueryApi) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		stats, err := b.GetRetentionStats()
+
+		return getRetentionStatsResponse{RetentionStats: stats, Err: err}, nil
+	}
+}
+
+type getReceiptRequest struct {
+	TxID string
+}
+
+type getReceiptResponse struct {
+	blockchain.Receipt
+	Err error `json:"-"`
+}
+
+func (r getReceiptResponse) error() error { return r.Err }
+
+func makeGetReceiptEndpoint(r ReceiptQueryApi) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(getReceiptRequest)
+		receipt, err := r.GetReceipt(req.TxID)
+
+		return getReceiptResponse{Receipt: receipt, Err: err}, nil
+	}
+}
+
+type checkTransactionInclusionRequest struct {
+	TxID string
+}
+
+type checkTransactionInclusionResponse struct {
+	blockchain.TxInclusion
+	Err error `json:"-"`
+}
+
+func (r checkTransactionInclusionResponse) error() error { return r.Err }
+
+func makeCheckTransactionInclusionEndpoint(i InclusionQueryApi) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(checkTransactionInclusionRequest)
+		inclusion, err := i.CheckTransaction(req.TxID)
+
+		return checkTransactionInclusionResponse{TxInclusion: inclusion, Err: err}, nil
+	}
+}
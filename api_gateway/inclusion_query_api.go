@@ -0,0 +1,29 @@
+package api_gateway
+
+import (
+	"github.com/it-chain/engine/blockchain"
+)
+
+// this is an api for a light node to check a transaction against its own header chain
+type InclusionQueryApi struct {
+	checker TxInclusionChecker
+}
+
+func NewInclusionQueryApi(checker TxInclusionChecker) InclusionQueryApi {
+	return InclusionQueryApi{
+		checker: checker,
+	}
+}
+
+// this checker asks full peers for a merkle proof and verifies it with the synced header
+type TxInclusionChecker interface {
+	CheckTransaction(txID string) (blockchain.TxInclusion, error)
+}
+
+func (i InclusionQueryApi) CheckTransaction(txID string) (blockchain.TxInclusion, error) {
+	if txID == "" {
+		return blockchain.TxInclusion{}, ErrInvalidArgument
+	}
+
+	return i.checker.CheckTransaction(txID)
+}
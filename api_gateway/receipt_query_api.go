@@ -0,0 +1,31 @@
+package api_gateway
+
+import (
+	"github.com/it-chain/engine/blockchain"
+)
+
+// this is an api for querying execution result of committed transactions
+type ReceiptQueryApi struct {
+	receiptRepository ReceiptRepository
+}
+
+func NewReceiptQueryApi(receiptRepository ReceiptRepository) ReceiptQueryApi {
+	return ReceiptQueryApi{
+		receiptRepository: receiptRepository,
+	}
+}
+
+// this repository stores receipts of committed transactions
+type ReceiptRepository interface {
+	GetReceipt(txID string) (blockchain.Receipt, error)
+}
+
+// find a receipt of the transaction
+// blockchain.ErrReceiptNotFound is returned until the block containing the transaction is executed
+func (r ReceiptQueryApi) GetReceipt(txID string) (blockchain.Receipt, error) {
+	if txID == "" {
+		return blockchain.Receipt{}, ErrInvalidArgument
+	}
+
+	return r.receiptRepository.GetReceipt(txID)
+}
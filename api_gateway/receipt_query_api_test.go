@@ -0,0 +1,62 @@
+package api_gateway
+
+import (
+	"testing"
+
+	"github.com/it-chain/engine/blockchain"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockReceiptRepository struct {
+	receipts map[string]blockchain.Receipt
+}
+
+func (m mockReceiptRepository) GetReceipt(txID string) (blockchain.Receipt, error) {
+	receipt, ok := m.receipts[txID]
+	if !ok {
+		return blockchain.Receipt{}, blockchain.ErrReceiptNotFound
+	}
+
+	return receipt, nil
+}
+
+func TestReceiptQueryApi_GetReceipt(t *testing.T) {
+	// given
+	receipt := blockchain.Receipt{TxID: "tx1", Success: true, Data: map[string]string{"a": "1"}, BlockHeight: 7}
+	queryApi := NewReceiptQueryApi(mockReceiptRepository{
+		receipts: map[string]blockchain.Receipt{"tx1": receipt},
+	})
+
+	tests := map[string]struct {
+		input  string
+		output blockchain.Receipt
+		err    error
+	}{
+		"success": {
+			input:  "tx1",
+			output: receipt,
+			err:    nil,
+		},
+		"empty tx id": {
+			input:  "",
+			output: blockchain.Receipt{},
+			err:    ErrInvalidArgument,
+		},
+		"not executed": {
+			input:  "tx2",
+			output: blockchain.Receipt{},
+			err:    blockchain.ErrReceiptNotFound,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+
+		// when
+		output, err := queryApi.GetReceipt(test.input)
+
+		// then
+		assert.Equal(t, test.err, err)
+		assert.Equal(t, test.output, output)
+	}
+}
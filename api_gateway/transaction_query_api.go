@@ -5,6 +5,7 @@ import (
 	"errors"
 	"log"
 
+	"github.com/it-chain/engine/blockchain"
 	"github.com/it-chain/engine/txpool"
 	"github.com/it-chain/leveldb-wrapper"
 )
@@ -65,6 +66,19 @@ func (t TransactionEventListener) HandleTransactionDeletedEvent(event txpool.TxD
 	}
 }
 
+// this function listens to BlockCommittedEvent and removes committed transactions from repository
+// so that they are not proposed again
+func (t TransactionEventListener) HandleBlockCommittedEvent(event blockchain.BlockCommittedEvent) {
+
+	for _, txID := range event.TxIDs {
+		err := t.transactionRepository.Remove(txID)
+
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+	}
+}
+
 type LeveldbTransactionPoolRepository struct {
 	leveldb *leveldbwrapper.DB
 }
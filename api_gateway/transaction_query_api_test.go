@@ -6,6 +6,7 @@ import (
 
 	"time"
 
+	"github.com/it-chain/engine/blockchain"
 	"github.com/it-chain/engine/txpool"
 	"github.com/it-chain/midgard"
 	"github.com/it-chain/midgard/bus/rabbitmq"
@@ -161,6 +162,39 @@ func TestTransactionRepository_FindAll(t *testing.T) {
 	assert.Equal(t, nil, err2)
 }
 
+func TestTransactionEventListener_HandleBlockCommittedEvent(t *testing.T) {
+	// Given
+	dbPath := "./.test"
+
+	tr := NewTransactionRepository(dbPath)
+	listener := NewTransactionEventListener(tr)
+
+	defer func() {
+		tr.leveldb.Close()
+		os.RemoveAll(dbPath)
+	}()
+
+	for _, id := range []string{"1", "2", "3"} {
+		assert.NoError(t, tr.Save(txpool.Transaction{TxId: id}))
+	}
+
+	// When
+	listener.HandleBlockCommittedEvent(blockchain.BlockCommittedEvent{
+		EventModel: midgard.EventModel{
+			ID:   "seal",
+			Type: "block.committed",
+		},
+		Height: 1,
+		TxIDs:  []string{"1", "3", "4"},
+	})
+
+	// Then
+	txs, err := tr.FindAll()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(txs))
+	assert.Equal(t, "2", txs[0].TxId)
+}
+
 func TestTransactionQueryApi_FindUncommittedTransactions(t *testing.T) {
 
 	api, client, tearDown := setApiUp(t)
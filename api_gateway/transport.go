@@ -8,9 +8,10 @@ import (
 	kitlog "github.com/go-kit/kit/log"
 	kithttp "github.com/go-kit/kit/transport/http"
 	"github.com/gorilla/mux"
+	"github.com/it-chain/engine/blockchain"
 )
 
-func MakeHandler(bs TransactionQueryApi, logger kitlog.Logger) http.Handler {
+func MakeHandler(bs TransactionQueryApi, bq BlockQueryApi, rq ReceiptQueryApi, logger kitlog.Logger) http.Handler {
 
 	opts := []kithttp.ServerOption{
 		kithttp.ServerErrorLogger(logger),
@@ -23,9 +24,62 @@ func MakeHandler(bs TransactionQueryApi, logger kitlog.Logger) http.Handler {
 		opts...,
 	)
 
+	getTransactionProofHandler := kithttp.NewServer(
+		makeGetTransactionProofEndpoint(bq),
+		decodeGetTransactionProofRequest,
+		encodeResponse,
+		opts...,
+	)
+
+	verifyTransactionProofHandler := kithttp.NewServer(
+		makeVerifyTransactionProofEndpoint(bq),
+		decodeVerifyTransactionProofRequest,
+		encodeResponse,
+		opts...,
+	)
+
+	getRetentionStatsHandler := kithttp.NewServer(
+		makeGetRetentionStatsEndpoint(bq),
+		decodeGetRetentionStatsRequest,
+		encodeResponse,
+		opts...,
+	)
+
+	getReceiptHandler := kithttp.NewServer(
+		makeGetReceiptEndpoint(rq),
+		decodeGetReceiptRequest,
+		encodeResponse,
+		opts...,
+	)
+
 	r := mux.NewRouter()
 
 	r.Handle("/transactions", findAllUncommittedTransactionsHandler).Methods("GET")
+	r.Handle("/transactions/proof/verify", verifyTransactionProofHandler).Methods("POST")
+	r.Handle("/transactions/{id}/proof", getTransactionProofHandler).Methods("GET")
+	r.Handle("/transactions/{id}/receipt", getReceiptHandler).Methods("GET")
+	r.Handle("/blocks/retention", getRetentionStatsHandler).Methods("GET")
+
+	return r
+}
+
+// light node only has block headers, so it serves transaction inclusion checks instead of block queries
+func MakeLightHandler(iq InclusionQueryApi, logger kitlog.Logger) http.Handler {
+
+	opts := []kithttp.ServerOption{
+		kithttp.ServerErrorLogger(logger),
+	}
+
+	checkTransactionInclusionHandler := kithttp.NewServer(
+		makeCheckTransactionInclusionEndpoint(iq),
+		decodeCheckTransactionInclusionRequest,
+		encodeResponse,
+		opts...,
+	)
+
+	r := mux.NewRouter()
+
+	r.Handle("/transactions/{id}/inclusion", checkTransactionInclusionHandler).Methods("GET")
 
 	return r
 }
@@ -36,6 +90,51 @@ func decodeFindAllUncommittedTransactionsRequest(_ context.Context, r *http.Requ
 	return nil, nil
 }
 
+func decodeGetTransactionProofRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	vars := mux.Vars(r)
+	id, ok := vars["id"]
+	if !ok {
+		return nil, ErrInvalidArgument
+	}
+
+	return getTransactionProofRequest{TxID: id}, nil
+}
+
+// this return nil because this request body is empty
+func decodeGetRetentionStatsRequest(_ context.Context, r *http.Request) (interface{}, error) {
+
+	return nil, nil
+}
+
+func decodeGetReceiptRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	vars := mux.Vars(r)
+	id, ok := vars["id"]
+	if !ok {
+		return nil, ErrInvalidArgument
+	}
+
+	return getReceiptRequest{TxID: id}, nil
+}
+
+func decodeCheckTransactionInclusionRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	vars := mux.Vars(r)
+	id, ok := vars["id"]
+	if !ok {
+		return nil, ErrInvalidArgument
+	}
+
+	return checkTransactionInclusionRequest{TxID: id}, nil
+}
+
+func decodeVerifyTransactionProofRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var request verifyTransactionProofRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		return nil, ErrInvalidArgument
+	}
+
+	return request, nil
+}
+
 func encodeResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
 
 	if e, ok := response.(errorer); ok && e.error() != nil {
@@ -54,10 +153,13 @@ type errorer interface {
 func encodeError(_ context.Context, err error, w http.ResponseWriter) {
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
 	switch err {
-	//case cargo.ErrUnknown:
-	//	w.WriteHeader(http.StatusNotFound)
-	//case ErrInvalidArgument:
-	//	w.WriteHeader(http.StatusBadRequest)
+	case blockchain.ErrBlockNotFound, blockchain.ErrTxNotInBlock, blockchain.ErrReceiptNotFound, blockchain.ErrTxProofNotFound:
+		w.WriteHeader(http.StatusNotFound)
+	case blockchain.ErrBlockPruned:
+		// transaction was committed but this node no longer keeps its block body
+		w.WriteHeader(http.StatusGone)
+	case ErrInvalidArgument:
+		w.WriteHeader(http.StatusBadRequest)
 	default:
 		w.WriteHeader(http.StatusInternalServerError)
 	}
@@ -0,0 +1,45 @@
+package api_gateway
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/it-chain/engine/blockchain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncodeResponse_TransactionProof(t *testing.T) {
+	tests := map[string]struct {
+		input  getTransactionProofResponse
+		status int
+		body   string
+	}{
+		"success": {
+			input:  getTransactionProofResponse{TransactionProof: TransactionProof{BlockHeight: 7}},
+			status: http.StatusOK,
+			body:   `{"BlockHeight":7,"BlockSeal":null,"TxSealRoot":null,"Proof":{"TxID":"","TxHash":null,"Path":null,"Version":0}}` + "\n",
+		},
+		"not in block": {
+			input:  getTransactionProofResponse{Err: blockchain.ErrTxNotInBlock},
+			status: http.StatusNotFound,
+			body:   `{"error":"transaction is not in the block"}` + "\n",
+		},
+	}
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+
+		// given
+		recorder := httptest.NewRecorder()
+
+		// when
+		err := encodeResponse(context.Background(), recorder, test.input)
+
+		// then
+		assert.NoError(t, err)
+		assert.Equal(t, test.status, recorder.Code)
+		assert.Equal(t, test.body, recorder.Body.String())
+	}
+}
@@ -1,39 +1,700 @@
 package api
 
 import (
+	"bytes"
+	"log"
+	"sync"
+	"time"
+
 	"github.com/it-chain/engine/blockchain"
 )
 
 type BlockApi struct {
-	publisherId string
+	publisherId        string
+	blockRepository    blockchain.BlockRepository
+	blockPool          blockchain.BlockPool
+	poolConfig         blockchain.PoolConfig
+	syncState          *blockchain.BlockSyncState
+	grpcCommandService blockchain.GrpcCommandService
+	peerRepository     blockchain.PeerRepository
+	validator          blockchain.BlockValidator
+	stateRootValidator *blockchain.StateRootValidator
+	reorgRule          blockchain.ReorgRule
+	mutex              *sync.Mutex
+
+	// 동기화 과정의 상태. mutex 를 잡은 다음 syncMutex 를 잡는다.
+	syncMutex          *sync.Mutex
+	syncCheckPeers     map[string]bool
+	syncCheckResponses []blockchain.SyncCheckResponse
+	syncCheckPeerCount int
+	syncCheckDeadline  time.Time
+	syncCheckRetry     int
+	syncTarget         *syncTarget
+	downloadConfig     blockchain.DownloadConfig
+}
+
+// syncTarget 은 quorum 이 동의한 마지막 block 의 height, seal 과 Construct 단계에서 block 을 받아오는 peer 들이다.
+// 받은 block 들은 검증한 다음 tip 까지 이어질 때까지 blocks 에 보관하고, tip 의 seal 이 맞을 때 한번에 commit 한다.
+type syncTarget struct {
+	height     blockchain.BlockHeight
+	seal       []byte
+	peers      []blockchain.PeerId
+	downloader *blockchain.BlockDownloader
+	lastBlock  blockchain.Block
+	blocks     []blockchain.Block
 }
 
-func NewBlockApi(publisherId string) (BlockApi, error) {
+func (target *syncTarget) hasPeer(peerId blockchain.PeerId) bool {
+	for _, peer := range target.peers {
+		if peer.Id == peerId.Id {
+			return true
+		}
+	}
+
+	return false
+}
+
+func NewBlockApi(publisherId string, blockRepository blockchain.BlockRepository, grpcCommandService blockchain.GrpcCommandService, peerRepository blockchain.PeerRepository) (BlockApi, error) {
 	return BlockApi{
-		publisherId: publisherId,
+		publisherId:        publisherId,
+		blockRepository:    blockRepository,
+		blockPool:          blockchain.NewBlockPool(),
+		poolConfig:         blockchain.DefaultPoolConfig(),
+		syncState:          blockchain.NewBlockSyncState(),
+		grpcCommandService: grpcCommandService,
+		peerRepository:     peerRepository,
+		validator:          &blockchain.DefaultValidator{},
+		reorgRule:          blockchain.LongestChainRule{},
+		mutex:              &sync.Mutex{},
+		syncMutex:          &sync.Mutex{},
+		syncCheckPeers:     make(map[string]bool),
+		syncCheckResponses: make([]blockchain.SyncCheckResponse, 0),
+		downloadConfig:     blockchain.DefaultDownloadConfig(),
 	}, nil
 }
 
-// TODO: Check 과정에서 임의의 노드에게서 받은 blockchain 정보로 동기화 되었는지 확인한다.
-func (bApi *BlockApi) SyncedCheck(block blockchain.Block) error {
+// SetSignatureVerifier 는 동기화로 받은 block 의 creator 서명을 검증하도록 설정한다.
+func (bApi *BlockApi) SetSignatureVerifier(verifier blockchain.SignatureVerifier) {
+	bApi.defaultValidator().SetSignatureVerifier(verifier)
+}
+
+// SetBlockLimit 은 transaction 개수나 크기의 한도를 넘는 block 을 거부하도록 설정한다.
+func (bApi *BlockApi) SetBlockLimit(limit blockchain.BlockLimit) {
+	bApi.defaultValidator().SetBlockLimit(limit)
+}
+
+// SetMaxTimestampDrift 는 local 시각보다 drift 이상 앞선 timestamp 의 block 을 거부하도록 설정한다.
+func (bApi *BlockApi) SetMaxTimestampDrift(drift time.Duration) {
+	bApi.defaultValidator().SetMaxTimestampDrift(drift)
+}
+
+func (bApi *BlockApi) defaultValidator() *blockchain.DefaultValidator {
+	if validator, ok := bApi.validator.(*blockchain.DefaultValidator); ok {
+		return validator
+	}
+
+	validator := &blockchain.DefaultValidator{}
+	bApi.validator = validator
+
+	return validator
+}
+
+// SetStateRootValidator 는 pool 의 block 을 commit 할 때 snapshot height 의 block 의 state root 를 local state 와 비교하도록 설정한다.
+func (bApi *BlockApi) SetStateRootValidator(validator *blockchain.StateRootValidator) {
+	bApi.stateRootValidator = validator
+}
+
+// SetReorgRule 은 pool 에 fork 된 branch 가 생겼을 때 reorg 여부를 결정할 rule 을 설정한다.
+func (bApi *BlockApi) SetReorgRule(rule blockchain.ReorgRule) {
+	bApi.reorgRule = rule
+}
+
+// SetBlockPoolConfig 는 block pool 의 한도를 설정한다. block 을 받기 전에 호출해야 한다.
+func (bApi *BlockApi) SetBlockPoolConfig(config blockchain.PoolConfig) {
+	bApi.poolConfig = config
+	bApi.blockPool = blockchain.NewBlockPoolWithConfig(config)
+}
+
+// SetDownloadConfig 는 동기화 요청의 range 크기, window, 응답 대기 시간과 재요청 횟수를 설정한다.
+func (bApi *BlockApi) SetDownloadConfig(config blockchain.DownloadConfig) {
+	bApi.downloadConfig = config
+}
+
+// LoadState 는 재시작 전의 block pool 과 동기화 상태를 eventstore 에서 복원한다.
+// SetBlockPoolConfig 다음, block 을 받기 전에 호출해야 한다.
+// 동기화 중에 재시작했다면 응답을 기다리던 peer 정보는 남아 있지 않으므로 Check 단계부터 다시 시작한다.
+func (bApi *BlockApi) LoadState() error {
+	blockPool, err := blockchain.LoadBlockPool(bApi.poolConfig)
+	if err != nil {
+		return err
+	}
+
+	syncState, err := blockchain.LoadBlockSyncState()
+	if err != nil {
+		return err
+	}
+
+	bApi.mutex.Lock()
+	bApi.blockPool = blockPool
+	bApi.mutex.Unlock()
+
+	bApi.syncMutex.Lock()
+	bApi.syncState = syncState
+	bApi.syncMutex.Unlock()
+
+	if syncState.IsProgressing() == blockchain.DONE {
+		return nil
+	}
+
+	// 다시 시작하지 못하면 동기화를 중단한다. pool 에 남은 block 이 다음 동기화를 시작한다.
+	if err := bApi.Synchronize(); err != nil {
+		log.Printf("failed to resume synchronization: [%v]", err)
+
+		bApi.syncMutex.Lock()
+		bApi.abortSync()
+		bApi.syncMutex.Unlock()
+	}
+
 	return nil
 }
 
+// Synchronize 는 동기화의 Check 단계를 시작한다.
+// 알고 있는 모든 peer 에게 last block 을 요청하고, 응답은 SyncedCheck 에서 처리한다.
+// 응답 대기 시간(DownloadConfig.Timeout) 안에 quorum 이 동의한 tip 이 없으면 CheckSyncTimeout 이 Check 단계를 다시 시작한다.
+func (bApi *BlockApi) Synchronize() error {
+	bApi.syncMutex.Lock()
+	defer bApi.syncMutex.Unlock()
+
+	if bApi.syncState.IsProgressing() == blockchain.PROGRESSING && bApi.syncInFlight() {
+		return nil
+	}
+
+	bApi.syncCheckRetry = 0
+
+	return bApi.startSyncCheck()
+}
+
+func (bApi *BlockApi) startSyncCheck() error {
+	peers, err := bApi.peerRepository.FindAll()
+	if err != nil {
+		return err
+	}
+
+	if len(peers) == 0 {
+		return ErrNoPeer
+	}
+
+	bApi.syncCheckPeers = make(map[string]bool)
+	bApi.syncCheckResponses = make([]blockchain.SyncCheckResponse, 0)
+	for _, peer := range peers {
+		if err := bApi.grpcCommandService.SyncCheckRequest(peer.PeerId); err != nil {
+			log.Printf("fail to request sync check to [%s]: [%v]", peer.PeerId.ToString(), err)
+			continue
+		}
+		bApi.syncCheckPeers[peer.PeerId.Id] = true
+	}
+
+	if len(bApi.syncCheckPeers) == 0 {
+		return ErrSyncCheckRequest
+	}
+
+	// quorum 은 알고 있는 전체 peer 수를 기준으로 계산한다.
+	bApi.syncCheckPeerCount = len(peers)
+	bApi.syncCheckDeadline = time.Now().Add(bApi.downloadConfig.Timeout)
+	bApi.syncState.SetProgress(blockchain.PROGRESSING)
+
+	return nil
+}
+
+// syncInFlight 는 응답을 기다리는 sync check 요청이나 받고 있는 block 이 있는지 확인한다.
+// 재시작으로 복원된 동기화 상태에는 둘 다 없다.
+func (bApi *BlockApi) syncInFlight() bool {
+	return len(bApi.syncCheckPeers) != 0 || bApi.syncTarget != nil
+}
+
+// SyncedCheck 는 peer 가 보낸 last block 을 모은다.
+// quorum 이상의 peer 가 같은 last block (height, seal) 을 알려주면 그 block 을 신뢰할 수 있는 tip 으로 선택하고,
+// tip 이 자신의 last block 보다 높다면 tip 에 동의한 peer 들로부터 block 을 나누어 받는 Construct 단계를 시작한다.
+// 모든 peer 가 응답했는데도 quorum 이 동의한 tip 이 없다면 동기화를 중단한다.
+func (bApi *BlockApi) SyncedCheck(peerId blockchain.PeerId, block blockchain.Block) error {
+	if block == nil {
+		return ErrNilBlock
+	}
+
+	bApi.mutex.Lock()
+	defer bApi.mutex.Unlock()
+
+	bApi.syncMutex.Lock()
+	defer bApi.syncMutex.Unlock()
+
+	// 요청하지 않은 응답이거나 이미 Construct 단계인 경우
+	if bApi.syncState.IsProgressing() == blockchain.DONE || bApi.syncTarget != nil || !bApi.syncCheckPeers[peerId.Id] {
+		return nil
+	}
+	delete(bApi.syncCheckPeers, peerId.Id)
+
+	bApi.syncCheckResponses = append(bApi.syncCheckResponses, blockchain.SyncCheckResponse{
+		PeerId: peerId,
+		Block:  block,
+	})
+
+	tip, err := blockchain.SelectReliableTip(bApi.syncCheckResponses, bApi.syncCheckPeerCount)
+	if err != nil {
+		if len(bApi.syncCheckPeers) == 0 {
+			bApi.abortSync()
+			return ErrNoReliablePeer
+		}
+
+		// 아직 응답하지 않은 peer 가 있다.
+		return nil
+	}
+
+	lastBlock, err := bApi.blockRepository.GetLastBlock()
+	if err != nil {
+		return ErrGetLastBlock
+	}
+
+	if tip.Height <= lastBlock.GetHeight() {
+		return bApi.finishSync(lastBlock)
+	}
+
+	downloader, err := blockchain.NewBlockDownloader(lastBlock.GetHeight()+1, tip.Height, tip.Peers, bApi.grpcCommandService.RequestBlockRange, bApi.downloadConfig)
+	if err != nil {
+		bApi.abortSync()
+		return err
+	}
+
+	bApi.syncTarget = &syncTarget{
+		height:     tip.Height,
+		seal:       tip.Seal,
+		peers:      tip.Peers,
+		downloader: downloader,
+		lastBlock:  lastBlock,
+		blocks:     make([]blockchain.Block, 0),
+	}
+
+	if err := downloader.Start(time.Now()); err != nil {
+		bApi.abortSync()
+		return err
+	}
+
+	return nil
+}
+
+// SyncBlock 은 Construct 단계에서 peer 가 보낸 하나의 block 을 처리한다.
+func (bApi *BlockApi) SyncBlock(peerId blockchain.PeerId, block blockchain.Block) error {
+	if block == nil {
+		return ErrNilBlock
+	}
+
+	return bApi.SyncBlocks(peerId, blockchain.BlockRangeResponse{
+		From:   block.GetHeight(),
+		To:     block.GetHeight(),
+		Blocks: []blockchain.Block{block},
+	})
+}
+
+// SyncBlocks 는 Construct 단계에서 peer 가 보낸 range 응답을 downloader 에 넘기고,
+// height 순서대로 이어지는 block 들을 검증한다.
+// target height 의 block 이 quorum 이 동의한 tip 과 같으면 검증한 block 들을 commit 하고,
+// block pool 의 block 들을 이어서 commit 한 다음(PostConstruct) 동기화를 마친다.
+// 잘못된 block 이나 다른 tip 으로 이어지는 block 을 받으면 아무것도 commit 하지 않고 동기화를 중단한다.
+// 동기화가 끝난 뒤나 다른 peer 에게 다시 요청한 range 의 늦은 응답은 무시한다.
+func (bApi *BlockApi) SyncBlocks(peerId blockchain.PeerId, response blockchain.BlockRangeResponse) error {
+	bApi.mutex.Lock()
+	defer bApi.mutex.Unlock()
+
+	bApi.syncMutex.Lock()
+	defer bApi.syncMutex.Unlock()
+
+	if bApi.syncTarget == nil {
+		return nil
+	}
+
+	if !bApi.syncTarget.hasPeer(peerId) {
+		return ErrNotSyncTarget
+	}
+
+	orderedBlocks, err := bApi.syncTarget.downloader.Receive(peerId, response, time.Now())
+	if err == blockchain.ErrUnrequestedBlock {
+		return nil
+	}
+
+	if err != nil {
+		bApi.abortSync()
+		return err
+	}
+
+	for _, block := range orderedBlocks {
+		if err := bApi.verifySyncBlock(block); err != nil {
+			// 잘못된 block 을 보낸 peer 로부터는 더 이상 동기화하지 않는다.
+			bApi.abortSync()
+			return err
+		}
+	}
+
+	if !bApi.syncTarget.downloader.Done() {
+		return nil
+	}
+
+	return bApi.commitSyncBlocks()
+}
+
+// CheckSyncTimeout 은 응답이 없는 block 요청을 다른 peer 에게 다시 요청한다.
+// Check 단계에서 응답 대기 시간이 지나도록 quorum 이 동의한 tip 이 없으면 Check 단계를 다시 시작한다.
+// 재요청 횟수를 넘기면 동기화를 중단한다.
+func (bApi *BlockApi) CheckSyncTimeout() error {
+	bApi.syncMutex.Lock()
+	defer bApi.syncMutex.Unlock()
+
+	now := time.Now()
+
+	if bApi.syncTarget == nil {
+		return bApi.checkSyncCheckTimeout(now)
+	}
+
+	if err := bApi.syncTarget.downloader.CheckTimeout(now); err != nil {
+		bApi.abortSync()
+		return err
+	}
+
+	return nil
+}
+
+// checkSyncCheckTimeout 은 응답하지 않는 peer 가 남은 채로 deadline 이 지난 Check 단계를 중단하고 다시 시작한다.
+func (bApi *BlockApi) checkSyncCheckTimeout(now time.Time) error {
+	if bApi.syncState.IsProgressing() != blockchain.PROGRESSING || len(bApi.syncCheckPeers) == 0 || now.Before(bApi.syncCheckDeadline) {
+		return nil
+	}
+
+	bApi.abortSync()
+
+	if bApi.syncCheckRetry >= bApi.downloadConfig.MaxRetry {
+		bApi.syncCheckRetry = 0
+		return ErrNoReliablePeer
+	}
+
+	bApi.syncCheckRetry++
+
+	if err := bApi.startSyncCheck(); err != nil {
+		bApi.abortSync()
+		return err
+	}
+
+	return nil
+}
+
+// verifySyncBlock 은 block 이 마지막으로 검증한 block 의 다음 block 인지 검증하고 commit 할 block 으로 보관한다.
+func (bApi *BlockApi) verifySyncBlock(block blockchain.Block) error {
+	target := bApi.syncTarget
+
+	if err := bApi.validator.ValidateBlock(block, target.lastBlock); err != nil {
+		return err
+	}
+
+	// quorum 이 동의한 tip 과 다른 chain 이라면 commit 하지 않는다.
+	if block.GetHeight() == target.height && !bytes.Equal(block.GetSeal(), target.seal) {
+		return ErrSyncTipMismatch
+	}
+
+	target.blocks = append(target.blocks, block)
+	target.lastBlock = block
+
+	return nil
+}
+
+// commitSyncBlocks 는 tip 까지 검증한 block 들을 commit 하고 동기화를 마친다.
+func (bApi *BlockApi) commitSyncBlocks() error {
+	target := bApi.syncTarget
+	blockPool := bApi.loadBlockPool()
+
+	for _, block := range target.blocks {
+		if err := blockchain.NewSaveAction(bApi.blockRepository).DoAction(block); err != nil {
+			bApi.abortSync()
+			return err
+		}
+
+		if pooledBlock := blockPool.GetBySeal(block.GetSeal()); pooledBlock != nil {
+			blockPool.Delete(pooledBlock)
+		}
+	}
+
+	return bApi.finishSync(target.lastBlock)
+}
+
+// finishSync 는 lastBlock 에 이어지는 block pool 의 block 들을 commit 하고 동기화 상태를 DONE 으로 바꾼다.
+func (bApi *BlockApi) finishSync(lastBlock blockchain.Block) error {
+	bApi.abortSync()
+	bApi.syncCheckRetry = 0
+
+	return bApi.commitBlocksFromPool(lastBlock)
+}
+
+func (bApi *BlockApi) abortSync() {
+	bApi.syncTarget = nil
+	bApi.syncCheckPeers = make(map[string]bool)
+	bApi.syncCheckResponses = make([]blockchain.SyncCheckResponse, 0)
+	bApi.syncCheckPeerCount = 0
+	bApi.syncState.SetProgress(blockchain.DONE)
+}
+
 // 받은 block을 block pool에 추가한다.
+// block pool 에 추가되면 BlockAddToPoolEvent 가 발생하고, 해당 event 를 받아 CheckAndSaveBlockFromPool 이 호출된다.
 func (bApi *BlockApi) AddBlockToPool(block blockchain.Block) error {
+	if block == nil {
+		return ErrNilBlock
+	}
+
+	bApi.mutex.Lock()
+	defer bApi.mutex.Unlock()
+
+	if err := bApi.loadBlockPool().Add(block); err != nil {
+		return err
+	}
+
+	return bApi.pruneBlockPool()
+}
+
+// pruneBlockPool 은 마지막 block 을 기준으로 pool 의 한도를 벗어난 block 들을 제거한다.
+func (bApi *BlockApi) pruneBlockPool() error {
+	lastBlock, err := bApi.blockRepository.GetLastBlock()
+	if err != nil {
+		return ErrGetLastBlock
+	}
+
+	evicted := bApi.loadBlockPool().Prune(lastBlock.GetHeight(), time.Now())
+	if len(evicted) != 0 {
+		log.Printf("evicted [%v] blocks from block pool", len(evicted))
+	}
+
 	return nil
 }
 
+// CheckAndSaveBlockFromPool 은 pool 에 있는 height 의 block 들을 committed chain 과 비교한다.
+// 마지막 block 에 이어지는 block 들을 모두 commit 하고, committed chain 에서 갈라진 branch 는 reorg rule 에 따라 reorg 한다.
+// 마지막 block 보다 높지만 chain 에 이어지지 않는 block 이 남아 있으면 동기화를 시작한다.
 func (bApi *BlockApi) CheckAndSaveBlockFromPool(height blockchain.BlockHeight) error {
+	if bApi.SyncIsProgressing() == blockchain.PROGRESSING {
+		return ErrSyncProcessing
+	}
+
+	bApi.mutex.Lock()
+	defer bApi.mutex.Unlock()
+
+	blockPool := bApi.loadBlockPool()
+
+	if len(blockPool.GetByHeight(height)) == 0 {
+		// 이전 event 에서 이미 commit 되어 pool 에서 제거된 경우
+		return nil
+	}
+
+	lastBlock, err := bApi.blockRepository.GetLastBlock()
+	if err != nil {
+		return ErrGetLastBlock
+	}
+
+	if err := bApi.commitBlocksFromPool(lastBlock); err != nil {
+		return err
+	}
+
+	orphans := make([]blockchain.Block, 0)
+	for _, block := range blockPool.GetByHeight(height) {
+		connected, err := bApi.reorgToBranch(block)
+		if err != nil {
+			return err
+		}
+
+		if !connected {
+			orphans = append(orphans, block)
+		}
+	}
+
+	lastBlock, err = bApi.blockRepository.GetLastBlock()
+	if err != nil {
+		return ErrGetLastBlock
+	}
+
+	if len(orphans) != 0 && compareHeight(height, lastBlock.GetHeight()) > 0 {
+		return blockchain.NewSyncAction(bApi).DoAction(orphans[0])
+	}
+
+	return bApi.pruneBlockPool()
+}
+
+// commitBlocksFromPool 은 lastBlock 에 PrevSeal 로 이어지는 block 중 가장 긴 경로를 height 순서대로 검증하여 commit 하고 pool 에서 제거한다.
+// 검증에 실패한 block 은 pool 에서 제거하고 그 앞의 block 까지만 commit 한다.
+func (bApi *BlockApi) commitBlocksFromPool(lastBlock blockchain.Block) error {
+	blockPool := bApi.loadBlockPool()
+	branch := blockchain.ExtendBranch(blockchain.Branch{ForkPoint: lastBlock}, blockPool)
+
+	prevBlock := lastBlock
+	for _, block := range branch.Blocks {
+		if err := bApi.validatePoolBlock(block, prevBlock); err != nil {
+			blockPool.Delete(block)
+			return err
+		}
+
+		if err := blockchain.NewSaveAction(bApi.blockRepository).DoAction(block); err != nil {
+			return err
+		}
+
+		blockPool.Delete(block)
+		prevBlock = block
+	}
+
+	return nil
+}
+
+// reorgToBranch 는 block 이 committed chain 에서 갈라진 branch 에 속하면 branch 를 가장 길게 늘린 뒤
+// reorg rule 이 허락할 때 committed chain 을 branch 로 바꾼다.
+// 이미 commit 된 block 은 pool 에서 제거하고, chain 에 이어지지 않는 block 은 pool 에 남겨두고 false 를 반환한다.
+func (bApi *BlockApi) reorgToBranch(block blockchain.Block) (bool, error) {
+	blockPool := bApi.loadBlockPool()
+
+	// transaction 이 지워진 block 도 commit 된 block 이다.
+	if _, err := bApi.blockRepository.GetBlockBySeal(block.GetSeal()); err == nil || err == blockchain.ErrBlockPruned {
+		blockPool.Delete(block)
+		return true, nil
+	} else if err != blockchain.ErrBlockNotFound {
+		return false, err
+	}
+
+	branch, err := blockchain.FindBranch(block, blockPool, bApi.blockRepository)
+	if err == blockchain.ErrBranchNotConnected {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	branch = blockchain.ExtendBranch(branch, blockPool)
+
+	lastBlock, err := bApi.blockRepository.GetLastBlock()
+	if err != nil {
+		return true, ErrGetLastBlock
+	}
+
+	if bytes.Equal(branch.ForkPoint.GetSeal(), lastBlock.GetSeal()) {
+		return true, nil
+	}
+
+	current := make([]blockchain.Block, 0)
+	for height := branch.ForkPoint.GetHeight() + 1; height <= lastBlock.GetHeight(); height++ {
+		committed, err := bApi.blockRepository.GetBlockByHeight(height)
+		if err != nil {
+			return true, err
+		}
+		current = append(current, committed)
+	}
+
+	if !bApi.reorgRule.ShouldReorg(current, branch) {
+		return true, nil
+	}
+
+	// 아무것도 되돌리기 전에 branch 의 모든 block 을 검증한다.
+	if err := bApi.validateBranch(branch); err != nil {
+		return true, err
+	}
+
+	return true, bApi.reorganize(current, branch)
+}
+
+// validateBranch 는 branch 의 block 들을 ForkPoint 부터 차례대로 이전 block 에 대해 검증한다.
+// 검증에 실패한 block 은 다시 선택되지 않도록 pool 에서 제거한다.
+func (bApi *BlockApi) validateBranch(branch blockchain.Branch) error {
+	prevBlock := branch.ForkPoint
+	for _, block := range branch.Blocks {
+		if err := bApi.validatePoolBlock(block, prevBlock); err != nil {
+			bApi.loadBlockPool().Delete(block)
+			return err
+		}
+
+		prevBlock = block
+	}
+
+	return nil
+}
+
+// validatePoolBlock 은 pool 의 block 을 prevBlock 에 대해 검증하고, snapshot height 의 block 이면 state root 도 검증한다.
+func (bApi *BlockApi) validatePoolBlock(block blockchain.Block, prevBlock blockchain.Block) error {
+	if err := bApi.validator.ValidateBlock(block, prevBlock); err != nil {
+		return err
+	}
+
+	if bApi.stateRootValidator == nil {
+		return nil
+	}
+
+	return bApi.stateRootValidator.ValidateStateRoot(block)
+}
+
+// reorganize 는 마지막 block 부터 current 를 되돌린 다음 branch 의 block 들을 commit 한다.
+// 되돌린 block 은 나중에 다시 선택될 수 있도록 pool 에 넣는다.
+// 중간에 실패하면 restoreChain 으로 원래 chain 을 복구한다.
+func (bApi *BlockApi) reorganize(current []blockchain.Block, branch blockchain.Branch) error {
+	blockPool := bApi.loadBlockPool()
+
+	log.Printf("reorganize chain from height [%v]: rollback [%v] blocks, apply [%v] blocks",
+		branch.ForkPoint.GetHeight()+1, len(current), len(branch.Blocks))
+
+	for i := len(current) - 1; i >= 0; i-- {
+		if err := blockchain.NewRollbackAction(bApi.blockRepository).DoAction(current[i]); err != nil {
+			return bApi.restoreChain(current[i+1:], nil, err)
+		}
+
+		if err := blockPool.Add(current[i]); err != nil {
+			return bApi.restoreChain(current[i:], nil, err)
+		}
+	}
+
+	for i, block := range branch.Blocks {
+		if err := blockchain.NewReapplyAction(bApi.blockRepository).DoAction(block); err != nil {
+			return bApi.restoreChain(current, branch.Blocks[:i], err)
+		}
+
+		blockPool.Delete(block)
+	}
+
 	return nil
 }
 
+// restoreChain 은 실패한 reorg 에서 commit 한 applied 를 되돌리고 되돌렸던 rolledBack 을 다시 commit 하여 원래 chain 으로 복구한다.
+// applied 는 다시 선택될 수 있도록 pool 에 넣고, 복구에 성공하면 reorg 가 실패한 원인 err 를 반환한다.
+func (bApi *BlockApi) restoreChain(rolledBack []blockchain.Block, applied []blockchain.Block, err error) error {
+	blockPool := bApi.loadBlockPool()
+
+	log.Printf("fail to reorganize chain, restore [%v] blocks: [%v]", len(rolledBack), err)
+
+	for i := len(applied) - 1; i >= 0; i-- {
+		if restoreErr := blockchain.NewRollbackAction(bApi.blockRepository).DoAction(applied[i]); restoreErr != nil {
+			return restoreErr
+		}
+
+		if restoreErr := blockPool.Add(applied[i]); restoreErr != nil {
+			log.Printf("fail to return block [%v] to block pool: [%v]", applied[i].GetHeight(), restoreErr)
+		}
+	}
+
+	for _, block := range rolledBack {
+		if restoreErr := blockchain.NewReapplyAction(bApi.blockRepository).DoAction(block); restoreErr != nil {
+			return restoreErr
+		}
+
+		blockPool.Delete(block)
+	}
+
+	return err
+}
+
 func (bApi *BlockApi) SyncIsProgressing() blockchain.ProgressState {
-	return blockchain.DONE
+	bApi.syncMutex.Lock()
+	defer bApi.syncMutex.Unlock()
+
+	return bApi.syncState.IsProgressing()
 }
 
 func (bApi *BlockApi) loadBlockPool() blockchain.BlockPool {
-	return nil
+	return bApi.blockPool
 }
 
 func compareHeight(height1 uint64, height2 uint64) int64 {
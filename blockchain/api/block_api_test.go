@@ -1,10 +1,14 @@
 package api_test
 
 import (
+	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/it-chain/engine/blockchain"
 	"github.com/it-chain/engine/blockchain/api"
+	"github.com/it-chain/engine/blockchain/test/mock"
+	"github.com/it-chain/engine/core/eventstore"
 	"github.com/magiconair/properties/assert"
 )
 
@@ -25,7 +29,12 @@ func TestBlockApi_AddBlockToPool(t *testing.T) {
 
 	publisherId := "zf"
 
-	blockApi, _ := api.NewBlockApi(publisherId)
+	blockRepository := mock.BlockRepository{}
+	blockRepository.GetLastBlockFunc = func() (blockchain.Block, error) {
+		return &blockchain.DefaultBlock{Height: uint64(10)}, nil
+	}
+
+	blockApi, _ := api.NewBlockApi(publisherId, blockRepository, mock.GrpcCommandService{}, mock.PeerRepository{})
 
 	for testName, test := range tests {
 		t.Logf("running test case %s", testName)
@@ -34,6 +43,35 @@ func TestBlockApi_AddBlockToPool(t *testing.T) {
 	}
 }
 
+func TestBlockApi_AddBlockToPool_Limit(t *testing.T) {
+	// given
+	committed := []blockchain.Block{
+		&blockchain.DefaultBlock{Seal: []byte("seal0"), Height: blockchain.BlockHeight(0)},
+	}
+
+	peerRepository := mock.PeerRepository{}
+	peerRepository.FindAllFunc = func() ([]blockchain.Peer, error) {
+		return []blockchain.Peer{}, nil
+	}
+
+	blockApi, _ := api.NewBlockApi("zf", newChainRepository(&committed), mock.GrpcCommandService{}, peerRepository)
+	blockApi.SetBlockPoolConfig(blockchain.PoolConfig{MaxHeightDistance: 5})
+
+	// when: tip 에서 한도보다 먼 block
+	err := blockApi.AddBlockToPool(&blockchain.DefaultBlock{Seal: []byte("far"), PrevSeal: []byte("x"), Height: blockchain.BlockHeight(6)})
+
+	// then: pool 에 남지 않으므로 동기화를 시작하지 않는다.
+	assert.Equal(t, nil, err)
+	assert.Equal(t, nil, blockApi.CheckAndSaveBlockFromPool(blockchain.BlockHeight(6)))
+
+	// when: 한도 안의 block
+	err = blockApi.AddBlockToPool(&blockchain.DefaultBlock{Seal: []byte("near"), PrevSeal: []byte("x"), Height: blockchain.BlockHeight(5)})
+
+	// then: chain 에 이어지지 않으므로 동기화를 시작한다.
+	assert.Equal(t, nil, err)
+	assert.Equal(t, api.ErrNoPeer, blockApi.CheckAndSaveBlockFromPool(blockchain.BlockHeight(5)))
+}
+
 func TestBlockApi_CheckAndSaveBlockFromPool(t *testing.T) {
 	tests := map[string]struct {
 		input struct {
@@ -51,7 +89,7 @@ func TestBlockApi_CheckAndSaveBlockFromPool(t *testing.T) {
 	publisherId := "zf"
 
 	// When
-	blockApi, _ := api.NewBlockApi(publisherId)
+	blockApi, _ := api.NewBlockApi(publisherId, mock.BlockRepository{}, mock.GrpcCommandService{}, mock.PeerRepository{})
 
 	for testName, test := range tests {
 		t.Logf("running test case %s", testName)
@@ -69,9 +107,828 @@ func TestBlockApi_SyncIsProgressing(t *testing.T) {
 	publisherId := "zf"
 
 	// when
-	blockApi, _ := api.NewBlockApi(publisherId)
+	blockApi, _ := api.NewBlockApi(publisherId, mock.BlockRepository{}, mock.GrpcCommandService{}, mock.PeerRepository{})
 
 	// then
 	state := blockApi.SyncIsProgressing()
 	assert.Equal(t, blockchain.DONE, state)
 }
+
+func TestBlockApi_CheckAndSaveBlockFromPool_CommitConsecutiveBlocks(t *testing.T) {
+	// given
+	eventstore.InitForMock(mock.NewEventLog())
+	defer eventstore.Close()
+
+	genesis := &blockchain.DefaultBlock{Seal: []byte("seal0"), Height: blockchain.BlockHeight(0)}
+	committed := []blockchain.Block{genesis}
+
+	peerRepository := mock.PeerRepository{}
+	peerRepository.FindAllFunc = func() ([]blockchain.Peer, error) {
+		return []blockchain.Peer{}, nil
+	}
+
+	blockApi, _ := api.NewBlockApi("zf", newChainRepository(&committed), mock.GrpcCommandService{}, peerRepository)
+
+	block1 := mock.NewLegacyBlock(t, genesis.Seal, 1)
+	block2 := mock.NewLegacyBlock(t, block1.Seal, 2)
+	block3 := mock.NewLegacyBlock(t, []byte("other"), 3)
+
+	// when: blocks arrive out of order
+	blockApi.AddBlockToPool(block3)
+	blockApi.AddBlockToPool(block2)
+	err := blockApi.CheckAndSaveBlockFromPool(blockchain.BlockHeight(2))
+
+	// then: nothing links to last block yet, and there is no peer to synchronize with
+	assert.Equal(t, api.ErrNoPeer, err)
+	assert.Equal(t, 1, len(committed))
+
+	// when
+	blockApi.AddBlockToPool(block1)
+	err = blockApi.CheckAndSaveBlockFromPool(blockchain.BlockHeight(1))
+
+	// then: block1 and block2 are committed, block3 does not link to block2
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 3, len(committed))
+	assert.Equal(t, block1.Seal, committed[1].GetSeal())
+	assert.Equal(t, block2.Seal, committed[2].GetSeal())
+
+	// when: event of already committed block arrives
+	err = blockApi.CheckAndSaveBlockFromPool(blockchain.BlockHeight(2))
+
+	// then
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 3, len(committed))
+
+	// when: block lower than last block
+	blockApi.AddBlockToPool(mock.NewLegacyBlock(t, genesis.Seal, 1))
+	err = blockApi.CheckAndSaveBlockFromPool(blockchain.BlockHeight(1))
+
+	// then
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 3, len(committed))
+}
+
+func TestBlockApi_CheckAndSaveBlockFromPool_InvalidBlock(t *testing.T) {
+	// given
+	eventstore.InitForMock(mock.NewEventLog())
+	defer eventstore.Close()
+
+	genesis := &blockchain.DefaultBlock{Seal: []byte("seal0"), Height: blockchain.BlockHeight(0)}
+	committed := []blockchain.Block{genesis}
+
+	blockApi, _ := api.NewBlockApi("zf", newChainRepository(&committed), mock.GrpcCommandService{}, mock.PeerRepository{})
+
+	block1 := mock.NewLegacyBlock(t, genesis.Seal, 1)
+	forged := mock.NewLegacyBlock(t, block1.Seal, 2)
+	forged.Seal = []byte("forged")
+	block3 := mock.NewLegacyBlock(t, forged.Seal, 3)
+
+	// when: block2 의 seal 이 잘못됨
+	blockApi.AddBlockToPool(block1)
+	blockApi.AddBlockToPool(forged)
+	blockApi.AddBlockToPool(block3)
+	err := blockApi.CheckAndSaveBlockFromPool(blockchain.BlockHeight(1))
+
+	// then: 검증한 block1 까지만 commit 한다.
+	assert.Equal(t, blockchain.ErrInvalidSeal, err)
+	assert.Equal(t, 2, len(committed))
+	assert.Equal(t, block1.Seal, committed[1].GetSeal())
+
+	// when
+	err = blockApi.CheckAndSaveBlockFromPool(blockchain.BlockHeight(2))
+
+	// then: 잘못된 block 은 pool 에서 제거되었다.
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 2, len(committed))
+}
+
+func TestBlockApi_CheckAndSaveBlockFromPool_StateRoot(t *testing.T) {
+	// given
+	eventstore.InitForMock(mock.NewEventLog())
+	defer eventstore.Close()
+
+	genesis := &blockchain.DefaultBlock{Seal: []byte("seal0"), Height: blockchain.BlockHeight(0)}
+	committed := []blockchain.Block{genesis}
+
+	icodes := []blockchain.ICodeState{{ID: "icode1", GitUrl: "url", CommitHash: "hash"}}
+	parliament := []string{"peer1"}
+	icodeStateRepository := mock.ICodeStateRepository{}
+	icodeStateRepository.FindAllICodeStatesFunc = func() ([]blockchain.ICodeState, error) {
+		return icodes, nil
+	}
+
+	blockApi, _ := api.NewBlockApi("zf", newChainRepository(&committed), mock.GrpcCommandService{}, mock.PeerRepository{})
+	blockApi.SetStateRootValidator(blockchain.NewStateRootValidator(blockchain.SnapshotConfig{Interval: 2}, icodeStateRepository, parliament))
+
+	block1 := mock.NewSnapshotBlock(t, genesis.Seal, 1, nil)
+	forged := mock.NewSnapshotBlock(t, block1.Seal, 2, blockchain.SnapshotStateRoot(nil, parliament))
+
+	// when: snapshot height 의 block 이 local state 와 다른 state root 를 담았다.
+	blockApi.AddBlockToPool(block1)
+	blockApi.AddBlockToPool(forged)
+	err := blockApi.CheckAndSaveBlockFromPool(blockchain.BlockHeight(1))
+
+	// then: snapshot height 가 아닌 block1 까지만 commit 한다.
+	assert.Equal(t, blockchain.ErrStateRootMismatch, err)
+	assert.Equal(t, 2, len(committed))
+
+	// when: local state 와 같은 state root
+	block2 := mock.NewSnapshotBlock(t, block1.Seal, 2, blockchain.SnapshotStateRoot(icodes, parliament))
+	blockApi.AddBlockToPool(block2)
+	err = blockApi.CheckAndSaveBlockFromPool(blockchain.BlockHeight(2))
+
+	// then
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 3, len(committed))
+	assert.Equal(t, block2.Seal, committed[2].GetSeal())
+}
+
+func TestBlockApi_CheckAndSaveBlockFromPool_Reorg(t *testing.T) {
+	// given
+	eventstore.InitForMock(mock.NewEventLog())
+	defer eventstore.Close()
+
+	genesis := &blockchain.DefaultBlock{Seal: []byte("seal0"), Height: blockchain.BlockHeight(0)}
+	a1 := mock.NewLegacyBlock(t, genesis.Seal, 1)
+	committed := []blockchain.Block{genesis, a1}
+
+	peerRepository := mock.PeerRepository{}
+	peerRepository.FindAllFunc = func() ([]blockchain.Peer, error) {
+		return []blockchain.Peer{}, nil
+	}
+
+	blockApi, _ := api.NewBlockApi("zf", newChainRepository(&committed), mock.GrpcCommandService{}, peerRepository)
+
+	b1 := mock.NewLegacyBlock(t, genesis.Seal, 1)
+	b2 := mock.NewLegacyBlock(t, b1.Seal, 2)
+
+	// when: 같은 길이의 fork
+	blockApi.AddBlockToPool(b1)
+	err := blockApi.CheckAndSaveBlockFromPool(blockchain.BlockHeight(1))
+
+	// then: 더 길지 않으므로 reorg 하지 않는다.
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 2, len(committed))
+	assert.Equal(t, a1.Seal, committed[1].GetSeal())
+
+	// when: fork 된 branch 가 더 길어짐
+	blockApi.AddBlockToPool(b2)
+	err = blockApi.CheckAndSaveBlockFromPool(blockchain.BlockHeight(2))
+
+	// then
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 3, len(committed))
+	assert.Equal(t, b1.Seal, committed[1].GetSeal())
+	assert.Equal(t, b2.Seal, committed[2].GetSeal())
+
+	// when: 되돌려진 block 은 pool 에 남아 있으므로 a1 에 이어지는 block 이 오면 다시 후보가 된다.
+	a2 := mock.NewLegacyBlock(t, a1.Seal, 2)
+	a3 := mock.NewLegacyBlock(t, a2.Seal, 3)
+	blockApi.AddBlockToPool(a2)
+	blockApi.AddBlockToPool(a3)
+	err = blockApi.CheckAndSaveBlockFromPool(blockchain.BlockHeight(3))
+
+	// then
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 4, len(committed))
+	assert.Equal(t, a1.Seal, committed[1].GetSeal())
+	assert.Equal(t, a3.Seal, committed[3].GetSeal())
+}
+
+func TestBlockApi_CheckAndSaveBlockFromPool_ReorgInvalidBranch(t *testing.T) {
+	// given
+	eventstore.InitForMock(mock.NewEventLog())
+	defer eventstore.Close()
+
+	genesis := &blockchain.DefaultBlock{Seal: []byte("seal0"), Height: blockchain.BlockHeight(0)}
+	a1 := mock.NewLegacyBlock(t, genesis.Seal, 1)
+	committed := []blockchain.Block{genesis, a1}
+
+	blockRepository := newChainRepository(&committed)
+	blockRepository.RemoveBlockFunc = func(block blockchain.Block) error {
+		t.Fatal("committed block must not be rolled back for an invalid branch")
+		return nil
+	}
+
+	blockApi, _ := api.NewBlockApi("zf", blockRepository, mock.GrpcCommandService{}, mock.PeerRepository{})
+
+	b1 := mock.NewLegacyBlock(t, genesis.Seal, 1)
+	b2 := mock.NewLegacyBlock(t, b1.Seal, 2)
+	b2.TxSeal = [][]byte{[]byte("forged")}
+
+	// when: 더 긴 branch 이지만 b2 의 tx seal 이 잘못됨
+	blockApi.AddBlockToPool(b1)
+	blockApi.AddBlockToPool(b2)
+	err := blockApi.CheckAndSaveBlockFromPool(blockchain.BlockHeight(2))
+
+	// then: 아무것도 되돌리지 않는다.
+	assert.Equal(t, blockchain.ErrInvalidTxSeal, err)
+	assert.Equal(t, 2, len(committed))
+	assert.Equal(t, a1.Seal, committed[1].GetSeal())
+
+	// when
+	err = blockApi.CheckAndSaveBlockFromPool(blockchain.BlockHeight(2))
+
+	// then: 잘못된 block 은 pool 에서 제거되었다.
+	assert.Equal(t, nil, err)
+	assert.Equal(t, a1.Seal, committed[1].GetSeal())
+}
+
+func TestBlockApi_CheckAndSaveBlockFromPool_ReorgRestoreOnFailure(t *testing.T) {
+	// given
+	eventstore.InitForMock(mock.NewEventLog())
+	defer eventstore.Close()
+
+	genesis := &blockchain.DefaultBlock{Seal: []byte("seal0"), Height: blockchain.BlockHeight(0)}
+	a1 := mock.NewLegacyBlock(t, genesis.Seal, 1)
+	committed := []blockchain.Block{genesis, a1}
+
+	b1 := mock.NewLegacyBlock(t, genesis.Seal, 1)
+	b2 := mock.NewLegacyBlock(t, b1.Seal, 2)
+
+	errDiskFull := errors.New("disk full")
+	diskFull := true
+
+	blockRepository := newChainRepository(&committed)
+	addBlock := blockRepository.AddBlockFunc
+	blockRepository.AddBlockFunc = func(block blockchain.Block) error {
+		if diskFull && string(block.GetSeal()) == string(b2.Seal) {
+			return errDiskFull
+		}
+		return addBlock(block)
+	}
+
+	blockApi, _ := api.NewBlockApi("zf", blockRepository, mock.GrpcCommandService{}, mock.PeerRepository{})
+
+	// when: b1 은 commit 되었지만 b2 를 commit 하지 못함
+	blockApi.AddBlockToPool(b1)
+	blockApi.AddBlockToPool(b2)
+	err := blockApi.CheckAndSaveBlockFromPool(blockchain.BlockHeight(2))
+
+	// then: 원래 chain 으로 복구한다.
+	assert.Equal(t, errDiskFull, err)
+	assert.Equal(t, 2, len(committed))
+	assert.Equal(t, a1.Seal, committed[1].GetSeal())
+
+	// when: 다시 commit 할 수 있게 됨
+	diskFull = false
+	err = blockApi.CheckAndSaveBlockFromPool(blockchain.BlockHeight(2))
+
+	// then: branch 의 block 들은 pool 에 남아 있으므로 다시 reorg 한다.
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 3, len(committed))
+	assert.Equal(t, b1.Seal, committed[1].GetSeal())
+	assert.Equal(t, b2.Seal, committed[2].GetSeal())
+}
+
+func TestBlockApi_CheckAndSaveBlockFromPool_FinalityRule(t *testing.T) {
+	// given
+	eventstore.InitForMock(mock.NewEventLog())
+	defer eventstore.Close()
+
+	genesis := &blockchain.DefaultBlock{Seal: []byte("seal0"), Height: blockchain.BlockHeight(0)}
+	a1 := mock.NewLegacyBlock(t, genesis.Seal, 1)
+	a2 := mock.NewLegacyBlock(t, a1.Seal, 2)
+	committed := []blockchain.Block{genesis, a1, a2}
+
+	peerRepository := mock.PeerRepository{}
+	peerRepository.FindAllFunc = func() ([]blockchain.Peer, error) {
+		return []blockchain.Peer{}, nil
+	}
+
+	blockApi, _ := api.NewBlockApi("zf", newChainRepository(&committed), mock.GrpcCommandService{}, peerRepository)
+
+	b1 := mock.NewLegacyBlock(t, genesis.Seal, 1)
+
+	finalityChecker := mock.FinalityChecker{}
+	finalityChecker.IsFinalFunc = func(block blockchain.Block) bool {
+		return string(block.GetSeal()) == string(b1.Seal)
+	}
+	blockApi.SetReorgRule(blockchain.NewFinalityRule(finalityChecker, blockchain.LongestChainRule{}))
+
+	// when: 더 짧지만 확정된 block 을 가진 branch
+	blockApi.AddBlockToPool(b1)
+	err := blockApi.CheckAndSaveBlockFromPool(blockchain.BlockHeight(1))
+
+	// then
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 2, len(committed))
+	assert.Equal(t, b1.Seal, committed[1].GetSeal())
+
+	// when: 되돌려진 a2 의 height 에 대한 event 가 와도 확정된 b1 은 되돌리지 않고 동기화도 하지 않는다.
+	err = blockApi.CheckAndSaveBlockFromPool(blockchain.BlockHeight(2))
+
+	// then
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 2, len(committed))
+	assert.Equal(t, b1.Seal, committed[1].GetSeal())
+}
+
+// newChainRepository 는 committed 를 chain 으로 사용하는 mock repository 를 만든다.
+func newChainRepository(committed *[]blockchain.Block) mock.BlockRepository {
+	blockRepository := mock.BlockRepository{}
+	blockRepository.GetLastBlockFunc = func() (blockchain.Block, error) {
+		return (*committed)[len(*committed)-1], nil
+	}
+	blockRepository.GetBlockByHeightFunc = func(height uint64) (blockchain.Block, error) {
+		if height >= uint64(len(*committed)) {
+			return nil, blockchain.ErrBlockNotFound
+		}
+		return (*committed)[height], nil
+	}
+	blockRepository.GetBlockBySealFunc = func(seal []byte) (blockchain.Block, error) {
+		for _, block := range *committed {
+			if string(block.GetSeal()) == string(seal) {
+				return block, nil
+			}
+		}
+		return nil, blockchain.ErrBlockNotFound
+	}
+	blockRepository.AddBlockFunc = func(block blockchain.Block) error {
+		*committed = append(*committed, block)
+		return nil
+	}
+	blockRepository.RemoveBlockFunc = func(block blockchain.Block) error {
+		*committed = (*committed)[:len(*committed)-1]
+		return nil
+	}
+	return blockRepository
+}
+
+func TestBlockApi_AddBlockToPool_NilBlock(t *testing.T) {
+	// given
+	blockApi, _ := api.NewBlockApi("zf", mock.BlockRepository{}, mock.GrpcCommandService{}, mock.PeerRepository{})
+
+	// when
+	err := blockApi.AddBlockToPool(nil)
+
+	// then
+	assert.Equal(t, api.ErrNilBlock, err)
+}
+
+func TestBlockApi_Synchronize(t *testing.T) {
+	// given
+	eventstore.InitForMock(mock.NewEventLog())
+	defer eventstore.Close()
+
+	genesis := &blockchain.DefaultBlock{Seal: []byte("genesis"), Height: blockchain.BlockHeight(0)}
+	committed := []blockchain.Block{genesis}
+
+	blockRepository := mock.BlockRepository{}
+	blockRepository.GetLastBlockFunc = func() (blockchain.Block, error) {
+		return committed[len(committed)-1], nil
+	}
+	blockRepository.AddBlockFunc = func(block blockchain.Block) error {
+		committed = append(committed, block)
+		return nil
+	}
+
+	peerRepository := mock.PeerRepository{}
+	peerRepository.FindAllFunc = func() ([]blockchain.Peer, error) {
+		return []blockchain.Peer{
+			{PeerId: blockchain.PeerId{Id: "peer1"}},
+			{PeerId: blockchain.PeerId{Id: "peer2"}},
+			{PeerId: blockchain.PeerId{Id: "peer3"}},
+		}, nil
+	}
+
+	syncCheckRequested := make([]string, 0)
+	requestedRanges := make([]string, 0)
+	grpcCommandService := mock.GrpcCommandService{}
+	grpcCommandService.SyncCheckRequestFunc = func(peerId blockchain.PeerId) error {
+		syncCheckRequested = append(syncCheckRequested, peerId.Id)
+		return nil
+	}
+	grpcCommandService.RequestBlockRangeFunc = func(peerId blockchain.PeerId, from blockchain.BlockHeight, to blockchain.BlockHeight) error {
+		requestedRanges = append(requestedRanges, fmt.Sprintf("%s:%d-%d", peerId.Id, from, to))
+		return nil
+	}
+
+	blockApi, _ := api.NewBlockApi("zf", blockRepository, grpcCommandService, peerRepository)
+
+	block1 := mock.NewLegacyBlock(t, genesis.Seal, 1)
+	block2 := mock.NewLegacyBlock(t, block1.Seal, 2)
+	block3 := mock.NewLegacyBlock(t, block2.Seal, 3)
+
+	// when: check 단계 시작
+	err := blockApi.Synchronize()
+
+	// then
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 3, len(syncCheckRequested))
+	assert.Equal(t, blockchain.PROGRESSING, blockApi.SyncIsProgressing())
+
+	// when: 동기화 중에 합의된 block 은 pool 에만 쌓인다.
+	blockApi.AddBlockToPool(block3)
+	err = blockApi.CheckAndSaveBlockFromPool(block3.GetHeight())
+
+	// then
+	assert.Equal(t, api.ErrSyncProcessing, err)
+
+	// when: peer3 은 다른 chain 의 더 높은 block 을 알려줌
+	err = blockApi.SyncedCheck(blockchain.PeerId{Id: "peer3"}, &blockchain.DefaultBlock{Seal: []byte("fake"), Height: blockchain.BlockHeight(50)})
+
+	// then: 하나의 peer 만으로는 동기화하지 않는다.
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 0, len(requestedRanges))
+
+	// when
+	err = blockApi.SyncedCheck(blockchain.PeerId{Id: "peer2"}, block2)
+
+	// then: 아직 quorum 이 되지 않음
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 0, len(requestedRanges))
+
+	// when: peer1 과 peer2 가 같은 tip 에 동의함
+	err = blockApi.SyncedCheck(blockchain.PeerId{Id: "peer1"}, block2)
+
+	// then
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []string{"peer1:1-2"}, requestedRanges)
+
+	// when: target 이 아닌 peer 의 block
+	err = blockApi.SyncBlock(blockchain.PeerId{Id: "peer3"}, block1)
+
+	// then
+	assert.Equal(t, api.ErrNotSyncTarget, err)
+
+	// when: peer1 이 요청한 range 의 일부만 보냄
+	err = blockApi.SyncBlock(blockchain.PeerId{Id: "peer1"}, block1)
+
+	// then: 받지 못한 block 은 tip 에 동의한 다른 peer 에게 요청한다.
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []string{"peer1:1-2", "peer2:2-2"}, requestedRanges)
+
+	// then: tip 까지 검증하기 전에는 commit 하지 않는다.
+	assert.Equal(t, 1, len(committed))
+
+	// when: target height 까지 받으면 pool 의 block 까지 commit 하고 동기화를 마친다.
+	err = blockApi.SyncBlocks(blockchain.PeerId{Id: "peer2"}, blockchain.BlockRangeResponse{From: 2, To: 2, Blocks: []blockchain.Block{block2}})
+
+	// then
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 4, len(committed))
+	assert.Equal(t, block3.Seal, committed[3].GetSeal())
+	assert.Equal(t, blockchain.DONE, blockApi.SyncIsProgressing())
+
+	// when: 동기화가 끝난 뒤 늦게 도착한 응답
+	err = blockApi.SyncBlocks(blockchain.PeerId{Id: "peer1"}, blockchain.BlockRangeResponse{From: 1, To: 2, Blocks: []blockchain.Block{block1}})
+
+	// then: 무시한다.
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 4, len(committed))
+}
+
+func TestBlockApi_LoadState_RestartDuringSync(t *testing.T) {
+	// given
+	eventstore.InitForMock(mock.NewEventLog())
+	defer eventstore.Close()
+
+	genesis := &blockchain.DefaultBlock{Seal: []byte("genesis"), Height: blockchain.BlockHeight(0)}
+	committed := []blockchain.Block{genesis}
+
+	blockRepository := mock.BlockRepository{}
+	blockRepository.GetLastBlockFunc = func() (blockchain.Block, error) {
+		return committed[len(committed)-1], nil
+	}
+	blockRepository.AddBlockFunc = func(block blockchain.Block) error {
+		committed = append(committed, block)
+		return nil
+	}
+
+	peerRepository := mock.PeerRepository{}
+	peerRepository.FindAllFunc = func() ([]blockchain.Peer, error) {
+		return []blockchain.Peer{
+			{PeerId: blockchain.PeerId{Id: "peer1"}},
+			{PeerId: blockchain.PeerId{Id: "peer2"}},
+		}, nil
+	}
+
+	syncCheckRequested := make([]string, 0)
+	requestedRanges := make([]string, 0)
+	grpcCommandService := mock.GrpcCommandService{}
+	grpcCommandService.SyncCheckRequestFunc = func(peerId blockchain.PeerId) error {
+		syncCheckRequested = append(syncCheckRequested, peerId.Id)
+		return nil
+	}
+	grpcCommandService.RequestBlockRangeFunc = func(peerId blockchain.PeerId, from blockchain.BlockHeight, to blockchain.BlockHeight) error {
+		requestedRanges = append(requestedRanges, fmt.Sprintf("%s:%d-%d", peerId.Id, from, to))
+		return nil
+	}
+
+	block1 := mock.NewLegacyBlock(t, genesis.Seal, 1)
+	block2 := mock.NewLegacyBlock(t, block1.Seal, 2)
+	block3 := mock.NewLegacyBlock(t, block2.Seal, 3)
+
+	// 동기화를 시작하고, 동기화 중에 받은 block 은 pool 에 쌓인다.
+	blockApi, _ := api.NewBlockApi("zf", blockRepository, grpcCommandService, peerRepository)
+	assert.Equal(t, nil, blockApi.LoadState())
+	assert.Equal(t, nil, blockApi.Synchronize())
+	assert.Equal(t, nil, blockApi.AddBlockToPool(block3))
+	assert.Equal(t, nil, blockApi.SyncedCheck(blockchain.PeerId{Id: "peer1"}, block2))
+
+	// when: 응답을 다 받기 전에 재시작
+	restarted, _ := api.NewBlockApi("zf", blockRepository, grpcCommandService, peerRepository)
+	assert.Equal(t, blockchain.DONE, restarted.SyncIsProgressing())
+	err := restarted.LoadState()
+
+	// then: 동기화 상태가 복원되고 Check 단계부터 다시 시작한다.
+	assert.Equal(t, nil, err)
+	assert.Equal(t, blockchain.PROGRESSING, restarted.SyncIsProgressing())
+	assert.Equal(t, 4, len(syncCheckRequested))
+
+	// when: 이전 실행에서 받은 응답은 다시 받아야 한다.
+	assert.Equal(t, nil, restarted.SyncedCheck(blockchain.PeerId{Id: "peer1"}, block2))
+	assert.Equal(t, nil, restarted.SyncedCheck(blockchain.PeerId{Id: "peer2"}, block2))
+	err = restarted.SyncBlocks(blockchain.PeerId{Id: "peer1"}, blockchain.BlockRangeResponse{From: 1, To: 2, Blocks: []blockchain.Block{block1, block2}})
+
+	// then: 재시작 전에 pool 에 쌓인 block 까지 commit 한다.
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []string{"peer1:1-2"}, requestedRanges)
+	assert.Equal(t, 4, len(committed))
+	assert.Equal(t, block3.Seal, committed[3].GetSeal())
+	assert.Equal(t, blockchain.DONE, restarted.SyncIsProgressing())
+}
+
+func TestBlockApi_LoadState_NoPeerToResume(t *testing.T) {
+	// given
+	eventstore.InitForMock(mock.NewEventLog())
+	defer eventstore.Close()
+
+	blockchain.NewBlockSyncState().SetProgress(blockchain.PROGRESSING)
+
+	peerRepository := mock.PeerRepository{}
+	peerRepository.FindAllFunc = func() ([]blockchain.Peer, error) {
+		return []blockchain.Peer{}, nil
+	}
+
+	blockApi, _ := api.NewBlockApi("zf", mock.BlockRepository{}, mock.GrpcCommandService{}, peerRepository)
+
+	// when
+	err := blockApi.LoadState()
+
+	// then: 다시 시작할 수 없는 동기화는 중단한다.
+	assert.Equal(t, nil, err)
+	assert.Equal(t, blockchain.DONE, blockApi.SyncIsProgressing())
+}
+
+func TestBlockApi_Synchronize_AlreadySynced(t *testing.T) {
+	// given
+	eventstore.InitForMock(mock.NewEventLog())
+	defer eventstore.Close()
+
+	genesis := &blockchain.DefaultBlock{Seal: []byte("genesis"), Height: blockchain.BlockHeight(0)}
+
+	blockRepository := mock.BlockRepository{}
+	blockRepository.GetLastBlockFunc = func() (blockchain.Block, error) {
+		return genesis, nil
+	}
+
+	peerRepository := mock.PeerRepository{}
+	peerRepository.FindAllFunc = func() ([]blockchain.Peer, error) {
+		return []blockchain.Peer{{PeerId: blockchain.PeerId{Id: "peer1"}}}, nil
+	}
+
+	grpcCommandService := mock.GrpcCommandService{}
+	grpcCommandService.SyncCheckRequestFunc = func(peerId blockchain.PeerId) error {
+		return nil
+	}
+
+	blockApi, _ := api.NewBlockApi("zf", blockRepository, grpcCommandService, peerRepository)
+
+	// when
+	blockApi.Synchronize()
+	err := blockApi.SyncedCheck(blockchain.PeerId{Id: "peer1"}, genesis)
+
+	// then
+	assert.Equal(t, nil, err)
+	assert.Equal(t, blockchain.DONE, blockApi.SyncIsProgressing())
+}
+
+func TestBlockApi_Synchronize_Fail(t *testing.T) {
+	eventstore.InitForMock(mock.NewEventLog())
+	defer eventstore.Close()
+
+	tests := map[string]struct {
+		input struct {
+			peers               []blockchain.Peer
+			syncCheckRequestErr error
+		}
+		err error
+	}{
+		"no peer": {
+			input: struct {
+				peers               []blockchain.Peer
+				syncCheckRequestErr error
+			}{
+				peers: []blockchain.Peer{},
+			},
+			err: api.ErrNoPeer,
+		},
+		"sync check request fail": {
+			input: struct {
+				peers               []blockchain.Peer
+				syncCheckRequestErr error
+			}{
+				peers:               []blockchain.Peer{{PeerId: blockchain.PeerId{Id: "peer1"}}},
+				syncCheckRequestErr: errors.New("publish error"),
+			},
+			err: api.ErrSyncCheckRequest,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+
+		peerRepository := mock.PeerRepository{}
+		peerRepository.FindAllFunc = func() ([]blockchain.Peer, error) {
+			return test.input.peers, nil
+		}
+
+		grpcCommandService := mock.GrpcCommandService{}
+		grpcCommandService.SyncCheckRequestFunc = func(peerId blockchain.PeerId) error {
+			return test.input.syncCheckRequestErr
+		}
+
+		blockApi, _ := api.NewBlockApi("zf", mock.BlockRepository{}, grpcCommandService, peerRepository)
+
+		err := blockApi.Synchronize()
+		assert.Equal(t, test.err, err)
+		assert.Equal(t, blockchain.DONE, blockApi.SyncIsProgressing())
+	}
+}
+
+func TestBlockApi_SyncBlock_InvalidBlock(t *testing.T) {
+	// given
+	eventstore.InitForMock(mock.NewEventLog())
+	defer eventstore.Close()
+
+	genesis := &blockchain.DefaultBlock{Seal: []byte("genesis"), Height: blockchain.BlockHeight(0)}
+
+	blockRepository := mock.BlockRepository{}
+	blockRepository.GetLastBlockFunc = func() (blockchain.Block, error) {
+		return genesis, nil
+	}
+
+	peerRepository := mock.PeerRepository{}
+	peerRepository.FindAllFunc = func() ([]blockchain.Peer, error) {
+		return []blockchain.Peer{{PeerId: blockchain.PeerId{Id: "peer1"}}}, nil
+	}
+
+	grpcCommandService := mock.GrpcCommandService{}
+	grpcCommandService.SyncCheckRequestFunc = func(peerId blockchain.PeerId) error {
+		return nil
+	}
+	grpcCommandService.RequestBlockRangeFunc = func(peerId blockchain.PeerId, from blockchain.BlockHeight, to blockchain.BlockHeight) error {
+		return nil
+	}
+
+	blockApi, _ := api.NewBlockApi("zf", blockRepository, grpcCommandService, peerRepository)
+
+	block1 := mock.NewLegacyBlock(t, genesis.Seal, 1)
+	block1.Seal = []byte("forged")
+
+	blockApi.Synchronize()
+	blockApi.SyncedCheck(blockchain.PeerId{Id: "peer1"}, mock.NewLegacyBlock(t, genesis.Seal, 1))
+
+	// when
+	err := blockApi.SyncBlock(blockchain.PeerId{Id: "peer1"}, block1)
+
+	// then
+	assert.Equal(t, blockchain.ErrInvalidSeal, err)
+	assert.Equal(t, blockchain.DONE, blockApi.SyncIsProgressing())
+}
+
+func TestBlockApi_SyncedCheck_NoReliablePeer(t *testing.T) {
+	// given
+	eventstore.InitForMock(mock.NewEventLog())
+	defer eventstore.Close()
+
+	genesis := &blockchain.DefaultBlock{Seal: []byte("genesis"), Height: blockchain.BlockHeight(0)}
+
+	blockRepository := mock.BlockRepository{}
+	blockRepository.GetLastBlockFunc = func() (blockchain.Block, error) {
+		return genesis, nil
+	}
+
+	peerRepository := mock.PeerRepository{}
+	peerRepository.FindAllFunc = func() ([]blockchain.Peer, error) {
+		return []blockchain.Peer{
+			{PeerId: blockchain.PeerId{Id: "peer1"}},
+			{PeerId: blockchain.PeerId{Id: "peer2"}},
+		}, nil
+	}
+
+	grpcCommandService := mock.GrpcCommandService{}
+	grpcCommandService.SyncCheckRequestFunc = func(peerId blockchain.PeerId) error {
+		return nil
+	}
+	grpcCommandService.RequestBlockRangeFunc = func(peerId blockchain.PeerId, from blockchain.BlockHeight, to blockchain.BlockHeight) error {
+		t.Fatal("block must not be requested without a reliable peer")
+		return nil
+	}
+
+	blockApi, _ := api.NewBlockApi("zf", blockRepository, grpcCommandService, peerRepository)
+	blockApi.Synchronize()
+
+	// when
+	err1 := blockApi.SyncedCheck(blockchain.PeerId{Id: "peer1"}, &blockchain.DefaultBlock{Seal: []byte("seal_a"), Height: blockchain.BlockHeight(3)})
+	err2 := blockApi.SyncedCheck(blockchain.PeerId{Id: "peer2"}, &blockchain.DefaultBlock{Seal: []byte("seal_b"), Height: blockchain.BlockHeight(3)})
+
+	// then
+	assert.Equal(t, nil, err1)
+	assert.Equal(t, api.ErrNoReliablePeer, err2)
+	assert.Equal(t, blockchain.DONE, blockApi.SyncIsProgressing())
+}
+
+func TestBlockApi_CheckSyncTimeout_RestartSyncCheck(t *testing.T) {
+	// given
+	eventstore.InitForMock(mock.NewEventLog())
+	defer eventstore.Close()
+
+	genesis := &blockchain.DefaultBlock{Seal: []byte("genesis"), Height: blockchain.BlockHeight(0)}
+
+	blockRepository := mock.BlockRepository{}
+	blockRepository.GetLastBlockFunc = func() (blockchain.Block, error) {
+		return genesis, nil
+	}
+
+	peerRepository := mock.PeerRepository{}
+	peerRepository.FindAllFunc = func() ([]blockchain.Peer, error) {
+		return []blockchain.Peer{
+			{PeerId: blockchain.PeerId{Id: "peer1"}},
+			{PeerId: blockchain.PeerId{Id: "peer2"}},
+		}, nil
+	}
+
+	syncCheckRequested := 0
+	grpcCommandService := mock.GrpcCommandService{}
+	grpcCommandService.SyncCheckRequestFunc = func(peerId blockchain.PeerId) error {
+		syncCheckRequested++
+		return nil
+	}
+
+	blockApi, _ := api.NewBlockApi("zf", blockRepository, grpcCommandService, peerRepository)
+	blockApi.SetDownloadConfig(blockchain.DownloadConfig{RangeSize: 32, WindowSize: 4, Timeout: 0, MaxRetry: 1})
+	blockApi.Synchronize()
+
+	// peer2 는 응답하지 않는다.
+	blockApi.SyncedCheck(blockchain.PeerId{Id: "peer1"}, &blockchain.DefaultBlock{Seal: []byte("seal_a"), Height: blockchain.BlockHeight(3)})
+
+	// when
+	err := blockApi.CheckSyncTimeout()
+
+	// then: 모든 peer 에게 last block 을 다시 요청한다.
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 4, syncCheckRequested)
+	assert.Equal(t, blockchain.PROGRESSING, blockApi.SyncIsProgressing())
+
+	// when: 재요청 횟수를 넘김
+	err = blockApi.CheckSyncTimeout()
+
+	// then
+	assert.Equal(t, api.ErrNoReliablePeer, err)
+	assert.Equal(t, 4, syncCheckRequested)
+	assert.Equal(t, blockchain.DONE, blockApi.SyncIsProgressing())
+}
+
+func TestBlockApi_SyncBlock_TipMismatch(t *testing.T) {
+	// given
+	eventstore.InitForMock(mock.NewEventLog())
+	defer eventstore.Close()
+
+	genesis := &blockchain.DefaultBlock{Seal: []byte("genesis"), Height: blockchain.BlockHeight(0)}
+
+	blockRepository := mock.BlockRepository{}
+	blockRepository.GetLastBlockFunc = func() (blockchain.Block, error) {
+		return genesis, nil
+	}
+	blockRepository.AddBlockFunc = func(block blockchain.Block) error {
+		t.Fatal("block which does not match the agreed tip must not be committed")
+		return nil
+	}
+
+	peerRepository := mock.PeerRepository{}
+	peerRepository.FindAllFunc = func() ([]blockchain.Peer, error) {
+		return []blockchain.Peer{{PeerId: blockchain.PeerId{Id: "peer1"}}}, nil
+	}
+
+	grpcCommandService := mock.GrpcCommandService{}
+	grpcCommandService.SyncCheckRequestFunc = func(peerId blockchain.PeerId) error {
+		return nil
+	}
+	grpcCommandService.RequestBlockRangeFunc = func(peerId blockchain.PeerId, from blockchain.BlockHeight, to blockchain.BlockHeight) error {
+		return nil
+	}
+
+	blockApi, _ := api.NewBlockApi("zf", blockRepository, grpcCommandService, peerRepository)
+	blockApi.Synchronize()
+	blockApi.SyncedCheck(blockchain.PeerId{Id: "peer1"}, &blockchain.DefaultBlock{Seal: []byte("advertised"), Height: blockchain.BlockHeight(1)})
+
+	// when: 검증은 통과하지만 알려준 tip 과 다른 block
+	err := blockApi.SyncBlock(blockchain.PeerId{Id: "peer1"}, mock.NewLegacyBlock(t, genesis.Seal, 1))
+
+	// then
+	assert.Equal(t, api.ErrSyncTipMismatch, err)
+	assert.Equal(t, blockchain.DONE, blockApi.SyncIsProgressing())
+}
@@ -0,0 +1,178 @@
+package api
+
+import (
+	"log"
+	"sync"
+
+	"github.com/it-chain/engine/blockchain"
+)
+
+// BlockProposeApi 는 txpool 이 제안한 transaction 들로 마지막 block 의 다음 block 을 만들어 consensus 에 검증을 요청한다.
+// block 한도를 넘은 transaction 은 txpool 에 남아 있다가 다음 ProposeBlock 에 다시 들어온다.
+type BlockProposeApi struct {
+	publisherId       string
+	blockRepository   blockchain.BlockRepository
+	commandService    blockchain.CommandService
+	signer            blockchain.Signer
+	limit             blockchain.BlockLimit
+	receiptRepository blockchain.ReceiptRepository
+	snapshotState     *snapshotState
+	validator         *blockchain.DefaultValidator
+	// 제안했지만 아직 commit 되지 않은 transaction 과 그 transaction 을 담은 block 의 height
+	inFlight map[string]blockchain.BlockHeight
+	mutex    *sync.Mutex
+}
+
+func NewBlockProposeApi(publisherId string, blockRepository blockchain.BlockRepository, commandService blockchain.CommandService, signer blockchain.Signer) *BlockProposeApi {
+	return &BlockProposeApi{
+		publisherId:     publisherId,
+		blockRepository: blockRepository,
+		commandService:  commandService,
+		signer:          signer,
+		validator:       &blockchain.DefaultValidator{},
+		inFlight:        make(map[string]blockchain.BlockHeight),
+		mutex:           &sync.Mutex{},
+	}
+}
+
+// SetBlockLimit 은 block 하나에 담을 transaction 개수와 크기의 한도를 설정한다.
+func (api *BlockProposeApi) SetBlockLimit(limit blockchain.BlockLimit) {
+	api.limit = limit
+}
+
+// SetReceiptRepository 는 새 block 의 header 에 마지막 block 의 receipts root 를 담도록 설정한다.
+func (api *BlockProposeApi) SetReceiptRepository(receiptRepository blockchain.ReceiptRepository) {
+	api.receiptRepository = receiptRepository
+}
+
+// snapshotState 는 snapshot height 의 block 에 담을 state root 를 계산하는 데 필요한 설정이다.
+type snapshotState struct {
+	config               blockchain.SnapshotConfig
+	icodeStateRepository blockchain.ICodeStateRepository
+	parliament           []string
+}
+
+// SetSnapshotState 는 snapshot height 의 block header 에 현재 icode state 와 parliament 의 state root 를 담도록 설정한다.
+// 설정하지 않으면 state root 를 담지 않으므로 그 block 으로는 snapshot 을 검증할 수 없다.
+func (api *BlockProposeApi) SetSnapshotState(config blockchain.SnapshotConfig, icodeStateRepository blockchain.ICodeStateRepository, parliament []string) {
+	api.snapshotState = &snapshotState{
+		config:               config,
+		icodeStateRepository: icodeStateRepository,
+		parliament:           append([]string{}, parliament...),
+	}
+}
+
+// ProposeBlock 은 마지막 block 위에 txList 로 block 을 만들고 node 의 key 로 서명해서 consensus 에 보낸다.
+// txpool 은 transaction 을 commit 된 다음에 지우므로, 한도를 넘은 transaction 이나 보내지 못한 block 의 transaction 은
+// 다음 ProposeBlock 의 txList 에 다시 들어온다. 혼자서도 한도를 넘는 transaction 은 담지 않는다.
+// 제안한 block 의 height 가 commit 되기 전에는 그 block 의 transaction 을 다시 제안하지 않는다.
+func (api *BlockProposeApi) ProposeBlock(txList []blockchain.Transaction) error {
+	api.mutex.Lock()
+	defer api.mutex.Unlock()
+
+	lastBlock, err := api.blockRepository.GetLastBlock()
+	if err != nil {
+		return ErrGetLastBlock
+	}
+
+	api.releaseInFlight(lastBlock.GetHeight())
+
+	candidates := make([]blockchain.Transaction, 0, len(txList))
+	for _, tx := range txList {
+		if _, ok := api.inFlight[tx.GetID()]; ok {
+			continue
+		}
+		candidates = append(candidates, tx)
+	}
+
+	fit, _, oversized, err := api.limit.Split(candidates)
+	if err != nil {
+		return err
+	}
+
+	for _, tx := range oversized {
+		log.Printf("skip transaction [%s]: %s", tx.GetID(), blockchain.ErrTransactionTooLarge)
+	}
+
+	if len(fit) == 0 {
+		return ErrEmptyTxList
+	}
+
+	block, err := api.propose(lastBlock, fit)
+	if err != nil {
+		return err
+	}
+
+	for _, tx := range fit {
+		api.inFlight[tx.GetID()] = block.GetHeight()
+	}
+
+	return nil
+}
+
+// releaseInFlight 는 height 가 commit 된 block 의 transaction 을 다시 제안할 수 있게 한다.
+// 제안한 block 이 commit 되었다면 그 transaction 은 txpool 에서 지워지고, 다른 block 이 commit 되었다면 다시 제안해야 한다.
+func (api *BlockProposeApi) releaseInFlight(lastHeight blockchain.BlockHeight) {
+	for txID, height := range api.inFlight {
+		if height <= lastHeight {
+			delete(api.inFlight, txID)
+		}
+	}
+}
+
+func (api *BlockProposeApi) propose(lastBlock blockchain.Block, txList []blockchain.Transaction) (blockchain.Block, error) {
+	receiptsRoot, err := api.lastReceiptsRoot(lastBlock)
+	if err != nil {
+		return nil, err
+	}
+
+	height := lastBlock.GetHeight() + 1
+	stateRoot, err := api.stateRoot(height)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := blockchain.CreateProposedBlockWithRoots(lastBlock.GetSeal(), height, stateRoot, receiptsRoot, txList, []byte(api.publisherId), api.signer)
+	if err != nil {
+		return nil, err
+	}
+
+	// local 시각이 마지막 block 보다 늦다면 다른 node 가 거부할 block 을 보내지 않는다.
+	if err := api.validator.ValidateTimestamp(block, lastBlock); err != nil {
+		return nil, err
+	}
+
+	if err := api.commandService.SendBlockValidateCommand(block); err != nil {
+		return nil, err
+	}
+
+	return block, nil
+}
+
+// lastReceiptsRoot 는 lastBlock 의 receipts root 를 반환한다. 아직 실행 결과가 없으면 빈 root 를 담는다.
+func (api *BlockProposeApi) lastReceiptsRoot(lastBlock blockchain.Block) ([]byte, error) {
+	if api.receiptRepository == nil {
+		return nil, nil
+	}
+
+	receiptsRoot, err := api.receiptRepository.GetReceiptsRoot(lastBlock.GetHeight())
+	if err == blockchain.ErrReceiptNotFound {
+		return nil, nil
+	}
+
+	return receiptsRoot, err
+}
+
+// stateRoot 는 height 가 snapshot height 이면 현재 icode state 와 parliament 의 state root 를 반환한다.
+func (api *BlockProposeApi) stateRoot(height blockchain.BlockHeight) ([]byte, error) {
+	if api.snapshotState == nil || !api.snapshotState.config.IsSnapshotHeight(height) {
+		return nil, nil
+	}
+
+	icodes, err := api.snapshotState.icodeStateRepository.FindAllICodeStates()
+	if err != nil {
+		return nil, err
+	}
+
+	return blockchain.SnapshotStateRoot(icodes, api.snapshotState.parliament), nil
+}
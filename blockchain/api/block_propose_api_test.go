@@ -0,0 +1,186 @@
+package api_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/it-chain/engine/blockchain"
+	"github.com/it-chain/engine/blockchain/api"
+	"github.com/it-chain/engine/blockchain/test/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func newProposeTxList(ids ...string) []blockchain.Transaction {
+	txList := make([]blockchain.Transaction, 0, len(ids))
+	for _, id := range ids {
+		txList = append(txList, &blockchain.DefaultTransaction{ID: id})
+	}
+
+	return txList
+}
+
+func proposedTxIDs(block blockchain.Block) []string {
+	ids := make([]string, 0)
+	for _, tx := range block.GetTxList() {
+		ids = append(ids, tx.GetID())
+	}
+
+	return ids
+}
+
+func TestBlockProposeApi_ProposeBlock_InFlight(t *testing.T) {
+	// given
+	lastBlock := &blockchain.DefaultBlock{Seal: []byte("seal"), Height: 3}
+	blockRepository := mock.BlockRepository{}
+	blockRepository.GetLastBlockFunc = func() (blockchain.Block, error) {
+		return lastBlock, nil
+	}
+
+	proposed := make([]blockchain.Block, 0)
+	sendErr := error(nil)
+	commandService := mock.CommandService{}
+	commandService.SendBlockValidateCommandFunc = func(block blockchain.Block) error {
+		if sendErr != nil {
+			return sendErr
+		}
+
+		proposed = append(proposed, block)
+		return nil
+	}
+
+	signer := mock.Signer{}
+	signer.SignFunc = func(data []byte) ([]byte, error) {
+		return data, nil
+	}
+
+	proposeApi := api.NewBlockProposeApi("peer", blockRepository, commandService, signer)
+	proposeApi.SetBlockLimit(blockchain.BlockLimit{MaxTransactions: 2})
+
+	// when
+	err := proposeApi.ProposeBlock(newProposeTxList("tx1", "tx2", "tx3"))
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"tx1", "tx2"}, proposedTxIDs(proposed[0]))
+
+	// when: txpool 에 남은 transaction 이 다시 들어오면 commit 되지 않은 block 의 transaction 은 건너뛴다.
+	err = proposeApi.ProposeBlock(newProposeTxList("tx1", "tx2", "tx3", "tx4"))
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"tx3", "tx4"}, proposedTxIDs(proposed[1]))
+
+	// when: 보내지 못한 block 의 transaction 은 제안 중인 것으로 남지 않는다.
+	sendErr = errors.New("send failed")
+	err = proposeApi.ProposeBlock(newProposeTxList("tx1", "tx2", "tx3", "tx4", "tx5"))
+
+	// then
+	assert.Equal(t, sendErr, err)
+
+	// when
+	sendErr = nil
+	err = proposeApi.ProposeBlock(newProposeTxList("tx1", "tx2", "tx3", "tx4", "tx5"))
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"tx5"}, proposedTxIDs(proposed[2]))
+
+	// when: 모든 transaction 이 제안 중이다.
+	err = proposeApi.ProposeBlock(newProposeTxList("tx1", "tx2", "tx3", "tx4", "tx5"))
+
+	// then
+	assert.Equal(t, api.ErrEmptyTxList, err)
+
+	// when: 제안한 height 에 다른 block 이 commit 되면 그 block 에 없던 transaction 을 다시 제안한다.
+	lastBlock = &blockchain.DefaultBlock{Seal: []byte("other"), Height: 4}
+	err = proposeApi.ProposeBlock(newProposeTxList("tx1", "tx2", "tx3", "tx4", "tx5"))
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"tx1", "tx2"}, proposedTxIDs(proposed[3]))
+}
+
+func TestBlockProposeApi_ProposeBlock_TimestampBeforeLastBlock(t *testing.T) {
+	// given: local 시각이 마지막 block 의 timestamp 보다 늦다.
+	lastBlock := &blockchain.DefaultBlock{Seal: []byte("seal"), Height: 3, Timestamp: time.Now().Add(time.Hour)}
+	blockRepository := mock.BlockRepository{}
+	blockRepository.GetLastBlockFunc = func() (blockchain.Block, error) {
+		return lastBlock, nil
+	}
+
+	proposed := make([]blockchain.Block, 0)
+	commandService := mock.CommandService{}
+	commandService.SendBlockValidateCommandFunc = func(block blockchain.Block) error {
+		proposed = append(proposed, block)
+		return nil
+	}
+
+	signer := mock.Signer{}
+	signer.SignFunc = func(data []byte) ([]byte, error) {
+		return data, nil
+	}
+
+	proposeApi := api.NewBlockProposeApi("peer", blockRepository, commandService, signer)
+
+	// when
+	err := proposeApi.ProposeBlock(newProposeTxList("tx1"))
+
+	// then
+	timestampErr, ok := err.(*blockchain.TimestampError)
+	assert.True(t, ok)
+	assert.Equal(t, blockchain.ErrTimestampNotIncreasing, timestampErr.Err)
+	assert.Equal(t, 0, len(proposed))
+
+	// when: 시각이 맞춰지면 txpool 에 남아 있던 transaction 을 다시 제안한다.
+	lastBlock.Timestamp = time.Now().Add(-time.Second)
+	err = proposeApi.ProposeBlock(newProposeTxList("tx1"))
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"tx1"}, proposedTxIDs(proposed[0]))
+}
+
+func TestBlockProposeApi_ProposeBlock_SnapshotStateRoot(t *testing.T) {
+	// given
+	lastBlock := &blockchain.DefaultBlock{Seal: []byte("seal"), Height: 9}
+	blockRepository := mock.BlockRepository{}
+	blockRepository.GetLastBlockFunc = func() (blockchain.Block, error) {
+		return lastBlock, nil
+	}
+
+	proposed := make([]*blockchain.DefaultBlock, 0)
+	commandService := mock.CommandService{}
+	commandService.SendBlockValidateCommandFunc = func(block blockchain.Block) error {
+		proposed = append(proposed, block.(*blockchain.DefaultBlock))
+		return nil
+	}
+
+	signer := mock.Signer{}
+	signer.SignFunc = func(data []byte) ([]byte, error) {
+		return data, nil
+	}
+
+	states := []blockchain.ICodeState{{ID: "icode1", CommitHash: "hash"}}
+	parliament := []string{"peer1", "peer2"}
+
+	proposeApi := api.NewBlockProposeApi("peer", blockRepository, commandService, signer)
+	proposeApi.SetSnapshotState(blockchain.SnapshotConfig{Interval: 10}, newICodeStateRepository(&states), parliament)
+
+	// when: snapshot height 의 block
+	err := proposeApi.ProposeBlock(newProposeTxList("tx1"))
+
+	// then: 현재 icode state 와 parliament 의 state root 를 header 에 담는다.
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(10), proposed[0].GetHeight())
+	assert.Equal(t, blockchain.SnapshotStateRoot(states, parliament), proposed[0].GetStateRoot())
+
+	// when: snapshot height 가 아닌 block
+	lastBlock = &blockchain.DefaultBlock{Seal: proposed[0].GetSeal(), Height: 10}
+	err = proposeApi.ProposeBlock(newProposeTxList("tx2"))
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(11), proposed[1].GetHeight())
+	assert.Nil(t, proposed[1].GetStateRoot())
+}
@@ -0,0 +1,60 @@
+package api
+
+import (
+	"github.com/it-chain/engine/blockchain"
+)
+
+// BlockQueryApi 는 block pool 에 staged 된 block 과 repository 에 commit 된 block 을 조회한다.
+type BlockQueryApi struct {
+	blockPool       blockchain.BlockPool
+	blockRepository blockchain.BlockRepository
+}
+
+func NewBlockQueryApi(blockPool blockchain.BlockPool, blockRepository blockchain.BlockRepository) BlockQueryApi {
+	return BlockQueryApi{
+		blockPool:       blockPool,
+		blockRepository: blockRepository,
+	}
+}
+
+// fork 로 height 에 여러 block 이 있으면 seal 이 가장 작은 block 을 반환한다.
+func (q BlockQueryApi) GetStagedBlockByHeight(height blockchain.BlockHeight) (blockchain.Block, error) {
+	blocks := q.blockPool.GetByHeight(height)
+	if len(blocks) == 0 {
+		return nil, blockchain.ErrBlockNotFound
+	}
+	return blocks[0], nil
+}
+
+// blockId 는 block seal 이다.
+func (q BlockQueryApi) GetStagedBlockById(blockId string) (blockchain.Block, error) {
+	block := q.blockPool.GetBySeal([]byte(blockId))
+	if block == nil {
+		return nil, blockchain.ErrBlockNotFound
+	}
+	return block, nil
+}
+
+func (q BlockQueryApi) GetLastCommitedBlock() (blockchain.Block, error) {
+	return q.blockRepository.GetLastBlock()
+}
+
+func (q BlockQueryApi) GetCommitedBlockByHeight(height blockchain.BlockHeight) (blockchain.Block, error) {
+	return q.blockRepository.GetBlockByHeight(height)
+}
+
+func (q BlockQueryApi) GetBlockByHeight(blockHeight uint64) (blockchain.Block, error) {
+	return q.blockRepository.GetBlockByHeight(blockHeight)
+}
+
+func (q BlockQueryApi) GetBlockBySeal(seal []byte) (blockchain.Block, error) {
+	return q.blockRepository.GetBlockBySeal(seal)
+}
+
+func (q BlockQueryApi) GetBlockByTxID(txid string) (blockchain.Block, error) {
+	return q.blockRepository.GetBlockByTxID(txid)
+}
+
+func (q BlockQueryApi) GetLastBlock() (blockchain.Block, error) {
+	return q.blockRepository.GetLastBlock()
+}
@@ -0,0 +1,74 @@
+package api_test
+
+import (
+	"testing"
+
+	"github.com/it-chain/engine/blockchain"
+	"github.com/it-chain/engine/blockchain/api"
+	"github.com/it-chain/engine/blockchain/test/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlockQueryApi_GetStagedBlock(t *testing.T) {
+	// given
+	pool := blockchain.NewBlockPool()
+	pool.Add(&blockchain.DefaultBlock{
+		Seal:   []byte("seal"),
+		Height: blockchain.BlockHeight(3),
+	})
+
+	queryApi := api.NewBlockQueryApi(pool, mock.BlockRepository{})
+
+	// when
+	block, err := queryApi.GetStagedBlockByHeight(blockchain.BlockHeight(3))
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("seal"), block.GetSeal())
+
+	// when
+	block, err = queryApi.GetStagedBlockById("seal")
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(3), block.GetHeight())
+
+	// when
+	_, err = queryApi.GetStagedBlockByHeight(blockchain.BlockHeight(4))
+
+	// then
+	assert.Equal(t, blockchain.ErrBlockNotFound, err)
+
+	// when
+	_, err = queryApi.GetStagedBlockById("unknown")
+
+	// then
+	assert.Equal(t, blockchain.ErrBlockNotFound, err)
+}
+
+func TestBlockQueryApi_GetCommitedBlock(t *testing.T) {
+	// given
+	blockRepository := mock.BlockRepository{}
+	blockRepository.GetLastBlockFunc = func() (blockchain.Block, error) {
+		return &blockchain.DefaultBlock{Height: blockchain.BlockHeight(7)}, nil
+	}
+	blockRepository.GetBlockByHeightFunc = func(blockHeight uint64) (blockchain.Block, error) {
+		return &blockchain.DefaultBlock{Height: blockHeight}, nil
+	}
+
+	queryApi := api.NewBlockQueryApi(blockchain.NewBlockPool(), blockRepository)
+
+	// when
+	block, err := queryApi.GetLastCommitedBlock()
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(7), block.GetHeight())
+
+	// when
+	block, err = queryApi.GetCommitedBlockByHeight(blockchain.BlockHeight(5))
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(5), block.GetHeight())
+}
@@ -5,3 +5,15 @@ import "errors"
 var ErrNilBlock = errors.New("block is nil")
 var ErrSyncProcessing = errors.New("Sync is in progress")
 var ErrGetLastBlock = errors.New("failed get last block")
+var ErrNoPeer = errors.New("no peer to synchronize with")
+var ErrSyncCheckRequest = errors.New("failed to request sync check to any peer")
+var ErrNotSyncTarget = errors.New("block is not from the sync target peer")
+var ErrNoReliablePeer = errors.New("no chain tip is agreed by a quorum of peers")
+var ErrSyncTipMismatch = errors.New("synchronized block does not match the agreed chain tip")
+var ErrEmptyTxList = errors.New("no transaction to propose")
+var ErrInvalidBlockID = errors.New("block id is not a hex encoded seal")
+var ErrHeaderRangeRequest = errors.New("failed to request header range to any peer")
+var ErrTxProofRequest = errors.New("failed to request tx proof to any peer")
+var ErrTxProofTimeout = errors.New("no peer answered the tx proof request in time")
+var ErrSnapshotRequest = errors.New("failed to request snapshot to any peer")
+var ErrNoHeaderChain = errors.New("no header chain to verify snapshot")
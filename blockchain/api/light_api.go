@@ -0,0 +1,248 @@
+package api
+
+import (
+	"bytes"
+	"sync"
+	"time"
+
+	"github.com/it-chain/engine/blockchain"
+)
+
+// full node 가 tx proof 요청에 응답하기를 기다리는 기본 시간
+const DefaultTxProofTimeout = 5 * time.Second
+
+// LightApi 는 block 대신 signed header 만 동기화하는 light node 의 api 이다.
+// header chain 은 seal 과 creator 서명으로 검증하고, transaction 포함 여부는 full node 가 보낸 Merkle proof 를 header 의 tx root 로 검증한다.
+type LightApi struct {
+	headerRepository   blockchain.HeaderRepository
+	grpcCommandService blockchain.LightGrpcCommandService
+	peerRepository     blockchain.PeerRepository
+	validator          *blockchain.DefaultValidator
+	proofTimeout       time.Duration
+	mutex              *sync.Mutex
+
+	// txID 별로 proof 응답을 기다리는 CheckTransaction 들
+	proofMutex   *sync.Mutex
+	proofWaiters map[string][]chan blockchain.TxProofResponse
+}
+
+func NewLightApi(headerRepository blockchain.HeaderRepository, grpcCommandService blockchain.LightGrpcCommandService, peerRepository blockchain.PeerRepository) *LightApi {
+	return &LightApi{
+		headerRepository:   headerRepository,
+		grpcCommandService: grpcCommandService,
+		peerRepository:     peerRepository,
+		validator:          &blockchain.DefaultValidator{},
+		proofTimeout:       DefaultTxProofTimeout,
+		mutex:              &sync.Mutex{},
+		proofMutex:         &sync.Mutex{},
+		proofWaiters:       make(map[string][]chan blockchain.TxProofResponse),
+	}
+}
+
+// SetSignatureVerifier 는 동기화로 받은 header 의 creator 서명을 검증하도록 설정한다.
+func (api *LightApi) SetSignatureVerifier(verifier blockchain.SignatureVerifier) {
+	api.validator.SetSignatureVerifier(verifier)
+}
+
+// SetMaxTimestampDrift 는 local 시각보다 drift 이상 앞선 timestamp 의 header 를 거부하도록 설정한다.
+func (api *LightApi) SetMaxTimestampDrift(drift time.Duration) {
+	api.validator.SetMaxTimestampDrift(drift)
+}
+
+// SetTxProofTimeout 은 CheckTransaction 이 proof 응답을 기다리는 시간을 설정한다.
+func (api *LightApi) SetTxProofTimeout(timeout time.Duration) {
+	api.proofTimeout = timeout
+}
+
+// InitGenesis 는 header chain 의 시작인 genesis header 를 저장한다.
+// 이미 header 가 있으면 genesis header 가 같은지만 확인한다.
+func (api *LightApi) InitGenesis(genesis blockchain.Block) error {
+	api.mutex.Lock()
+	defer api.mutex.Unlock()
+
+	header, err := api.headerRepository.GetHeaderByHeight(genesis.GetHeight())
+	if err == blockchain.ErrHeaderNotFound {
+		return api.headerRepository.AddHeader(blockchain.NewSignedHeader(genesis))
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if !bytes.Equal(header.Seal, genesis.GetSeal()) {
+		return blockchain.ErrGenesisSealMismatch
+	}
+
+	return nil
+}
+
+// SyncHeaders 는 마지막 header 다음부터 MaxHeaderRangeSize 개의 header 를 peer 에게 요청한다.
+// 응답은 SyncHeaderRange 에서 처리한다.
+func (api *LightApi) SyncHeaders() error {
+	peers, err := api.peerRepository.FindAll()
+	if err != nil {
+		return err
+	}
+
+	if len(peers) == 0 {
+		return ErrNoPeer
+	}
+
+	lastHeader, err := api.headerRepository.GetLastHeader()
+	if err != nil {
+		return err
+	}
+
+	for _, peer := range peers {
+		if api.requestNextHeaders(peer.PeerId, lastHeader) == nil {
+			return nil
+		}
+	}
+
+	return ErrHeaderRangeRequest
+}
+
+// SyncHeaderRange 는 peer 가 보낸 header 들을 순서대로 검증해서 저장한다.
+// 이미 가진 header 는 건너뛰고, 검증에 실패하면 그 뒤의 header 는 저장하지 않는다.
+// 응답이 가득 차 있으면 같은 peer 에게 다음 범위를 요청한다.
+func (api *LightApi) SyncHeaderRange(peerId blockchain.PeerId, headers []blockchain.SignedHeader) error {
+	api.mutex.Lock()
+	defer api.mutex.Unlock()
+
+	lastHeader, err := api.headerRepository.GetLastHeader()
+	if err != nil {
+		return err
+	}
+
+	for _, header := range headers {
+		if header.Height <= lastHeader.Height {
+			continue
+		}
+
+		if err := api.validator.ValidateHeader(header, lastHeader); err != nil {
+			return err
+		}
+
+		if err := api.headerRepository.AddHeader(header); err != nil {
+			return err
+		}
+
+		lastHeader = header
+	}
+
+	if len(headers) < blockchain.MaxHeaderRangeSize {
+		return nil
+	}
+
+	return api.requestNextHeaders(peerId, lastHeader)
+}
+
+func (api *LightApi) requestNextHeaders(peerId blockchain.PeerId, lastHeader blockchain.SignedHeader) error {
+	return api.grpcCommandService.RequestHeaderRange(peerId, lastHeader.Height+1, lastHeader.Height+blockchain.MaxHeaderRangeSize)
+}
+
+// CheckTransaction 은 모든 peer 에게 txID 의 Merkle proof 를 요청하고, 처음으로 검증에 성공한 proof 를 반환한다.
+// 모든 peer 가 응답했는데 검증된 proof 가 없으면 검증 실패 원인을, 어떤 peer 도 transaction 을 갖고 있지 않으면 ErrTxProofNotFound 를 반환한다.
+func (api *LightApi) CheckTransaction(txID string) (blockchain.TxInclusion, error) {
+	peers, err := api.peerRepository.FindAll()
+	if err != nil {
+		return blockchain.TxInclusion{}, err
+	}
+
+	if len(peers) == 0 {
+		return blockchain.TxInclusion{}, ErrNoPeer
+	}
+
+	responses := make(chan blockchain.TxProofResponse, len(peers))
+	api.addProofWaiter(txID, responses)
+	defer api.removeProofWaiter(txID, responses)
+
+	requested := 0
+	for _, peer := range peers {
+		if api.grpcCommandService.RequestTxProof(peer.PeerId, txID) == nil {
+			requested++
+		}
+	}
+
+	if requested == 0 {
+		return blockchain.TxInclusion{}, ErrTxProofRequest
+	}
+
+	timeout := time.NewTimer(api.proofTimeout)
+	defer timeout.Stop()
+
+	lastErr := blockchain.ErrTxProofNotFound
+	for i := 0; i < requested; i++ {
+		select {
+		case response := <-responses:
+			inclusion, err := api.verifyTxProof(response)
+			if err == nil {
+				return inclusion, nil
+			}
+			if err != blockchain.ErrTxProofNotFound {
+				lastErr = err
+			}
+
+		case <-timeout.C:
+			return blockchain.TxInclusion{}, ErrTxProofTimeout
+		}
+	}
+
+	return blockchain.TxInclusion{}, lastErr
+}
+
+// ReceiveTxProof 는 peer 가 보낸 proof 를 그 txID 를 기다리는 CheckTransaction 에 전달한다.
+// 기다리는 요청이 없으면 버린다.
+func (api *LightApi) ReceiveTxProof(response blockchain.TxProofResponse) error {
+	api.proofMutex.Lock()
+	defer api.proofMutex.Unlock()
+
+	for _, waiter := range api.proofWaiters[response.TxID] {
+		select {
+		case waiter <- response:
+		default:
+		}
+	}
+
+	return nil
+}
+
+func (api *LightApi) verifyTxProof(response blockchain.TxProofResponse) (blockchain.TxInclusion, error) {
+	if !response.Found {
+		return blockchain.TxInclusion{}, blockchain.ErrTxProofNotFound
+	}
+
+	// 아직 동기화하지 않은 header 의 proof 는 검증할 수 없다.
+	header, err := api.headerRepository.GetHeaderByHeight(response.Height)
+	if err != nil {
+		return blockchain.TxInclusion{}, err
+	}
+
+	return api.validator.VerifyTxInclusion(response, header)
+}
+
+func (api *LightApi) addProofWaiter(txID string, waiter chan blockchain.TxProofResponse) {
+	api.proofMutex.Lock()
+	defer api.proofMutex.Unlock()
+
+	api.proofWaiters[txID] = append(api.proofWaiters[txID], waiter)
+}
+
+func (api *LightApi) removeProofWaiter(txID string, waiter chan blockchain.TxProofResponse) {
+	api.proofMutex.Lock()
+	defer api.proofMutex.Unlock()
+
+	waiters := make([]chan blockchain.TxProofResponse, 0, len(api.proofWaiters[txID]))
+	for _, w := range api.proofWaiters[txID] {
+		if w != waiter {
+			waiters = append(waiters, w)
+		}
+	}
+
+	if len(waiters) == 0 {
+		delete(api.proofWaiters, txID)
+		return
+	}
+
+	api.proofWaiters[txID] = waiters
+}
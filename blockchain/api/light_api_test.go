@@ -0,0 +1,199 @@
+package api_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/it-chain/engine/blockchain"
+	"github.com/it-chain/engine/blockchain/api"
+	"github.com/it-chain/engine/blockchain/test/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+type headerRepository struct {
+	headers []blockchain.SignedHeader
+}
+
+func (r *headerRepository) AddHeader(header blockchain.SignedHeader) error {
+	r.headers = append(r.headers, header)
+	return nil
+}
+
+func (r *headerRepository) GetHeaderByHeight(height blockchain.BlockHeight) (blockchain.SignedHeader, error) {
+	if height >= uint64(len(r.headers)) {
+		return blockchain.SignedHeader{}, blockchain.ErrHeaderNotFound
+	}
+
+	return r.headers[height], nil
+}
+
+func (r *headerRepository) GetLastHeader() (blockchain.SignedHeader, error) {
+	if len(r.headers) == 0 {
+		return blockchain.SignedHeader{}, blockchain.ErrHeaderNotFound
+	}
+
+	return r.headers[len(r.headers)-1], nil
+}
+
+func (r *headerRepository) Close() {}
+
+func newLightPeerRepository(ids ...string) mock.PeerRepository {
+	peerRepository := mock.PeerRepository{}
+	peerRepository.FindAllFunc = func() ([]blockchain.Peer, error) {
+		peers := make([]blockchain.Peer, 0)
+		for _, id := range ids {
+			peers = append(peers, blockchain.Peer{PeerId: blockchain.PeerId{Id: id}})
+		}
+		return peers, nil
+	}
+
+	return peerRepository
+}
+
+func TestLightApi_InitGenesis(t *testing.T) {
+	// given
+	genesis := mock.NewLegacyBlock(t, []byte("genesis"), 0)
+	headers := &headerRepository{}
+	lightApi := api.NewLightApi(headers, mock.LightGrpcCommandService{}, mock.PeerRepository{})
+
+	// when
+	err := lightApi.InitGenesis(genesis)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(headers.headers))
+	assert.Equal(t, genesis.Seal, headers.headers[0].Seal)
+
+	// when: 이미 저장된 genesis 와 다른 genesis
+	err = lightApi.InitGenesis(mock.NewLegacyBlock(t, []byte("other"), 0))
+
+	// then
+	assert.Equal(t, blockchain.ErrGenesisSealMismatch, err)
+}
+
+func TestLightApi_SyncHeaderRange(t *testing.T) {
+	// given
+	genesis := mock.NewLegacyBlock(t, []byte("genesis"), 0)
+	block1 := mock.NewLegacyBlock(t, genesis.Seal, 1)
+	block2 := mock.NewLegacyBlock(t, block1.Seal, 2)
+	forged := mock.NewLegacyBlock(t, block2.Seal, 3)
+	forged.Timestamp = forged.Timestamp.Add(time.Second)
+
+	headers := &headerRepository{}
+	requested := make([]blockchain.BlockHeight, 0)
+	grpcCommandService := mock.LightGrpcCommandService{}
+	grpcCommandService.RequestHeaderRangeFunc = func(peerId blockchain.PeerId, from blockchain.BlockHeight, to blockchain.BlockHeight) error {
+		requested = append(requested, from)
+		return nil
+	}
+
+	lightApi := api.NewLightApi(headers, grpcCommandService, newLightPeerRepository("full"))
+	assert.NoError(t, lightApi.InitGenesis(genesis))
+
+	// when: 이미 가진 genesis 와 다음 header 들
+	err := lightApi.SyncHeaderRange(blockchain.PeerId{Id: "full"}, []blockchain.SignedHeader{
+		blockchain.NewSignedHeader(genesis),
+		blockchain.NewSignedHeader(block1),
+		blockchain.NewSignedHeader(block2),
+	})
+
+	// then: 응답이 가득 차지 않았으므로 다음 범위는 요청하지 않는다.
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(headers.headers))
+	assert.Equal(t, 0, len(requested))
+
+	// when: seal 이 맞지 않는 header
+	err = lightApi.SyncHeaderRange(blockchain.PeerId{Id: "full"}, []blockchain.SignedHeader{blockchain.NewSignedHeader(forged)})
+
+	// then
+	assert.Equal(t, blockchain.ErrInvalidSeal, err)
+	assert.Equal(t, 3, len(headers.headers))
+
+	// when
+	err = lightApi.SyncHeaders()
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, []blockchain.BlockHeight{3}, requested)
+}
+
+func TestLightApi_CheckTransaction(t *testing.T) {
+	// given
+	genesis := mock.NewLegacyBlock(t, []byte("genesis"), 0)
+	block1 := mock.NewLegacyBlock(t, genesis.Seal, 1)
+
+	headers := &headerRepository{}
+	headers.AddHeader(blockchain.NewSignedHeader(genesis))
+	headers.AddHeader(blockchain.NewSignedHeader(block1))
+
+	validator := blockchain.DefaultValidator{}
+	proof, err := validator.BuildTxProof(block1, "tx")
+	assert.NoError(t, err)
+
+	tests := map[string]struct {
+		input []blockchain.TxProofResponse
+		err   error
+	}{
+		"success": {
+			input: []blockchain.TxProofResponse{
+				{TxID: "tx"},
+				{TxID: "tx", Found: true, Height: 1, Seal: block1.Seal, Proof: proof},
+			},
+			err: nil,
+		},
+		"not found": {
+			input: []blockchain.TxProofResponse{{TxID: "tx"}, {TxID: "tx"}},
+			err:   blockchain.ErrTxProofNotFound,
+		},
+		"forged proof": {
+			input: []blockchain.TxProofResponse{
+				{TxID: "tx"},
+				{TxID: "tx", Found: true, Height: 1, Seal: block1.Seal, Proof: blockchain.MerkleProof{TxID: "tx", TxHash: []byte("forged"), Path: proof.Path}},
+			},
+			err: blockchain.ErrTxNotIncluded,
+		},
+		"header not synced": {
+			input: []blockchain.TxProofResponse{
+				{TxID: "tx"},
+				{TxID: "tx", Found: true, Height: 2, Seal: []byte("seal2"), Proof: proof},
+			},
+			err: blockchain.ErrHeaderNotFound,
+		},
+		"timeout": {
+			input: []blockchain.TxProofResponse{{TxID: "tx"}},
+			err:   api.ErrTxProofTimeout,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+
+		var lightApi *api.LightApi
+		responses := test.input
+
+		// peer 는 요청을 받으면 바로 응답한다.
+		grpcCommandService := mock.LightGrpcCommandService{}
+		grpcCommandService.RequestTxProofFunc = func(peerId blockchain.PeerId, txID string) error {
+			if len(responses) == 0 {
+				return nil
+			}
+			response := responses[0]
+			responses = responses[1:]
+			go lightApi.ReceiveTxProof(response)
+			return nil
+		}
+
+		lightApi = api.NewLightApi(headers, grpcCommandService, newLightPeerRepository("full1", "full2"))
+		lightApi.SetTxProofTimeout(100 * time.Millisecond)
+
+		// when
+		inclusion, err := lightApi.CheckTransaction("tx")
+
+		// then
+		assert.Equal(t, test.err, err)
+		if err == nil {
+			assert.Equal(t, block1.Seal, inclusion.Seal)
+			assert.Equal(t, block1.TxSeal[0], inclusion.TxRoot)
+		}
+	}
+}
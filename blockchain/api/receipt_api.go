@@ -0,0 +1,129 @@
+package api
+
+import (
+	"encoding/hex"
+	"sync"
+
+	"github.com/it-chain/engine/blockchain"
+)
+
+// ReceiptApi 는 commit 된 block 을 icode 에 실행 요청하고, 실행 결과를 receipt 로 저장한다.
+type ReceiptApi struct {
+	blockRepository   blockchain.BlockRepository
+	receiptRepository blockchain.ReceiptRepository
+	commandService    blockchain.CommandService
+	validator         *blockchain.DefaultValidator
+	replay            *replayState
+}
+
+// replayState 는 reorg 로 되돌린 block 이 있어 icode 의 state 를 처음부터 다시 만들어야 하는지 기억한다.
+type replayState struct {
+	mutex   *sync.Mutex
+	pending bool
+}
+
+func NewReceiptApi(blockRepository blockchain.BlockRepository, receiptRepository blockchain.ReceiptRepository, commandService blockchain.CommandService) ReceiptApi {
+	return ReceiptApi{
+		blockRepository:   blockRepository,
+		receiptRepository: receiptRepository,
+		commandService:    commandService,
+		validator:         &blockchain.DefaultValidator{},
+		replay: &replayState{
+			mutex: &sync.Mutex{},
+		},
+	}
+}
+
+// ExecuteBlock 은 seal 의 block 을 icode 에 실행 요청한다.
+// 되돌린 block 이 있었다면 icode 의 state 를 비우고 height 1 부터 block 까지 다시 실행하도록 요청한다.
+func (api ReceiptApi) ExecuteBlock(seal []byte) error {
+	block, err := api.blockRepository.GetBlockBySeal(seal)
+	if err != nil {
+		return err
+	}
+
+	api.replay.mutex.Lock()
+	defer api.replay.mutex.Unlock()
+
+	if !api.replay.pending {
+		return api.commandService.SendBlockExecuteCommand(block)
+	}
+
+	if err := api.replayChain(block); err != nil {
+		return err
+	}
+
+	api.replay.pending = false
+
+	return nil
+}
+
+// RollbackBlock 은 reorg 로 되돌린 block 의 receipt 를 지운다.
+// icode 의 state 는 transaction 별로 되돌릴 수 없으므로, 다음에 commit 되는 block 을 실행할 때 처음부터 다시 만든다.
+// block.committed 와 같은 순서로 받아야 하므로 되돌린 block 의 event 는 commit event 와 같은 handler 로 받는다.
+func (api ReceiptApi) RollbackBlock(height blockchain.BlockHeight, txIDs []string) error {
+	api.replay.mutex.Lock()
+	defer api.replay.mutex.Unlock()
+
+	if err := api.receiptRepository.Remove(height, txIDs); err != nil {
+		return err
+	}
+
+	api.replay.pending = true
+
+	return nil
+}
+
+// replayChain 은 icode 의 state 를 비운 다음 height 1 부터 block 까지 차례대로 실행하도록 요청한다.
+// 다시 실행한 block 의 receipt 는 icode 의 결과로 덮어쓴다.
+// transaction 을 지웠거나 snapshot 으로 시작해 없는 block 이 있으면 state 를 다시 만들 수 없으므로 그 error 를 반환한다.
+func (api ReceiptApi) replayChain(block blockchain.Block) error {
+	blocks := make([]blockchain.Block, 0, block.GetHeight())
+	for height := blockchain.BlockHeight(1); height < block.GetHeight(); height++ {
+		committed, err := api.blockRepository.GetBlockByHeight(height)
+		if err != nil {
+			return err
+		}
+
+		blocks = append(blocks, committed)
+	}
+	blocks = append(blocks, block)
+
+	if err := api.commandService.SendResetBlockExecuteCommand(blocks[0]); err != nil {
+		return err
+	}
+
+	for _, committed := range blocks[1:] {
+		if err := api.commandService.SendBlockExecuteCommand(committed); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SaveReceipts 는 icode 의 실행 결과를 block 의 transaction 순서대로 receipt 로 만들고 receipts root 와 함께 저장한다.
+// blockID 는 BlockExecuteCommand 의 ID 인 block seal 의 hex 이다.
+func (api ReceiptApi) SaveReceipts(blockID string, results []blockchain.TxResult) error {
+	seal, err := hex.DecodeString(blockID)
+	if err != nil || len(seal) == 0 {
+		return ErrInvalidBlockID
+	}
+
+	block, err := api.blockRepository.GetBlockBySeal(seal)
+	if err != nil {
+		return err
+	}
+
+	receipts, err := blockchain.NewReceipts(block, results)
+	if err != nil {
+		return err
+	}
+
+	receiptsRoot, err := api.validator.BuildReceiptsRoot(receipts)
+	if err != nil {
+		return err
+	}
+
+	return api.receiptRepository.Save(block.GetHeight(), receipts, receiptsRoot)
+}
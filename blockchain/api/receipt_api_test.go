@@ -0,0 +1,251 @@
+package api_test
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/it-chain/engine/blockchain"
+	"github.com/it-chain/engine/blockchain/api"
+	"github.com/it-chain/engine/blockchain/test/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+type receiptRepository struct {
+	receipts map[string]blockchain.Receipt
+	roots    map[blockchain.BlockHeight][]byte
+}
+
+func newReceiptRepository() *receiptRepository {
+	return &receiptRepository{
+		receipts: make(map[string]blockchain.Receipt),
+		roots:    make(map[blockchain.BlockHeight][]byte),
+	}
+}
+
+func (r *receiptRepository) Save(height blockchain.BlockHeight, receipts []blockchain.Receipt, receiptsRoot []byte) error {
+	for _, receipt := range receipts {
+		r.receipts[receipt.TxID] = receipt
+	}
+	r.roots[height] = receiptsRoot
+
+	return nil
+}
+
+func (r *receiptRepository) Remove(height blockchain.BlockHeight, txIDs []string) error {
+	for _, txID := range txIDs {
+		delete(r.receipts, txID)
+	}
+	delete(r.roots, height)
+
+	return nil
+}
+
+func (r *receiptRepository) GetReceipt(txID string) (blockchain.Receipt, error) {
+	receipt, ok := r.receipts[txID]
+	if !ok {
+		return blockchain.Receipt{}, blockchain.ErrReceiptNotFound
+	}
+
+	return receipt, nil
+}
+
+func (r *receiptRepository) GetReceiptsRoot(height blockchain.BlockHeight) ([]byte, error) {
+	root, ok := r.roots[height]
+	if !ok {
+		return nil, blockchain.ErrReceiptNotFound
+	}
+
+	return root, nil
+}
+
+func (r *receiptRepository) Close() {}
+
+func TestReceiptApi_SaveReceipts(t *testing.T) {
+	// given
+	block := &blockchain.DefaultBlock{
+		Seal:   []byte("seal"),
+		Height: 4,
+		TxList: []*blockchain.DefaultTransaction{{ID: "tx1"}, {ID: "tx2"}},
+	}
+
+	blockRepository := mock.BlockRepository{}
+	blockRepository.GetBlockBySealFunc = func(seal []byte) (blockchain.Block, error) {
+		if string(seal) != "seal" {
+			return nil, blockchain.ErrBlockNotFound
+		}
+		return block, nil
+	}
+
+	executed := make([]blockchain.Block, 0)
+	commandService := mock.CommandService{}
+	commandService.SendBlockExecuteCommandFunc = func(block blockchain.Block) error {
+		executed = append(executed, block)
+		return nil
+	}
+
+	receipts := newReceiptRepository()
+	receiptApi := api.NewReceiptApi(blockRepository, receipts, commandService)
+
+	// when
+	err := receiptApi.ExecuteBlock([]byte("seal"))
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, []blockchain.Block{block}, executed)
+
+	// when
+	err = receiptApi.SaveReceipts(hex.EncodeToString([]byte("seal")), []blockchain.TxResult{
+		{TxId: "tx1", Success: true, Data: map[string]string{"a": "1"}},
+		{TxId: "tx2", Success: false},
+	})
+
+	// then
+	assert.NoError(t, err)
+
+	receipt, err := receipts.GetReceipt("tx1")
+	assert.NoError(t, err)
+	assert.True(t, receipt.Success)
+	assert.Equal(t, uint64(4), receipt.BlockHeight)
+	assert.Equal(t, map[string]string{"a": "1"}, receipt.Data)
+
+	expectedReceipts, _ := blockchain.NewReceipts(block, []blockchain.TxResult{
+		{TxId: "tx1", Success: true, Data: map[string]string{"a": "1"}},
+		{TxId: "tx2", Success: false},
+	})
+	validator := blockchain.DefaultValidator{}
+	expectedRoot, _ := validator.BuildReceiptsRoot(expectedReceipts)
+	root, err := receipts.GetReceiptsRoot(4)
+	assert.NoError(t, err)
+	assert.Equal(t, expectedRoot, root)
+
+	// when
+	err = receiptApi.SaveReceipts("not hex", nil)
+
+	// then
+	assert.Equal(t, api.ErrInvalidBlockID, err)
+
+	// when
+	err = receiptApi.SaveReceipts(hex.EncodeToString([]byte("other")), nil)
+
+	// then
+	assert.Equal(t, blockchain.ErrBlockNotFound, err)
+}
+
+func TestReceiptApi_RollbackBlock(t *testing.T) {
+	// given
+	chain := []blockchain.Block{
+		&blockchain.DefaultBlock{Seal: []byte("seal0"), Height: 0},
+		&blockchain.DefaultBlock{Seal: []byte("seal1"), Height: 1, TxList: []*blockchain.DefaultTransaction{{ID: "tx1"}}},
+		&blockchain.DefaultBlock{Seal: []byte("fork2"), Height: 2, TxList: []*blockchain.DefaultTransaction{{ID: "tx3"}}},
+	}
+	pruned := false
+
+	blockRepository := mock.BlockRepository{}
+	blockRepository.GetBlockBySealFunc = func(seal []byte) (blockchain.Block, error) {
+		for _, block := range chain {
+			if string(block.GetSeal()) == string(seal) {
+				return block, nil
+			}
+		}
+		return nil, blockchain.ErrBlockNotFound
+	}
+	blockRepository.GetBlockByHeightFunc = func(height uint64) (blockchain.Block, error) {
+		if pruned && height == 1 {
+			return nil, blockchain.ErrBlockPruned
+		}
+		return chain[height], nil
+	}
+
+	executed := make([]string, 0)
+	commandService := mock.CommandService{}
+	commandService.SendBlockExecuteCommandFunc = func(block blockchain.Block) error {
+		executed = append(executed, string(block.GetSeal()))
+		return nil
+	}
+	commandService.SendResetBlockExecuteCommandFunc = func(block blockchain.Block) error {
+		executed = append(executed, "reset:"+string(block.GetSeal()))
+		return nil
+	}
+
+	receipts := newReceiptRepository()
+	receipts.Save(2, []blockchain.Receipt{{TxID: "tx2", BlockHeight: 2}}, []byte("root2"))
+	receiptApi := api.NewReceiptApi(blockRepository, receipts, commandService)
+
+	// when: height 2 의 block 이 reorg 로 되돌려졌다.
+	err := receiptApi.RollbackBlock(2, []string{"tx2"})
+
+	// then
+	assert.NoError(t, err)
+	_, err = receipts.GetReceipt("tx2")
+	assert.Equal(t, blockchain.ErrReceiptNotFound, err)
+	_, err = receipts.GetReceiptsRoot(2)
+	assert.Equal(t, blockchain.ErrReceiptNotFound, err)
+
+	// when: transaction 을 지운 block 이 있으면 state 를 다시 만들 수 없다.
+	pruned = true
+	err = receiptApi.ExecuteBlock([]byte("fork2"))
+
+	// then
+	assert.Equal(t, blockchain.ErrBlockPruned, err)
+	assert.Empty(t, executed)
+
+	// when: branch 의 block 이 commit 되었다.
+	pruned = false
+	err = receiptApi.ExecuteBlock([]byte("fork2"))
+
+	// then: icode 의 state 를 비우고 height 1 부터 다시 실행한다.
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"reset:seal1", "fork2"}, executed)
+
+	// when
+	executed = make([]string, 0)
+	err = receiptApi.ExecuteBlock([]byte("fork2"))
+
+	// then: 다시 실행한 다음부터는 block 만 실행한다.
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"fork2"}, executed)
+}
+
+func TestBlockProposeApi_ProposeBlock_ReceiptsRoot(t *testing.T) {
+	// given
+	lastBlock := &blockchain.DefaultBlock{Seal: []byte("seal"), Height: 3}
+	blockRepository := mock.BlockRepository{}
+	blockRepository.GetLastBlockFunc = func() (blockchain.Block, error) {
+		return lastBlock, nil
+	}
+
+	proposed := make([]blockchain.Block, 0)
+	commandService := mock.CommandService{}
+	commandService.SendBlockValidateCommandFunc = func(block blockchain.Block) error {
+		proposed = append(proposed, block)
+		return nil
+	}
+
+	signer := mock.Signer{}
+	signer.SignFunc = func(data []byte) ([]byte, error) {
+		return data, nil
+	}
+
+	receipts := newReceiptRepository()
+	proposeApi := api.NewBlockProposeApi("peer", blockRepository, commandService, signer)
+	proposeApi.SetReceiptRepository(receipts)
+
+	// when: 마지막 block 이 아직 실행되지 않았다.
+	err := proposeApi.ProposeBlock(newProposeTxList("tx1"))
+
+	// then
+	assert.NoError(t, err)
+	assert.Empty(t, proposed[0].(*blockchain.DefaultBlock).ReceiptsRoot)
+
+	// when
+	receipts.Save(3, nil, []byte("receiptsroot"))
+	err = proposeApi.ProposeBlock(newProposeTxList("tx2"))
+
+	// then
+	assert.NoError(t, err)
+	block := proposed[1].(*blockchain.DefaultBlock)
+	assert.Equal(t, []byte("receiptsroot"), block.ReceiptsRoot)
+
+	validator := blockchain.DefaultValidator{}
+	assert.NoError(t, validator.ValidateBlock(block, lastBlock))
+}
@@ -0,0 +1,211 @@
+package api
+
+import (
+	"bytes"
+	"log"
+	"sync"
+
+	"github.com/it-chain/engine/blockchain"
+)
+
+// SnapshotApi 는 일정 height 마다 signed snapshot 을 만들고, 새 node 가 peer 의 snapshot 으로 시작할 수 있도록 한다.
+// 받은 snapshot 은 light sync 로 검증한 header chain 의 header 와 비교해서 검증한다.
+type SnapshotApi struct {
+	publisherId          string
+	blockRepository      blockchain.SnapshotBlockRepository
+	snapshotRepository   blockchain.SnapshotRepository
+	icodeStateRepository blockchain.ICodeStateRepository
+	headerRepository     blockchain.HeaderRepository
+	grpcCommandService   blockchain.SnapshotGrpcCommandService
+	commandService       blockchain.CommandService
+	peerRepository       blockchain.PeerRepository
+	signer               blockchain.Signer
+	validator            *blockchain.DefaultValidator
+	config               blockchain.SnapshotConfig
+	parliament           []string
+	mutex                *sync.Mutex
+}
+
+func NewSnapshotApi(publisherId string, blockRepository blockchain.SnapshotBlockRepository, snapshotRepository blockchain.SnapshotRepository, icodeStateRepository blockchain.ICodeStateRepository, grpcCommandService blockchain.SnapshotGrpcCommandService, peerRepository blockchain.PeerRepository, signer blockchain.Signer) *SnapshotApi {
+	return &SnapshotApi{
+		publisherId:          publisherId,
+		blockRepository:      blockRepository,
+		snapshotRepository:   snapshotRepository,
+		icodeStateRepository: icodeStateRepository,
+		grpcCommandService:   grpcCommandService,
+		peerRepository:       peerRepository,
+		signer:               signer,
+		validator:            &blockchain.DefaultValidator{},
+		parliament:           make([]string, 0),
+		mutex:                &sync.Mutex{},
+	}
+}
+
+// SetSnapshotConfig 는 snapshot 을 만드는 height 간격을 설정한다.
+func (api *SnapshotApi) SetSnapshotConfig(config blockchain.SnapshotConfig) {
+	api.config = config
+}
+
+// SetParliament 는 snapshot 에 담을 parliament 구성원을 설정한다.
+func (api *SnapshotApi) SetParliament(parliament []string) {
+	api.parliament = append([]string{}, parliament...)
+}
+
+// SetHeaderRepository 는 받은 snapshot 을 검증할 header chain 을 설정한다.
+// 설정하지 않으면 snapshot 을 받지 않는다.
+func (api *SnapshotApi) SetHeaderRepository(headerRepository blockchain.HeaderRepository) {
+	api.headerRepository = headerRepository
+}
+
+// SetCommandService 는 snapshot 으로 시작한 다음 snapshot 의 icode 를 배포하도록 설정한다.
+// 설정하지 않으면 snapshot 의 icode 는 배포되지 않는다.
+func (api *SnapshotApi) SetCommandService(commandService blockchain.CommandService) {
+	api.commandService = commandService
+}
+
+// SetSignatureVerifier 는 받은 snapshot 의 creator 서명을 검증하도록 설정한다.
+// 설정하지 않으면 snapshot 을 받지 않는다.
+func (api *SnapshotApi) SetSignatureVerifier(verifier blockchain.SignatureVerifier) {
+	api.validator.SetSignatureVerifier(verifier)
+}
+
+// TakeSnapshot 은 height 가 snapshot height 라면 그 height 의 block 과 현재 icode state 로 snapshot 을 만들어 저장한다.
+// snapshot height 가 아니면 아무것도 하지 않는다.
+func (api *SnapshotApi) TakeSnapshot(height blockchain.BlockHeight) error {
+	if !api.config.IsSnapshotHeight(height) {
+		return nil
+	}
+
+	api.mutex.Lock()
+	defer api.mutex.Unlock()
+
+	block, err := api.blockRepository.GetBlockByHeight(height)
+	if err != nil {
+		return err
+	}
+
+	icodes, err := api.icodeStateRepository.FindAllICodeStates()
+	if err != nil {
+		return err
+	}
+
+	snapshot, err := blockchain.NewSnapshot(block, icodes, api.parliament, []byte(api.publisherId), api.signer)
+	if err != nil {
+		return err
+	}
+
+	// block 이 합의한 state 와 다르면 다른 node 가 받지 않을 snapshot 이다.
+	if !bytes.Equal(snapshot.StateRoot(), snapshot.Header.StateRoot) {
+		return blockchain.ErrSnapshotStateRootMismatch
+	}
+
+	return api.snapshotRepository.Save(snapshot)
+}
+
+// SaveICodeState 는 배포된 icode 를 다음 snapshot 에 담도록 기록한다.
+func (api *SnapshotApi) SaveICodeState(state blockchain.ICodeState) error {
+	return api.icodeStateRepository.SaveICodeState(state)
+}
+
+// RemoveICodeState 는 삭제된 icode 를 다음 snapshot 에서 뺀다.
+func (api *SnapshotApi) RemoveICodeState(id string) error {
+	return api.icodeStateRepository.RemoveICodeState(id)
+}
+
+func (api *SnapshotApi) GetLastSnapshot() (blockchain.Snapshot, error) {
+	return api.snapshotRepository.GetLastSnapshot()
+}
+
+// RequestSnapshot 은 모든 peer 에게 마지막 snapshot 을 요청한다. 응답은 ReceiveSnapshot 에서 처리한다.
+func (api *SnapshotApi) RequestSnapshot() error {
+	peers, err := api.peerRepository.FindAll()
+	if err != nil {
+		return err
+	}
+
+	if len(peers) == 0 {
+		return ErrNoPeer
+	}
+
+	requested := false
+	for _, peer := range peers {
+		if err := api.grpcCommandService.RequestSnapshot(peer.PeerId); err != nil {
+			log.Printf("fail to request snapshot to [%s]: [%v]", peer.PeerId.ToString(), err)
+			continue
+		}
+		requested = true
+	}
+
+	if !requested {
+		return ErrSnapshotRequest
+	}
+
+	return nil
+}
+
+// ReceiveSnapshot 은 peer 가 보낸 snapshot 을 header chain 과 node 의 parliament 로 검증하고, 마지막 block 보다 높으면 snapshot 의 block 부터 chain 을 시작한다.
+// 반환값은 snapshot 을 가져왔는지 여부이다. 가져왔다면 다음 block 부터 동기화해야 한다.
+// header chain 이 아직 snapshot height 까지 동기화되지 않았으면 ErrHeaderNotFound 를 반환한다.
+func (api *SnapshotApi) ReceiveSnapshot(peerId blockchain.PeerId, snapshot blockchain.Snapshot) (bool, error) {
+	if api.headerRepository == nil {
+		return false, ErrNoHeaderChain
+	}
+
+	api.mutex.Lock()
+	defer api.mutex.Unlock()
+
+	lastBlock, err := api.blockRepository.GetLastBlock()
+	if err != nil && err != blockchain.ErrBlockNotFound {
+		return false, err
+	}
+
+	if lastBlock != nil && lastBlock.GetHeight() >= snapshot.Height() {
+		return false, nil
+	}
+
+	header, err := api.headerRepository.GetHeaderByHeight(snapshot.Height())
+	if err != nil {
+		return false, err
+	}
+
+	if err := api.validator.ValidateSnapshot(snapshot, header, api.parliament); err != nil {
+		return false, err
+	}
+
+	if err := api.snapshotRepository.Save(snapshot); err != nil {
+		return false, err
+	}
+
+	// 다음 snapshot 에 담을 state 를 기록한다. 배포는 snapshot 을 가져온 다음 icode component 에 요청한다.
+	for _, icode := range snapshot.ICodes {
+		if err := api.icodeStateRepository.SaveICodeState(icode); err != nil {
+			return false, err
+		}
+	}
+
+	if err := api.blockRepository.AddSnapshotBlock(blockchain.NewBlockFromHeader(snapshot.Header)); err != nil {
+		return false, err
+	}
+
+	log.Printf("bootstrapped from snapshot of [%s] at height [%d]", peerId.ToString(), snapshot.Height())
+
+	api.deployICodes(snapshot.ICodes)
+
+	return true, nil
+}
+
+// deployICodes 는 snapshot 의 icode 를 git url 로 배포하도록 icode 에 요청한다.
+// snapshot 은 icode 의 code 위치만 담고 실행 state 는 담지 않으므로, 배포된 icode 는 빈 state 로 시작한다.
+// snapshot 은 이미 가져왔으므로 요청하지 못한 icode 는 기록만 하고 나머지를 요청한다.
+func (api *SnapshotApi) deployICodes(icodes []blockchain.ICodeState) {
+	if api.commandService == nil {
+		log.Printf("icodes of snapshot are not deployed: no command service")
+		return
+	}
+
+	for _, icode := range icodes {
+		if err := api.commandService.SendICodeDeployCommand(icode); err != nil {
+			log.Printf("fail to request deploy of icode [%s]: %s", icode.ID, err.Error())
+		}
+	}
+}
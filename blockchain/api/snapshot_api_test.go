@@ -0,0 +1,283 @@
+package api_test
+
+import (
+	"testing"
+
+	"github.com/it-chain/engine/blockchain"
+	"github.com/it-chain/engine/blockchain/api"
+	"github.com/it-chain/engine/blockchain/test/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func newSnapshotRepository(saved *[]blockchain.Snapshot) mock.SnapshotRepository {
+	snapshotRepository := mock.SnapshotRepository{}
+	snapshotRepository.SaveFunc = func(snapshot blockchain.Snapshot) error {
+		*saved = append(*saved, snapshot)
+		return nil
+	}
+
+	return snapshotRepository
+}
+
+func newICodeStateRepository(states *[]blockchain.ICodeState) mock.ICodeStateRepository {
+	icodeStateRepository := mock.ICodeStateRepository{}
+	icodeStateRepository.SaveICodeStateFunc = func(state blockchain.ICodeState) error {
+		*states = append(*states, state)
+		return nil
+	}
+	icodeStateRepository.FindAllICodeStatesFunc = func() ([]blockchain.ICodeState, error) {
+		return *states, nil
+	}
+
+	return icodeStateRepository
+}
+
+func TestSnapshotApi_TakeSnapshot(t *testing.T) {
+	icodes := []blockchain.ICodeState{{ID: "icode1", CommitHash: "hash"}}
+	stateRoot := blockchain.SnapshotStateRoot(icodes, []string{"peer1", "peer2"})
+
+	tests := map[string]struct {
+		input struct {
+			height    blockchain.BlockHeight
+			stateRoot []byte
+		}
+		output int
+		err    error
+	}{
+		"snapshot height": {
+			input: struct {
+				height    blockchain.BlockHeight
+				stateRoot []byte
+			}{height: 10, stateRoot: stateRoot},
+			output: 1,
+			err:    nil,
+		},
+		"not snapshot height": {
+			input: struct {
+				height    blockchain.BlockHeight
+				stateRoot []byte
+			}{height: 11, stateRoot: stateRoot},
+			output: 0,
+			err:    nil,
+		},
+		"state differs from block": {
+			input: struct {
+				height    blockchain.BlockHeight
+				stateRoot []byte
+			}{height: 10, stateRoot: []byte("otherroot")},
+			output: 0,
+			err:    blockchain.ErrSnapshotStateRootMismatch,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+
+		// given
+		saved := make([]blockchain.Snapshot, 0)
+		states := append([]blockchain.ICodeState{}, icodes...)
+
+		blockRepository := mock.SnapshotBlockRepository{}
+		blockRepository.GetBlockByHeightFunc = func(height uint64) (blockchain.Block, error) {
+			return mock.NewSnapshotBlock(t, []byte("prevseal"), height, test.input.stateRoot), nil
+		}
+
+		snapshotApi := api.NewSnapshotApi("peer1", blockRepository, newSnapshotRepository(&saved), newICodeStateRepository(&states), mock.SnapshotGrpcCommandService{}, mock.PeerRepository{}, mock.NewSigner())
+		snapshotApi.SetSnapshotConfig(blockchain.SnapshotConfig{Interval: 10})
+		snapshotApi.SetParliament([]string{"peer1", "peer2"})
+
+		// when
+		err := snapshotApi.TakeSnapshot(test.input.height)
+
+		// then
+		assert.Equal(t, test.err, err)
+		assert.Equal(t, test.output, len(saved))
+
+		if test.output != 0 {
+			assert.Equal(t, test.input.height, saved[0].Height())
+			assert.Equal(t, states, saved[0].ICodes)
+			assert.Equal(t, []string{"peer1", "peer2"}, saved[0].Parliament)
+			assert.Equal(t, []byte("peer1"), saved[0].Creator)
+			assert.Equal(t, append([]byte("signed:"), saved[0].Hash()...), saved[0].Signature)
+		}
+	}
+}
+
+func newSnapshotVerifier() mock.SignatureVerifier {
+	verifier := mock.SignatureVerifier{}
+	verifier.VerifyFunc = func(peerId blockchain.PeerId, data []byte, signature []byte) (bool, error) {
+		if peerId.Id != "peer2" {
+			return false, blockchain.ErrPublicKeyNotFound
+		}
+		return string(signature) == "signed:"+string(data), nil
+	}
+
+	return verifier
+}
+
+func TestSnapshotApi_ReceiveSnapshot(t *testing.T) {
+	icodes := []blockchain.ICodeState{{ID: "icode1"}}
+	parliament := []string{"peer2", "peer3"}
+	stateRoot := blockchain.SnapshotStateRoot(icodes, parliament)
+
+	// header chain: genesis - block1 - block2
+	genesis := mock.NewLegacyBlock(t, []byte("genesis"), 0)
+	block1 := mock.NewSnapshotBlock(t, genesis.Seal, 1, stateRoot)
+	block2 := mock.NewSnapshotBlock(t, block1.Seal, 2, stateRoot)
+	headers := &headerRepository{headers: []blockchain.SignedHeader{
+		blockchain.NewSignedHeader(genesis),
+		blockchain.NewSignedHeader(block1),
+		blockchain.NewSignedHeader(block2),
+	}}
+
+	newSnapshot := func(block blockchain.Block, creator string) blockchain.Snapshot {
+		snapshot, err := blockchain.NewSnapshot(block, icodes, parliament, []byte(creator), mock.NewSigner())
+		assert.NoError(t, err)
+		return snapshot
+	}
+
+	forgedState := newSnapshot(block2, "peer2")
+	forgedState.ICodes = []blockchain.ICodeState{{ID: "icode1", CommitHash: "forged"}}
+
+	tests := map[string]struct {
+		input     blockchain.Snapshot
+		lastBlock blockchain.Block
+		imported  bool
+		err       error
+	}{
+		"import": {
+			input:     newSnapshot(block2, "peer2"),
+			lastBlock: genesis,
+			imported:  true,
+			err:       nil,
+		},
+		"already synced": {
+			input:     newSnapshot(block1, "peer2"),
+			lastBlock: block2,
+			imported:  false,
+			err:       nil,
+		},
+		"header not synced": {
+			input:     newSnapshot(mock.NewSnapshotBlock(t, block2.Seal, 3, stateRoot), "peer2"),
+			lastBlock: genesis,
+			imported:  false,
+			err:       blockchain.ErrHeaderNotFound,
+		},
+		"forged block": {
+			input:     newSnapshot(mock.NewSnapshotBlock(t, []byte("otherseal"), 2, stateRoot), "peer2"),
+			lastBlock: genesis,
+			imported:  false,
+			err:       blockchain.ErrSnapshotHeaderMismatch,
+		},
+		"forged state": {
+			input:     forgedState,
+			lastBlock: genesis,
+			imported:  false,
+			err:       blockchain.ErrSnapshotStateRootMismatch,
+		},
+		"creator not parliament member": {
+			input:     newSnapshot(block2, "peer4"),
+			lastBlock: genesis,
+			imported:  false,
+			err:       blockchain.ErrSnapshotCreatorNotMember,
+		},
+		"creator key not registered": {
+			input:     newSnapshot(block2, "peer3"),
+			lastBlock: genesis,
+			imported:  false,
+			err:       blockchain.ErrPublicKeyNotFound,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+
+		// given
+		saved := make([]blockchain.Snapshot, 0)
+		states := make([]blockchain.ICodeState, 0)
+		added := make([]blockchain.Block, 0)
+		deployed := make([]blockchain.ICodeState, 0)
+
+		blockRepository := mock.SnapshotBlockRepository{}
+		blockRepository.GetLastBlockFunc = func() (blockchain.Block, error) {
+			return test.lastBlock, nil
+		}
+		blockRepository.AddSnapshotBlockFunc = func(block blockchain.Block) error {
+			added = append(added, block)
+			return nil
+		}
+
+		snapshotApi := api.NewSnapshotApi("peer1", blockRepository, newSnapshotRepository(&saved), newICodeStateRepository(&states), mock.SnapshotGrpcCommandService{}, mock.PeerRepository{}, mock.NewSigner())
+		snapshotApi.SetHeaderRepository(headers)
+		snapshotApi.SetParliament(parliament)
+		snapshotApi.SetSignatureVerifier(newSnapshotVerifier())
+
+		commandService := mock.CommandService{}
+		commandService.SendICodeDeployCommandFunc = func(icode blockchain.ICodeState) error {
+			deployed = append(deployed, icode)
+			return nil
+		}
+		snapshotApi.SetCommandService(commandService)
+
+		// when
+		imported, err := snapshotApi.ReceiveSnapshot(blockchain.PeerId{Id: "peer2"}, test.input)
+
+		// then
+		assert.Equal(t, test.err, err)
+		assert.Equal(t, test.imported, imported)
+
+		if test.imported {
+			assert.Equal(t, 1, len(saved))
+			assert.Equal(t, test.input.ICodes, states)
+			assert.Equal(t, 1, len(added))
+			assert.Equal(t, block2.Seal, added[0].GetSeal())
+			assert.Equal(t, block2.Height, added[0].GetHeight())
+			// snapshot 의 icode 를 배포하도록 요청한다.
+			assert.Equal(t, test.input.ICodes, deployed)
+		} else {
+			// 검증에 실패한 snapshot 의 state 와 block 은 저장하지 않고 icode 도 배포하지 않는다.
+			assert.Equal(t, 0, len(saved))
+			assert.Equal(t, 0, len(states))
+			assert.Equal(t, 0, len(added))
+			assert.Equal(t, 0, len(deployed))
+		}
+	}
+}
+
+func TestSnapshotApi_ReceiveSnapshot_NoHeaderChain(t *testing.T) {
+	// given
+	snapshotApi := api.NewSnapshotApi("peer1", mock.SnapshotBlockRepository{}, mock.SnapshotRepository{}, mock.ICodeStateRepository{}, mock.SnapshotGrpcCommandService{}, mock.PeerRepository{}, mock.NewSigner())
+
+	// when
+	imported, err := snapshotApi.ReceiveSnapshot(blockchain.PeerId{Id: "peer2"}, blockchain.Snapshot{})
+
+	// then
+	assert.Equal(t, api.ErrNoHeaderChain, err)
+	assert.False(t, imported)
+}
+
+func TestSnapshotApi_RequestSnapshot(t *testing.T) {
+	// given
+	requested := make([]string, 0)
+
+	grpcCommandService := mock.SnapshotGrpcCommandService{}
+	grpcCommandService.RequestSnapshotFunc = func(peerId blockchain.PeerId) error {
+		requested = append(requested, peerId.Id)
+		return nil
+	}
+
+	snapshotApi := api.NewSnapshotApi("peer1", mock.SnapshotBlockRepository{}, mock.SnapshotRepository{}, mock.ICodeStateRepository{}, grpcCommandService, newLightPeerRepository("peer2", "peer3"), mock.NewSigner())
+
+	// when
+	err := snapshotApi.RequestSnapshot()
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"peer2", "peer3"}, requested)
+
+	// when
+	err = api.NewSnapshotApi("peer1", mock.SnapshotBlockRepository{}, mock.SnapshotRepository{}, mock.ICodeStateRepository{}, grpcCommandService, newLightPeerRepository(), mock.NewSigner()).RequestSnapshot()
+
+	// then
+	assert.Equal(t, api.ErrNoPeer, err)
+}
@@ -1,7 +1,6 @@
 package blockchain
 
 import (
-	"encoding/json"
 	"time"
 
 	"bytes"
@@ -20,13 +19,17 @@ type Block = ygg.Block
 type BlockHeight = uint64
 
 type DefaultBlock struct {
-	Seal      []byte
-	PrevSeal  []byte
-	Height    uint64
-	TxList    []*DefaultTransaction
-	TxSeal    [][]byte
-	Timestamp time.Time
-	Creator   []byte
+	Seal         []byte
+	PrevSeal     []byte
+	Height       uint64
+	TxList       []*DefaultTransaction
+	TxSeal       [][]byte
+	Timestamp    time.Time
+	Creator      []byte
+	Version      BlockVersion
+	StateRoot    []byte
+	ReceiptsRoot []byte
+	Signature    []byte
 }
 
 // TODO: Write test case
@@ -74,6 +77,22 @@ func (block *DefaultBlock) SetTimestamp(currentTime time.Time) {
 	block.Timestamp = currentTime
 }
 
+func (block *DefaultBlock) SetVersion(version BlockVersion) {
+	block.Version = version
+}
+
+func (block *DefaultBlock) SetStateRoot(stateRoot []byte) {
+	block.StateRoot = stateRoot
+}
+
+func (block *DefaultBlock) SetReceiptsRoot(receiptsRoot []byte) {
+	block.ReceiptsRoot = receiptsRoot
+}
+
+func (block *DefaultBlock) SetSignature(signature []byte) {
+	block.Signature = signature
+}
+
 // TODO: Write test case
 func (block *DefaultBlock) GetSeal() []byte {
 	return block.Seal
@@ -113,13 +132,29 @@ func (block *DefaultBlock) GetTimestamp() time.Time {
 	return block.Timestamp
 }
 
+func (block *DefaultBlock) GetVersion() BlockVersion {
+	return block.Version
+}
+
+func (block *DefaultBlock) GetStateRoot() []byte {
+	return block.StateRoot
+}
+
+func (block *DefaultBlock) GetReceiptsRoot() []byte {
+	return block.ReceiptsRoot
+}
+
+func (block *DefaultBlock) GetSignature() []byte {
+	return block.Signature
+}
+
+func (block *DefaultBlock) GetHeader() BlockHeader {
+	return NewBlockHeader(block)
+}
+
 // TODO: Write test case
 func (block *DefaultBlock) Serialize() ([]byte, error) {
-	data, err := json.Marshal(block)
-	if err != nil {
-		return nil, err
-	}
-	return data, nil
+	return EncodeBlock(block), nil
 }
 
 // TODO: Write test case
@@ -128,11 +163,13 @@ func (block *DefaultBlock) Deserialize(serializedBlock []byte) error {
 		return ErrDecodingEmptyBlock
 	}
 
-	err := json.Unmarshal(serializedBlock, block)
+	decoded, err := DecodeBlock(serializedBlock)
 	if err != nil {
 		return err
 	}
 
+	*block = *decoded
+
 	return nil
 }
 
@@ -162,20 +199,24 @@ type Action interface {
 }
 
 // TODO: Write test case
-func CreateSaveOrSyncAction(checkResult int64) Action {
+func CreateSaveOrSyncAction(checkResult int64, blockRepository BlockRepository, syncService SyncService) Action {
 	if checkResult > 0 {
-		return NewSyncAction()
+		return NewSyncAction(syncService)
 	} else if checkResult == 0 {
-		return NewSaveAction()
+		return NewSaveAction(blockRepository)
 	} else {
 		return NewDefaultAction()
 	}
 }
 
-type SyncAction struct{}
+type SyncAction struct {
+	syncService SyncService
+}
 
-func NewSyncAction() *SyncAction {
-	return &SyncAction{}
+func NewSyncAction(syncService SyncService) *SyncAction {
+	return &SyncAction{
+		syncService: syncService,
+	}
 }
 func (block *DefaultBlock) On(event midgard.Event) error {
 
@@ -194,6 +235,10 @@ func (block *DefaultBlock) On(event midgard.Event) error {
 		block.TxList = TxList
 		block.TxSeal = v.TxSeal
 		block.Timestamp = v.Timestamp
+		block.Version = v.Version
+		block.StateRoot = v.StateRoot
+		block.ReceiptsRoot = v.ReceiptsRoot
+		block.Signature = v.Signature
 		block.Creator = v.Creator
 
 	default:
@@ -208,37 +253,53 @@ func NewEmptyBlock(prevSeal []byte, height uint64, creator []byte) *DefaultBlock
 	return block
 }
 
+// DoAction 은 마지막 block 보다 높은 block 을 받았을 때 동기화를 시작한다.
 func (syncAction *SyncAction) DoAction(block Block) error {
-	// TODO: Start synchronize
-	return nil
+	return syncAction.syncService.Synchronize()
 }
 
 type SaveAction struct {
-	blockPool BlockPool
+	blockRepository BlockRepository
 }
 
-func NewSaveAction() *SaveAction {
-	return &SaveAction{}
+func NewSaveAction(blockRepository BlockRepository) *SaveAction {
+	return &SaveAction{
+		blockRepository: blockRepository,
+	}
 }
 
-// TODO: Write test case
+// DoAction 은 block 을 repository 에 commit 하고 BlockCommittedEvent 를 저장한다.
 func (saveAction *SaveAction) DoAction(block Block) error {
+	if err := saveAction.blockRepository.AddBlock(block); err != nil {
+		return err
+	}
+
 	event, err := createBlockCommittedEvent(block)
 	if err != nil {
 		return err
 	}
 	blockId := string(block.GetSeal())
-	eventstore.Save(blockId, event)
-	return nil
+	return eventstore.Save(blockId, event)
 }
 
 func createBlockCommittedEvent(block Block) (BlockCommittedEvent, error) {
-	seal := string(block.GetSeal())
+	txIDs := make([]string, 0, len(block.GetTxList()))
+	for _, tx := range block.GetTxList() {
+		txIDs = append(txIDs, tx.GetID())
+	}
+
 	return BlockCommittedEvent{
 		EventModel: midgard.EventModel{
-			ID: seal,
+			ID:   string(block.GetSeal()),
+			Type: "block.committed",
+			Time: time.Now(),
 		},
-		Seal: seal,
+		Seal:      block.GetSeal(),
+		PrevSeal:  block.GetPrevSeal(),
+		Height:    block.GetHeight(),
+		Timestamp: block.GetTimestamp(),
+		Creator:   block.GetCreator(),
+		TxIDs:     txIDs,
 	}, nil
 }
 
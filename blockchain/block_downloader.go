@@ -0,0 +1,261 @@
+package blockchain
+
+import (
+	"errors"
+	"sort"
+	"time"
+)
+
+var ErrInvalidDownloadRange = errors.New("invalid block download range")
+var ErrNoDownloadPeer = errors.New("no peer to download blocks from")
+var ErrDownloadFailed = errors.New("block range download failed after retries")
+var ErrUnrequestedBlock = errors.New("block is not requested from the peer")
+
+// RequestRangeFunc 는 peer 에게 from 부터 to 까지의 block 을 요청한다.
+type RequestRangeFunc func(peerId PeerId, from BlockHeight, to BlockHeight) error
+
+type DownloadConfig struct {
+	// 한 번의 요청으로 받을 최대 block 수
+	RangeSize uint64
+	// 동시에 응답을 기다리는 최대 요청 수
+	WindowSize int
+	// 요청의 응답을 기다리는 시간
+	Timeout time.Duration
+	// 하나의 range 를 다시 요청하는 최대 횟수
+	MaxRetry int
+}
+
+func DefaultDownloadConfig() DownloadConfig {
+	return DownloadConfig{
+		RangeSize:  32,
+		WindowSize: 4,
+		Timeout:    10 * time.Second,
+		MaxRetry:   3,
+	}
+}
+
+type blockRange struct {
+	from     BlockHeight
+	to       BlockHeight
+	retry    int
+	lastPeer string
+}
+
+type inflightRange struct {
+	blockRange
+	peerId   PeerId
+	deadline time.Time
+}
+
+// BlockDownloader 는 [from, to] 의 block 들을 RangeSize 단위로 나누어 여러 peer 에게 요청한다.
+// 응답을 기다리는 요청은 WindowSize 개로 제한되고, 응답이 없는 range 는 다른 peer 에게 다시 요청한다.
+// 받은 block 들은 height 순서대로만 반환된다.
+type BlockDownloader struct {
+	config    DownloadConfig
+	peers     []PeerId
+	request   RequestRangeFunc
+	pending   []*blockRange
+	inflight  map[BlockHeight]*inflightRange
+	received  map[BlockHeight]Block
+	next      BlockHeight
+	to        BlockHeight
+	peerIndex int
+}
+
+func NewBlockDownloader(from BlockHeight, to BlockHeight, peers []PeerId, request RequestRangeFunc, config DownloadConfig) (*BlockDownloader, error) {
+	if from > to {
+		return nil, ErrInvalidDownloadRange
+	}
+
+	if len(peers) == 0 {
+		return nil, ErrNoDownloadPeer
+	}
+
+	if config.RangeSize == 0 {
+		config.RangeSize = 1
+	}
+
+	if config.WindowSize <= 0 {
+		config.WindowSize = 1
+	}
+
+	pending := make([]*blockRange, 0)
+	for start := from; start <= to; start += config.RangeSize {
+		end := start + config.RangeSize - 1
+		if end > to || end < start {
+			end = to
+		}
+
+		pending = append(pending, &blockRange{from: start, to: end})
+
+		if end == to {
+			break
+		}
+	}
+
+	return &BlockDownloader{
+		config:   config,
+		peers:    peers,
+		request:  request,
+		pending:  pending,
+		inflight: make(map[BlockHeight]*inflightRange),
+		received: make(map[BlockHeight]Block),
+		next:     from,
+		to:       to,
+	}, nil
+}
+
+// Start 는 window 크기만큼 range 를 요청한다.
+func (d *BlockDownloader) Start(now time.Time) error {
+	return d.fill(now)
+}
+
+// Receive 는 peer 가 보낸 range 응답을 받아 height 순서대로 이어지는 block 들을 반환한다.
+// 응답한 range 중 peer 가 보내지 않은 block 은 다른 peer 에게 다시 요청한다. 빈 응답은 peer 가 그 range 의 block 을 가지고 있지 않다는 뜻이다.
+// peer 에게 요청 중인 range 의 응답이 아니면 (이미 다른 peer 에게 다시 요청한 range 의 늦은 응답 등) ErrUnrequestedBlock 을 반환한다.
+func (d *BlockDownloader) Receive(peerId PeerId, response BlockRangeResponse, now time.Time) ([]Block, error) {
+	r := d.findInflight(peerId, response.From)
+	if r == nil {
+		return nil, ErrUnrequestedBlock
+	}
+
+	for _, block := range response.Blocks {
+		if block == nil || block.GetHeight() < r.from || block.GetHeight() > r.to {
+			continue
+		}
+
+		d.received[block.GetHeight()] = block
+	}
+
+	delete(d.inflight, r.from)
+
+	if err := d.requeueMissing(r); err != nil {
+		return nil, err
+	}
+
+	if err := d.fill(now); err != nil {
+		return nil, err
+	}
+
+	return d.drain(), nil
+}
+
+// CheckTimeout 은 deadline 이 지난 요청을 다른 peer 에게 다시 요청한다.
+func (d *BlockDownloader) CheckTimeout(now time.Time) error {
+	for from, r := range d.inflight {
+		if now.Before(r.deadline) {
+			continue
+		}
+
+		delete(d.inflight, from)
+
+		if err := d.requeueMissing(r); err != nil {
+			return err
+		}
+	}
+
+	return d.fill(now)
+}
+
+// Done 은 모든 block 을 순서대로 반환했는지 확인한다.
+func (d *BlockDownloader) Done() bool {
+	return d.next > d.to
+}
+
+// requeueMissing 은 range 중 아직 받지 못한 block 들을 다시 요청 대기열에 넣는다.
+func (d *BlockDownloader) requeueMissing(r *inflightRange) error {
+	start := r.from
+	for height := r.from; height <= r.to+1; height++ {
+		_, ok := d.received[height]
+		if height <= r.to && height >= d.next && !ok {
+			continue
+		}
+
+		if start < height {
+			if r.retry+1 > d.config.MaxRetry {
+				return ErrDownloadFailed
+			}
+
+			d.enqueue(&blockRange{
+				from:     start,
+				to:       height - 1,
+				retry:    r.retry + 1,
+				lastPeer: r.peerId.Id,
+			})
+		}
+		start = height + 1
+	}
+
+	return nil
+}
+
+func (d *BlockDownloader) enqueue(r *blockRange) {
+	d.pending = append(d.pending, r)
+	sort.Slice(d.pending, func(i, j int) bool {
+		return d.pending[i].from < d.pending[j].from
+	})
+}
+
+func (d *BlockDownloader) fill(now time.Time) error {
+	for len(d.inflight) < d.config.WindowSize && len(d.pending) != 0 {
+		r := d.pending[0]
+		peerId := d.pickPeer(r.lastPeer)
+
+		if err := d.request(peerId, r.from, r.to); err != nil {
+			r.retry++
+			r.lastPeer = peerId.Id
+			if r.retry > d.config.MaxRetry {
+				return ErrDownloadFailed
+			}
+			continue
+		}
+
+		d.pending = d.pending[1:]
+		d.inflight[r.from] = &inflightRange{
+			blockRange: *r,
+			peerId:     peerId,
+			deadline:   now.Add(d.config.Timeout),
+		}
+	}
+
+	return nil
+}
+
+// pickPeer 는 peer 들을 돌아가며 선택한다. 가능하면 이전에 실패한 peer 는 제외한다.
+func (d *BlockDownloader) pickPeer(exclude string) PeerId {
+	for i := 0; i < len(d.peers); i++ {
+		peerId := d.peers[d.peerIndex%len(d.peers)]
+		d.peerIndex++
+
+		if len(d.peers) == 1 || peerId.Id != exclude {
+			return peerId
+		}
+	}
+
+	return d.peers[0]
+}
+
+func (d *BlockDownloader) findInflight(peerId PeerId, height BlockHeight) *inflightRange {
+	for _, r := range d.inflight {
+		if r.peerId.Id == peerId.Id && r.from <= height && height <= r.to {
+			return r
+		}
+	}
+
+	return nil
+}
+
+func (d *BlockDownloader) drain() []Block {
+	blocks := make([]Block, 0)
+
+	for {
+		block, ok := d.received[d.next]
+		if !ok {
+			return blocks
+		}
+
+		blocks = append(blocks, block)
+		delete(d.received, d.next)
+		d.next++
+	}
+}
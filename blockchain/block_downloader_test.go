@@ -0,0 +1,232 @@
+package blockchain_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/it-chain/engine/blockchain"
+	"github.com/stretchr/testify/assert"
+)
+
+type rangeRecorder struct {
+	requests []string
+}
+
+func (r *rangeRecorder) request(peerId blockchain.PeerId, from blockchain.BlockHeight, to blockchain.BlockHeight) error {
+	r.requests = append(r.requests, fmt.Sprintf("%s:%d-%d", peerId.Id, from, to))
+	return nil
+}
+
+func blocksOf(from uint64, to uint64) []blockchain.Block {
+	blocks := make([]blockchain.Block, 0)
+	for height := from; height <= to; height++ {
+		blocks = append(blocks, &blockchain.DefaultBlock{Height: height, Seal: []byte(fmt.Sprintf("seal%d", height))})
+	}
+
+	return blocks
+}
+
+func rangeOf(from uint64, to uint64, blocks []blockchain.Block) blockchain.BlockRangeResponse {
+	return blockchain.BlockRangeResponse{From: from, To: to, Blocks: blocks}
+}
+
+func heightsOf(blocks []blockchain.Block) []uint64 {
+	heights := make([]uint64, 0)
+	for _, block := range blocks {
+		heights = append(heights, block.GetHeight())
+	}
+
+	return heights
+}
+
+func TestNewBlockDownloader(t *testing.T) {
+	tests := map[string]struct {
+		input struct {
+			from  uint64
+			to    uint64
+			peers []blockchain.PeerId
+		}
+		err error
+	}{
+		"success": {
+			input: struct {
+				from  uint64
+				to    uint64
+				peers []blockchain.PeerId
+			}{from: 1, to: 10, peers: []blockchain.PeerId{{Id: "peer1"}}},
+			err: nil,
+		},
+		"invalid range": {
+			input: struct {
+				from  uint64
+				to    uint64
+				peers []blockchain.PeerId
+			}{from: 10, to: 1, peers: []blockchain.PeerId{{Id: "peer1"}}},
+			err: blockchain.ErrInvalidDownloadRange,
+		},
+		"no peer": {
+			input: struct {
+				from  uint64
+				to    uint64
+				peers []blockchain.PeerId
+			}{from: 1, to: 10, peers: []blockchain.PeerId{}},
+			err: blockchain.ErrNoDownloadPeer,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+
+		recorder := &rangeRecorder{}
+		_, err := blockchain.NewBlockDownloader(test.input.from, test.input.to, test.input.peers, recorder.request, blockchain.DefaultDownloadConfig())
+		assert.Equal(t, test.err, err)
+	}
+}
+
+func TestBlockDownloader_Receive(t *testing.T) {
+	// given
+	recorder := &rangeRecorder{}
+	config := blockchain.DownloadConfig{RangeSize: 3, WindowSize: 2, Timeout: time.Second, MaxRetry: 1}
+	peers := []blockchain.PeerId{{Id: "peer1"}, {Id: "peer2"}}
+	now := time.Now()
+
+	downloader, err := blockchain.NewBlockDownloader(1, 8, peers, recorder.request, config)
+	assert.NoError(t, err)
+
+	// when
+	err = downloader.Start(now)
+
+	// then: window 크기만큼 peer 들에게 나누어 요청한다.
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"peer1:1-3", "peer2:4-6"}, recorder.requests)
+
+	// when: 뒤쪽 range 가 먼저 도착
+	blocks, err := downloader.Receive(blockchain.PeerId{Id: "peer2"}, rangeOf(4, 6, blocksOf(4, 6)), now)
+
+	// then: 순서대로 이어지지 않으므로 아직 반환하지 않는다.
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{}, heightsOf(blocks))
+	assert.Equal(t, []string{"peer1:1-3", "peer2:4-6", "peer1:7-8"}, recorder.requests)
+
+	// when: 요청하지 않은 peer 의 block
+	_, err = downloader.Receive(blockchain.PeerId{Id: "peer2"}, rangeOf(1, 3, blocksOf(1, 3)), now)
+
+	// then
+	assert.Equal(t, blockchain.ErrUnrequestedBlock, err)
+
+	// when
+	blocks, err = downloader.Receive(blockchain.PeerId{Id: "peer1"}, rangeOf(1, 3, blocksOf(1, 3)), now)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{1, 2, 3, 4, 5, 6}, heightsOf(blocks))
+	assert.False(t, downloader.Done())
+
+	// when
+	blocks, err = downloader.Receive(blockchain.PeerId{Id: "peer1"}, rangeOf(7, 8, blocksOf(7, 8)), now)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{7, 8}, heightsOf(blocks))
+	assert.True(t, downloader.Done())
+}
+
+func TestBlockDownloader_CheckTimeout(t *testing.T) {
+	// given
+	recorder := &rangeRecorder{}
+	config := blockchain.DownloadConfig{RangeSize: 2, WindowSize: 1, Timeout: time.Second, MaxRetry: 1}
+	peers := []blockchain.PeerId{{Id: "peer1"}, {Id: "peer2"}}
+	now := time.Now()
+
+	downloader, _ := blockchain.NewBlockDownloader(1, 2, peers, recorder.request, config)
+	downloader.Start(now)
+
+	// when: deadline 전
+	err := downloader.CheckTimeout(now.Add(500 * time.Millisecond))
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"peer1:1-2"}, recorder.requests)
+
+	// when: deadline 이 지나면 다른 peer 에게 다시 요청한다.
+	err = downloader.CheckTimeout(now.Add(time.Second))
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"peer1:1-2", "peer2:1-2"}, recorder.requests)
+
+	// when: 늦게 도착한 이전 요청의 응답
+	_, err = downloader.Receive(blockchain.PeerId{Id: "peer1"}, rangeOf(1, 2, blocksOf(1, 2)), now.Add(time.Second))
+
+	// then
+	assert.Equal(t, blockchain.ErrUnrequestedBlock, err)
+
+	// when: 재요청 횟수를 넘김
+	err = downloader.CheckTimeout(now.Add(3 * time.Second))
+
+	// then
+	assert.Equal(t, blockchain.ErrDownloadFailed, err)
+}
+
+func TestBlockDownloader_EmptyResponse(t *testing.T) {
+	// given
+	recorder := &rangeRecorder{}
+	config := blockchain.DownloadConfig{RangeSize: 4, WindowSize: 1, Timeout: time.Second, MaxRetry: 2}
+	peers := []blockchain.PeerId{{Id: "peer1"}, {Id: "peer2"}}
+	now := time.Now()
+
+	downloader, _ := blockchain.NewBlockDownloader(1, 4, peers, recorder.request, config)
+	downloader.Start(now)
+
+	// when: peer1 이 block 을 가지고 있지 않음
+	blocks, err := downloader.Receive(blockchain.PeerId{Id: "peer1"}, rangeOf(1, 4, []blockchain.Block{}), now)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(blocks))
+	assert.Equal(t, []string{"peer1:1-4", "peer2:1-4"}, recorder.requests)
+
+	// when: 중간 block 이 빠진 응답
+	blocks, err = downloader.Receive(blockchain.PeerId{Id: "peer2"}, rangeOf(1, 4, []blockchain.Block{blocksOf(1, 1)[0], blocksOf(3, 4)[0], blocksOf(3, 4)[1]}), now)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{1}, heightsOf(blocks))
+	assert.Equal(t, []string{"peer1:1-4", "peer2:1-4", "peer1:2-2"}, recorder.requests)
+
+	// when
+	blocks, err = downloader.Receive(blockchain.PeerId{Id: "peer1"}, rangeOf(2, 2, blocksOf(2, 2)), now)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{2, 3, 4}, heightsOf(blocks))
+	assert.True(t, downloader.Done())
+}
+
+func TestBlockDownloader_EmptyResponse_OnlyAnsweredRange(t *testing.T) {
+	// given: peer1 이 두 개의 range 를 받고 있다.
+	recorder := &rangeRecorder{}
+	config := blockchain.DownloadConfig{RangeSize: 2, WindowSize: 2, Timeout: time.Second, MaxRetry: 2}
+	peers := []blockchain.PeerId{{Id: "peer1"}}
+	now := time.Now()
+
+	downloader, _ := blockchain.NewBlockDownloader(1, 4, peers, recorder.request, config)
+	downloader.Start(now)
+	assert.Equal(t, []string{"peer1:1-2", "peer1:3-4"}, recorder.requests)
+
+	// when: 3-4 range 에 대한 빈 응답
+	blocks, err := downloader.Receive(blockchain.PeerId{Id: "peer1"}, rangeOf(3, 4, []blockchain.Block{}), now)
+
+	// then: 응답한 range 만 다시 요청하고 1-2 는 계속 기다린다.
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(blocks))
+	assert.Equal(t, []string{"peer1:1-2", "peer1:3-4", "peer1:3-4"}, recorder.requests)
+
+	// when
+	blocks, err = downloader.Receive(blockchain.PeerId{Id: "peer1"}, rangeOf(1, 2, blocksOf(1, 2)), now)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{1, 2}, heightsOf(blocks))
+}
@@ -1,9 +1,6 @@
 package blockchain
 
 import (
-	"encoding/json"
-	"io/ioutil"
-	"os"
 	"time"
 
 	"github.com/it-chain/engine/common"
@@ -11,29 +8,39 @@ import (
 	"github.com/it-chain/midgard"
 )
 
+// CreateGenesisBlock 함수는 genesis 파일로 genesis block 을 만든다.
+// genesis 파일의 seal 이 내용과 맞지 않으면 ErrGenesisSealMismatch 를 반환한다.
 func CreateGenesisBlock(genesisconfFilePath string) (Block, error) {
 
-	//declare
-	GenesisBlock := &DefaultBlock{}
-	validator := DefaultValidator{}
-	TimeStamp := (time.Now()).Round(0)
-
-	//set
-	err := setBlockWithConfig(genesisconfFilePath, GenesisBlock)
+	config, err := LoadGenesisConfig(genesisconfFilePath)
 
 	if err != nil {
 		return nil, ErrSetConfig
 	}
 
+	if err := VerifyGenesisConfig(config); err != nil {
+		return nil, err
+	}
+
+	GenesisBlock, err := createGenesisBlock(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return GenesisBlock, nil
+}
+
+func createGenesisBlock(config GenesisConfig) (*DefaultBlock, error) {
+
 	//build
-	Seal, err := validator.BuildSeal(TimeStamp, GenesisBlock.PrevSeal, GenesisBlock.TxSeal, GenesisBlock.Creator)
+	block, err := NewGenesisBlock(config)
 
 	if err != nil {
 		return nil, ErrBuildingSeal
 	}
 
 	//create
-	createEvent, err := createBlockCreatedEvent(Seal, GenesisBlock.PrevSeal, GenesisBlock.Height, convertTxType(GenesisBlock.TxList), GenesisBlock.TxSeal, TimeStamp, GenesisBlock.Creator)
+	createEvent, err := createBlockCreatedEvent(block.GetSeal(), block.GetHeader(), convertTxType(block.TxList), block.TxSeal, nil)
 	if err != nil {
 		return nil, ErrCreatingEvent
 	}
@@ -42,6 +49,7 @@ func CreateGenesisBlock(genesisconfFilePath string) (Block, error) {
 	eventstore.Save(createEvent.GetID(), createEvent)
 
 	//on
+	GenesisBlock := &DefaultBlock{}
 	err = GenesisBlock.On(createEvent)
 	if err != nil {
 		return nil, ErrOnEvent
@@ -50,27 +58,7 @@ func CreateGenesisBlock(genesisconfFilePath string) (Block, error) {
 	return GenesisBlock, nil
 }
 
-func setBlockWithConfig(filePath string, block Block) error {
-	jsonFile, err := os.Open(filePath)
-	defer jsonFile.Close()
-	if err != nil {
-		return err
-	}
-
-	byteValue, err := ioutil.ReadAll(jsonFile)
-	if err != nil {
-		return err
-	}
-
-	err = json.Unmarshal(byteValue, block)
-	if err != nil {
-		return err
-	}
-
-	return nil
-}
-
-func createBlockCreatedEvent(seal []byte, prevSeal []byte, height uint64, txList []Transaction, txSeal [][]byte, timeStamp time.Time, creator []byte) (*BlockCreatedEvent, error) {
+func createBlockCreatedEvent(seal []byte, header BlockHeader, txList []Transaction, txSeal [][]byte, signature []byte) (*BlockCreatedEvent, error) {
 	txListBytes, err := common.Serialize(txList)
 
 	if err != nil {
@@ -82,17 +70,34 @@ func createBlockCreatedEvent(seal []byte, prevSeal []byte, height uint64, txList
 			ID:   string(seal),
 			Type: "block.created",
 		},
-		Seal:      seal,
-		PrevSeal:  prevSeal,
-		Height:    height,
-		TxList:    txListBytes,
-		TxSeal:    txSeal,
-		Timestamp: timeStamp,
-		Creator:   creator,
+		Seal:         seal,
+		PrevSeal:     header.PrevSeal,
+		Height:       header.Height,
+		TxList:       txListBytes,
+		TxSeal:       txSeal,
+		Timestamp:    header.Timestamp,
+		Creator:      header.Creator,
+		Version:      header.Version,
+		StateRoot:    header.StateRoot,
+		ReceiptsRoot: header.ReceiptsRoot,
+		Signature:    signature,
 	}, nil
 }
 
-func CreateProposedBlock(prevSeal []byte, height uint64, txList []Transaction, Creator []byte) (Block, error) {
+// CreateProposedBlock 함수는 block 을 만들고 signer 로 seal 에 서명한다.
+// 이전 block 의 receipts root 를 모를 때 사용한다.
+func CreateProposedBlock(prevSeal []byte, height uint64, txList []Transaction, Creator []byte, signer Signer) (Block, error) {
+	return CreateProposedBlockWithReceiptsRoot(prevSeal, height, nil, txList, Creator, signer)
+}
+
+// CreateProposedBlockWithReceiptsRoot 함수는 이전 block 의 receipts root 를 header 에 담아 block 을 만들고 signer 로 seal 에 서명한다.
+func CreateProposedBlockWithReceiptsRoot(prevSeal []byte, height uint64, receiptsRoot []byte, txList []Transaction, Creator []byte, signer Signer) (Block, error) {
+	return CreateProposedBlockWithRoots(prevSeal, height, nil, receiptsRoot, txList, Creator, signer)
+}
+
+// CreateProposedBlockWithRoots 함수는 state root 와 이전 block 의 receipts root 를 header 에 담아 block 을 만들고 signer 로 seal 에 서명한다.
+// snapshot height 의 block 은 stateRoot 로 SnapshotStateRoot 를 담는다.
+func CreateProposedBlockWithRoots(prevSeal []byte, height uint64, stateRoot []byte, receiptsRoot []byte, txList []Transaction, Creator []byte, signer Signer) (Block, error) {
 
 	//declare
 	ProposedBlock := &DefaultBlock{}
@@ -106,14 +111,32 @@ func CreateProposedBlock(prevSeal []byte, height uint64, txList []Transaction, C
 		return nil, ErrBuildingTxSeal
 	}
 
-	Seal, err := validator.BuildSeal(TimeStamp, prevSeal, txSeal, Creator)
+	header := BlockHeader{
+		Version:      CurrentBlockVersion,
+		Height:       height,
+		PrevSeal:     prevSeal,
+		TxRoot:       txRoot(txSeal),
+		Timestamp:    TimeStamp,
+		Creator:      Creator,
+		StateRoot:    stateRoot,
+		ReceiptsRoot: receiptsRoot,
+	}
+
+	Seal, err := validator.BuildHeaderSeal(header)
 
 	if err != nil {
 		return nil, ErrBuildingSeal
 	}
 
+	//sign
+	Signature, err := signer.Sign(Seal)
+
+	if err != nil {
+		return nil, ErrSigningBlock
+	}
+
 	//create
-	createEvent, err := createBlockCreatedEvent(Seal, prevSeal, height, txList, txSeal, TimeStamp, Creator)
+	createEvent, err := createBlockCreatedEvent(Seal, header, txList, txSeal, Signature)
 	if err != nil {
 		return nil, ErrCreatingEvent
 	}
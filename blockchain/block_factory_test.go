@@ -1,14 +1,14 @@
 package blockchain_test
 
 import (
+	"errors"
 	"testing"
 
-	"io/ioutil"
-	"log"
 	"os"
 	"time"
 
 	"github.com/it-chain/engine/blockchain"
+	"github.com/it-chain/engine/blockchain/test/mock"
 	"github.com/it-chain/engine/core/eventstore"
 	"github.com/it-chain/midgard"
 	"github.com/stretchr/testify/assert"
@@ -53,12 +53,25 @@ func TestCreateGenesisBlock(t *testing.T) {
 				TxList:    make([]*blockchain.DefaultTransaction, 0),
 				TxSeal:    make([][]byte, 0),
 				Timestamp: (time.Now()).Round(0),
-				Creator:   make([]byte, 0),
+				Creator:   []byte("creator"),
 			},
 
 			err: nil,
 		},
 
+		"fail create genesisBlock: seal mismatch": {
+
+			input: struct {
+				ConfigFilePath string
+			}{
+				ConfigFilePath: "./TamperedBlockConfig.json",
+			},
+
+			output: nil,
+
+			err: blockchain.ErrGenesisSealMismatch,
+		},
+
 		"fail create genesisBlock: wrong file path": {
 
 			input: struct {
@@ -85,24 +98,17 @@ func TestCreateGenesisBlock(t *testing.T) {
 	defer eventstore.Close()
 
 	GenesisFilePath := "./GenesisBlockConfig.json"
+	TamperedFilePath := "./TamperedBlockConfig.json"
 
 	defer os.Remove(GenesisFilePath)
+	defer os.Remove(TamperedFilePath)
 
-	GenesisBlockConfigJson := []byte(`{
-								  "Seal":[],
-								  "PrevSeal":[],
-								  "Height":0,
-								  "TxList":[],
-								  "TxSeal":[],
-								  "TimeStamp":"0001-01-01T00:00:00-00:00",
-								  "Creator":[]
-								}`)
+	GenesisConfig, err := blockchain.NewGenesisConfig("chain", "creator", []string{"creator"}, time.Now())
+	assert.NoError(t, err)
+	assert.NoError(t, blockchain.SaveGenesisConfig(GenesisFilePath, GenesisConfig))
 
-	err := ioutil.WriteFile(GenesisFilePath, GenesisBlockConfigJson, 0644)
-
-	if err != nil {
-		log.Println(err.Error())
-	}
+	GenesisConfig.Parliament = append(GenesisConfig.Parliament, "intruder")
+	assert.NoError(t, blockchain.SaveGenesisConfig(TamperedFilePath, GenesisConfig))
 
 	for testName, test := range tests {
 		t.Logf("Running test case %s", testName)
@@ -224,6 +230,11 @@ func TestCreateProposedBlock(t *testing.T) {
 	eventstore.InitForMock(repo)
 	defer eventstore.Close()
 
+	signer := mock.Signer{}
+	signer.SignFunc = func(data []byte) ([]byte, error) {
+		return append([]byte("signed:"), data...), nil
+	}
+
 	for testName, test := range tests {
 
 		t.Logf("Running test case %s", testName)
@@ -234,6 +245,7 @@ func TestCreateProposedBlock(t *testing.T) {
 			test.input.height,
 			test.input.txList,
 			test.input.creator,
+			signer,
 		)
 
 		//then
@@ -249,6 +261,28 @@ func TestCreateProposedBlock(t *testing.T) {
 		assert.Equal(t, test.output.GetTxList(), ProposedBlock.GetTxList())
 		assert.Equal(t, test.output.GetTimestamp().String()[:19], ProposedBlock.GetTimestamp().String()[:19])
 		assert.Equal(t, test.output.GetCreator(), ProposedBlock.GetCreator())
+		assert.Equal(t, blockchain.CurrentBlockVersion, ProposedBlock.(*blockchain.DefaultBlock).GetVersion())
+		assert.Equal(t, append([]byte("signed:"), ProposedBlock.GetSeal()...), ProposedBlock.(*blockchain.DefaultBlock).GetSignature())
+
+		validator := blockchain.DefaultValidator{}
+		valid, err := validator.ValidateSeal(ProposedBlock.GetSeal(), ProposedBlock)
+		assert.NoError(t, err)
+		assert.True(t, valid)
 	}
 
 }
+
+func TestCreateProposedBlock_SignError(t *testing.T) {
+	// given
+	signer := mock.Signer{}
+	signer.SignFunc = func(data []byte) ([]byte, error) {
+		return nil, errors.New("sign error")
+	}
+
+	// when
+	ProposedBlock, err := blockchain.CreateProposedBlock([]byte("prevseal"), 1, []blockchain.Transaction{&blockchain.DefaultTransaction{}}, []byte("junksound"), signer)
+
+	// then
+	assert.Equal(t, blockchain.ErrSigningBlock, err)
+	assert.Nil(t, ProposedBlock)
+}
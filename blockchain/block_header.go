@@ -0,0 +1,100 @@
+package blockchain
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrUnsupportedBlockVersion = errors.New("unsupported block version")
+var ErrBlockVersionDowngrade = errors.New("block version is lower than previous block version")
+
+type BlockVersion = uint32
+
+const (
+	// LegacyBlockVersion 의 seal 은 prevSeal, tx root, timestamp 만 hash 한다.
+	LegacyBlockVersion BlockVersion = 0
+
+	// HeaderBlockVersion 의 seal 은 BlockHeader 의 ReceiptsRoot 를 제외한 모든 field 를 hash 한다.
+	HeaderBlockVersion BlockVersion = 1
+
+	// ReceiptBlockVersion 의 seal 은 ReceiptsRoot 까지 hash 한다.
+	ReceiptBlockVersion BlockVersion = 2
+
+	CurrentBlockVersion = ReceiptBlockVersion
+)
+
+// VersionedBlock 은 version 과 state root, receipts root 를 가지는 block 이다.
+// 이 interface 를 구현하지 않는 block 은 LegacyBlockVersion 으로 취급한다.
+type VersionedBlock interface {
+	GetVersion() BlockVersion
+	GetStateRoot() []byte
+	GetReceiptsRoot() []byte
+}
+
+// BlockHeader 는 block seal 이 commit 하는 field 들의 모음이다.
+// transaction 은 block 이 commit 된 다음 실행되므로 ReceiptsRoot 는 이전 block 의 receipts root 이다.
+type BlockHeader struct {
+	Version      BlockVersion
+	Height       BlockHeight
+	PrevSeal     []byte
+	TxRoot       []byte
+	Timestamp    time.Time
+	Creator      []byte
+	StateRoot    []byte
+	ReceiptsRoot []byte
+}
+
+func NewBlockHeader(block Block) BlockHeader {
+	header := BlockHeader{
+		Version:   blockVersion(block),
+		Height:    block.GetHeight(),
+		PrevSeal:  block.GetPrevSeal(),
+		TxRoot:    txRoot(block.GetTxSeal()),
+		Timestamp: block.GetTimestamp(),
+		Creator:   block.GetCreator(),
+	}
+
+	if v, ok := block.(VersionedBlock); ok {
+		header.StateRoot = v.GetStateRoot()
+		header.ReceiptsRoot = v.GetReceiptsRoot()
+	}
+
+	return header
+}
+
+// Bytes 함수는 header 를 고정된 순서의 binary 로 encoding 한다.
+// 가변 길이 field 는 4 byte 길이 prefix 를 붙여서 field 경계가 모호하지 않게 한다.
+// ReceiptsRoot 는 ReceiptBlockVersion 부터 포함되므로 이전 version 의 seal 은 그대로 유지된다.
+func (header BlockHeader) Bytes() []byte {
+	buf := make([]byte, 0, 68+len(header.PrevSeal)+len(header.TxRoot)+len(header.Creator)+len(header.StateRoot)+len(header.ReceiptsRoot))
+
+	buf = appendUint32(buf, header.Version)
+	buf = appendUint64(buf, header.Height)
+	buf = appendLengthPrefixed(buf, header.PrevSeal)
+	buf = appendLengthPrefixed(buf, header.TxRoot)
+	buf = appendTime(buf, header.Timestamp)
+	buf = appendLengthPrefixed(buf, header.Creator)
+	buf = appendLengthPrefixed(buf, header.StateRoot)
+
+	if header.Version >= ReceiptBlockVersion {
+		buf = appendLengthPrefixed(buf, header.ReceiptsRoot)
+	}
+
+	return buf
+}
+
+func blockVersion(block Block) BlockVersion {
+	if v, ok := block.(VersionedBlock); ok {
+		return v.GetVersion()
+	}
+
+	return LegacyBlockVersion
+}
+
+func txRoot(txSeal [][]byte) []byte {
+	if len(txSeal) == 0 {
+		return make([]byte, 0)
+	}
+
+	return txSeal[0]
+}
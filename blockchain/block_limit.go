@@ -0,0 +1,90 @@
+package blockchain
+
+import "errors"
+
+var ErrTooManyTransactions = errors.New("block has more transactions than the limit")
+var ErrBlockTooLarge = errors.New("block transactions exceed the byte size limit")
+var ErrTransactionTooLarge = errors.New("transaction exceeds the byte size limit")
+
+// BlockLimit 은 block 하나에 담을 수 있는 transaction 의 개수와 serialize 된 transaction 의 byte 합의 한도이다.
+// MaxTransactionBytes 는 transaction 하나의 byte 한도이다. 0 인 한도는 제한하지 않는다.
+type BlockLimit struct {
+	MaxTransactions     int
+	MaxBytes            int
+	MaxTransactionBytes int
+}
+
+// Check 는 txList 가 한도 안에 있는지 확인한다. 다른 peer 가 만든 block 을 검증할 때 사용한다.
+func (l BlockLimit) Check(txList []Transaction) error {
+	if l.MaxTransactions > 0 && len(txList) > l.MaxTransactions {
+		return ErrTooManyTransactions
+	}
+
+	if l.MaxBytes <= 0 && l.MaxTransactionBytes <= 0 {
+		return nil
+	}
+
+	total := 0
+	for _, tx := range txList {
+		size, err := txSize(tx)
+		if err != nil {
+			return err
+		}
+
+		if l.MaxTransactionBytes > 0 && size > l.MaxTransactionBytes {
+			return ErrTransactionTooLarge
+		}
+
+		total += size
+		if l.MaxBytes > 0 && total > l.MaxBytes {
+			return ErrBlockTooLarge
+		}
+	}
+
+	return nil
+}
+
+// Split 은 txList 를 순서대로 한도 안에 들어가는 fit 과 다음 block 으로 넘길 overflow 로 나눈다.
+// MaxTransactionBytes 나 혼자서도 MaxBytes 를 넘는 transaction 은 어떤 block 에도 담을 수 없으므로 oversized 로 따로 반환한다.
+func (l BlockLimit) Split(txList []Transaction) (fit []Transaction, overflow []Transaction, oversized []Transaction, err error) {
+	fit = make([]Transaction, 0)
+	overflow = make([]Transaction, 0)
+	oversized = make([]Transaction, 0)
+
+	total := 0
+	for _, tx := range txList {
+		size, err := txSize(tx)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+
+		if (l.MaxTransactionBytes > 0 && size > l.MaxTransactionBytes) || (l.MaxBytes > 0 && size > l.MaxBytes) {
+			oversized = append(oversized, tx)
+			continue
+		}
+
+		// 한번 넘치면 순서를 지키기 위해 나머지는 모두 다음 block 으로 넘긴다.
+		full := len(overflow) > 0 ||
+			(l.MaxTransactions > 0 && len(fit) >= l.MaxTransactions) ||
+			(l.MaxBytes > 0 && total+size > l.MaxBytes)
+
+		if full {
+			overflow = append(overflow, tx)
+			continue
+		}
+
+		fit = append(fit, tx)
+		total += size
+	}
+
+	return fit, overflow, oversized, nil
+}
+
+func txSize(tx Transaction) (int, error) {
+	serialized, err := tx.Serialize()
+	if err != nil {
+		return 0, err
+	}
+
+	return len(serialized), nil
+}
@@ -0,0 +1,89 @@
+package blockchain_test
+
+import (
+	"testing"
+
+	"github.com/it-chain/engine/blockchain"
+	"github.com/stretchr/testify/assert"
+)
+
+func txSize(t *testing.T, tx blockchain.Transaction) int {
+	serialized, err := tx.Serialize()
+	assert.NoError(t, err)
+
+	return len(serialized)
+}
+
+func txIDs(txList []blockchain.Transaction) []string {
+	ids := make([]string, 0, len(txList))
+	for _, tx := range txList {
+		ids = append(ids, tx.GetID())
+	}
+
+	return ids
+}
+
+func TestBlockLimit_Split(t *testing.T) {
+	small1 := &blockchain.DefaultTransaction{ID: "s1"}
+	small2 := &blockchain.DefaultTransaction{ID: "s2"}
+	small3 := &blockchain.DefaultTransaction{ID: "s3"}
+	large := &blockchain.DefaultTransaction{ID: "large", PeerID: string(make([]byte, 512))}
+	txList := []blockchain.Transaction{small1, large, small2, small3}
+
+	smallSize := txSize(t, small1)
+	largeSize := txSize(t, large)
+
+	tests := map[string]struct {
+		input     blockchain.BlockLimit
+		fit       []string
+		overflow  []string
+		oversized []string
+	}{
+		"no limit": {
+			input:     blockchain.BlockLimit{},
+			fit:       []string{"s1", "large", "s2", "s3"},
+			overflow:  []string{},
+			oversized: []string{},
+		},
+		"transaction count": {
+			input:     blockchain.BlockLimit{MaxTransactions: 2},
+			fit:       []string{"s1", "large"},
+			overflow:  []string{"s2", "s3"},
+			oversized: []string{},
+		},
+		"byte size keeps order": {
+			input:     blockchain.BlockLimit{MaxBytes: smallSize + largeSize},
+			fit:       []string{"s1", "large"},
+			overflow:  []string{"s2", "s3"},
+			oversized: []string{},
+		},
+		"oversized transaction": {
+			input:     blockchain.BlockLimit{MaxBytes: smallSize * 2},
+			fit:       []string{"s1", "s2"},
+			overflow:  []string{"s3"},
+			oversized: []string{"large"},
+		},
+		"transaction byte size": {
+			input:     blockchain.BlockLimit{MaxBytes: smallSize + largeSize, MaxTransactionBytes: smallSize},
+			fit:       []string{"s1", "s2", "s3"},
+			overflow:  []string{},
+			oversized: []string{"large"},
+		},
+	}
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+
+		// when
+		fit, overflow, oversized, err := test.input.Split(txList)
+
+		// then
+		assert.NoError(t, err)
+		assert.Equal(t, test.fit, txIDs(fit))
+		assert.Equal(t, test.overflow, txIDs(overflow))
+		assert.Equal(t, test.oversized, txIDs(oversized))
+
+		// fit 은 항상 한도를 지킨다.
+		assert.NoError(t, test.input.Check(fit))
+	}
+}
@@ -1,34 +1,118 @@
 package blockchain
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
+	"log"
+	"sort"
+	"time"
 
 	"github.com/it-chain/engine/common"
 	"github.com/it-chain/engine/core/eventstore"
 	"github.com/it-chain/midgard"
 )
 
+// BlockPool 은 commit 되기 전의 block 들을 보관한다.
+// fork 가 생기면 같은 height 에 여러 block 이 있을 수 있으므로 block 은 seal 로 구분한다.
 type BlockPool interface {
 	Add(block Block) error
-	Get(height BlockHeight) Block
-	Delete(height Block)
+	GetByHeight(height BlockHeight) []Block
+	GetBySeal(seal []byte) Block
+	GetByPrevSeal(prevSeal []byte) []Block
+	Delete(block Block)
+	Size() int
+
+	// Prune 은 pool 의 한도를 벗어난 block 들을 제거하고 반환한다.
+	Prune(tipHeight BlockHeight, now time.Time) []Block
 }
 
 var BLOCK_POOL_AID = "BLOCK_POOL_AID"
 
+// PoolConfig 는 block pool 의 한도이다. 0 인 값은 제한하지 않는다.
+type PoolConfig struct {
+	// pool 이 보관하는 최대 block 수
+	MaxBlocks int
+	// 마지막 block 과 pool 의 block 사이의 최대 height 차이
+	MaxHeightDistance uint64
+	// pool 에 추가된 block 을 보관하는 시간
+	Expiration time.Duration
+	// 마지막 compaction 이후 pool 의 event 가 이만큼 쌓이면 snapshot 으로 새 stream 을 시작한다.
+	CompactThreshold int
+}
+
+func DefaultPoolConfig() PoolConfig {
+	return PoolConfig{
+		MaxBlocks:         1000,
+		MaxHeightDistance: 100,
+		Expiration:        10 * time.Minute,
+		CompactThreshold:  1000,
+	}
+}
+
+// Pool 은 height 별로 seal(string) 을 key 로 block 을 가진다.
 type BlockPoolModel struct {
 	midgard.AggregateModel
-	Pool map[BlockHeight]Block
+	Pool map[BlockHeight]map[string]Block
+
+	config PoolConfig
+	// block 이 pool 에 추가된 시각. key 는 seal(string) 이다.
+	addedAt map[string]time.Time
+	// 마지막 compaction 이후 적용된 event 수
+	eventCount int
+	// 현재 event 를 저장하는 stream 의 세대. compaction 할 때마다 1 씩 늘어난다.
+	generation uint64
 }
 
 func NewBlockPool() *BlockPoolModel {
+	return NewBlockPoolWithConfig(DefaultPoolConfig())
+}
+
+func NewBlockPoolWithConfig(config PoolConfig) *BlockPoolModel {
 	return &BlockPoolModel{
 		AggregateModel: midgard.AggregateModel{
 			ID: BLOCK_POOL_AID,
 		},
-		Pool: make(map[BlockHeight]Block),
+		Pool:    make(map[BlockHeight]map[string]Block),
+		config:  config,
+		addedAt: make(map[string]time.Time),
+	}
+}
+
+// LoadBlockPool 은 eventstore 에 저장된 block pool 의 event 들을 적용해서 재시작 전의 pool 을 복원한다.
+// BLOCK_POOL_AID stream 에서 마지막 세대를 찾은 다음, compaction 된 적이 있으면 그 세대의 stream 만 적용한다.
+// 마지막 세대의 stream 은 snapshot 으로 시작하므로 이전 세대의 event 는 다시 적용하지 않는다.
+func LoadBlockPool(config PoolConfig) (*BlockPoolModel, error) {
+	pool := NewBlockPoolWithConfig(config)
+	if err := eventstore.Load(pool, BLOCK_POOL_AID); err != nil {
+		return nil, err
+	}
+
+	if pool.generation == 0 {
+		return pool, nil
+	}
+
+	latest := NewBlockPoolWithConfig(config)
+	latest.generation = pool.generation
+	if err := eventstore.Load(latest, latest.streamID()); err != nil {
+		return nil, err
 	}
+
+	return latest, nil
+}
+
+// BlockPoolStreamID 는 generation 세대의 block pool event 가 저장되는 stream 이다.
+// 첫 세대는 BLOCK_POOL_AID 이고, BLOCK_POOL_AID 에는 세대가 바뀔 때마다 BlockPoolRotatedEvent 가 저장된다.
+func BlockPoolStreamID(generation uint64) string {
+	if generation == 0 {
+		return BLOCK_POOL_AID
+	}
+
+	return fmt.Sprintf("%s_%d", BLOCK_POOL_AID, generation)
+}
+
+func (p *BlockPoolModel) streamID() string {
+	return BlockPoolStreamID(p.generation)
 }
 
 func (p *BlockPoolModel) Add(block Block) error {
@@ -37,22 +121,175 @@ func (p *BlockPoolModel) Add(block Block) error {
 		return err
 	}
 
-	eventstore.Save(BLOCK_POOL_AID, event)
+	eventstore.Save(p.streamID(), event)
 
 	p.On(&event)
 
+	return p.compactIfNeeded()
+}
+
+// GetByHeight 는 height 의 모든 후보 block 을 seal 순서로 반환한다.
+func (p *BlockPoolModel) GetByHeight(height BlockHeight) []Block {
+	return sortBySeal(p.Pool[height])
+}
+
+func (p *BlockPoolModel) GetBySeal(seal []byte) Block {
+	for _, candidates := range p.Pool {
+		if block, ok := candidates[string(seal)]; ok {
+			return block
+		}
+	}
 	return nil
 }
 
-func (p *BlockPoolModel) Get(height BlockHeight) Block {
-	return p.Pool[height]
+// GetByPrevSeal 은 prevSeal 의 block 에 이어지는 block 들을 seal 순서로 반환한다.
+func (p *BlockPoolModel) GetByPrevSeal(prevSeal []byte) []Block {
+	children := make(map[string]Block)
+	for _, candidates := range p.Pool {
+		for seal, block := range candidates {
+			if bytes.Equal(block.GetPrevSeal(), prevSeal) {
+				children[seal] = block
+			}
+		}
+	}
+	return sortBySeal(children)
 }
 
 func (p *BlockPoolModel) Delete(block Block) {
 	event := createBlockRemoveFromPoolEvent(block)
-	eventstore.Save(BLOCK_POOL_AID, event)
+	eventstore.Save(p.streamID(), event)
 
 	p.On(&event)
+
+	if err := p.compactIfNeeded(); err != nil {
+		log.Printf("failed to compact block pool: %s", err.Error())
+	}
+}
+
+func (p *BlockPoolModel) Size() int {
+	size := 0
+	for _, candidates := range p.Pool {
+		size += len(candidates)
+	}
+	return size
+}
+
+// Prune 은 만료되었거나 tipHeight 와의 height 차이가 MaxHeightDistance 보다 큰 block 을 제거한다.
+// 남은 block 이 MaxBlocks 보다 많으면 tipHeight 에서 먼 block, 먼저 추가된 block 순서로 제거한다.
+func (p *BlockPoolModel) Prune(tipHeight BlockHeight, now time.Time) []Block {
+	evicted := make([]Block, 0)
+	remaining := make([]Block, 0)
+
+	for height := range p.Pool {
+		for _, block := range p.GetByHeight(height) {
+			if p.isExpired(block, now) || p.isTooFar(block, tipHeight) {
+				evicted = append(evicted, block)
+				continue
+			}
+			remaining = append(remaining, block)
+		}
+	}
+
+	if p.config.MaxBlocks > 0 && len(remaining) > p.config.MaxBlocks {
+		sort.Slice(remaining, func(i, j int) bool {
+			di := heightDistance(remaining[i].GetHeight(), tipHeight)
+			dj := heightDistance(remaining[j].GetHeight(), tipHeight)
+			if di != dj {
+				return di < dj
+			}
+
+			ti := p.addedAt[string(remaining[i].GetSeal())]
+			tj := p.addedAt[string(remaining[j].GetSeal())]
+			if !ti.Equal(tj) {
+				return ti.After(tj)
+			}
+
+			return bytes.Compare(remaining[i].GetSeal(), remaining[j].GetSeal()) < 0
+		})
+
+		evicted = append(evicted, remaining[p.config.MaxBlocks:]...)
+	}
+
+	sort.Slice(evicted, func(i, j int) bool {
+		if evicted[i].GetHeight() != evicted[j].GetHeight() {
+			return evicted[i].GetHeight() < evicted[j].GetHeight()
+		}
+		return bytes.Compare(evicted[i].GetSeal(), evicted[j].GetSeal()) < 0
+	})
+
+	for _, block := range evicted {
+		p.Delete(block)
+	}
+
+	return evicted
+}
+
+func (p *BlockPoolModel) isExpired(block Block, now time.Time) bool {
+	if p.config.Expiration <= 0 {
+		return false
+	}
+
+	addedAt, ok := p.addedAt[string(block.GetSeal())]
+	return ok && now.Sub(addedAt) > p.config.Expiration
+}
+
+func (p *BlockPoolModel) isTooFar(block Block, tipHeight BlockHeight) bool {
+	if p.config.MaxHeightDistance == 0 {
+		return false
+	}
+
+	return heightDistance(block.GetHeight(), tipHeight) > p.config.MaxHeightDistance
+}
+
+// compactIfNeeded 는 마지막 compaction 이후 CompactThreshold 개 이상의 event 가 쌓였으면
+// 현재 pool 의 block 들을 하나의 BlockPoolCompactedEvent 로 다음 세대의 stream 에 저장하고, 이후 event 도 그 stream 에 저장한다.
+// snapshot 을 저장한 다음 BlockPoolRotatedEvent 로 세대를 바꾸므로, 그 사이에 멈추더라도 이전 세대로 복원할 수 있다.
+// 세대를 바꾼 다음에는 더 이상 읽지 않는 이전 세대의 stream 을 지운다.
+// BLOCK_POOL_AID 는 세대를 기록하므로 지우지 않는다.
+func (p *BlockPoolModel) compactIfNeeded() error {
+	if p.config.CompactThreshold <= 0 || p.eventCount < p.config.CompactThreshold {
+		return nil
+	}
+
+	event, err := createBlockPoolCompactedEvent(p)
+	if err != nil {
+		return err
+	}
+
+	previous := p.generation
+	next := previous + 1
+	if err := eventstore.Save(BlockPoolStreamID(next), event); err != nil {
+		return err
+	}
+
+	rotated := createBlockPoolRotatedEvent(next)
+	if err := eventstore.Save(BLOCK_POOL_AID, rotated); err != nil {
+		return err
+	}
+
+	if err := p.On(&rotated); err != nil {
+		return err
+	}
+
+	if err := p.On(&event); err != nil {
+		return err
+	}
+
+	// pool 은 이미 새 세대로 복원되므로 이전 세대를 지우지 못해도 compaction 은 성공한 것이다.
+	if previous > 0 {
+		if err := eventstore.Delete(BlockPoolStreamID(previous)); err != nil {
+			log.Printf("failed to delete block pool stream [%s]: %s", BlockPoolStreamID(previous), err.Error())
+		}
+	}
+
+	return nil
+}
+
+func heightDistance(height BlockHeight, tipHeight BlockHeight) uint64 {
+	if height > tipHeight {
+		return height - tipHeight
+	}
+	return tipHeight - height
 }
 
 func createBlockAddToPoolEvent(block Block) (BlockAddToPoolEvent, error) {
@@ -61,26 +298,85 @@ func createBlockAddToPoolEvent(block Block) (BlockAddToPoolEvent, error) {
 		return BlockAddToPoolEvent{}, ErrTxListMarshal
 	}
 
+	header := NewBlockHeader(block)
+
+	var signature []byte
+	if signed, ok := block.(SignedBlock); ok {
+		signature = signed.GetSignature()
+	}
+
 	return BlockAddToPoolEvent{
 		EventModel: midgard.EventModel{
-			ID: BLOCK_POOL_AID,
+			ID:   BLOCK_POOL_AID,
+			Type: "blockpool.added",
+			Time: time.Now(),
 		},
-		Seal:      block.GetSeal(),
-		PrevSeal:  block.GetPrevSeal(),
-		Height:    block.GetHeight(),
-		TxList:    txListBytes,
-		TxSeal:    block.GetTxSeal(),
-		Timestamp: block.GetTimestamp(),
-		Creator:   block.GetCreator(),
+		Seal:         block.GetSeal(),
+		PrevSeal:     block.GetPrevSeal(),
+		Height:       block.GetHeight(),
+		TxList:       txListBytes,
+		TxSeal:       block.GetTxSeal(),
+		Timestamp:    block.GetTimestamp(),
+		Creator:      block.GetCreator(),
+		Version:      header.Version,
+		StateRoot:    header.StateRoot,
+		ReceiptsRoot: header.ReceiptsRoot,
+		Signature:    signature,
 	}, nil
 }
 
 func createBlockRemoveFromPoolEvent(block Block) BlockRemoveFromPoolEvent {
 	return BlockRemoveFromPoolEvent{
 		EventModel: midgard.EventModel{
-			ID: BLOCK_POOL_AID,
+			ID:   BLOCK_POOL_AID,
+			Type: "blockpool.removed",
 		},
 		Height: block.GetHeight(),
+		Seal:   block.GetSeal(),
+	}
+}
+
+func createBlockPoolCompactedEvent(p *BlockPoolModel) (BlockPoolCompactedEvent, error) {
+	blocks := make([]Block, 0, p.Size())
+	addedAt := make([]time.Time, 0, p.Size())
+
+	heights := make([]BlockHeight, 0, len(p.Pool))
+	for height := range p.Pool {
+		heights = append(heights, height)
+	}
+	sort.Slice(heights, func(i, j int) bool { return heights[i] < heights[j] })
+
+	for _, height := range heights {
+		for _, block := range p.GetByHeight(height) {
+			blocks = append(blocks, block)
+			addedAt = append(addedAt, p.addedAt[string(block.GetSeal())])
+		}
+	}
+
+	encoded, err := EncodeBlockList(blocks)
+	if err != nil {
+		return BlockPoolCompactedEvent{}, err
+	}
+
+	return BlockPoolCompactedEvent{
+		EventModel: midgard.EventModel{
+			ID:   BLOCK_POOL_AID,
+			Type: "blockpool.compacted",
+			Time: time.Now(),
+		},
+		Blocks:  encoded,
+		AddedAt: addedAt,
+	}, nil
+}
+
+func createBlockPoolRotatedEvent(generation uint64) BlockPoolRotatedEvent {
+	return BlockPoolRotatedEvent{
+		EventModel: midgard.EventModel{
+			ID:   BLOCK_POOL_AID,
+			Type: "blockpool.rotated",
+			Time: time.Now(),
+		},
+		Generation: generation,
 	}
 }
 
@@ -96,17 +392,69 @@ func (p *BlockPoolModel) On(event midgard.Event) error {
 		if err != nil {
 			return err
 		}
-		(p.Pool)[v.Height] = block
+		p.put(block, v.Time)
 
 	case *BlockRemoveFromPoolEvent:
-		delete(p.Pool, v.Height)
+		// Seal 이 없는 event 는 height 의 모든 후보를 제거한다.
+		if len(v.Seal) == 0 {
+			for seal := range p.Pool[v.Height] {
+				delete(p.addedAt, seal)
+			}
+			delete(p.Pool, v.Height)
+			break
+		}
+
+		delete(p.Pool[v.Height], string(v.Seal))
+		delete(p.addedAt, string(v.Seal))
+		if len(p.Pool[v.Height]) == 0 {
+			delete(p.Pool, v.Height)
+		}
+
+	case *BlockPoolCompactedEvent:
+		blocks, err := DecodeBlockList(v.Blocks)
+		if err != nil {
+			return err
+		}
+
+		if len(blocks) != len(v.AddedAt) {
+			return ErrOnEvent
+		}
+
+		p.Pool = make(map[BlockHeight]map[string]Block)
+		p.addedAt = make(map[string]time.Time)
+		for i, block := range blocks {
+			p.put(block, v.AddedAt[i])
+		}
+
+		// snapshot 이전의 event 는 더 이상 pool 상태에 영향을 주지 않는다.
+		p.eventCount = 0
+
+	case *BlockPoolRotatedEvent:
+		p.generation = v.Generation
 
 	default:
 		return errors.New(fmt.Sprintf("unhandled event [%s]", v))
 	}
+
+	p.eventCount++
+
 	return nil
 }
 
+// put 은 block 을 pool 에 넣고 추가된 시각을 기록한다.
+// 시각이 없는 event 로 추가된 block 은 적용한 시각부터 만료 시간을 센다.
+func (p *BlockPoolModel) put(block Block, addedAt time.Time) {
+	if addedAt.IsZero() {
+		addedAt = time.Now()
+	}
+
+	if p.Pool[block.GetHeight()] == nil {
+		p.Pool[block.GetHeight()] = make(map[string]Block)
+	}
+	p.Pool[block.GetHeight()][string(block.GetSeal())] = block
+	p.addedAt[string(block.GetSeal())] = addedAt
+}
+
 func createBlockFromAddToPoolEvent(event *BlockAddToPoolEvent) (Block, error) {
 	txList, err := deserializeTxList(event.TxList)
 	if err != nil {
@@ -114,16 +462,33 @@ func createBlockFromAddToPoolEvent(event *BlockAddToPoolEvent) (Block, error) {
 	}
 
 	return &DefaultBlock{
-		Seal:      event.Seal,
-		PrevSeal:  event.PrevSeal,
-		Height:    event.Height,
-		TxList:    txList,
-		TxSeal:    event.TxSeal,
-		Timestamp: event.Timestamp,
-		Creator:   event.Creator,
+		Seal:         event.Seal,
+		PrevSeal:     event.PrevSeal,
+		Height:       event.Height,
+		TxList:       txList,
+		TxSeal:       event.TxSeal,
+		Timestamp:    event.Timestamp,
+		Creator:      event.Creator,
+		Version:      event.Version,
+		StateRoot:    event.StateRoot,
+		ReceiptsRoot: event.ReceiptsRoot,
+		Signature:    event.Signature,
 	}, nil
 }
 
+func sortBySeal(candidates map[string]Block) []Block {
+	blocks := make([]Block, 0, len(candidates))
+	for _, block := range candidates {
+		blocks = append(blocks, block)
+	}
+
+	sort.Slice(blocks, func(i, j int) bool {
+		return bytes.Compare(blocks[i].GetSeal(), blocks[j].GetSeal()) < 0
+	})
+
+	return blocks
+}
+
 // BlockSyncState Aggregate ID
 var BC_SYNC_STATE_AID = "BC_SYNC_STATE_AID"
 
@@ -153,11 +518,26 @@ func NewBlockSyncState() *BlockSyncState {
 	}
 }
 
+// LoadBlockSyncState 는 eventstore 에 저장된 event 들로 재시작 전의 동기화 상태를 복원한다.
+func LoadBlockSyncState() (*BlockSyncState, error) {
+	syncState := NewBlockSyncState()
+	if err := eventstore.Load(syncState, BC_SYNC_STATE_AID); err != nil {
+		return nil, err
+	}
+
+	return syncState, nil
+}
+
 func (bss *BlockSyncState) GetID() string {
 	return BC_SYNC_STATE_AID
 }
 
+// SetProgress 는 동기화 상태가 바뀔 때만 event 를 저장한다.
 func (bss *BlockSyncState) SetProgress(state ProgressState) {
+	if bss.isProgress == state {
+		return
+	}
+
 	var event midgard.Event
 	if state == PROGRESSING {
 		event = createSyncStartEvent()
@@ -171,7 +551,8 @@ func (bss *BlockSyncState) SetProgress(state ProgressState) {
 func createSyncStartEvent() *SyncStartEvent {
 	return &SyncStartEvent{
 		EventModel: midgard.EventModel{
-			ID: BC_SYNC_STATE_AID,
+			ID:   BC_SYNC_STATE_AID,
+			Type: "sync.started",
 		},
 	}
 }
@@ -179,7 +560,8 @@ func createSyncStartEvent() *SyncStartEvent {
 func createSyncDoneEvent() *SyncDoneEvent {
 	return &SyncDoneEvent{
 		EventModel: midgard.EventModel{
-			ID: BC_SYNC_STATE_AID,
+			ID:   BC_SYNC_STATE_AID,
+			Type: "sync.done",
 		},
 	}
 }
@@ -2,8 +2,11 @@ package blockchain_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/it-chain/engine/blockchain"
+	"github.com/it-chain/engine/blockchain/test/mock"
+	"github.com/it-chain/engine/core/eventstore"
 	"github.com/it-chain/midgard"
 	"github.com/stretchr/testify/assert"
 )
@@ -19,7 +22,18 @@ func TestBlockPoolModel(t *testing.T) {
 	pool.Add(block1)
 
 	// Then
-	assert.Equal(t, uint64(2), pool.Get(blockchain.BlockHeight(2)).GetHeight())
+	assert.Equal(t, uint64(2), pool.GetByHeight(blockchain.BlockHeight(2))[0].GetHeight())
+	assert.Equal(t, nil, pool.GetBySeal([]byte("seal")))
+
+	// When
+	block3 := &blockchain.DefaultBlock{
+		Seal:   []byte("seal"),
+		Height: blockchain.BlockHeight(3),
+	}
+	pool.Add(block3)
+
+	// Then
+	assert.Equal(t, uint64(3), pool.GetBySeal([]byte("seal")).GetHeight())
 
 	// When
 	block2 := &blockchain.DefaultBlock{
@@ -28,7 +42,7 @@ func TestBlockPoolModel(t *testing.T) {
 	pool.Delete(block2)
 
 	// Then
-	assert.Equal(t, nil, pool.Get(blockchain.BlockHeight(2)))
+	assert.Equal(t, 0, len(pool.GetByHeight(blockchain.BlockHeight(2))))
 
 	// when
 	aggregateID := pool.GetID()
@@ -48,8 +62,7 @@ func TestBlockPoolModel_On(t *testing.T) {
 	err := pool.On(event1)
 	// then
 	assert.Equal(t, nil, err)
-	assert.Equal(t, blockchain.BlockHeight(1), pool.Pool[blockchain.BlockHeight(1)].GetHeight())
-	assert.Equal(t, []byte{0x1}, pool.Pool[blockchain.BlockHeight(1)].GetSeal())
+	assert.Equal(t, blockchain.BlockHeight(1), pool.Pool[blockchain.BlockHeight(1)][string([]byte{0x1})].GetHeight())
 
 	event2 := &blockchain.BlockAddToPoolEvent{
 		Height: 2,
@@ -59,8 +72,7 @@ func TestBlockPoolModel_On(t *testing.T) {
 	err2 := pool.On(event2)
 	// then
 	assert.Equal(t, nil, err2)
-	assert.Equal(t, blockchain.BlockHeight(2), pool.Pool[blockchain.BlockHeight(2)].GetHeight())
-	assert.Equal(t, []byte{0x2}, pool.Pool[blockchain.BlockHeight(2)].GetSeal())
+	assert.Equal(t, blockchain.BlockHeight(2), pool.Pool[blockchain.BlockHeight(2)][string([]byte{0x2})].GetHeight())
 
 	// Same height with event1, but different seal
 	event3 := &blockchain.BlockAddToPoolEvent{
@@ -71,9 +83,55 @@ func TestBlockPoolModel_On(t *testing.T) {
 	err3 := pool.On(event3)
 	// then
 	assert.Equal(t, nil, err3)
-	assert.Equal(t, blockchain.BlockHeight(1), pool.Pool[blockchain.BlockHeight(1)].GetHeight())
-	assert.Equal(t, []byte{0x3}, pool.Pool[blockchain.BlockHeight(1)].GetSeal())
+	assert.Equal(t, 2, len(pool.Pool[blockchain.BlockHeight(1)]))
+	assert.Equal(t, []byte{0x1}, pool.Pool[blockchain.BlockHeight(1)][string([]byte{0x1})].GetSeal())
+	assert.Equal(t, []byte{0x3}, pool.Pool[blockchain.BlockHeight(1)][string([]byte{0x3})].GetSeal())
+
+	// when
+	err4 := pool.On(&blockchain.BlockRemoveFromPoolEvent{Height: 1, Seal: []byte{0x1}})
+
+	// then: only the block of the seal is removed
+	assert.Equal(t, nil, err4)
+	assert.Equal(t, 1, len(pool.Pool[blockchain.BlockHeight(1)]))
+	assert.Equal(t, []byte{0x3}, pool.Pool[blockchain.BlockHeight(1)][string([]byte{0x3})].GetSeal())
+
+	// when
+	err5 := pool.On(&blockchain.BlockRemoveFromPoolEvent{Height: 1})
+
+	// then: event without seal removes every block of the height
+	assert.Equal(t, nil, err5)
+	_, ok := pool.Pool[blockchain.BlockHeight(1)]
+	assert.False(t, ok)
+}
+
+func TestBlockPoolModel_Candidates(t *testing.T) {
+	pool := blockchain.NewBlockPool()
+
+	a1 := &blockchain.DefaultBlock{Seal: []byte("a1"), PrevSeal: []byte("seal0"), Height: 1}
+	b1 := &blockchain.DefaultBlock{Seal: []byte("b1"), PrevSeal: []byte("seal0"), Height: 1}
+	b2 := &blockchain.DefaultBlock{Seal: []byte("b2"), PrevSeal: []byte("b1"), Height: 2}
+
+	// when
+	pool.Add(b1)
+	pool.Add(a1)
+	pool.Add(b2)
 
+	// then: candidates are sorted by seal
+	candidates := pool.GetByHeight(1)
+	assert.Equal(t, 2, len(candidates))
+	assert.Equal(t, []byte("a1"), candidates[0].GetSeal())
+	assert.Equal(t, []byte("b1"), candidates[1].GetSeal())
+
+	assert.Equal(t, 2, len(pool.GetByPrevSeal([]byte("seal0"))))
+	assert.Equal(t, []byte("b2"), pool.GetByPrevSeal([]byte("b1"))[0].GetSeal())
+	assert.Equal(t, []byte("b1"), pool.GetBySeal([]byte("b1")).GetSeal())
+
+	// when
+	pool.Delete(a1)
+
+	// then
+	assert.Equal(t, 1, len(pool.GetByHeight(1)))
+	assert.Equal(t, nil, pool.GetBySeal([]byte("a1")))
 }
 
 func TestBlockSyncState(t *testing.T) {
@@ -107,7 +165,17 @@ func TestBlockSyncState(t *testing.T) {
 }
 
 func TestBlockSyncState_SetProgress(t *testing.T) {
-	// when
+	// given
+	saved := make([]midgard.Event, 0)
+
+	repo := MockRepostiory{}
+	repo.saveFunc = func(aggregateID string, events ...midgard.Event) error {
+		saved = append(saved, events...)
+		return nil
+	}
+	eventstore.InitForMock(repo)
+	defer eventstore.Close()
+
 	syncState := blockchain.NewBlockSyncState()
 
 	// when
@@ -115,10 +183,257 @@ func TestBlockSyncState_SetProgress(t *testing.T) {
 
 	// then
 	assert.Equal(t, blockchain.PROGRESSING, syncState.IsProgressing())
+	assert.Equal(t, 1, len(saved))
 
 	// when
 	syncState.SetProgress(blockchain.DONE)
 
 	// then
 	assert.Equal(t, blockchain.DONE, syncState.IsProgressing())
+	assert.Equal(t, 2, len(saved))
+
+	// when: 상태가 바뀌지 않음
+	syncState.SetProgress(blockchain.DONE)
+
+	// then: event 를 저장하지 않는다.
+	assert.Equal(t, blockchain.DONE, syncState.IsProgressing())
+	assert.Equal(t, 2, len(saved))
+}
+
+func TestBlockPoolModel_Prune(t *testing.T) {
+	now := time.Now()
+
+	tests := map[string]struct {
+		input struct {
+			config  blockchain.PoolConfig
+			blocks  []*blockchain.DefaultBlock
+			addedAt []time.Time
+		}
+		output []string
+	}{
+		"expired": {
+			input: struct {
+				config  blockchain.PoolConfig
+				blocks  []*blockchain.DefaultBlock
+				addedAt []time.Time
+			}{
+				config:  blockchain.PoolConfig{Expiration: time.Minute},
+				blocks:  []*blockchain.DefaultBlock{{Seal: []byte("old"), Height: 1}, {Seal: []byte("new"), Height: 1}},
+				addedAt: []time.Time{now.Add(-2 * time.Minute), now},
+			},
+			output: []string{"old"},
+		},
+		"too far from tip": {
+			input: struct {
+				config  blockchain.PoolConfig
+				blocks  []*blockchain.DefaultBlock
+				addedAt []time.Time
+			}{
+				config:  blockchain.PoolConfig{MaxHeightDistance: 3},
+				blocks:  []*blockchain.DefaultBlock{{Seal: []byte("low"), Height: 1}, {Seal: []byte("near"), Height: 12}, {Seal: []byte("high"), Height: 14}},
+				addedAt: []time.Time{now, now, now},
+			},
+			output: []string{"low", "high"},
+		},
+		"too many blocks": {
+			input: struct {
+				config  blockchain.PoolConfig
+				blocks  []*blockchain.DefaultBlock
+				addedAt []time.Time
+			}{
+				config:  blockchain.PoolConfig{MaxBlocks: 1},
+				blocks:  []*blockchain.DefaultBlock{{Seal: []byte("a11"), Height: 11}, {Seal: []byte("b11"), Height: 11}, {Seal: []byte("a13"), Height: 13}},
+				addedAt: []time.Time{now, now.Add(-time.Second), now},
+			},
+			output: []string{"b11", "a13"},
+		},
+		"no limit": {
+			input: struct {
+				config  blockchain.PoolConfig
+				blocks  []*blockchain.DefaultBlock
+				addedAt []time.Time
+			}{
+				config:  blockchain.PoolConfig{},
+				blocks:  []*blockchain.DefaultBlock{{Seal: []byte("a"), Height: 1}, {Seal: []byte("b"), Height: 100}},
+				addedAt: []time.Time{now.Add(-time.Hour), now},
+			},
+			output: []string{},
+		},
+	}
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+
+		// given
+		pool := blockchain.NewBlockPoolWithConfig(test.input.config)
+		for i, block := range test.input.blocks {
+			pool.On(&blockchain.BlockAddToPoolEvent{
+				EventModel: midgard.EventModel{Time: test.input.addedAt[i]},
+				Seal:       block.Seal,
+				Height:     block.Height,
+			})
+		}
+
+		// when
+		evicted := pool.Prune(blockchain.BlockHeight(10), now)
+
+		// then
+		seals := make([]string, 0)
+		for _, block := range evicted {
+			seals = append(seals, string(block.GetSeal()))
+		}
+		assert.Equal(t, test.output, seals)
+		assert.Equal(t, len(test.input.blocks)-len(test.output), pool.Size())
+	}
+}
+
+func TestBlockPoolModel_Compaction(t *testing.T) {
+	// given
+	pool := blockchain.NewBlockPoolWithConfig(blockchain.PoolConfig{CompactThreshold: 3})
+	saved := make([]midgard.Event, 0)
+	streams := make([]string, 0)
+
+	repo := MockRepostiory{}
+	repo.saveFunc = func(aggregateID string, events ...midgard.Event) error {
+		for _, event := range events {
+			saved = append(saved, event)
+			streams = append(streams, aggregateID)
+		}
+		return nil
+	}
+	eventstore.InitForMock(repo)
+	defer eventstore.Close()
+
+	block1 := &blockchain.DefaultBlock{Seal: []byte("seal1"), PrevSeal: []byte("seal0"), Height: 1}
+	block2 := &blockchain.DefaultBlock{Seal: []byte("seal2"), PrevSeal: []byte("seal1"), Height: 2}
+	block3 := &blockchain.DefaultBlock{Seal: []byte("seal3"), PrevSeal: []byte("seal2"), Height: 3}
+
+	// when
+	assert.NoError(t, pool.Add(block1))
+	assert.NoError(t, pool.Add(block2))
+	pool.Delete(block1)
+
+	// then: 3 번째 event 다음에 snapshot 이 다음 세대의 stream 에 저장되고, BLOCK_POOL_AID 는 그 세대를 가리킨다.
+	assert.Equal(t, 5, len(saved))
+	compacted, ok := saved[3].(blockchain.BlockPoolCompactedEvent)
+	assert.True(t, ok)
+	assert.Equal(t, blockchain.BLOCK_POOL_AID, compacted.GetID())
+	assert.Equal(t, blockchain.BlockPoolStreamID(1), streams[3])
+
+	rotated, ok := saved[4].(blockchain.BlockPoolRotatedEvent)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(1), rotated.Generation)
+	assert.Equal(t, blockchain.BLOCK_POOL_AID, streams[4])
+
+	// when: compaction 이후의 event
+	assert.NoError(t, pool.Add(block3))
+
+	// then: 새 stream 에 저장된다.
+	assert.Equal(t, blockchain.BlockPoolStreamID(1), streams[5])
+
+	// when: snapshot 만으로 pool 을 복원
+	restored := blockchain.NewBlockPool()
+	assert.NoError(t, restored.On(&compacted))
+
+	// then
+	assert.Equal(t, 1, restored.Size())
+	assert.Equal(t, []byte("seal2"), restored.GetBySeal([]byte("seal2")).GetSeal())
+	assert.Equal(t, nil, restored.GetBySeal([]byte("seal1")))
+}
+
+func TestLoadBlockPool(t *testing.T) {
+	// given
+	eventLog := mock.NewEventLog()
+	loaded := make([]string, 0)
+
+	repo := mock.EventRepository{}
+	repo.SaveFunc = eventLog.Save
+	repo.CloseFunc = func() {}
+	repo.LoadFunc = func(aggregate midgard.Aggregate, aggregateID string) error {
+		loaded = append(loaded, aggregateID)
+		return eventLog.Load(aggregate, aggregateID)
+	}
+	eventstore.InitForMock(repo)
+	defer eventstore.Close()
+
+	config := blockchain.PoolConfig{CompactThreshold: 3}
+	pool := blockchain.NewBlockPoolWithConfig(config)
+
+	block1 := &blockchain.DefaultBlock{Seal: []byte("seal1"), PrevSeal: []byte("seal0"), Height: 1}
+	block2 := &blockchain.DefaultBlock{Seal: []byte("seal2"), PrevSeal: []byte("seal1"), Height: 2}
+	fork2 := &blockchain.DefaultBlock{Seal: []byte("fork2"), PrevSeal: []byte("seal1"), Height: 2}
+	block3 := &blockchain.DefaultBlock{Seal: []byte("seal3"), PrevSeal: []byte("seal2"), Height: 3}
+
+	// compaction 이전과 이후의 event 가 모두 저장된다.
+	assert.NoError(t, pool.Add(block1))
+	assert.NoError(t, pool.Add(block2))
+	pool.Delete(block1)
+	assert.NoError(t, pool.Add(fork2))
+	assert.NoError(t, pool.Add(block3))
+
+	// when
+	restored, err := blockchain.LoadBlockPool(config)
+
+	// then: 세대를 찾은 다음 마지막 세대의 stream 만 적용한다.
+	assert.NoError(t, err)
+	assert.Equal(t, []string{blockchain.BLOCK_POOL_AID, blockchain.BlockPoolStreamID(1)}, loaded)
+	assert.Equal(t, pool.Size(), restored.Size())
+	assert.Equal(t, nil, restored.GetBySeal([]byte("seal1")))
+	assert.Equal(t, 2, len(restored.GetByHeight(2)))
+	assert.Equal(t, []byte("seal3"), restored.GetBySeal([]byte("seal3")).GetSeal())
+
+	// when: 복원한 pool 의 event 도 마지막 세대의 stream 에 저장된다.
+	restored.Delete(fork2)
+	again, err := blockchain.LoadBlockPool(config)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, nil, again.GetBySeal([]byte("fork2")))
+	assert.Equal(t, restored.Size(), again.Size())
+}
+
+func TestBlockPoolModel_CompactionDeletesSupersededStream(t *testing.T) {
+	// given
+	eventstore.InitForMock(mock.NewEventLog())
+	defer eventstore.Close()
+
+	config := blockchain.PoolConfig{CompactThreshold: 2}
+	pool := blockchain.NewBlockPoolWithConfig(config)
+
+	block1 := &blockchain.DefaultBlock{Seal: []byte("seal1"), PrevSeal: []byte("seal0"), Height: 1}
+	block2 := &blockchain.DefaultBlock{Seal: []byte("seal2"), PrevSeal: []byte("seal1"), Height: 2}
+	block3 := &blockchain.DefaultBlock{Seal: []byte("seal3"), PrevSeal: []byte("seal2"), Height: 3}
+
+	// when: 두 번째 event 와 세 번째 event 다음에 compaction 한다.
+	assert.NoError(t, pool.Add(block1))
+	assert.NoError(t, pool.Add(block2))
+	assert.NoError(t, pool.Add(block3))
+
+	// then: 첫 세대의 stream 은 지워졌다.
+	superseded := blockchain.NewBlockPoolWithConfig(config)
+	assert.NoError(t, eventstore.Load(superseded, blockchain.BlockPoolStreamID(1)))
+	assert.Equal(t, 0, superseded.Size())
+
+	// then: 마지막 세대의 stream 으로 복원된다.
+	restored, err := blockchain.LoadBlockPool(config)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, restored.Size())
+}
+
+func TestLoadBlockSyncState(t *testing.T) {
+	// given
+	eventstore.InitForMock(mock.NewEventLog())
+	defer eventstore.Close()
+
+	syncState := blockchain.NewBlockSyncState()
+	syncState.SetProgress(blockchain.PROGRESSING)
+	syncState.SetProgress(blockchain.DONE)
+	syncState.SetProgress(blockchain.PROGRESSING)
+
+	// when
+	restored, err := blockchain.LoadBlockSyncState()
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, blockchain.PROGRESSING, restored.IsProgressing())
 }
@@ -0,0 +1,61 @@
+package blockchain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/it-chain/engine/blockchain"
+	"github.com/it-chain/engine/blockchain/test/mock"
+	"github.com/it-chain/engine/core/eventstore"
+	"github.com/it-chain/midgard"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSaveAction_DoAction(t *testing.T) {
+	// given
+	block := &blockchain.DefaultBlock{
+		Seal:      []byte("seal"),
+		PrevSeal:  []byte("prevseal"),
+		Height:    4,
+		Timestamp: time.Now().Round(0),
+		Creator:   []byte("creator"),
+		TxList: []*blockchain.DefaultTransaction{
+			{ID: "tx1"},
+			{ID: "tx2"},
+		},
+	}
+
+	added := make([]blockchain.Block, 0)
+	blockRepository := mock.BlockRepository{}
+	blockRepository.AddBlockFunc = func(block blockchain.Block) error {
+		added = append(added, block)
+		return nil
+	}
+
+	saved := make([]midgard.Event, 0)
+	eventstore.InitForMock(MockRepostiory{
+		saveFunc: func(aggregateID string, events ...midgard.Event) error {
+			assert.Equal(t, "seal", aggregateID)
+			saved = append(saved, events...)
+			return nil
+		},
+	})
+	defer eventstore.Close()
+
+	// when
+	err := blockchain.NewSaveAction(blockRepository).DoAction(block)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(added))
+	assert.Equal(t, 1, len(saved))
+
+	event := saved[0].(blockchain.BlockCommittedEvent)
+	assert.Equal(t, "block.committed", event.Type)
+	assert.Equal(t, block.Seal, event.Seal)
+	assert.Equal(t, block.PrevSeal, event.PrevSeal)
+	assert.Equal(t, block.Height, event.Height)
+	assert.Equal(t, block.Timestamp, event.Timestamp)
+	assert.Equal(t, block.Creator, event.Creator)
+	assert.Equal(t, []string{"tx1", "tx2"}, event.TxIDs)
+}
@@ -0,0 +1,257 @@
+package blockchain
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"hash"
+	"io"
+)
+
+var ErrInvalidChainFile = errors.New("not a chain file")
+var ErrUnsupportedChainFileVersion = errors.New("unsupported chain file version")
+var ErrChainFileChecksum = errors.New("chain file checksum mismatch")
+var ErrChainFileTruncated = errors.New("chain file is truncated")
+var ErrChainConflict = errors.New("block conflicts with committed block")
+
+// chain file 형식
+// - header : magic(4 byte) + version(1 byte)
+// - record : 4 byte 길이 + DefaultBlock.Serialize 결과 + 결과의 sha256(32 byte)
+// - trailer: 길이 0 인 record + block 수(8 byte) + 모든 record checksum 을 이어서 hash 한 sha256(32 byte)
+// record 를 하나씩 읽고 쓰므로 chain 전체를 memory 에 올리지 않는다.
+var chainFileMagic = []byte("ITCF")
+
+const chainFileVersion byte = 1
+
+// ChainWriter 는 block 들을 chain file 형식으로 쓴다. 마지막에 Close 로 trailer 를 써야 한다.
+type ChainWriter struct {
+	w     *bufio.Writer
+	hash  hash.Hash
+	count uint64
+}
+
+func NewChainWriter(w io.Writer) (*ChainWriter, error) {
+	writer := &ChainWriter{
+		w:    bufio.NewWriter(w),
+		hash: sha256.New(),
+	}
+
+	header := append(append([]byte{}, chainFileMagic...), chainFileVersion)
+	if _, err := writer.w.Write(header); err != nil {
+		return nil, err
+	}
+
+	return writer, nil
+}
+
+func (writer *ChainWriter) Write(block Block) error {
+	data, err := block.Serialize()
+	if err != nil {
+		return err
+	}
+
+	checksum := sha256.Sum256(data)
+
+	record := appendLengthPrefixed(nil, data)
+	if _, err := writer.w.Write(append(record, checksum[:]...)); err != nil {
+		return err
+	}
+
+	writer.hash.Write(checksum[:])
+	writer.count++
+
+	return nil
+}
+
+// Close 는 trailer 를 쓰고 buffer 를 비운다. 아래의 writer 는 닫지 않는다.
+func (writer *ChainWriter) Close() error {
+	trailer := appendUint32(nil, 0)
+	trailer = appendUint64(trailer, writer.count)
+	trailer = append(trailer, writer.hash.Sum(nil)...)
+
+	if _, err := writer.w.Write(trailer); err != nil {
+		return err
+	}
+
+	return writer.w.Flush()
+}
+
+// ChainReader 는 chain file 에서 block 을 하나씩 읽고 checksum 을 검증한다.
+type ChainReader struct {
+	r     *bufio.Reader
+	hash  hash.Hash
+	count uint64
+}
+
+func NewChainReader(r io.Reader) (*ChainReader, error) {
+	reader := &ChainReader{
+		r:    bufio.NewReader(r),
+		hash: sha256.New(),
+	}
+
+	header, err := reader.read(len(chainFileMagic) + 1)
+	if err != nil {
+		return nil, err
+	}
+
+	if !bytes.Equal(header[:len(chainFileMagic)], chainFileMagic) {
+		return nil, ErrInvalidChainFile
+	}
+
+	if header[len(chainFileMagic)] != chainFileVersion {
+		return nil, ErrUnsupportedChainFileVersion
+	}
+
+	return reader, nil
+}
+
+// Next 는 다음 block 을 반환한다.
+// trailer 까지 읽고 block 수와 전체 checksum 이 맞으면 io.EOF 를 반환한다.
+func (reader *ChainReader) Next() (Block, error) {
+	lengthBytes, err := reader.read(4)
+	if err != nil {
+		return nil, err
+	}
+
+	length := newDecoder(lengthBytes).readUint32()
+	if length == 0 {
+		return nil, reader.readTrailer()
+	}
+
+	data, err := reader.read(int(length) + sha256.Size)
+	if err != nil {
+		return nil, err
+	}
+
+	serialized, checksum := data[:length], data[length:]
+	if expected := sha256.Sum256(serialized); !bytes.Equal(expected[:], checksum) {
+		return nil, ErrChainFileChecksum
+	}
+
+	block := &DefaultBlock{}
+	if err := block.Deserialize(serialized); err != nil {
+		return nil, err
+	}
+
+	reader.hash.Write(checksum)
+	reader.count++
+
+	return block, nil
+}
+
+func (reader *ChainReader) readTrailer() error {
+	trailer, err := reader.read(8 + sha256.Size)
+	if err != nil {
+		return err
+	}
+
+	if newDecoder(trailer[:8]).readUint64() != reader.count || !bytes.Equal(trailer[8:], reader.hash.Sum(nil)) {
+		return ErrChainFileChecksum
+	}
+
+	return io.EOF
+}
+
+func (reader *ChainReader) read(n int) ([]byte, error) {
+	buf := make([]byte, n)
+	if _, err := io.ReadFull(reader.r, buf); err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return nil, ErrChainFileTruncated
+		}
+		return nil, err
+	}
+
+	return buf, nil
+}
+
+// ExportChain 함수는 from 부터 to 까지의 committed block 을 chain file 형식으로 w 에 쓰고 쓴 block 수를 반환한다.
+func ExportChain(blockQueryApi BlockQueryApi, from BlockHeight, to BlockHeight, w io.Writer) (uint64, error) {
+	writer, err := NewChainWriter(w)
+	if err != nil {
+		return 0, err
+	}
+
+	for height := from; height <= to; height++ {
+		block, err := blockQueryApi.GetBlockByHeight(height)
+		if err != nil {
+			return writer.count, err
+		}
+
+		if err := writer.Write(block); err != nil {
+			return writer.count, err
+		}
+	}
+
+	return writer.count, writer.Close()
+}
+
+// ImportChain 함수는 chain file 의 block 들을 validator 로 검증한 뒤 순서대로 commit 하고 commit 한 block 수를 반환한다.
+// height 0 block 은 genesisConfig 로 만든 genesis block 과 seal 이 같아야 하며, 다르면 ErrGenesisSealMismatch 를 반환한다.
+// 이미 같은 block 이 commit 되어 있으면 건너뛰고, 다른 block 이 commit 되어 있으면 ErrChainConflict 를 반환한다.
+// 검증에 실패하면 그 전까지 commit 한 block 은 그대로 둔다.
+func ImportChain(r io.Reader, blockRepository BlockRepository, validator *DefaultValidator, genesisConfig GenesisConfig) (uint64, error) {
+	if err := VerifyGenesisConfig(genesisConfig); err != nil {
+		return 0, err
+	}
+
+	reader, err := NewChainReader(r)
+	if err != nil {
+		return 0, err
+	}
+
+	imported := uint64(0)
+	for {
+		block, err := reader.Next()
+		if err == io.EOF {
+			return imported, nil
+		}
+		if err != nil {
+			return imported, err
+		}
+
+		committed, err := importBlock(block, blockRepository, validator, genesisConfig.Seal)
+		if err != nil {
+			return imported, err
+		}
+
+		if committed {
+			imported++
+		}
+	}
+}
+
+func importBlock(block Block, blockRepository BlockRepository, validator *DefaultValidator, genesisSeal []byte) (bool, error) {
+	if block.GetHeight() == 0 && !bytes.Equal(block.GetSeal(), genesisSeal) {
+		return false, ErrGenesisSealMismatch
+	}
+
+	lastBlock, err := blockRepository.GetLastBlock()
+	if err != nil && err != ErrBlockNotFound {
+		return false, err
+	}
+
+	if lastBlock != nil && block.GetHeight() <= lastBlock.GetHeight() {
+		stored, err := blockRepository.GetBlockByHeight(block.GetHeight())
+		if err != nil {
+			return false, err
+		}
+
+		if !bytes.Equal(stored.GetSeal(), block.GetSeal()) {
+			return false, ErrChainConflict
+		}
+
+		return false, nil
+	}
+
+	nextHeight := BlockHeight(0)
+	if lastBlock != nil {
+		nextHeight = lastBlock.GetHeight() + 1
+	}
+
+	if err := verifyChainBlock(block, lastBlock, nextHeight, validator); err != nil {
+		return false, err
+	}
+
+	return true, blockRepository.AddBlock(block)
+}
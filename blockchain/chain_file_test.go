@@ -0,0 +1,213 @@
+package blockchain_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/it-chain/engine/blockchain"
+	"github.com/it-chain/engine/blockchain/test/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func exportChain(t *testing.T, chain []blockchain.Block) []byte {
+	buf := &bytes.Buffer{}
+	count, err := blockchain.ExportChain(newChainQueryApi(chain), 0, blockchain.BlockHeight(len(chain)-1), buf)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(len(chain)), count)
+	return buf.Bytes()
+}
+
+// newImportRepository 는 committed 를 chain 으로 사용하는 mock repository 를 만든다.
+func newImportRepository(committed *[]blockchain.Block) mock.BlockRepository {
+	blockRepository := mock.BlockRepository{}
+	blockRepository.GetLastBlockFunc = func() (blockchain.Block, error) {
+		if len(*committed) == 0 {
+			return nil, blockchain.ErrBlockNotFound
+		}
+		return (*committed)[len(*committed)-1], nil
+	}
+	blockRepository.GetBlockByHeightFunc = func(height uint64) (blockchain.Block, error) {
+		if height >= uint64(len(*committed)) {
+			return nil, blockchain.ErrBlockNotFound
+		}
+		return (*committed)[height], nil
+	}
+	blockRepository.AddBlockFunc = func(block blockchain.Block) error {
+		*committed = append(*committed, block)
+		return nil
+	}
+	return blockRepository
+}
+
+func TestChainFile_ReadWrite(t *testing.T) {
+	// given
+	chain := newChain(t, 3)
+	data := exportChain(t, chain)
+
+	// when
+	reader, err := blockchain.NewChainReader(bytes.NewReader(data))
+	assert.NoError(t, err)
+
+	// then
+	for _, expected := range chain {
+		block, err := reader.Next()
+		assert.NoError(t, err)
+		assert.Equal(t, expected.GetSeal(), block.GetSeal())
+		assert.Equal(t, expected.GetHeight(), block.GetHeight())
+	}
+
+	_, err = reader.Next()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestChainFile_Corrupted(t *testing.T) {
+	data := exportChain(t, newChain(t, 2))
+
+	tests := map[string]struct {
+		input func() []byte
+		err   error
+	}{
+		"wrong magic": {
+			input: func() []byte {
+				corrupted := append([]byte{}, data...)
+				corrupted[0] = 'X'
+				return corrupted
+			},
+			err: blockchain.ErrInvalidChainFile,
+		},
+		"unsupported version": {
+			input: func() []byte {
+				corrupted := append([]byte{}, data...)
+				corrupted[4] = 9
+				return corrupted
+			},
+			err: blockchain.ErrUnsupportedChainFileVersion,
+		},
+		"flipped block byte": {
+			input: func() []byte {
+				corrupted := append([]byte{}, data...)
+				corrupted[20] ^= 0xff
+				return corrupted
+			},
+			err: blockchain.ErrChainFileChecksum,
+		},
+		"truncated": {
+			input: func() []byte {
+				return data[:len(data)-10]
+			},
+			err: blockchain.ErrChainFileTruncated,
+		},
+		"wrong block count": {
+			input: func() []byte {
+				corrupted := append([]byte{}, data...)
+				corrupted[len(corrupted)-33] ^= 0x01
+				return corrupted
+			},
+			err: blockchain.ErrChainFileChecksum,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+
+		// when
+		reader, err := blockchain.NewChainReader(bytes.NewReader(test.input()))
+		for err == nil {
+			_, err = reader.Next()
+		}
+
+		// then
+		assert.Equal(t, test.err, err)
+	}
+}
+
+func TestImportChain(t *testing.T) {
+	// given
+	config := newGenesisConfig(t)
+	chain := mock.NewChain(t, config, 4)
+	data := exportChain(t, chain)
+	committed := make([]blockchain.Block, 0)
+
+	// when
+	count, err := blockchain.ImportChain(bytes.NewReader(data), newImportRepository(&committed), &blockchain.DefaultValidator{}, config)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(4), count)
+	assert.Equal(t, 4, len(committed))
+
+	// when: 같은 file 을 다시 import
+	count, err = blockchain.ImportChain(bytes.NewReader(data), newImportRepository(&committed), &blockchain.DefaultValidator{}, config)
+
+	// then: 이미 commit 된 block 은 건너뛴다.
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0), count)
+	assert.Equal(t, 4, len(committed))
+
+	// when: 같은 genesis 에서 갈라진 chain 의 file
+	fork := mock.NewChain(t, config, 3)
+	other := exportChain(t, fork)
+	_, err = blockchain.ImportChain(bytes.NewReader(other), newImportRepository(&committed), &blockchain.DefaultValidator{}, config)
+
+	// then
+	assert.Equal(t, blockchain.ErrChainConflict, err)
+}
+
+func TestImportChain_GenesisMismatch(t *testing.T) {
+	// given: config 와 다른 genesis 로 시작하는 chain file
+	config := newGenesisConfig(t)
+	chain := newChain(t, 3)
+	data := exportChain(t, chain)
+
+	tests := map[string]struct {
+		input struct {
+			committed []blockchain.Block
+		}
+		err error
+	}{
+		"empty repository": {
+			input: struct {
+				committed []blockchain.Block
+			}{committed: []blockchain.Block{}},
+			err: blockchain.ErrGenesisSealMismatch,
+		},
+		"file genesis already committed": {
+			input: struct {
+				committed []blockchain.Block
+			}{committed: []blockchain.Block{chain[0]}},
+			err: blockchain.ErrGenesisSealMismatch,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+
+		committed := test.input.committed
+
+		// when
+		count, err := blockchain.ImportChain(bytes.NewReader(data), newImportRepository(&committed), &blockchain.DefaultValidator{}, config)
+
+		// then
+		assert.Equal(t, test.err, err)
+		assert.Equal(t, uint64(0), count)
+		assert.Equal(t, len(test.input.committed), len(committed))
+	}
+}
+
+func TestImportChain_InvalidBlock(t *testing.T) {
+	// given
+	config := newGenesisConfig(t)
+	chain := mock.NewChain(t, config, 4)
+	chain[2].(*blockchain.DefaultBlock).TxList[0].ID = "tampered"
+	data := exportChain(t, chain)
+	committed := make([]blockchain.Block, 0)
+
+	// when
+	count, err := blockchain.ImportChain(bytes.NewReader(data), newImportRepository(&committed), &blockchain.DefaultValidator{}, config)
+
+	// then: 검증에 실패한 block 전까지만 commit 된다.
+	assert.Equal(t, blockchain.ErrInvalidTxSeal, err)
+	assert.Equal(t, uint64(2), count)
+	assert.Equal(t, 2, len(committed))
+}
@@ -0,0 +1,172 @@
+package blockchain
+
+import "bytes"
+
+// ChainReport 는 committed chain 을 genesis 부터 검증한 결과이다.
+// Err 가 nil 이면 LastHeight 까지 모든 block 이 올바르고, 아니면 BrokenHeight 가 처음 검증에 실패한 block 의 height 이다.
+// snapshot 으로 시작한 chain 은 genesis 다음에 SnapshotHeight 부터 검증하고, HeaderOnlyBlocks 는 transaction 없이 header 로만 검증한 block 수이다.
+type ChainReport struct {
+	LastHeight       BlockHeight
+	SnapshotHeight   BlockHeight
+	VerifiedBlocks   uint64
+	HeaderOnlyBlocks uint64
+	BrokenHeight     BlockHeight
+	Err              error
+}
+
+func (report ChainReport) IsBroken() bool {
+	return report.Err != nil
+}
+
+// HeaderQueryApi 는 transaction 이 지워진 block 의 header 를 조회할 수 있는 repository 이다.
+type HeaderQueryApi interface {
+	GetHeaderByHeight(height BlockHeight) (SignedHeader, error)
+}
+
+// SnapshotHeightQueryApi 는 snapshot 으로 시작한 chain 의 snapshot block height 를 알려주는 repository 이다.
+// snapshot 으로 시작하지 않았으면 0 을 반환한다.
+type SnapshotHeightQueryApi interface {
+	GetSnapshotHeight() (BlockHeight, error)
+}
+
+// VerifyChain 함수는 genesis 부터 마지막 block 까지 PrevSeal 연결을 확인하고 TxSeal 과 seal 을 validator 로 다시 계산해서 비교한다.
+// validator 에 SignatureVerifier 가 설정되어 있으면 genesis 를 제외한 block 과 transaction 의 서명도 검증한다.
+// blockQueryApi 가 HeaderQueryApi 이면 transaction 이 지워진 block 은 header 로 검증한다.
+// blockQueryApi 가 SnapshotHeightQueryApi 이면 snapshot 이전의 block 은 없으므로 genesis 다음에 snapshot block 부터 검증한다.
+// snapshot block 은 이전 block 과의 연결 대신 seal 과 서명만 검증한다.
+// block 을 읽거나 decoding 하지 못한 height 도 깨진 block 으로 report 한다.
+func VerifyChain(blockQueryApi BlockQueryApi, validator *DefaultValidator) (ChainReport, error) {
+	lastBlock, err := blockQueryApi.GetLastBlock()
+	if err != nil {
+		return ChainReport{}, err
+	}
+
+	snapshotHeight, err := getSnapshotHeight(blockQueryApi)
+	if err != nil {
+		return ChainReport{}, err
+	}
+
+	report := ChainReport{
+		LastHeight:     lastBlock.GetHeight(),
+		SnapshotHeight: snapshotHeight,
+	}
+
+	var prevBlock Block
+	for height := BlockHeight(0); height <= report.LastHeight; height++ {
+		if height > 0 && height < snapshotHeight {
+			height = snapshotHeight
+			prevBlock = nil
+		}
+
+		block, headerOnly, err := getChainBlock(blockQueryApi, height)
+		if err == nil {
+			if headerOnly || (snapshotHeight > 0 && height == snapshotHeight) {
+				err = verifyChainHeader(NewSignedHeader(block), prevBlock, height, validator)
+				headerOnly = true
+			} else {
+				err = verifyChainBlock(block, prevBlock, height, validator)
+			}
+		}
+
+		if err != nil {
+			report.BrokenHeight = height
+			report.Err = err
+			return report, nil
+		}
+
+		report.VerifiedBlocks++
+		if headerOnly {
+			report.HeaderOnlyBlocks++
+		}
+		prevBlock = block
+	}
+
+	return report, nil
+}
+
+func getSnapshotHeight(blockQueryApi BlockQueryApi) (BlockHeight, error) {
+	query, ok := blockQueryApi.(SnapshotHeightQueryApi)
+	if !ok {
+		return 0, nil
+	}
+
+	return query.GetSnapshotHeight()
+}
+
+// getChainBlock 은 height 의 block 을 반환한다. transaction 이 지워진 block 이면 header 로 만든 block 과 true 를 반환한다.
+func getChainBlock(blockQueryApi BlockQueryApi, height BlockHeight) (Block, bool, error) {
+	block, err := blockQueryApi.GetBlockByHeight(height)
+	if err != ErrBlockPruned {
+		return block, false, err
+	}
+
+	query, ok := blockQueryApi.(HeaderQueryApi)
+	if !ok {
+		return nil, false, err
+	}
+
+	header, err := query.GetHeaderByHeight(height)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return NewBlockFromHeader(header), true, nil
+}
+
+// verifyChainHeader 는 transaction 이 없는 block 을 header 로 검증한다.
+// prevBlock 이 nil 이면 snapshot block 이므로 이전 block 과의 연결은 확인하지 않는다.
+func verifyChainHeader(header SignedHeader, prevBlock Block, height BlockHeight, validator *DefaultValidator) error {
+	if header.Height != height {
+		return ErrInvalidBlockHeight
+	}
+
+	if prevBlock != nil {
+		return validator.ValidateHeader(header, NewSignedHeader(prevBlock))
+	}
+
+	seal, err := validator.buildSignedHeaderSeal(header.BlockHeader)
+	if err != nil {
+		return err
+	}
+
+	if !bytes.Equal(seal, header.Seal) {
+		return ErrInvalidSeal
+	}
+
+	if validator.verifier == nil {
+		return nil
+	}
+
+	return ValidateBlockSignature(NewBlockFromHeader(header), validator.verifier)
+}
+
+func verifyChainBlock(block Block, prevBlock Block, height BlockHeight, validator *DefaultValidator) error {
+	if block.GetHeight() != height {
+		return ErrInvalidBlockHeight
+	}
+
+	if prevBlock != nil {
+		return validator.ValidateBlock(block, prevBlock)
+	}
+
+	// genesis block 은 이전 block 과 서명이 없으므로 tx seal 과 seal 만 검증한다.
+	valid, err := validator.ValidateBlockTxSeal(block)
+	if err != nil {
+		return err
+	}
+
+	if !valid {
+		return ErrInvalidTxSeal
+	}
+
+	valid, err = validator.ValidateSeal(block.GetSeal(), block)
+	if err != nil {
+		return err
+	}
+
+	if !valid {
+		return ErrInvalidSeal
+	}
+
+	return nil
+}
@@ -0,0 +1,276 @@
+package blockchain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/it-chain/engine/blockchain"
+	"github.com/it-chain/engine/blockchain/test/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func newGenesisConfig(t *testing.T) blockchain.GenesisConfig {
+	config, err := blockchain.NewGenesisConfig("chain", "creator", []string{"creator"}, time.Now())
+	assert.NoError(t, err)
+	return config
+}
+
+func newChain(t *testing.T, length int) []blockchain.Block {
+	return mock.NewChain(t, newGenesisConfig(t), length)
+}
+
+func newChainQueryApi(chain []blockchain.Block) mock.BlockQueryApi {
+	queryApi := mock.BlockQueryApi{}
+	queryApi.GetLastBlockFunc = func() (blockchain.Block, error) {
+		if len(chain) == 0 {
+			return nil, blockchain.ErrBlockNotFound
+		}
+		return chain[len(chain)-1], nil
+	}
+	queryApi.GetBlockByHeightFunc = func(height uint64) (blockchain.Block, error) {
+		if height >= uint64(len(chain)) || chain[height] == nil {
+			return nil, blockchain.ErrBlockNotFound
+		}
+		return chain[height], nil
+	}
+	return queryApi
+}
+
+func TestVerifyChain(t *testing.T) {
+	tests := map[string]struct {
+		input  func() []blockchain.Block
+		output blockchain.ChainReport
+	}{
+		"valid chain": {
+			input: func() []blockchain.Block {
+				return newChain(t, 4)
+			},
+			output: blockchain.ChainReport{LastHeight: 3, VerifiedBlocks: 4},
+		},
+		"tampered genesis": {
+			input: func() []blockchain.Block {
+				chain := newChain(t, 3)
+				chain[0].(*blockchain.DefaultBlock).Creator = []byte("attacker")
+				return chain
+			},
+			output: blockchain.ChainReport{LastHeight: 2, VerifiedBlocks: 0, BrokenHeight: 0, Err: blockchain.ErrInvalidSeal},
+		},
+		"broken prev seal": {
+			input: func() []blockchain.Block {
+				chain := newChain(t, 4)
+				chain[2] = mock.NewBlock(t, []byte("other"), 2, blockchain.CurrentBlockVersion)
+				return chain
+			},
+			output: blockchain.ChainReport{LastHeight: 3, VerifiedBlocks: 2, BrokenHeight: 2, Err: blockchain.ErrPrevSealMismatch},
+		},
+		"tampered tx": {
+			input: func() []blockchain.Block {
+				chain := newChain(t, 4)
+				chain[3].(*blockchain.DefaultBlock).TxList[0].ID = "tampered"
+				return chain
+			},
+			output: blockchain.ChainReport{LastHeight: 3, VerifiedBlocks: 3, BrokenHeight: 3, Err: blockchain.ErrInvalidTxSeal},
+		},
+		"missing block": {
+			input: func() []blockchain.Block {
+				chain := newChain(t, 4)
+				chain[1] = nil
+				return chain
+			},
+			output: blockchain.ChainReport{LastHeight: 3, VerifiedBlocks: 1, BrokenHeight: 1, Err: blockchain.ErrBlockNotFound},
+		},
+	}
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+
+		// when
+		report, err := blockchain.VerifyChain(newChainQueryApi(test.input()), &blockchain.DefaultValidator{})
+
+		// then
+		assert.NoError(t, err)
+		assert.Equal(t, test.output, report)
+		assert.Equal(t, test.output.Err != nil, report.IsBroken())
+	}
+}
+
+// storedChainRepository 는 prunedHeight 이하 block 의 transaction 을 지우고,
+// snapshotHeight 의 snapshot block 으로 시작해서 그 이전 block 이 없는 repository 를 흉내 낸다.
+type storedChainRepository struct {
+	mock.BlockQueryApi
+	chain          []blockchain.Block
+	prunedHeight   blockchain.BlockHeight
+	snapshotHeight blockchain.BlockHeight
+}
+
+func newStoredChainRepository(chain []blockchain.Block, prunedHeight blockchain.BlockHeight, snapshotHeight blockchain.BlockHeight) storedChainRepository {
+	stored := append([]blockchain.Block{}, chain...)
+	if snapshotHeight > 0 {
+		stored[snapshotHeight] = blockchain.NewBlockFromHeader(blockchain.NewSignedHeader(chain[snapshotHeight]))
+	}
+
+	return storedChainRepository{
+		BlockQueryApi:  newChainQueryApi(stored),
+		chain:          stored,
+		prunedHeight:   prunedHeight,
+		snapshotHeight: snapshotHeight,
+	}
+}
+
+func (r storedChainRepository) GetBlockByHeight(height uint64) (blockchain.Block, error) {
+	if height > 0 && height < r.snapshotHeight {
+		return nil, blockchain.ErrBlockNotFound
+	}
+
+	if height > 0 && height <= r.prunedHeight {
+		return nil, blockchain.ErrBlockPruned
+	}
+
+	return r.BlockQueryApi.GetBlockByHeight(height)
+}
+
+func (r storedChainRepository) GetHeaderByHeight(height blockchain.BlockHeight) (blockchain.SignedHeader, error) {
+	if height >= uint64(len(r.chain)) || (height > 0 && height < r.snapshotHeight) {
+		return blockchain.SignedHeader{}, blockchain.ErrHeaderNotFound
+	}
+
+	return blockchain.NewSignedHeader(r.chain[height]), nil
+}
+
+func (r storedChainRepository) GetSnapshotHeight() (blockchain.BlockHeight, error) {
+	return r.snapshotHeight, nil
+}
+
+func TestVerifyChain_Pruned(t *testing.T) {
+	tests := map[string]struct {
+		input  func() blockchain.BlockQueryApi
+		output blockchain.ChainReport
+	}{
+		"pruned blocks verified by header": {
+			input: func() blockchain.BlockQueryApi {
+				return newStoredChainRepository(newChain(t, 6), 3, 0)
+			},
+			output: blockchain.ChainReport{LastHeight: 5, VerifiedBlocks: 6, HeaderOnlyBlocks: 3},
+		},
+		"tampered pruned header": {
+			input: func() blockchain.BlockQueryApi {
+				chain := newChain(t, 6)
+				chain[2].(*blockchain.DefaultBlock).Creator = []byte("attacker")
+				return newStoredChainRepository(chain, 3, 0)
+			},
+			output: blockchain.ChainReport{LastHeight: 5, VerifiedBlocks: 2, HeaderOnlyBlocks: 1, BrokenHeight: 2, Err: blockchain.ErrInvalidSeal},
+		},
+		"broken link after pruned header": {
+			input: func() blockchain.BlockQueryApi {
+				chain := newChain(t, 6)
+				chain[4] = mock.NewBlock(t, []byte("other"), 4, blockchain.CurrentBlockVersion)
+				return newStoredChainRepository(chain, 3, 0)
+			},
+			output: blockchain.ChainReport{LastHeight: 5, VerifiedBlocks: 4, HeaderOnlyBlocks: 3, BrokenHeight: 4, Err: blockchain.ErrPrevSealMismatch},
+		},
+		"header not available": {
+			input: func() blockchain.BlockQueryApi {
+				repository := newStoredChainRepository(newChain(t, 6), 3, 0)
+				return mock.BlockQueryApi{
+					GetLastBlockFunc:     repository.GetLastBlock,
+					GetBlockByHeightFunc: repository.GetBlockByHeight,
+				}
+			},
+			output: blockchain.ChainReport{LastHeight: 5, VerifiedBlocks: 1, BrokenHeight: 1, Err: blockchain.ErrBlockPruned},
+		},
+	}
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+
+		// when
+		report, err := blockchain.VerifyChain(test.input(), &blockchain.DefaultValidator{})
+
+		// then
+		assert.NoError(t, err)
+		assert.Equal(t, test.output, report)
+	}
+}
+
+func TestVerifyChain_Snapshot(t *testing.T) {
+	tests := map[string]struct {
+		input  func() blockchain.BlockQueryApi
+		output blockchain.ChainReport
+	}{
+		"starts at snapshot block": {
+			input: func() blockchain.BlockQueryApi {
+				return newStoredChainRepository(newChain(t, 6), 0, 3)
+			},
+			output: blockchain.ChainReport{LastHeight: 5, SnapshotHeight: 3, VerifiedBlocks: 4, HeaderOnlyBlocks: 1},
+		},
+		"pruned after snapshot": {
+			input: func() blockchain.BlockQueryApi {
+				return newStoredChainRepository(newChain(t, 6), 4, 3)
+			},
+			output: blockchain.ChainReport{LastHeight: 5, SnapshotHeight: 3, VerifiedBlocks: 4, HeaderOnlyBlocks: 2},
+		},
+		"tampered snapshot block": {
+			input: func() blockchain.BlockQueryApi {
+				chain := newChain(t, 6)
+				chain[3].(*blockchain.DefaultBlock).Creator = []byte("attacker")
+				return newStoredChainRepository(chain, 0, 3)
+			},
+			output: blockchain.ChainReport{LastHeight: 5, SnapshotHeight: 3, VerifiedBlocks: 1, HeaderOnlyBlocks: 0, BrokenHeight: 3, Err: blockchain.ErrInvalidSeal},
+		},
+		"broken link after snapshot block": {
+			input: func() blockchain.BlockQueryApi {
+				chain := newChain(t, 6)
+				chain[4] = mock.NewBlock(t, []byte("other"), 4, blockchain.CurrentBlockVersion)
+				return newStoredChainRepository(chain, 0, 3)
+			},
+			output: blockchain.ChainReport{LastHeight: 5, SnapshotHeight: 3, VerifiedBlocks: 2, HeaderOnlyBlocks: 1, BrokenHeight: 4, Err: blockchain.ErrPrevSealMismatch},
+		},
+	}
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+
+		// when
+		report, err := blockchain.VerifyChain(test.input(), &blockchain.DefaultValidator{})
+
+		// then
+		assert.NoError(t, err)
+		assert.Equal(t, test.output, report)
+	}
+}
+
+func TestVerifyChain_Signature(t *testing.T) {
+	// given
+	chain := newChain(t, 3)
+	for _, block := range chain[1:] {
+		block.(*blockchain.DefaultBlock).SetSignature([]byte("signature"))
+		for _, tx := range block.(*blockchain.DefaultBlock).TxList {
+			tx.Signature = []byte("signature")
+		}
+	}
+	chain[2].(*blockchain.DefaultBlock).SetSignature([]byte("forged"))
+
+	verifier := mock.SignatureVerifier{}
+	verifier.VerifyFunc = func(peerId blockchain.PeerId, data []byte, signature []byte) (bool, error) {
+		return string(signature) == "signature", nil
+	}
+
+	validator := &blockchain.DefaultValidator{}
+	validator.SetSignatureVerifier(verifier)
+
+	// when
+	report, err := blockchain.VerifyChain(newChainQueryApi(chain), validator)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, blockchain.BlockHeight(2), report.BrokenHeight)
+	assert.Equal(t, blockchain.ErrInvalidBlockSignature, report.Err)
+}
+
+func TestVerifyChain_EmptyChain(t *testing.T) {
+	// when
+	_, err := blockchain.VerifyChain(newChainQueryApi(nil), &blockchain.DefaultValidator{})
+
+	// then
+	assert.Equal(t, blockchain.ErrBlockNotFound, err)
+}
@@ -32,6 +32,28 @@ type BlockValidateCommand struct {
 	Block Block
 }
 
+// commit 된 block 의 transaction 을 icode 에 실행 요청한다. Block 은 icode.Block 형태의 json 이다.
+// Reset 이면 icode 는 block 을 실행하기 전에 모든 icode 의 state 를 비운다. reorg 뒤에 chain 을 다시 실행할 때 사용한다.
+type BlockExecuteCommand struct {
+	midgard.CommandModel
+	Block []byte
+	Reset bool
+}
+
+// snapshot 으로 시작한 node 가 snapshot 의 icode 를 배포하도록 icode 에 요청한다. ID 는 icode 의 ID 이다.
+// icode.DeployCommand 와 같은 형태이다.
+type ICodeDeployCommand struct {
+	midgard.CommandModel
+	Url     string
+	SshPath string
+}
+
+// icode 가 실행한 block 의 transaction 결과. ID 는 BlockExecuteCommand 의 ID 이다.
+type BlockResultCommand struct {
+	midgard.CommandModel
+	TxResults []TxResult
+}
+
 type GrpcDeliverCommand struct {
 	midgard.CommandModel
 	Recipients []string
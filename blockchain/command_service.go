@@ -2,4 +2,7 @@ package blockchain
 
 type CommandService interface {
 	SendBlockValidateCommand(block Block) error
+	SendBlockExecuteCommand(block Block) error
+	SendResetBlockExecuteCommand(block Block) error
+	SendICodeDeployCommand(icode ICodeState) error
 }
@@ -0,0 +1,431 @@
+package blockchain
+
+import (
+	"encoding/binary"
+	"errors"
+	"time"
+)
+
+var ErrTruncatedEncoding = errors.New("encoded data is truncated")
+var ErrNonCanonicalEncoding = errors.New("encoded data is not canonical")
+var ErrBlockType = errors.New("Wrong block type")
+
+// encodingVersion 은 block, transaction binary encoding 형식의 version 이다.
+const encodingVersion byte = 1
+
+// binary encoding 규칙
+// - 정수는 big endian 고정 길이
+// - []byte, string 은 4 byte 길이 prefix + 내용
+// - list 는 4 byte 개수 prefix + 각 원소
+// - time 은 unix 초(8 byte) + nanosecond(4 byte), nanosecond 는 1e9 미만
+// - block 과 transaction 의 timestamp 는 zone offset(초, 4 byte) 도 저장한다.
+//   legacy seal 과 legacy transaction hash 는 timestamp 의 문자열(zone 포함)을 hash 하기 때문이다.
+//   header 와 transaction content 의 hash 에는 zone offset 을 포함하지 않는다.
+// - pointer 는 1 byte 존재 flag(0 또는 1) + 내용
+// 같은 값은 항상 같은 byte 로 encoding 되고, decoder 는 이 규칙을 벗어난 입력을 거부한다.
+
+func appendUint8(buf []byte, v uint8) []byte {
+	return append(buf, v)
+}
+
+func appendUint32(buf []byte, v uint32) []byte {
+	b := make([]byte, 4)
+	binary.BigEndian.PutUint32(b, v)
+	return append(buf, b...)
+}
+
+func appendUint64(buf []byte, v uint64) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, v)
+	return append(buf, b...)
+}
+
+func appendInt64(buf []byte, v int64) []byte {
+	return appendUint64(buf, uint64(v))
+}
+
+func appendLengthPrefixed(buf []byte, data []byte) []byte {
+	buf = appendUint32(buf, uint32(len(data)))
+	return append(buf, data...)
+}
+
+func appendString(buf []byte, s string) []byte {
+	return appendLengthPrefixed(buf, []byte(s))
+}
+
+func appendTime(buf []byte, t time.Time) []byte {
+	buf = appendInt64(buf, t.Unix())
+	return appendUint32(buf, uint32(t.Nanosecond()))
+}
+
+func appendZoneOffset(buf []byte, t time.Time) []byte {
+	_, offset := t.Zone()
+	return appendUint32(buf, uint32(int32(offset)))
+}
+
+// decoder 는 canonical encoding 을 앞에서부터 읽는다.
+// 처음 발생한 error 를 기억하고, 이후의 read 는 zero value 를 반환한다.
+type decoder struct {
+	data []byte
+	err  error
+}
+
+func newDecoder(data []byte) *decoder {
+	return &decoder{data: data}
+}
+
+func (d *decoder) next(n int) []byte {
+	if d.err != nil {
+		return nil
+	}
+
+	if n < 0 || len(d.data) < n {
+		d.err = ErrTruncatedEncoding
+		return nil
+	}
+
+	b := d.data[:n]
+	d.data = d.data[n:]
+	return b
+}
+
+func (d *decoder) readUint8() uint8 {
+	b := d.next(1)
+	if b == nil {
+		return 0
+	}
+	return b[0]
+}
+
+func (d *decoder) readUint32() uint32 {
+	b := d.next(4)
+	if b == nil {
+		return 0
+	}
+	return binary.BigEndian.Uint32(b)
+}
+
+func (d *decoder) readUint64() uint64 {
+	b := d.next(8)
+	if b == nil {
+		return 0
+	}
+	return binary.BigEndian.Uint64(b)
+}
+
+func (d *decoder) readInt64() int64 {
+	return int64(d.readUint64())
+}
+
+// readBytes 는 항상 nil 이 아닌 slice 를 복사해서 반환한다.
+func (d *decoder) readBytes() []byte {
+	length := d.readUint32()
+	b := d.next(int(length))
+	if b == nil {
+		return make([]byte, 0)
+	}
+	return append(make([]byte, 0, len(b)), b...)
+}
+
+func (d *decoder) readString() string {
+	return string(d.readBytes())
+}
+
+func (d *decoder) readTime() time.Time {
+	sec := d.readInt64()
+	nsec := d.readUint32()
+	if nsec >= uint32(time.Second) {
+		d.fail(ErrNonCanonicalEncoding)
+	}
+	return time.Unix(sec, int64(nsec)).UTC()
+}
+
+// readZoneOffset 은 zone offset 을 읽어서 t 를 그 zone 의 시각으로 바꾼다.
+// offset 이 0 이면 UTC 로 둔다.
+func (d *decoder) readZoneOffset(t time.Time) time.Time {
+	offset := int32(d.readUint32())
+	if offset <= -maxZoneOffset || offset >= maxZoneOffset {
+		d.fail(ErrNonCanonicalEncoding)
+	}
+
+	if offset == 0 {
+		return t
+	}
+
+	return t.In(time.FixedZone("", int(offset)))
+}
+
+// maxZoneOffset 은 zone offset 의 한도(초) 이다.
+const maxZoneOffset = 24 * 60 * 60
+
+func (d *decoder) readFlag() bool {
+	flag := d.readUint8()
+	if flag > 1 {
+		d.fail(ErrNonCanonicalEncoding)
+	}
+	return flag == 1
+}
+
+// readCount 는 list 의 원소 개수를 읽는다.
+// 각 원소는 최소 minSize byte 이므로 남은 data 로 만들 수 없는 개수는 거부한다.
+func (d *decoder) readCount(minSize int) int {
+	count := int(d.readUint32())
+	if d.err == nil && count*minSize > len(d.data) {
+		d.fail(ErrTruncatedEncoding)
+		return 0
+	}
+	return count
+}
+
+func (d *decoder) readVersion() {
+	if d.readUint8() != encodingVersion {
+		d.fail(ErrNonCanonicalEncoding)
+	}
+}
+
+func (d *decoder) fail(err error) {
+	if d.err == nil {
+		d.err = err
+	}
+}
+
+// finish 는 남은 byte 가 있으면 거부하고 처음 발생한 error 를 반환한다.
+func (d *decoder) finish() error {
+	if d.err == nil && len(d.data) != 0 {
+		d.err = ErrNonCanonicalEncoding
+	}
+	return d.err
+}
+
+// EncodeTransaction 함수는 transaction 을 canonical binary 형식으로 encoding 한다.
+func EncodeTransaction(tx *DefaultTransaction) []byte {
+	buf := appendUint8(nil, encodingVersion)
+	return appendTransaction(buf, tx)
+}
+
+// DecodeTransaction 함수는 EncodeTransaction 의 결과를 transaction 으로 되돌린다.
+func DecodeTransaction(data []byte) (*DefaultTransaction, error) {
+	d := newDecoder(data)
+	d.readVersion()
+	tx := readTransaction(d)
+
+	if err := d.finish(); err != nil {
+		return nil, err
+	}
+
+	return tx, nil
+}
+
+// EncodeBlock 함수는 block 을 canonical binary 형식으로 encoding 한다.
+func EncodeBlock(block *DefaultBlock) []byte {
+	buf := appendUint8(nil, encodingVersion)
+	return appendBlock(buf, block)
+}
+
+// DecodeBlock 함수는 EncodeBlock 의 결과를 block 으로 되돌린다.
+func DecodeBlock(data []byte) (*DefaultBlock, error) {
+	d := newDecoder(data)
+	d.readVersion()
+	block := readBlock(d)
+
+	if err := d.finish(); err != nil {
+		return nil, err
+	}
+
+	return block, nil
+}
+
+// EncodeBlockList 함수는 여러 block 을 하나의 canonical binary 로 encoding 한다.
+func EncodeBlockList(blocks []Block) ([]byte, error) {
+	return appendBlockList(appendUint8(nil, encodingVersion), blocks)
+}
+
+// DecodeBlockList 함수는 EncodeBlockList 의 결과를 block list 로 되돌린다.
+func DecodeBlockList(data []byte) ([]Block, error) {
+	d := newDecoder(data)
+	d.readVersion()
+	blocks := readBlockList(d)
+
+	if err := d.finish(); err != nil {
+		return nil, err
+	}
+
+	return blocks, nil
+}
+
+// EncodeBlockRangeResponse 함수는 range 응답을 canonical binary 형식으로 encoding 한다.
+func EncodeBlockRangeResponse(response BlockRangeResponse) ([]byte, error) {
+	buf := appendUint8(nil, encodingVersion)
+	buf = appendUint64(buf, response.From)
+	buf = appendUint64(buf, response.To)
+	return appendBlockList(buf, response.Blocks)
+}
+
+// DecodeBlockRangeResponse 함수는 EncodeBlockRangeResponse 의 결과를 range 응답으로 되돌린다.
+func DecodeBlockRangeResponse(data []byte) (BlockRangeResponse, error) {
+	d := newDecoder(data)
+	d.readVersion()
+
+	response := BlockRangeResponse{}
+	response.From = d.readUint64()
+	response.To = d.readUint64()
+	response.Blocks = readBlockList(d)
+
+	if err := d.finish(); err != nil {
+		return BlockRangeResponse{}, err
+	}
+
+	return response, nil
+}
+
+func appendBlockList(buf []byte, blocks []Block) ([]byte, error) {
+	buf = appendUint32(buf, uint32(len(blocks)))
+
+	for _, block := range blocks {
+		defaultBlock, ok := block.(*DefaultBlock)
+		if !ok {
+			return nil, ErrBlockType
+		}
+		buf = appendBlock(buf, defaultBlock)
+	}
+
+	return buf, nil
+}
+
+func readBlockList(d *decoder) []Block {
+	count := d.readCount(blockMinSize)
+	blocks := make([]Block, 0, count)
+	for i := 0; i < count && d.err == nil; i++ {
+		blocks = append(blocks, readBlock(d))
+	}
+
+	return blocks
+}
+
+// appendTransactionContent 는 hash 와 서명의 대상이 되는 field 만 encoding 한다.
+// Status 와 Signature 는 transaction 이 만들어진 뒤에 바뀌므로 포함하지 않는다.
+func appendTransactionContent(buf []byte, tx *DefaultTransaction) []byte {
+	buf = appendString(buf, tx.ID)
+	buf = appendString(buf, tx.PeerID)
+	buf = appendTime(buf, tx.Timestamp)
+	return appendTxData(buf, tx.TxData)
+}
+
+func appendTransaction(buf []byte, tx *DefaultTransaction) []byte {
+	buf = appendTransactionContent(buf, tx)
+	buf = appendInt64(buf, int64(tx.Status))
+	buf = appendLengthPrefixed(buf, tx.Signature)
+	return appendZoneOffset(buf, tx.Timestamp)
+}
+
+func readTransaction(d *decoder) *DefaultTransaction {
+	tx := &DefaultTransaction{}
+	tx.ID = d.readString()
+	tx.PeerID = d.readString()
+	tx.Timestamp = d.readTime()
+	tx.TxData = readTxData(d)
+	tx.Status = Status(d.readInt64())
+	tx.Signature = d.readBytes()
+	tx.Timestamp = d.readZoneOffset(tx.Timestamp)
+	return tx
+}
+
+func appendTxData(buf []byte, txData *TxData) []byte {
+	if txData == nil {
+		return appendUint8(buf, 0)
+	}
+
+	buf = appendUint8(buf, 1)
+	buf = appendString(buf, txData.Jsonrpc)
+	buf = appendString(buf, string(txData.Method))
+	buf = appendInt64(buf, int64(txData.Params.Type))
+	buf = appendString(buf, txData.Params.Function)
+	buf = appendUint32(buf, uint32(len(txData.Params.Args)))
+	for _, arg := range txData.Params.Args {
+		buf = appendString(buf, arg)
+	}
+	return appendString(buf, txData.ID)
+}
+
+func readTxData(d *decoder) *TxData {
+	if !d.readFlag() {
+		return nil
+	}
+
+	txData := &TxData{}
+	txData.Jsonrpc = d.readString()
+	txData.Method = TxDataType(d.readString())
+	txData.Params.Type = int(d.readInt64())
+	txData.Params.Function = d.readString()
+
+	count := d.readCount(4)
+	txData.Params.Args = make([]string, 0, count)
+	for i := 0; i < count && d.err == nil; i++ {
+		txData.Params.Args = append(txData.Params.Args, d.readString())
+	}
+
+	txData.ID = d.readString()
+	return txData
+}
+
+// blockMinSize 는 빈 field 만 가진 block 의 encoding 길이이다.
+const blockMinSize = 4 + 8 + 4 + 4 + 12 + 4 + 4 + 4 + 4 + 4 + 4
+
+func appendBlock(buf []byte, block *DefaultBlock) []byte {
+	buf = appendUint32(buf, block.Version)
+	buf = appendUint64(buf, block.Height)
+	buf = appendLengthPrefixed(buf, block.Seal)
+	buf = appendLengthPrefixed(buf, block.PrevSeal)
+	buf = appendTime(buf, block.Timestamp)
+	buf = appendZoneOffset(buf, block.Timestamp)
+	buf = appendLengthPrefixed(buf, block.Creator)
+	buf = appendLengthPrefixed(buf, block.StateRoot)
+	if block.Version >= ReceiptBlockVersion {
+		buf = appendLengthPrefixed(buf, block.ReceiptsRoot)
+	}
+	buf = appendLengthPrefixed(buf, block.Signature)
+
+	buf = appendUint32(buf, uint32(len(block.TxSeal)))
+	for _, node := range block.TxSeal {
+		buf = appendLengthPrefixed(buf, node)
+	}
+
+	buf = appendUint32(buf, uint32(len(block.TxList)))
+	for _, tx := range block.TxList {
+		buf = appendTransaction(buf, tx)
+	}
+
+	return buf
+}
+
+func readBlock(d *decoder) *DefaultBlock {
+	block := &DefaultBlock{}
+	block.Version = d.readUint32()
+	block.Height = d.readUint64()
+	block.Seal = d.readBytes()
+	block.PrevSeal = d.readBytes()
+	block.Timestamp = d.readZoneOffset(d.readTime())
+	block.Creator = d.readBytes()
+	block.StateRoot = d.readBytes()
+	if block.Version >= ReceiptBlockVersion {
+		block.ReceiptsRoot = d.readBytes()
+	}
+	block.Signature = d.readBytes()
+
+	count := d.readCount(4)
+	block.TxSeal = make([][]byte, 0, count)
+	for i := 0; i < count && d.err == nil; i++ {
+		block.TxSeal = append(block.TxSeal, d.readBytes())
+	}
+
+	count = d.readCount(txMinSize)
+	block.TxList = make([]*DefaultTransaction, 0, count)
+	for i := 0; i < count && d.err == nil; i++ {
+		block.TxList = append(block.TxList, readTransaction(d))
+	}
+
+	return block
+}
+
+// txMinSize 는 빈 field 만 가진 transaction 의 encoding 길이이다.
+const txMinSize = 4 + 4 + 12 + 1 + 8 + 4 + 4
@@ -0,0 +1,207 @@
+package blockchain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/it-chain/engine/blockchain"
+	"github.com/stretchr/testify/assert"
+)
+
+func newEncodingTestTx(id string) *blockchain.DefaultTransaction {
+	txData := blockchain.NewTxData("jsonrpc", blockchain.Invoke, blockchain.NewParams(1, "function", []string{"arg1", "arg2"}), "contract")
+	tx := blockchain.NewDefaultTransaction("peer", id, time.Unix(1500000000, 123456789).UTC(), txData)
+	tx.SetSignature([]byte("signature"))
+	return tx
+}
+
+func newEncodingTestBlock() *blockchain.DefaultBlock {
+	return &blockchain.DefaultBlock{
+		Seal:         []byte("seal"),
+		PrevSeal:     []byte("prevseal"),
+		Height:       3,
+		TxList:       []*blockchain.DefaultTransaction{newEncodingTestTx("tx1"), newEncodingTestTx("tx2")},
+		TxSeal:       [][]byte{[]byte("root"), []byte("leaf1"), []byte("leaf2")},
+		Timestamp:    time.Unix(1500000000, 987654321).UTC(),
+		Creator:      []byte("creator"),
+		Version:      blockchain.CurrentBlockVersion,
+		StateRoot:    []byte("stateroot"),
+		ReceiptsRoot: []byte("receiptsroot"),
+		Signature:    []byte("signature"),
+	}
+}
+
+func TestEncodeBlock_HeaderVersionHasNoReceiptsRoot(t *testing.T) {
+	// given
+	block := newEncodingTestBlock()
+	block.Version = blockchain.HeaderBlockVersion
+	block.ReceiptsRoot = nil
+
+	withReceiptsRoot := newEncodingTestBlock()
+
+	// when
+	data := blockchain.EncodeBlock(block)
+	decoded, err := blockchain.DecodeBlock(data)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, block.Signature, decoded.Signature)
+	assert.Equal(t, 0, len(decoded.ReceiptsRoot))
+	assert.Equal(t, len(blockchain.EncodeBlock(withReceiptsRoot))-len(data), 4+len(withReceiptsRoot.ReceiptsRoot))
+}
+
+func TestEncodeBlock_RoundTrip(t *testing.T) {
+	// given
+	block := newEncodingTestBlock()
+
+	// when
+	data := blockchain.EncodeBlock(block)
+	decoded, err := blockchain.DecodeBlock(data)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, block, decoded)
+	assert.Equal(t, data, blockchain.EncodeBlock(decoded))
+}
+
+func TestEncodeTransaction_RoundTrip(t *testing.T) {
+	tests := map[string]struct {
+		input *blockchain.DefaultTransaction
+	}{
+		"with tx data": {
+			input: newEncodingTestTx("tx1"),
+		},
+		"without tx data": {
+			input: &blockchain.DefaultTransaction{
+				ID:        "tx1",
+				Timestamp: time.Unix(0, 0).UTC(),
+				Signature: []byte{},
+			},
+		},
+	}
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+
+		// when
+		data := blockchain.EncodeTransaction(test.input)
+		decoded, err := blockchain.DecodeTransaction(data)
+
+		// then
+		assert.NoError(t, err)
+		assert.Equal(t, test.input, decoded)
+	}
+}
+
+func TestEncodeBlockList_RoundTrip(t *testing.T) {
+	// given
+	blocks := []blockchain.Block{newEncodingTestBlock(), newEncodingTestBlock()}
+
+	// when
+	data, err := blockchain.EncodeBlockList(blocks)
+	assert.NoError(t, err)
+	decoded, err := blockchain.DecodeBlockList(data)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, blocks, decoded)
+}
+
+func TestDecodeBlock_NonCanonical(t *testing.T) {
+	data := blockchain.EncodeBlock(newEncodingTestBlock())
+
+	// timestamp 의 nanosecond 위치 : version(1) + block version(4) + height(8) + seal(4+4) + prevSeal(4+8) + seconds(8)
+	nanosOffset := 1 + 4 + 8 + 8 + 12 + 8
+
+	tests := map[string]struct {
+		input func() []byte
+		err   error
+	}{
+		"trailing bytes": {
+			input: func() []byte {
+				return append(append([]byte{}, data...), 0)
+			},
+			err: blockchain.ErrNonCanonicalEncoding,
+		},
+		"truncated": {
+			input: func() []byte {
+				return data[:len(data)-1]
+			},
+			err: blockchain.ErrTruncatedEncoding,
+		},
+		"unknown encoding version": {
+			input: func() []byte {
+				b := append([]byte{}, data...)
+				b[0] = 2
+				return b
+			},
+			err: blockchain.ErrNonCanonicalEncoding,
+		},
+		"nanosecond overflow": {
+			input: func() []byte {
+				b := append([]byte{}, data...)
+				b[nanosOffset] = 0xff
+				return b
+			},
+			err: blockchain.ErrNonCanonicalEncoding,
+		},
+		"length larger than data": {
+			input: func() []byte {
+				b := append([]byte{}, data...)
+				b[1+4+8] = 0xff
+				return b
+			},
+			err: blockchain.ErrTruncatedEncoding,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+
+		// when
+		block, err := blockchain.DecodeBlock(test.input())
+
+		// then
+		assert.Equal(t, test.err, err)
+		assert.Nil(t, block)
+	}
+}
+
+func TestDecodeTransaction_InvalidTxDataFlag(t *testing.T) {
+	// given
+	tx := &blockchain.DefaultTransaction{ID: "a", PeerID: "b"}
+	data := blockchain.EncodeTransaction(tx)
+
+	// tx data flag 위치 : version(1) + id(4+1) + peerId(4+1) + timestamp(12)
+	data[1+5+5+12] = 2
+
+	// when
+	_, err := blockchain.DecodeTransaction(data)
+
+	// then
+	assert.Equal(t, blockchain.ErrNonCanonicalEncoding, err)
+}
+
+func TestDefaultTransaction_CalculateSeal(t *testing.T) {
+	// given
+	tx := newEncodingTestTx("tx1")
+	seal, err := tx.CalculateSeal()
+	assert.NoError(t, err)
+
+	// when
+	tx.Status = blockchain.StatusTransactionValid
+	tx.SetSignature([]byte("other signature"))
+	changedSeal, err := tx.CalculateSeal()
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, seal, changedSeal)
+
+	// when
+	tx.TxData.Params.Args = []string{"arg1", "arg3"}
+	changedSeal, err = tx.CalculateSeal()
+
+	// then
+	assert.NoError(t, err)
+	assert.NotEqual(t, seal, changedSeal)
+}
@@ -11,3 +11,4 @@ var ErrBuildingTxSeal = errors.New("Error in building tx seal")
 var ErrBuildingSeal = errors.New("Error in building seal")
 var ErrCreatingEvent = errors.New("Error in creating event")
 var ErrOnEvent = errors.New("Error on event")
+var ErrBlockNotFound = errors.New("block not found")
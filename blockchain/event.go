@@ -6,6 +6,23 @@ import (
 	"github.com/it-chain/midgard"
 )
 
+// Events 는 blockchain 이 eventstore 에 저장하는 모든 event 이다.
+// eventstore 에서 aggregate 를 복원하려면 이 event 들이 serializer 에 등록되어 있어야 한다.
+func Events() []midgard.Event {
+	return []midgard.Event{
+		SyncStartEvent{},
+		SyncDoneEvent{},
+		BlockAddToPoolEvent{},
+		BlockRemoveFromPoolEvent{},
+		BlockPoolCompactedEvent{},
+		BlockPoolRotatedEvent{},
+		BlockCommittedEvent{},
+		BlockCreatedEvent{},
+		BlockRolledBackEvent{},
+		BlockReappliedEvent{},
+	}
+}
+
 type SyncStartEvent struct {
 	midgard.EventModel
 }
@@ -16,33 +33,97 @@ type SyncDoneEvent struct {
 
 type BlockAddToPoolEvent struct {
 	midgard.EventModel
-	Seal      []byte
-	PrevSeal  []byte
-	Height    uint64
-	TxList    []byte
-	TxSeal    [][]byte
-	Timestamp time.Time
-	Creator   []byte
+	Seal         []byte
+	PrevSeal     []byte
+	Height       uint64
+	TxList       []byte
+	TxSeal       [][]byte
+	Timestamp    time.Time
+	Creator      []byte
+	Version      BlockVersion
+	StateRoot    []byte
+	ReceiptsRoot []byte
+	Signature    []byte
 }
 
 type BlockRemoveFromPoolEvent struct {
 	midgard.EventModel
 	Height uint64
+	Seal   []byte
 }
 
-// event when block is saved to event store
-type BlockCommittedEvent struct {
+// event when block pool's event stream is compacted.
+// Blocks 는 EncodeBlockList 로 encoding 한 pool 의 모든 block 이고, AddedAt 은 각 block 이 pool 에 추가된 시각이다.
+type BlockPoolCompactedEvent struct {
 	midgard.EventModel
-	Seal string
+	Blocks  []byte
+	AddedAt []time.Time
 }
 
-type BlockCreatedEvent struct {
+// event when block pool starts to save events to the stream of the next generation.
+// BLOCK_POOL_AID 에 저장되며, Generation 의 stream 은 BlockPoolCompactedEvent 로 시작한다.
+type BlockPoolRotatedEvent struct {
+	midgard.EventModel
+	Generation uint64
+}
+
+// event when block is committed to repository
+// txpool, icode, api gateway 가 block 을 다시 조회하지 않도록 header 와 transaction ID 목록을 담는다.
+type BlockCommittedEvent struct {
 	midgard.EventModel
 	Seal      []byte
 	PrevSeal  []byte
 	Height    uint64
-	TxList    []byte
-	TxSeal    [][]byte
 	Timestamp time.Time
 	Creator   []byte
+	TxIDs     []string
+}
+
+type BlockCreatedEvent struct {
+	midgard.EventModel
+	Seal         []byte
+	PrevSeal     []byte
+	Height       uint64
+	TxList       []byte
+	TxSeal       [][]byte
+	Timestamp    time.Time
+	Creator      []byte
+	Version      BlockVersion
+	StateRoot    []byte
+	ReceiptsRoot []byte
+	Signature    []byte
+}
+
+// event when block is removed from committed chain by reorganization
+// receipt 를 지우고 transaction 을 txpool 에 다시 넣을 수 있도록 transaction ID 목록과 transaction 들을 담는다.
+// TxList 는 BlockAddToPoolEvent 와 같이 json 으로 encoding 한 block 의 transaction 들이다.
+type BlockRolledBackEvent struct {
+	midgard.EventModel
+	Seal   string
+	Height uint64
+	TxIDs  []string
+	TxList []byte
+}
+
+// event when block of preferred branch is committed by reorganization
+type BlockReappliedEvent struct {
+	midgard.EventModel
+	Seal   string
+	Height uint64
+}
+
+// icode component 의 event 로, snapshot 에 담을 icode state 를 기록하기 위해 구독한다.
+// blockchain 의 event 가 아니므로 Events 에는 포함하지 않는다.
+// type : meta.created
+type MetaCreatedEvent struct {
+	midgard.EventModel
+	RepositoryName string
+	GitUrl         string
+	Path           string
+	CommitHash     string
+}
+
+// type : meta.deleted
+type MetaDeletedEvent struct {
+	midgard.EventModel
 }
@@ -0,0 +1,243 @@
+package blockchain
+
+import (
+	"errors"
+	"log"
+
+	"github.com/it-chain/engine/common"
+	"github.com/it-chain/engine/core/eventstore"
+	"github.com/it-chain/midgard"
+)
+
+var ErrBranchNotConnected = errors.New("branch does not connect to committed chain")
+
+// Branch 는 committed chain 의 ForkPoint block 에서 갈라져 나온 pool 의 block 들이다.
+// Blocks 는 height 오름차순이고, 첫 block 의 PrevSeal 은 ForkPoint 의 seal 이다.
+type Branch struct {
+	ForkPoint Block
+	Blocks    []Block
+}
+
+func (branch Branch) Tip() Block {
+	if len(branch.Blocks) == 0 {
+		return branch.ForkPoint
+	}
+
+	return branch.Blocks[len(branch.Blocks)-1]
+}
+
+// FindBranch 함수는 block 에서 PrevSeal 을 따라 pool 을 거슬러 올라가 committed chain 과 만나는 지점을 찾는다.
+// 중간에 끊기거나 height 가 이어지지 않으면 ErrBranchNotConnected 를 반환한다.
+func FindBranch(block Block, pool BlockPool, blockQueryApi BlockQueryApi) (Branch, error) {
+	blocks := []Block{block}
+
+	for {
+		first := blocks[0]
+
+		forkPoint, err := blockQueryApi.GetBlockBySeal(first.GetPrevSeal())
+		if err == nil {
+			if forkPoint.GetHeight()+1 != first.GetHeight() {
+				return Branch{}, ErrBranchNotConnected
+			}
+
+			return Branch{ForkPoint: forkPoint, Blocks: blocks}, nil
+		}
+
+		if err != ErrBlockNotFound {
+			return Branch{}, err
+		}
+
+		parent := pool.GetBySeal(first.GetPrevSeal())
+		if parent == nil || parent.GetHeight()+1 != first.GetHeight() {
+			return Branch{}, ErrBranchNotConnected
+		}
+
+		blocks = append([]Block{parent}, blocks...)
+	}
+}
+
+// ExtendBranch 함수는 branch 의 tip 에 이어지는 pool 의 block 중 가장 긴 경로를 branch 에 붙인다.
+// 길이가 같은 경로가 여럿이면 seal 이 작은 block 쪽을 고른다.
+func ExtendBranch(branch Branch, pool BlockPool) Branch {
+	blocks := append(make([]Block, 0, len(branch.Blocks)), branch.Blocks...)
+
+	return Branch{
+		ForkPoint: branch.ForkPoint,
+		Blocks:    append(blocks, longestPath(branch.Tip(), pool)...),
+	}
+}
+
+func longestPath(block Block, pool BlockPool) []Block {
+	longest := make([]Block, 0)
+
+	for _, child := range pool.GetByPrevSeal(block.GetSeal()) {
+		if child.GetHeight() != block.GetHeight()+1 {
+			continue
+		}
+
+		path := append([]Block{child}, longestPath(child, pool)...)
+		if len(path) > len(longest) {
+			longest = path
+		}
+	}
+
+	return longest
+}
+
+// ReorgRule 은 committed chain 을 branch 로 바꿀지 결정한다.
+// current 는 branch 의 ForkPoint 다음부터 마지막 block 까지 commit 된 block 들이다.
+type ReorgRule interface {
+	ShouldReorg(current []Block, branch Branch) bool
+}
+
+// LongestChainRule 은 branch 가 commit 된 chain 보다 길 때만 reorg 한다.
+type LongestChainRule struct{}
+
+func (LongestChainRule) ShouldReorg(current []Block, branch Branch) bool {
+	return len(branch.Blocks) > len(current)
+}
+
+// FinalityChecker 는 block 이 합의의 commit certificate 등으로 확정되었는지 알려준다.
+type FinalityChecker interface {
+	IsFinal(block Block) bool
+}
+
+// FinalityRule 은 확정된 block 은 되돌리지 않고, 확정된 block 을 가진 branch 로는 항상 reorg 한다.
+// 둘 다 아니면 fallback rule 을 따른다.
+type FinalityRule struct {
+	checker  FinalityChecker
+	fallback ReorgRule
+}
+
+func NewFinalityRule(checker FinalityChecker, fallback ReorgRule) *FinalityRule {
+	return &FinalityRule{
+		checker:  checker,
+		fallback: fallback,
+	}
+}
+
+func (rule *FinalityRule) ShouldReorg(current []Block, branch Branch) bool {
+	for _, block := range current {
+		if rule.checker.IsFinal(block) {
+			return false
+		}
+	}
+
+	for _, block := range branch.Blocks {
+		if rule.checker.IsFinal(block) {
+			return true
+		}
+	}
+
+	return rule.fallback.ShouldReorg(current, branch)
+}
+
+// FinalityRecord 는 합의가 commit 한 block 의 seal 을 기록한다.
+type FinalityRecord interface {
+	SaveFinalSeal(seal []byte) error
+	IsFinalSeal(seal []byte) (bool, error)
+}
+
+// ConsensusFinalityChecker 는 합의가 commit 한 것으로 기록된 block 을 확정된 block 으로 본다.
+type ConsensusFinalityChecker struct {
+	record FinalityRecord
+}
+
+func NewConsensusFinalityChecker(record FinalityRecord) *ConsensusFinalityChecker {
+	return &ConsensusFinalityChecker{
+		record: record,
+	}
+}
+
+// IsFinal 은 block 의 seal 이 합의의 commit 기록에 있는지 확인한다.
+// 기록을 읽지 못하면 확정된 block 을 되돌리지 않도록 확정된 것으로 본다.
+func (checker *ConsensusFinalityChecker) IsFinal(block Block) bool {
+	final, err := checker.record.IsFinalSeal(block.GetSeal())
+	if err != nil {
+		log.Printf("fail to read finality of block [%v]: [%v]", block.GetHeight(), err)
+		return true
+	}
+
+	return final
+}
+
+type RollbackAction struct {
+	blockRepository BlockRepository
+}
+
+func NewRollbackAction(blockRepository BlockRepository) *RollbackAction {
+	return &RollbackAction{
+		blockRepository: blockRepository,
+	}
+}
+
+// DoAction 은 마지막 block 을 repository 에서 제거하고 BlockRolledBackEvent 를 저장한다.
+// receipt 와 icode state 를 되돌리고 transaction 을 txpool 에 다시 넣는 것은 event 를 받은 component 가 한다.
+func (rollbackAction *RollbackAction) DoAction(block Block) error {
+	event, err := createBlockRolledBackEvent(block)
+	if err != nil {
+		return err
+	}
+
+	if err := rollbackAction.blockRepository.RemoveBlock(block); err != nil {
+		return err
+	}
+
+	return eventstore.Save(event.Seal, event)
+}
+
+type ReapplyAction struct {
+	blockRepository BlockRepository
+}
+
+func NewReapplyAction(blockRepository BlockRepository) *ReapplyAction {
+	return &ReapplyAction{
+		blockRepository: blockRepository,
+	}
+}
+
+// DoAction 은 branch 의 block 을 commit 하고 BlockCommittedEvent 다음에 BlockReappliedEvent 를 저장한다.
+func (reapplyAction *ReapplyAction) DoAction(block Block) error {
+	if err := NewSaveAction(reapplyAction.blockRepository).DoAction(block); err != nil {
+		return err
+	}
+
+	event := createBlockReappliedEvent(block)
+	return eventstore.Save(event.Seal, event)
+}
+
+func createBlockRolledBackEvent(block Block) (BlockRolledBackEvent, error) {
+	txListBytes, err := common.Serialize(block.GetTxList())
+	if err != nil {
+		return BlockRolledBackEvent{}, ErrTxListMarshal
+	}
+
+	txIDs := make([]string, 0, len(block.GetTxList()))
+	for _, tx := range block.GetTxList() {
+		txIDs = append(txIDs, tx.GetID())
+	}
+
+	seal := string(block.GetSeal())
+	return BlockRolledBackEvent{
+		EventModel: midgard.EventModel{
+			ID:   seal,
+			Type: "block.rolledback",
+		},
+		Seal:   seal,
+		Height: block.GetHeight(),
+		TxIDs:  txIDs,
+		TxList: txListBytes,
+	}, nil
+}
+
+func createBlockReappliedEvent(block Block) BlockReappliedEvent {
+	seal := string(block.GetSeal())
+	return BlockReappliedEvent{
+		EventModel: midgard.EventModel{
+			ID:   seal,
+			Type: "block.reapplied",
+		},
+		Seal:   seal,
+		Height: block.GetHeight(),
+	}
+}
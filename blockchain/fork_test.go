@@ -0,0 +1,228 @@
+package blockchain_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/it-chain/engine/blockchain"
+	"github.com/it-chain/engine/blockchain/test/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindBranch(t *testing.T) {
+	// given
+	committed := map[string]blockchain.Block{
+		"seal0": &blockchain.DefaultBlock{Seal: []byte("seal0"), Height: 0},
+		"a1":    &blockchain.DefaultBlock{Seal: []byte("a1"), PrevSeal: []byte("seal0"), Height: 1},
+	}
+
+	queryApi := mock.BlockQueryApi{}
+	queryApi.GetBlockBySealFunc = func(seal []byte) (blockchain.Block, error) {
+		if block, ok := committed[string(seal)]; ok {
+			return block, nil
+		}
+		return nil, blockchain.ErrBlockNotFound
+	}
+
+	pool := blockchain.NewBlockPool()
+	pool.Add(&blockchain.DefaultBlock{Seal: []byte("b1"), PrevSeal: []byte("seal0"), Height: 1})
+	pool.Add(&blockchain.DefaultBlock{Seal: []byte("b2"), PrevSeal: []byte("b1"), Height: 2})
+	pool.Add(&blockchain.DefaultBlock{Seal: []byte("gap"), PrevSeal: []byte("b1"), Height: 5})
+
+	tests := map[string]struct {
+		input struct {
+			block blockchain.Block
+		}
+		output struct {
+			forkPoint []byte
+			length    int
+		}
+		err error
+	}{
+		"success: branch from genesis": {
+			input: struct {
+				block blockchain.Block
+			}{block: pool.GetBySeal([]byte("b2"))},
+			output: struct {
+				forkPoint []byte
+				length    int
+			}{forkPoint: []byte("seal0"), length: 2},
+			err: nil,
+		},
+		"success: next block of last block": {
+			input: struct {
+				block blockchain.Block
+			}{block: &blockchain.DefaultBlock{Seal: []byte("a2"), PrevSeal: []byte("a1"), Height: 2}},
+			output: struct {
+				forkPoint []byte
+				length    int
+			}{forkPoint: []byte("a1"), length: 1},
+			err: nil,
+		},
+		"fail: parent not in pool": {
+			input: struct {
+				block blockchain.Block
+			}{block: &blockchain.DefaultBlock{Seal: []byte("c3"), PrevSeal: []byte("c2"), Height: 3}},
+			err: blockchain.ErrBranchNotConnected,
+		},
+		"fail: height not connected": {
+			input: struct {
+				block blockchain.Block
+			}{block: pool.GetBySeal([]byte("gap"))},
+			err: blockchain.ErrBranchNotConnected,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+
+		// when
+		branch, err := blockchain.FindBranch(test.input.block, pool, queryApi)
+
+		// then
+		assert.Equal(t, test.err, err)
+		if err != nil {
+			continue
+		}
+		assert.Equal(t, test.output.forkPoint, branch.ForkPoint.GetSeal())
+		assert.Equal(t, test.output.length, len(branch.Blocks))
+		assert.Equal(t, test.input.block.GetSeal(), branch.Tip().GetSeal())
+	}
+}
+
+func TestExtendBranch(t *testing.T) {
+	// given
+	forkPoint := &blockchain.DefaultBlock{Seal: []byte("seal0"), Height: 0}
+
+	pool := blockchain.NewBlockPool()
+	pool.Add(&blockchain.DefaultBlock{Seal: []byte("a1"), PrevSeal: []byte("seal0"), Height: 1})
+	pool.Add(&blockchain.DefaultBlock{Seal: []byte("b1"), PrevSeal: []byte("seal0"), Height: 1})
+	pool.Add(&blockchain.DefaultBlock{Seal: []byte("b2"), PrevSeal: []byte("b1"), Height: 2})
+	pool.Add(&blockchain.DefaultBlock{Seal: []byte("c1"), PrevSeal: []byte("seal0"), Height: 1})
+
+	// when
+	branch := blockchain.ExtendBranch(blockchain.Branch{ForkPoint: forkPoint}, pool)
+
+	// then: the longest path is chosen
+	assert.Equal(t, 2, len(branch.Blocks))
+	assert.Equal(t, []byte("b1"), branch.Blocks[0].GetSeal())
+	assert.Equal(t, []byte("b2"), branch.Tip().GetSeal())
+
+	// when
+	pool.Delete(pool.GetBySeal([]byte("b2")))
+	branch = blockchain.ExtendBranch(blockchain.Branch{ForkPoint: forkPoint}, pool)
+
+	// then: paths of same length are chosen by lowest seal
+	assert.Equal(t, 1, len(branch.Blocks))
+	assert.Equal(t, []byte("a1"), branch.Tip().GetSeal())
+}
+
+func TestReorgRule(t *testing.T) {
+	a1 := &blockchain.DefaultBlock{Seal: []byte("a1"), Height: 1}
+	a2 := &blockchain.DefaultBlock{Seal: []byte("a2"), Height: 2}
+	b1 := &blockchain.DefaultBlock{Seal: []byte("b1"), Height: 1}
+	b2 := &blockchain.DefaultBlock{Seal: []byte("b2"), Height: 2}
+	b3 := &blockchain.DefaultBlock{Seal: []byte("b3"), Height: 3}
+
+	finalSeals := make(map[string]bool)
+	checker := mock.FinalityChecker{}
+	checker.IsFinalFunc = func(block blockchain.Block) bool {
+		return finalSeals[string(block.GetSeal())]
+	}
+
+	tests := map[string]struct {
+		input struct {
+			rule    blockchain.ReorgRule
+			final   []string
+			current []blockchain.Block
+			branch  []blockchain.Block
+		}
+		output bool
+	}{
+		"longest: longer branch": {
+			input: struct {
+				rule    blockchain.ReorgRule
+				final   []string
+				current []blockchain.Block
+				branch  []blockchain.Block
+			}{rule: blockchain.LongestChainRule{}, current: []blockchain.Block{a1, a2}, branch: []blockchain.Block{b1, b2, b3}},
+			output: true,
+		},
+		"longest: same length": {
+			input: struct {
+				rule    blockchain.ReorgRule
+				final   []string
+				current []blockchain.Block
+				branch  []blockchain.Block
+			}{rule: blockchain.LongestChainRule{}, current: []blockchain.Block{a1, a2}, branch: []blockchain.Block{b1, b2}},
+			output: false,
+		},
+		"finality: current is final": {
+			input: struct {
+				rule    blockchain.ReorgRule
+				final   []string
+				current []blockchain.Block
+				branch  []blockchain.Block
+			}{rule: blockchain.NewFinalityRule(checker, blockchain.LongestChainRule{}), final: []string{"a1", "b3"}, current: []blockchain.Block{a1, a2}, branch: []blockchain.Block{b1, b2, b3}},
+			output: false,
+		},
+		"finality: branch is final": {
+			input: struct {
+				rule    blockchain.ReorgRule
+				final   []string
+				current []blockchain.Block
+				branch  []blockchain.Block
+			}{rule: blockchain.NewFinalityRule(checker, blockchain.LongestChainRule{}), final: []string{"b1"}, current: []blockchain.Block{a1, a2}, branch: []blockchain.Block{b1}},
+			output: true,
+		},
+		"finality: fallback": {
+			input: struct {
+				rule    blockchain.ReorgRule
+				final   []string
+				current []blockchain.Block
+				branch  []blockchain.Block
+			}{rule: blockchain.NewFinalityRule(checker, blockchain.LongestChainRule{}), current: []blockchain.Block{a1}, branch: []blockchain.Block{b1, b2}},
+			output: true,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+
+		// given
+		finalSeals = make(map[string]bool)
+		for _, seal := range test.input.final {
+			finalSeals[seal] = true
+		}
+
+		// when
+		result := test.input.rule.ShouldReorg(test.input.current, blockchain.Branch{Blocks: test.input.branch})
+
+		// then
+		assert.Equal(t, test.output, result)
+	}
+}
+
+func TestConsensusFinalityChecker_IsFinal(t *testing.T) {
+	// given
+	record := mock.FinalityRecord{}
+	record.IsFinalSealFunc = func(seal []byte) (bool, error) {
+		switch string(seal) {
+		case "final":
+			return true, nil
+		case "broken":
+			return false, errors.New("leveldb closed")
+		default:
+			return false, nil
+		}
+	}
+
+	checker := blockchain.NewConsensusFinalityChecker(record)
+
+	// when, then
+	assert.True(t, checker.IsFinal(&blockchain.DefaultBlock{Seal: []byte("final")}))
+	assert.False(t, checker.IsFinal(&blockchain.DefaultBlock{Seal: []byte("synced")}))
+
+	// 기록을 읽지 못한 block 은 되돌리지 않는다.
+	assert.True(t, checker.IsFinal(&blockchain.DefaultBlock{Seal: []byte("broken")}))
+}
@@ -0,0 +1,155 @@
+package blockchain
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"time"
+)
+
+var ErrEmptyChainID = errors.New("genesis chain id is empty")
+var ErrEmptyGenesisCreator = errors.New("genesis creator is empty")
+var ErrGenesisSealMismatch = errors.New("genesis seal does not match genesis config")
+
+// GenesisConfig 는 모든 node 가 같은 genesis block 으로 시작하기 위해 공유하는 genesis 파일의 내용이다.
+// Seal 은 나머지 field 로 만든 genesis block 의 seal 이다.
+type GenesisConfig struct {
+	ChainID    string
+	Creator    string
+	Parliament []string
+	Timestamp  time.Time
+	Seal       []byte
+}
+
+// NewGenesisConfig 함수는 주어진 내용으로 genesis block 을 만들고 seal 을 채운 GenesisConfig 를 반환한다.
+func NewGenesisConfig(chainID string, creator string, parliament []string, timestamp time.Time) (GenesisConfig, error) {
+	config := GenesisConfig{
+		ChainID:    chainID,
+		Creator:    creator,
+		Parliament: parliament,
+		Timestamp:  timestamp.Round(0).UTC(),
+	}
+
+	block, err := NewGenesisBlock(config)
+	if err != nil {
+		return GenesisConfig{}, err
+	}
+
+	config.Seal = block.GetSeal()
+
+	return config, nil
+}
+
+// genesisBlockVersion 은 genesis block 의 version 이다. genesis 에는 실행할 transaction 이 없으므로
+// block version 이 올라가도 이미 만들어진 chain 의 genesis seal 이 바뀌지 않도록 고정한다.
+const genesisBlockVersion = HeaderBlockVersion
+
+// NewGenesisBlock 함수는 config 로부터 genesis block 을 만든다.
+// 같은 config 로는 항상 같은 block 이 만들어진다.
+// chain id 와 parliament 는 StateRoot 로 seal 에 포함된다.
+func NewGenesisBlock(config GenesisConfig) (*DefaultBlock, error) {
+	if config.ChainID == "" {
+		return nil, ErrEmptyChainID
+	}
+
+	if config.Creator == "" {
+		return nil, ErrEmptyGenesisCreator
+	}
+
+	block := &DefaultBlock{
+		PrevSeal:  make([]byte, 0),
+		Height:    0,
+		TxList:    make([]*DefaultTransaction, 0),
+		TxSeal:    make([][]byte, 0),
+		Timestamp: config.Timestamp,
+		Creator:   []byte(config.Creator),
+		Version:   genesisBlockVersion,
+		StateRoot: genesisStateRoot(config),
+	}
+
+	validator := DefaultValidator{}
+	seal, err := validator.BuildBlockSeal(block)
+	if err != nil {
+		return nil, err
+	}
+
+	block.Seal = seal
+
+	return block, nil
+}
+
+// VerifyGenesisConfig 함수는 config 의 Seal 이 나머지 field 로 만든 genesis block 의 seal 과 같은지 확인한다.
+func VerifyGenesisConfig(config GenesisConfig) error {
+	block, err := NewGenesisBlock(config)
+	if err != nil {
+		return err
+	}
+
+	if !bytes.Equal(block.GetSeal(), config.Seal) {
+		return ErrGenesisSealMismatch
+	}
+
+	return nil
+}
+
+func LoadGenesisConfig(filePath string) (GenesisConfig, error) {
+	data, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return GenesisConfig{}, err
+	}
+
+	config := GenesisConfig{}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return GenesisConfig{}, err
+	}
+
+	return config, nil
+}
+
+func SaveGenesisConfig(filePath string, config GenesisConfig) error {
+	data, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(filePath, data, 0644)
+}
+
+// CheckGenesisBlock 함수는 저장된 genesis block 이 config 의 genesis block 과 같은지 확인한다.
+// 저장된 genesis block 이 없으면 config 로 genesis block 을 만들어 저장한다.
+func CheckGenesisBlock(config GenesisConfig, blockRepository BlockRepository) error {
+	if err := VerifyGenesisConfig(config); err != nil {
+		return err
+	}
+
+	storedBlock, err := blockRepository.GetBlockByHeight(0)
+	if err == ErrBlockNotFound {
+		genesisBlock, err := createGenesisBlock(config)
+		if err != nil {
+			return err
+		}
+
+		return blockRepository.AddBlock(genesisBlock)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if !bytes.Equal(storedBlock.GetSeal(), config.Seal) {
+		return ErrGenesisSealMismatch
+	}
+
+	return nil
+}
+
+func genesisStateRoot(config GenesisConfig) []byte {
+	buf := appendString(nil, config.ChainID)
+	buf = appendUint32(buf, uint32(len(config.Parliament)))
+	for _, member := range config.Parliament {
+		buf = appendString(buf, member)
+	}
+
+	return calculateHash(buf)
+}
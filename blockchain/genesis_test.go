@@ -0,0 +1,193 @@
+package blockchain_test
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/it-chain/engine/blockchain"
+	"github.com/it-chain/engine/blockchain/test/mock"
+	"github.com/it-chain/engine/core/eventstore"
+	"github.com/it-chain/midgard"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewGenesisBlock_Deterministic(t *testing.T) {
+	// given
+	timestamp := time.Now()
+	config, err := blockchain.NewGenesisConfig("chain", "creator", []string{"creator", "member"}, timestamp)
+	assert.NoError(t, err)
+
+	// when
+	block1, err1 := blockchain.NewGenesisBlock(config)
+	block2, err2 := blockchain.NewGenesisBlock(config)
+
+	// then
+	assert.NoError(t, err1)
+	assert.NoError(t, err2)
+	assert.Equal(t, config.Seal, block1.GetSeal())
+	assert.Equal(t, block1, block2)
+	assert.Equal(t, uint64(0), block1.GetHeight())
+	assert.Equal(t, []byte("creator"), block1.GetCreator())
+}
+
+func TestVerifyGenesisConfig(t *testing.T) {
+	config, err := blockchain.NewGenesisConfig("chain", "creator", []string{"creator"}, time.Now())
+	assert.NoError(t, err)
+
+	tests := map[string]struct {
+		input func() blockchain.GenesisConfig
+		err   error
+	}{
+		"success": {
+			input: func() blockchain.GenesisConfig {
+				return config
+			},
+			err: nil,
+		},
+		"chain id changed": {
+			input: func() blockchain.GenesisConfig {
+				c := config
+				c.ChainID = "other"
+				return c
+			},
+			err: blockchain.ErrGenesisSealMismatch,
+		},
+		"parliament changed": {
+			input: func() blockchain.GenesisConfig {
+				c := config
+				c.Parliament = []string{"intruder"}
+				return c
+			},
+			err: blockchain.ErrGenesisSealMismatch,
+		},
+		"timestamp changed": {
+			input: func() blockchain.GenesisConfig {
+				c := config
+				c.Timestamp = c.Timestamp.Add(time.Second)
+				return c
+			},
+			err: blockchain.ErrGenesisSealMismatch,
+		},
+		"empty chain id": {
+			input: func() blockchain.GenesisConfig {
+				c := config
+				c.ChainID = ""
+				return c
+			},
+			err: blockchain.ErrEmptyChainID,
+		},
+		"empty creator": {
+			input: func() blockchain.GenesisConfig {
+				c := config
+				c.Creator = ""
+				return c
+			},
+			err: blockchain.ErrEmptyGenesisCreator,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+
+		// when
+		err := blockchain.VerifyGenesisConfig(test.input())
+
+		// then
+		assert.Equal(t, test.err, err)
+	}
+}
+
+func TestSaveAndLoadGenesisConfig(t *testing.T) {
+	// given
+	filePath := "./genesis_test.json"
+	defer os.Remove(filePath)
+
+	config, err := blockchain.NewGenesisConfig("chain", "creator", []string{"creator"}, time.Now())
+	assert.NoError(t, err)
+
+	// when
+	assert.NoError(t, blockchain.SaveGenesisConfig(filePath, config))
+	loaded, err := blockchain.LoadGenesisConfig(filePath)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, config.Seal, loaded.Seal)
+	assert.NoError(t, blockchain.VerifyGenesisConfig(loaded))
+}
+
+func TestCheckGenesisBlock(t *testing.T) {
+	config, err := blockchain.NewGenesisConfig("chain", "creator", []string{"creator"}, time.Now())
+	assert.NoError(t, err)
+
+	otherConfig, err := blockchain.NewGenesisConfig("other chain", "creator", []string{"creator"}, time.Now())
+	assert.NoError(t, err)
+	otherGenesis, err := blockchain.NewGenesisBlock(otherConfig)
+	assert.NoError(t, err)
+
+	tests := map[string]struct {
+		input struct {
+			storedBlock blockchain.Block
+			storedErr   error
+		}
+		added bool
+		err   error
+	}{
+		"store genesis block when empty": {
+			input: struct {
+				storedBlock blockchain.Block
+				storedErr   error
+			}{storedBlock: nil, storedErr: blockchain.ErrBlockNotFound},
+			added: true,
+			err:   nil,
+		},
+		"same genesis block": {
+			input: struct {
+				storedBlock blockchain.Block
+				storedErr   error
+			}{storedBlock: &blockchain.DefaultBlock{Seal: config.Seal}, storedErr: nil},
+			added: false,
+			err:   nil,
+		},
+		"different genesis block": {
+			input: struct {
+				storedBlock blockchain.Block
+				storedErr   error
+			}{storedBlock: otherGenesis, storedErr: nil},
+			added: false,
+			err:   blockchain.ErrGenesisSealMismatch,
+		},
+	}
+
+	repo := MockRepostiory{}
+	repo.saveFunc = func(aggregateID string, events ...midgard.Event) error {
+		return nil
+	}
+
+	eventstore.InitForMock(repo)
+	defer eventstore.Close()
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+
+		// given
+		added := false
+		blockRepository := mock.BlockRepository{}
+		blockRepository.GetBlockByHeightFunc = func(blockHeight uint64) (blockchain.Block, error) {
+			assert.Equal(t, uint64(0), blockHeight)
+			return test.input.storedBlock, test.input.storedErr
+		}
+		blockRepository.AddBlockFunc = func(block blockchain.Block) error {
+			added = true
+			assert.Equal(t, config.Seal, block.GetSeal())
+			return nil
+		}
+
+		// when
+		err := blockchain.CheckGenesisBlock(config, blockRepository)
+
+		// then
+		assert.Equal(t, test.err, err)
+		assert.Equal(t, test.added, added)
+	}
+}
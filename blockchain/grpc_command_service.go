@@ -3,5 +3,34 @@ package blockchain
 type GrpcCommandService interface {
 	RequestBlock(peerId PeerId, height uint64) error
 	ResponseBlock(peerId PeerId, block Block) error
-	SyncCheckResponse(block Block) error
+	RequestBlockRange(peerId PeerId, from BlockHeight, to BlockHeight) error
+	ResponseBlockRange(peerId PeerId, response BlockRangeResponse) error
+	SyncCheckRequest(peerId PeerId) error
+	SyncCheckResponse(peerId PeerId, block Block) error
+}
+
+// BlockRangeRequest 는 "BlockRangeRequestProtocol"의 body 로, From 부터 To 까지의 block 을 요청한다.
+type BlockRangeRequest struct {
+	From BlockHeight
+	To   BlockHeight
+}
+
+// BlockRangeResponse 는 "BlockRangeResponseProtocol"의 body 로, 요청받은 range 와 그 중 가지고 있는 block 들이다.
+// 응답하는 peer 는 From 부터 이어지는 block 만 보내므로 Blocks 가 비어 있으면 From 의 block 도 가지고 있지 않다는 뜻이다.
+type BlockRangeResponse struct {
+	From   BlockHeight
+	To     BlockHeight
+	Blocks []Block
+}
+
+// LightGrpcCommandService 는 header 만 저장하는 light node 가 full node 에게 header 와 transaction proof 를 요청한다.
+type LightGrpcCommandService interface {
+	RequestHeaderRange(peerId PeerId, from BlockHeight, to BlockHeight) error
+	RequestTxProof(peerId PeerId, txID string) error
+}
+
+// SnapshotGrpcCommandService 는 새 node 가 peer 에게 마지막 snapshot 을 요청하고, peer 는 snapshot 을 전달한다.
+type SnapshotGrpcCommandService interface {
+	RequestSnapshot(peerId PeerId) error
+	ResponseSnapshot(peerId PeerId, snapshot Snapshot) error
 }
@@ -0,0 +1,143 @@
+package blockchain
+
+import (
+	"bytes"
+	"errors"
+	"time"
+)
+
+var ErrHeaderNotFound = errors.New("header not found")
+var ErrTxProofNotFound = errors.New("peer does not have the transaction")
+var ErrTxNotIncluded = errors.New("transaction inclusion proof does not match the header")
+
+// 하나의 HeaderRangeRequestProtocol 에 응답하는 최대 header 수
+const MaxHeaderRangeSize = 512
+
+// SignedHeader 는 light node 가 block 대신 저장하는 header 와 seal, creator 의 서명이다.
+// transaction 목록 없이도 seal 과 서명, transaction proof 를 검증할 수 있다.
+type SignedHeader struct {
+	BlockHeader
+	Seal      []byte
+	Signature []byte
+}
+
+func NewSignedHeader(block Block) SignedHeader {
+	header := SignedHeader{
+		BlockHeader: NewBlockHeader(block),
+		Seal:        block.GetSeal(),
+	}
+
+	if signed, ok := block.(SignedBlock); ok {
+		header.Signature = signed.GetSignature()
+	}
+
+	return header
+}
+
+// HeaderRepository 는 light node 가 검증한 header chain 을 저장한다.
+type HeaderRepository interface {
+	AddHeader(header SignedHeader) error
+	GetHeaderByHeight(height BlockHeight) (SignedHeader, error)
+	GetLastHeader() (SignedHeader, error)
+	Close()
+}
+
+// TxProofResponse 는 "TxProofResponseProtocol"의 body 로, full node 가 만든 transaction 의 Merkle proof 이다.
+// full node 에 transaction 이 없으면 Found 가 false 이다.
+type TxProofResponse struct {
+	TxID   string
+	Found  bool
+	Height BlockHeight
+	Seal   []byte
+	Proof  MerkleProof
+}
+
+// TxInclusion 은 light node 가 자신의 header chain 으로 검증한 transaction 의 포함 증명이다.
+type TxInclusion struct {
+	TxID   string
+	Height BlockHeight
+	Seal   []byte
+	TxRoot []byte
+	Proof  MerkleProof
+}
+
+// ValidateHeader 함수는 header 가 prevHeader 의 다음 header 로 올바른지 height, prev seal, timestamp, seal, 서명 순서로 검증한다.
+// verifier 가 설정되지 않았으면 서명은 검증하지 않는다.
+func (t *DefaultValidator) ValidateHeader(header SignedHeader, prevHeader SignedHeader) error {
+	if header.Height != prevHeader.Height+1 {
+		return ErrInvalidBlockHeight
+	}
+
+	if !bytes.Equal(header.PrevSeal, prevHeader.Seal) {
+		return ErrPrevSealMismatch
+	}
+
+	if header.Version < prevHeader.Version {
+		return ErrBlockVersionDowngrade
+	}
+
+	if err := t.validateTimestamp(header.Height, header.Timestamp, prevHeader.Timestamp, time.Now()); err != nil {
+		return err
+	}
+
+	seal, err := t.buildSignedHeaderSeal(header.BlockHeader)
+	if err != nil {
+		return err
+	}
+
+	if !bytes.Equal(seal, header.Seal) {
+		return ErrInvalidSeal
+	}
+
+	if t.verifier == nil {
+		return nil
+	}
+
+	if len(header.Signature) == 0 {
+		return ErrEmptyBlockSignature
+	}
+
+	valid, err := t.verifier.Verify(PeerId{Id: string(header.Creator)}, header.Seal, header.Signature)
+	if err != nil {
+		return err
+	}
+
+	if !valid {
+		return ErrInvalidBlockSignature
+	}
+
+	return nil
+}
+
+// buildSignedHeaderSeal 은 header 만으로 seal 을 다시 만든다.
+// legacy seal 도 tx root 만 hash 하므로 transaction 목록이 없어도 된다.
+func (t *DefaultValidator) buildSignedHeaderSeal(header BlockHeader) ([]byte, error) {
+	if header.Version == LegacyBlockVersion {
+		return t.BuildSeal(header.Timestamp, header.PrevSeal, [][]byte{header.TxRoot}, header.Creator)
+	}
+
+	return t.BuildHeaderSeal(header)
+}
+
+// VerifyTxInclusion 함수는 full node 가 보낸 proof 를 light node 가 검증한 header 의 tx root 로 검증한다.
+func (t *DefaultValidator) VerifyTxInclusion(response TxProofResponse, header SignedHeader) (TxInclusion, error) {
+	if !response.Found {
+		return TxInclusion{}, ErrTxProofNotFound
+	}
+
+	if response.Height != header.Height || !bytes.Equal(response.Seal, header.Seal) || response.Proof.TxID != response.TxID {
+		return TxInclusion{}, ErrTxNotIncluded
+	}
+
+	if !t.VerifyTxProof(response.Proof, header.BlockHeader) {
+		return TxInclusion{}, ErrTxNotIncluded
+	}
+
+	return TxInclusion{
+		TxID:   response.TxID,
+		Height: header.Height,
+		Seal:   header.Seal,
+		TxRoot: header.TxRoot,
+		Proof:  response.Proof,
+	}, nil
+}
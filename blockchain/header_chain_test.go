@@ -0,0 +1,189 @@
+package blockchain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/it-chain/engine/blockchain"
+	"github.com/it-chain/engine/blockchain/test/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultValidator_ValidateHeader(t *testing.T) {
+	prevHeader := blockchain.SignedHeader{
+		BlockHeader: blockchain.BlockHeader{Height: 3, Version: blockchain.HeaderBlockVersion},
+		Seal:        []byte("prevseal"),
+	}
+
+	tests := map[string]struct {
+		input func() blockchain.SignedHeader
+		err   error
+	}{
+		"success": {
+			input: func() blockchain.SignedHeader {
+				return blockchain.NewSignedHeader(mock.NewBlock(t, prevHeader.Seal, 4, blockchain.CurrentBlockVersion))
+			},
+			err: nil,
+		},
+		"version downgrade": {
+			input: func() blockchain.SignedHeader {
+				return blockchain.NewSignedHeader(mock.NewBlock(t, prevHeader.Seal, 4, blockchain.LegacyBlockVersion))
+			},
+			err: blockchain.ErrBlockVersionDowngrade,
+		},
+		"invalid height": {
+			input: func() blockchain.SignedHeader {
+				return blockchain.NewSignedHeader(mock.NewBlock(t, prevHeader.Seal, 5, blockchain.CurrentBlockVersion))
+			},
+			err: blockchain.ErrInvalidBlockHeight,
+		},
+		"prev seal mismatch": {
+			input: func() blockchain.SignedHeader {
+				return blockchain.NewSignedHeader(mock.NewBlock(t, []byte("otherseal"), 4, blockchain.CurrentBlockVersion))
+			},
+			err: blockchain.ErrPrevSealMismatch,
+		},
+		"tx root changed": {
+			input: func() blockchain.SignedHeader {
+				header := blockchain.NewSignedHeader(mock.NewBlock(t, prevHeader.Seal, 4, blockchain.CurrentBlockVersion))
+				header.TxRoot = []byte("otherroot")
+				return header
+			},
+			err: blockchain.ErrInvalidSeal,
+		},
+		"timestamp changed": {
+			input: func() blockchain.SignedHeader {
+				header := blockchain.NewSignedHeader(mock.NewBlock(t, prevHeader.Seal, 4, blockchain.CurrentBlockVersion))
+				header.Timestamp = header.Timestamp.Add(time.Second)
+				return header
+			},
+			err: blockchain.ErrInvalidSeal,
+		},
+	}
+
+	validator := blockchain.DefaultValidator{}
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+
+		err := validator.ValidateHeader(test.input(), prevHeader)
+		assert.Equal(t, test.err, err)
+	}
+}
+
+func TestDefaultValidator_ValidateHeader_Legacy(t *testing.T) {
+	// given
+	validator := blockchain.DefaultValidator{}
+	prevHeader := blockchain.SignedHeader{
+		BlockHeader: blockchain.BlockHeader{Height: 3},
+		Seal:        []byte("prevseal"),
+	}
+
+	// when
+	header := blockchain.NewSignedHeader(mock.NewBlock(t, prevHeader.Seal, 4, blockchain.LegacyBlockVersion))
+
+	// then
+	assert.NoError(t, validator.ValidateHeader(header, prevHeader))
+}
+
+func TestDefaultValidator_ValidateHeader_Signature(t *testing.T) {
+	prevHeader := blockchain.SignedHeader{
+		BlockHeader: blockchain.BlockHeader{Height: 3},
+		Seal:        []byte("prevseal"),
+	}
+
+	tests := map[string]struct {
+		input []byte
+		err   error
+	}{
+		"success": {
+			input: []byte("signature"),
+			err:   nil,
+		},
+		"empty signature": {
+			input: nil,
+			err:   blockchain.ErrEmptyBlockSignature,
+		},
+		"invalid signature": {
+			input: []byte("forged"),
+			err:   blockchain.ErrInvalidBlockSignature,
+		},
+	}
+
+	verifier := mock.SignatureVerifier{}
+	verifier.VerifyFunc = func(peerId blockchain.PeerId, data []byte, signature []byte) (bool, error) {
+		return peerId.Id == "creator" && string(signature) == "signature", nil
+	}
+
+	validator := blockchain.DefaultValidator{}
+	validator.SetSignatureVerifier(verifier)
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+
+		// given
+		block := mock.NewBlock(t, prevHeader.Seal, 4, blockchain.CurrentBlockVersion)
+		block.Signature = test.input
+
+		// when
+		err := validator.ValidateHeader(blockchain.NewSignedHeader(block), prevHeader)
+
+		// then
+		assert.Equal(t, test.err, err)
+	}
+}
+
+func TestDefaultValidator_VerifyTxInclusion(t *testing.T) {
+	validator := blockchain.DefaultValidator{}
+
+	block := newBlockWithTxs(t, 5)
+	block.SetHeight(7)
+	block.SetSeal([]byte("seal"))
+	header := blockchain.NewSignedHeader(block)
+
+	proof, err := validator.BuildTxProof(block, "tx3")
+	assert.NoError(t, err)
+
+	otherProof, err := validator.BuildTxProof(block, "tx1")
+	assert.NoError(t, err)
+
+	tests := map[string]struct {
+		input blockchain.TxProofResponse
+		err   error
+	}{
+		"success": {
+			input: blockchain.TxProofResponse{TxID: "tx3", Found: true, Height: 7, Seal: []byte("seal"), Proof: proof},
+			err:   nil,
+		},
+		"not found": {
+			input: blockchain.TxProofResponse{TxID: "tx3"},
+			err:   blockchain.ErrTxProofNotFound,
+		},
+		"other block": {
+			input: blockchain.TxProofResponse{TxID: "tx3", Found: true, Height: 7, Seal: []byte("otherseal"), Proof: proof},
+			err:   blockchain.ErrTxNotIncluded,
+		},
+		"proof of other tx": {
+			input: blockchain.TxProofResponse{TxID: "tx3", Found: true, Height: 7, Seal: []byte("seal"), Proof: otherProof},
+			err:   blockchain.ErrTxNotIncluded,
+		},
+		"forged tx hash": {
+			input: blockchain.TxProofResponse{TxID: "tx3", Found: true, Height: 7, Seal: []byte("seal"), Proof: blockchain.MerkleProof{TxID: "tx3", TxHash: []byte("forged"), Path: proof.Path}},
+			err:   blockchain.ErrTxNotIncluded,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+
+		// when
+		inclusion, err := validator.VerifyTxInclusion(test.input, header)
+
+		// then
+		assert.Equal(t, test.err, err)
+		if err == nil {
+			assert.Equal(t, header.TxRoot, inclusion.TxRoot)
+			assert.Equal(t, uint64(7), inclusion.Height)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package adapter
 
 import (
 	"errors"
+	"time"
 
 	"github.com/it-chain/engine/blockchain"
 	"github.com/it-chain/engine/txpool"
@@ -14,48 +15,129 @@ type BlockApi interface {
 	CheckAndSaveBlockFromPool(height blockchain.BlockHeight) error
 }
 
+type BlockProposeApi interface {
+	ProposeBlock(txList []blockchain.Transaction) error
+}
+
 type CommandHandler struct {
-	blockApi BlockApi
+	blockApi        BlockApi
+	blockProposeApi BlockProposeApi
+	blockQueryApi   blockchain.BlockQueryApi
+	verifier        blockchain.SignatureVerifier
+	finalityRecord  blockchain.FinalityRecord
+	validator       *blockchain.DefaultValidator
 }
 
-func NewCommandHandler(blockApi BlockApi) *CommandHandler {
+func NewCommandHandler(blockApi BlockApi, blockProposeApi BlockProposeApi, blockQueryApi blockchain.BlockQueryApi, verifier blockchain.SignatureVerifier, finalityRecord blockchain.FinalityRecord) *CommandHandler {
 	return &CommandHandler{
-		blockApi: blockApi,
+		blockApi:        blockApi,
+		blockProposeApi: blockProposeApi,
+		blockQueryApi:   blockQueryApi,
+		verifier:        verifier,
+		finalityRecord:  finalityRecord,
+		validator:       blockchain.NewDefaultValidator(),
 	}
 }
 
+// SetMaxTimestampDrift 는 local 시각보다 drift 이상 앞선 timestamp 의 합의된 block 을 거부하도록 설정한다.
+func (h *CommandHandler) SetMaxTimestampDrift(drift time.Duration) {
+	h.validator.SetMaxTimestampDrift(drift)
+}
+
 // txpool에서 받은 transactions들을 block으로 만들어서 consensus에 보내준다.
-func (h *CommandHandler) HandleProposeBlockCommand(command blockchain.ProposeBlockCommand) {
-	//rawTxList := command.Transactions
-	//
-	//txList, err := convertTxList(rawTxList)
-	//if err != nil {
-	//	// TODO: handle errors
-	//	return
-	//}
-	//
-	//block, err := handler.blockApi.CreateBlock(txList)
-	//if err != nil {
-	//	// TODO: handle errors
-	//	return
-	//}
-	// TODO: service는 api에서 호출되어야한다.
-	//dispatcher.SendBlockValidateCommand(block)
-}
-
-// TODO: yggdrasill/impl/Transaction과 txpool/Transaction이 다름.
-func convertTxList(txList []txpool.Transaction) ([]blockchain.Transaction, error) {
-	return nil, nil
-}
-
-/// 합의된 block이 넘어오면 block pool에 저장한다.
+func (h *CommandHandler) HandleProposeBlockCommand(command blockchain.ProposeBlockCommand) error {
+	txList := convertTxList(command.Transactions)
+
+	return h.blockProposeApi.ProposeBlock(txList)
+}
+
+// convertTxList 는 txpool 의 transaction 을 block 에 담기는 DefaultTransaction 으로 바꾼다.
+func convertTxList(txList []txpool.Transaction) []blockchain.Transaction {
+	converted := make([]blockchain.Transaction, 0, len(txList))
+
+	for _, tx := range txList {
+		converted = append(converted, convertTx(tx))
+	}
+
+	return converted
+}
+
+// convertTx 는 txpool 에서 제출자가 서명한 Signature 를 그대로 옮긴다.
+func convertTx(tx txpool.Transaction) *blockchain.DefaultTransaction {
+	return &blockchain.DefaultTransaction{
+		ID:        tx.TxId,
+		Status:    convertTxStatus(tx.TxStatus),
+		PeerID:    tx.PublishPeerId,
+		Timestamp: tx.TimeStamp,
+		TxData: blockchain.NewTxData(
+			tx.TxData.Jsonrpc,
+			blockchain.TxDataType(tx.TxData.Method),
+			blockchain.Params{
+				Function: tx.TxData.Params.Function,
+				Args:     tx.TxData.Params.Args,
+			},
+			tx.TxData.ICodeID,
+		),
+		Signature: tx.Signature,
+	}
+}
+
+// txpool 과 blockchain 은 transaction 상태의 상수 값이 반대이다.
+func convertTxStatus(status txpool.TransactionStatus) blockchain.Status {
+	if status == txpool.VALID {
+		return blockchain.StatusTransactionValid
+	}
+
+	return blockchain.StatusTransactionInvalid
+}
+
+/// 합의된 block이 넘어오면 creator 와 transaction 의 서명, timestamp 를 검증하고 block pool에 저장한다.
+/// timestamp 가 맞지 않으면 consensus 가 block 을 거부할 수 있도록 *blockchain.TimestampError 를 그대로 돌려준다.
+/// 합의가 commit 한 block 이므로 reorg 로 되돌리지 않도록 finality 를 기록한다.
+/// block pool 이 block 을 받지 않으면 그 error 를 consensus 에 돌려준다.
 func (h *CommandHandler) HandleConfirmBlockCommand(command blockchain.ConfirmBlockCommand) error {
 	block := command.Block
 	if block == nil {
 		return ErrBlockNil
 	}
 
-	h.blockApi.AddBlockToPool(block)
+	if err := blockchain.ValidateBlockSignature(block, h.verifier); err != nil {
+		return err
+	}
+
+	if err := blockchain.ValidateTxSignatures(block.GetTxList(), h.verifier); err != nil {
+		return err
+	}
+
+	if err := h.validateTimestamp(block); err != nil {
+		return err
+	}
+
+	if err := h.finalityRecord.SaveFinalSeal(block.GetSeal()); err != nil {
+		return err
+	}
+
+	return h.blockApi.AddBlockToPool(block)
+}
+
+// validateTimestamp 는 block 의 timestamp 가 이전 block 보다 늦고 local 시각보다 drift 이상 앞서지 않는지 검증한다.
+// 마지막으로 commit 한 block 보다 높은 block 은 마지막 block 과, 그렇지 않은 block 은 commit 된 이전 height 의 block 과 비교한다.
+func (h *CommandHandler) validateTimestamp(block blockchain.Block) error {
+	prevBlock, err := h.blockQueryApi.GetLastBlock()
+	if err != nil {
+		return err
+	}
+
+	if block.GetHeight() <= prevBlock.GetHeight() {
+		if block.GetHeight() == 0 {
+			return nil
+		}
+
+		prevBlock, err = h.blockQueryApi.GetBlockByHeight(block.GetHeight() - 1)
+		if err != nil {
+			return err
+		}
+	}
 
-	return nil
+	return h.validator.ValidateTimestamp(block, prevBlock)
 }
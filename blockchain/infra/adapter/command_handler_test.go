@@ -1,16 +1,22 @@
 package adapter_test
 
 import (
+	"bytes"
 	"testing"
+	"time"
 
 	"github.com/it-chain/engine/blockchain"
+	"github.com/it-chain/engine/blockchain/api"
 	"github.com/it-chain/engine/blockchain/infra/adapter"
 	"github.com/it-chain/engine/blockchain/test/mock"
+	"github.com/it-chain/engine/txpool"
 	"github.com/it-chain/midgard"
 	"github.com/stretchr/testify/assert"
 )
 
 func TestCommandHandler_HandleConfirmBlockCommand(t *testing.T) {
+	now := time.Now().Round(0)
+
 	tests := map[string]struct {
 		input struct {
 			command blockchain.ConfirmBlockCommand
@@ -24,12 +30,98 @@ func TestCommandHandler_HandleConfirmBlockCommand(t *testing.T) {
 				command: blockchain.ConfirmBlockCommand{
 					CommandModel: midgard.CommandModel{ID: "zf"},
 					Block: &blockchain.DefaultBlock{
-						Height: 99887,
+						Height:    99887,
+						Seal:      []byte("seal"),
+						Creator:   []byte("creator"),
+						Signature: []byte("signature"),
+						Timestamp: now,
 					},
 				},
 			},
 			err: nil,
 		},
+		"empty signature": {
+			input: struct {
+				command blockchain.ConfirmBlockCommand
+			}{
+				command: blockchain.ConfirmBlockCommand{
+					CommandModel: midgard.CommandModel{ID: "zf"},
+					Block: &blockchain.DefaultBlock{
+						Height:  99887,
+						Seal:    []byte("seal"),
+						Creator: []byte("creator"),
+					},
+				},
+			},
+			err: blockchain.ErrEmptyBlockSignature,
+		},
+		"invalid signature": {
+			input: struct {
+				command blockchain.ConfirmBlockCommand
+			}{
+				command: blockchain.ConfirmBlockCommand{
+					CommandModel: midgard.CommandModel{ID: "zf"},
+					Block: &blockchain.DefaultBlock{
+						Height:    99887,
+						Seal:      []byte("seal"),
+						Creator:   []byte("creator"),
+						Signature: []byte("forged"),
+					},
+				},
+			},
+			err: blockchain.ErrInvalidBlockSignature,
+		},
+		"rejected by block pool": {
+			input: struct {
+				command blockchain.ConfirmBlockCommand
+			}{
+				command: blockchain.ConfirmBlockCommand{
+					CommandModel: midgard.CommandModel{ID: "zf"},
+					Block: &blockchain.DefaultBlock{
+						Height:    99888,
+						Seal:      []byte("seal"),
+						Creator:   []byte("creator"),
+						Signature: []byte("signature"),
+						Timestamp: now,
+					},
+				},
+			},
+			err: api.ErrGetLastBlock,
+		},
+		"timestamp not increasing": {
+			input: struct {
+				command blockchain.ConfirmBlockCommand
+			}{
+				command: blockchain.ConfirmBlockCommand{
+					CommandModel: midgard.CommandModel{ID: "zf"},
+					Block: &blockchain.DefaultBlock{
+						Height:    99887,
+						Seal:      []byte("seal"),
+						Creator:   []byte("creator"),
+						Signature: []byte("signature"),
+						Timestamp: now.Add(-time.Hour),
+					},
+				},
+			},
+			err: blockchain.ErrTimestampNotIncreasing,
+		},
+		"timestamp too far ahead": {
+			input: struct {
+				command blockchain.ConfirmBlockCommand
+			}{
+				command: blockchain.ConfirmBlockCommand{
+					CommandModel: midgard.CommandModel{ID: "zf"},
+					Block: &blockchain.DefaultBlock{
+						Height:    99887,
+						Seal:      []byte("seal"),
+						Creator:   []byte("creator"),
+						Signature: []byte("signature"),
+						Timestamp: now.Add(time.Hour),
+					},
+				},
+			},
+			err: blockchain.ErrTimestampTooFarAhead,
+		},
 		"block nil error test": {
 			input: struct {
 				command blockchain.ConfirmBlockCommand
@@ -45,17 +137,171 @@ func TestCommandHandler_HandleConfirmBlockCommand(t *testing.T) {
 
 	blockApi := mock.BlockApi{}
 	blockApi.AddBlockToPoolFunc = func(block blockchain.Block) error {
+		if block.GetHeight() == uint64(99888) {
+			return api.ErrGetLastBlock
+		}
+
 		assert.Equal(t, block.GetHeight(), uint64(99887))
 		return nil
 	}
 
-	commandHandler := adapter.NewCommandHandler(blockApi)
+	verifier := mock.SignatureVerifier{}
+	verifier.VerifyFunc = func(peerId blockchain.PeerId, data []byte, signature []byte) (bool, error) {
+		assert.Equal(t, "creator", peerId.Id)
+		assert.Equal(t, []byte("seal"), data)
+		return string(signature) == "signature", nil
+	}
+
+	finalSeals := make([][]byte, 0)
+	finalityRecord := mock.FinalityRecord{}
+	finalityRecord.SaveFinalSealFunc = func(seal []byte) error {
+		finalSeals = append(finalSeals, seal)
+		return nil
+	}
+
+	blockQueryApi := mock.BlockQueryApi{}
+	blockQueryApi.GetLastBlockFunc = func() (blockchain.Block, error) {
+		return &blockchain.DefaultBlock{Height: 99886, Timestamp: now.Add(-time.Minute)}, nil
+	}
+
+	commandHandler := adapter.NewCommandHandler(blockApi, mock.BlockProposeApi{}, blockQueryApi, verifier, finalityRecord)
+	commandHandler.SetMaxTimestampDrift(time.Minute)
 	for testName, test := range tests {
 		t.Logf("running test case %s", testName)
 
 		err := commandHandler.HandleConfirmBlockCommand(test.input.command)
 
+		// timestamp 검증 실패는 consensus 가 알 수 있도록 감싸지 않은 *TimestampError 로 돌려준다.
+		if timestampErr, ok := err.(*blockchain.TimestampError); ok {
+			assert.Equal(t, uint64(99887), timestampErr.Height)
+			assert.Equal(t, timestampErr.Err, test.err)
+			continue
+		}
+
 		assert.Equal(t, err, test.err)
 	}
 
+	// 서명이 맞는 block 만 합의가 commit 한 block 으로 기록한다.
+	assert.Equal(t, [][]byte{[]byte("seal"), []byte("seal")}, finalSeals)
+
+}
+
+func TestCommandHandler_HandleProposeBlockCommand(t *testing.T) {
+	// given
+	config, err := blockchain.NewGenesisConfig("chain", "tmp peer 1", []string{"tmp peer 1"}, time.Now())
+	assert.NoError(t, err)
+	genesis, err := blockchain.NewGenesisBlock(config)
+	assert.NoError(t, err)
+
+	blockRepository := mock.BlockRepository{}
+	blockRepository.GetLastBlockFunc = func() (blockchain.Block, error) {
+		return genesis, nil
+	}
+
+	published := make([]blockchain.BlockValidateCommand, 0)
+	commandService := adapter.NewCommandService(func(exchange string, topic string, data interface{}) error {
+		published = append(published, data.(blockchain.BlockValidateCommand))
+		return nil
+	})
+
+	signer := mock.Signer{}
+	signer.SignFunc = func(data []byte) ([]byte, error) {
+		return append([]byte("signed:"), data...), nil
+	}
+
+	verifier := mock.SignatureVerifier{}
+	verifier.VerifyFunc = func(peerId blockchain.PeerId, data []byte, signature []byte) (bool, error) {
+		assert.Equal(t, "tmp peer 1", peerId.Id)
+		return bytes.Equal(append([]byte("signed:"), data...), signature), nil
+	}
+
+	pooled := make([]blockchain.Block, 0)
+	blockApi := mock.BlockApi{}
+	blockApi.AddBlockToPoolFunc = func(block blockchain.Block) error {
+		pooled = append(pooled, block)
+		return nil
+	}
+
+	blockProposeApi := api.NewBlockProposeApi("tmp peer 1", blockRepository, commandService, signer)
+	commandHandler := adapter.NewCommandHandler(blockApi, blockProposeApi, blockRepository, verifier, mock.FinalityRecord{})
+
+	timestamp := time.Now().Round(0)
+	txList := []txpool.Transaction{
+		{
+			TxId:          "tx1",
+			PublishPeerId: "tmp peer 1",
+			TxStatus:      txpool.VALID,
+			TimeStamp:     timestamp,
+			TxData: txpool.TxData{
+				Jsonrpc: "2.0",
+				Method:  txpool.Invoke,
+				Params:  txpool.Param{Function: "set", Args: []string{"a", "1"}},
+				ICodeID: "icode1",
+			},
+		},
+		{
+			TxId:          "tx2",
+			PublishPeerId: "tmp peer 1",
+			TxStatus:      txpool.INVALID,
+			TimeStamp:     timestamp,
+		},
+	}
+
+	// txpool 이 pool 에 넣을 때 제출자의 key 로 서명한다.
+	txSigner := adapter.NewTxSigner(signer)
+	for i := range txList {
+		txList[i].Signature, err = txSigner.SignTx(txList[i])
+		assert.NoError(t, err)
+	}
+
+	command := blockchain.ProposeBlockCommand{
+		CommandModel: midgard.CommandModel{ID: "propose"},
+		Transactions: txList,
+	}
+
+	// when
+	err = commandHandler.HandleProposeBlockCommand(command)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(published))
+
+	block := published[0].Block.(*blockchain.DefaultBlock)
+	assert.Equal(t, uint64(1), block.GetHeight())
+	assert.Equal(t, genesis.GetSeal(), block.GetPrevSeal())
+	assert.Equal(t, []byte("tmp peer 1"), block.GetCreator())
+	assert.Equal(t, append([]byte("signed:"), block.GetSeal()...), block.GetSignature())
+
+	assert.Equal(t, 2, len(block.TxList))
+	tx := block.TxList[0]
+	assert.Equal(t, "tx1", tx.ID)
+	assert.Equal(t, "tmp peer 1", tx.PeerID)
+	assert.Equal(t, blockchain.StatusTransactionValid, tx.Status)
+	assert.True(t, timestamp.Equal(tx.Timestamp))
+	assert.Equal(t, blockchain.NewTxData("2.0", blockchain.Invoke, blockchain.Params{Function: "set", Args: []string{"a", "1"}}, "icode1"), tx.TxData)
+	assert.Equal(t, txList[0].Signature, tx.Signature)
+	assert.Equal(t, blockchain.StatusTransactionInvalid, block.TxList[1].Status)
+
+	// 만들어진 block 은 다른 node 의 validator 로 서명까지 검증된다.
+	validator := blockchain.NewDefaultValidator()
+	validator.SetSignatureVerifier(verifier)
+	assert.NoError(t, validator.ValidateBlock(block, genesis))
+	assert.NoError(t, commandHandler.HandleConfirmBlockCommand(blockchain.ConfirmBlockCommand{Block: block}))
+	assert.Equal(t, 1, len(pooled))
+
+	// 제출자의 서명이 없는 transaction 은 거부된다.
+	block.TxList[1].Signature = nil
+	assert.Equal(t, &blockchain.TxSignatureError{TxID: "tx2", Err: blockchain.ErrEmptyTxSignature}, validator.ValidateBlock(block, genesis))
+}
+
+func TestCommandHandler_HandleProposeBlockCommand_EmptyTransactions(t *testing.T) {
+	// given
+	blockProposeApi := api.NewBlockProposeApi("tmp peer 1", mock.BlockRepository{}, adapter.NewCommandService(nil), mock.Signer{})
+	commandHandler := adapter.NewCommandHandler(mock.BlockApi{}, blockProposeApi, mock.BlockQueryApi{}, mock.SignatureVerifier{}, mock.FinalityRecord{})
+
+	// when
+	err := commandHandler.HandleProposeBlockCommand(blockchain.ProposeBlockCommand{})
+
+	// then
+	assert.Equal(t, api.ErrEmptyTxList, err)
 }
@@ -1,14 +1,19 @@
 package adapter
 
 import (
+	"encoding/hex"
+	"encoding/json"
 	"errors"
+	"time"
 
 	"github.com/it-chain/engine/blockchain"
+	"github.com/it-chain/engine/conf"
 	"github.com/it-chain/midgard"
 	"github.com/rs/xid"
 )
 
 var ErrEmptyBlock = errors.New("block is nil")
+var ErrEmptyICodeID = errors.New("icode id is empty")
 
 type Publisher func(exchange string, topic string, data interface{}) (err error) //해당 publish함수는 midgard 에서 의존성 주입을 받기 위해 interface로 작성한다.
 //모든 의존성 주입은 컴포넌트.go 에서 이루어짐
@@ -37,3 +42,102 @@ func (c *CommandService) SendBlockValidateCommand(block blockchain.Block) error
 
 	return c.publisher("Event", "Block", command)
 }
+
+// SendBlockExecuteCommand 는 commit 된 block 의 transaction 실행을 icode 에 요청한다.
+// command ID 는 block seal 의 hex 이고, icode 는 같은 ID 로 BlockResultCommand 를 보낸다.
+func (c *CommandService) SendBlockExecuteCommand(block blockchain.Block) error {
+	return c.sendBlockExecuteCommand(block, false)
+}
+
+// SendResetBlockExecuteCommand 는 icode 의 state 를 모두 비운 다음 block 의 transaction 을 실행하도록 요청한다.
+// 같은 topic 으로 보내므로 먼저 보낸 block 실행 요청보다 앞서 state 를 비우지 않는다.
+func (c *CommandService) SendResetBlockExecuteCommand(block blockchain.Block) error {
+	return c.sendBlockExecuteCommand(block, true)
+}
+
+func (c *CommandService) sendBlockExecuteCommand(block blockchain.Block, reset bool) error {
+	if block == nil {
+		return ErrEmptyBlock
+	}
+
+	serializedBlock, err := json.Marshal(newExecuteBlock(block))
+	if err != nil {
+		return err
+	}
+
+	command := blockchain.BlockExecuteCommand{
+		CommandModel: midgard.CommandModel{
+			ID: hex.EncodeToString(block.GetSeal()),
+		},
+		Block: serializedBlock,
+		Reset: reset,
+	}
+
+	return c.publisher("Command", "block.excute", command)
+}
+
+// SendICodeDeployCommand 는 snapshot 의 icode 를 git url 에서 받아 배포하도록 icode 에 요청한다.
+// ssh key 는 이 node 의 icode 설정을 사용한다.
+func (c *CommandService) SendICodeDeployCommand(icode blockchain.ICodeState) error {
+	if icode.ID == "" {
+		return ErrEmptyICodeID
+	}
+
+	command := blockchain.ICodeDeployCommand{
+		CommandModel: midgard.CommandModel{
+			ID: icode.ID,
+		},
+		Url:     icode.GitUrl,
+		SshPath: conf.GetConfiguration().Icode.SshPath,
+	}
+
+	return c.publisher("Command", "icode.deploy", command)
+}
+
+// executeBlock 은 icode.Block 의 json 형태이다.
+type executeBlock struct {
+	TxList []executeTransaction
+}
+
+type executeTransaction struct {
+	TxId      string
+	TimeStamp time.Time
+	TxData    executeTxData
+}
+
+type executeTxData struct {
+	Jsonrpc string
+	Method  blockchain.TxDataType
+	Params  blockchain.Params
+	ID      string
+	ICodeID string
+}
+
+func newExecuteBlock(block blockchain.Block) executeBlock {
+	txList := make([]executeTransaction, 0, len(block.GetTxList()))
+
+	for _, tx := range block.GetTxList() {
+		executeTx := executeTransaction{
+			TxId: tx.GetID(),
+		}
+
+		if defaultTx, ok := tx.(*blockchain.DefaultTransaction); ok {
+			executeTx.TimeStamp = defaultTx.Timestamp
+
+			// blockchain 의 TxData.ID 는 icode 의 ID 이다.
+			if defaultTx.TxData != nil {
+				executeTx.TxData = executeTxData{
+					Jsonrpc: defaultTx.TxData.Jsonrpc,
+					Method:  defaultTx.TxData.Method,
+					Params:  defaultTx.TxData.Params,
+					ID:      tx.GetID(),
+					ICodeID: defaultTx.TxData.ID,
+				}
+			}
+		}
+
+		txList = append(txList, executeTx)
+	}
+
+	return executeBlock{TxList: txList}
+}
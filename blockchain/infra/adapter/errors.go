@@ -10,3 +10,12 @@ var ErrSyncCheckResponse = errors.New("error when sync check response")
 var ErrEmptyNodeId = errors.New("empty nodeid proposed")
 var ErrEmptyBlockSeal = errors.New("empty block seal")
 var ErrBlockMissingProperties = errors.New("error when block miss some properties")
+var ErrSyncedCheck = errors.New("error when synced check")
+var ErrSyncBlock = errors.New("error when sync block")
+var ErrInvalidBlockRange = errors.New("invalid block range")
+var ErrEmptyTxID = errors.New("empty transaction id")
+var ErrResponseHeader = errors.New("error when response header")
+var ErrResponseTxProof = errors.New("error when response tx proof")
+var ErrSyncHeader = errors.New("error when sync header")
+var ErrResponseSnapshot = errors.New("error when response snapshot")
+var ErrImportSnapshot = errors.New("error when import snapshot")
@@ -6,19 +6,32 @@ import (
 	"github.com/it-chain/engine/blockchain"
 )
 
+// 하나의 BlockRangeRequestProtocol 에 응답하는 최대 block 수
+const MaxBlockRangeSize = 128
+
 type SyncBlockApi interface {
-	SyncedCheck(block blockchain.Block) error
+	SyncedCheck(peerId blockchain.PeerId, block blockchain.Block) error
+	SyncBlock(peerId blockchain.PeerId, block blockchain.Block) error
+	SyncBlocks(peerId blockchain.PeerId, response blockchain.BlockRangeResponse) error
 }
 
 type SyncCheckGrpcCommandService interface {
-	SyncCheckResponse(block blockchain.Block) error
+	SyncCheckResponse(peerId blockchain.PeerId, block blockchain.Block) error
 	ResponseBlock(peerId blockchain.PeerId, block blockchain.Block) error
+	ResponseBlockRange(peerId blockchain.PeerId, response blockchain.BlockRangeResponse) error
+	ResponseHeaderRange(peerId blockchain.PeerId, headers []blockchain.SignedHeader) error
+	ResponseTxProof(peerId blockchain.PeerId, response blockchain.TxProofResponse) error
+}
+
+type HeaderQueryApi interface {
+	GetHeaderByHeight(height blockchain.BlockHeight) (blockchain.SignedHeader, error)
 }
 
 type GrpcCommandHandler struct {
 	blockApi           SyncBlockApi
 	blockQueryApi      blockchain.BlockQueryApi
 	grpcCommandService SyncCheckGrpcCommandService
+	validator          *blockchain.DefaultValidator
 }
 
 func NewGrpcCommandHandler(blockApi SyncBlockApi, blockQueryService blockchain.BlockQueryApi, grpcCommandService SyncCheckGrpcCommandService) *GrpcCommandHandler {
@@ -26,26 +39,37 @@ func NewGrpcCommandHandler(blockApi SyncBlockApi, blockQueryService blockchain.B
 		blockApi:           blockApi,
 		blockQueryApi:      blockQueryService,
 		grpcCommandService: grpcCommandService,
+		validator:          &blockchain.DefaultValidator{},
 	}
 }
 
 func (g *GrpcCommandHandler) HandleGrpcCommand(command blockchain.GrpcReceiveCommand) error {
 	switch command.Protocol {
 	case "SyncCheckRequestProtocol":
-		//TODO: 상대방의 SyncCheck를 위해서 자신의 last block을 보내준다.
+		// 상대방의 SyncCheck를 위해서 자신의 last block을 보내준다.
 		block, err := g.blockQueryApi.GetLastBlock()
 		if err != nil {
 			return ErrGetLastBlock
 		}
 
-		err = g.grpcCommandService.SyncCheckResponse(block)
+		err = g.grpcCommandService.SyncCheckResponse(command.FromPeer.PeerId, block)
 		if err != nil {
 			return ErrSyncCheckResponse
 		}
 		break
 
 	case "SyncCheckResponseProtocol":
-		//TODO: 상대방의 last block을 받아서 SyncCheck를 시작한다.
+		// 상대방의 last block을 받아서 SyncCheck를 진행한다.
+		block := &blockchain.DefaultBlock{}
+		err := block.Deserialize(command.Body)
+		if err != nil {
+			return ErrBlockInfoDeliver
+		}
+
+		err = g.blockApi.SyncedCheck(command.FromPeer.PeerId, block)
+		if err != nil {
+			return ErrSyncedCheck
+		}
 		break
 
 	case "BlockRequestProtocol":
@@ -67,9 +91,141 @@ func (g *GrpcCommandHandler) HandleGrpcCommand(command blockchain.GrpcReceiveCom
 		break
 
 	case "BlockResponseProtocol":
-		//TODO: Construct 과정에서 block을 받는다.
+		// Construct 과정에서 block을 받는다.
+		block := &blockchain.DefaultBlock{}
+		err := block.Deserialize(command.Body)
+		if err != nil {
+			return ErrBlockInfoDeliver
+		}
+
+		err = g.blockApi.SyncBlock(command.FromPeer.PeerId, block)
+		if err != nil {
+			return ErrSyncBlock
+		}
+		break
+
+	case "BlockRangeRequestProtocol":
+		var blockRange blockchain.BlockRangeRequest
+		err := json.Unmarshal(command.Body, &blockRange)
+		if err != nil || blockRange.From > blockRange.To {
+			return ErrBlockInfoDeliver
+		}
+
+		if blockRange.To-blockRange.From >= MaxBlockRangeSize {
+			blockRange.To = blockRange.From + MaxBlockRangeSize - 1
+		}
+
+		// 가지고 있는 block 까지만 보낸다. 빈 응답을 받은 peer 는 다른 peer 에게 다시 요청한다.
+		blocks := make([]blockchain.Block, 0)
+		for height := blockRange.From; height <= blockRange.To; height++ {
+			block, err := g.blockQueryApi.GetBlockByHeight(height)
+			if err != nil {
+				break
+			}
+			blocks = append(blocks, block)
+		}
+
+		err = g.grpcCommandService.ResponseBlockRange(command.FromPeer.PeerId, blockchain.BlockRangeResponse{
+			From:   blockRange.From,
+			To:     blockRange.To,
+			Blocks: blocks,
+		})
+		if err != nil {
+			return ErrResponseBlock
+		}
+		break
+
+	case "BlockRangeResponseProtocol":
+		// Construct 과정에서 요청한 range 의 block 들을 한번에 받는다.
+		response, err := blockchain.DecodeBlockRangeResponse(command.Body)
+		if err != nil {
+			return ErrBlockInfoDeliver
+		}
+
+		err = g.blockApi.SyncBlocks(command.FromPeer.PeerId, response)
+		if err != nil {
+			return ErrSyncBlock
+		}
+		break
+
+	case "HeaderRangeRequestProtocol":
+		// light node 에게 block 대신 header 만 보낸다.
+		var headerRange blockchain.BlockRangeRequest
+		err := json.Unmarshal(command.Body, &headerRange)
+		if err != nil || headerRange.From > headerRange.To {
+			return ErrBlockInfoDeliver
+		}
+
+		if headerRange.To-headerRange.From >= blockchain.MaxHeaderRangeSize {
+			headerRange.To = headerRange.From + blockchain.MaxHeaderRangeSize - 1
+		}
+
+		headers := make([]blockchain.SignedHeader, 0)
+		for height := headerRange.From; height <= headerRange.To; height++ {
+			header, err := g.getHeaderByHeight(height)
+			if err != nil {
+				break
+			}
+			headers = append(headers, header)
+		}
+
+		err = g.grpcCommandService.ResponseHeaderRange(command.FromPeer.PeerId, headers)
+		if err != nil {
+			return ErrResponseHeader
+		}
+		break
+
+	case "TxProofRequestProtocol":
+		// light node 가 자신의 header 로 검증할 수 있도록 transaction 의 Merkle proof 를 보낸다.
+		var txID string
+		err := json.Unmarshal(command.Body, &txID)
+		if err != nil || txID == "" {
+			return ErrBlockInfoDeliver
+		}
+
+		err = g.grpcCommandService.ResponseTxProof(command.FromPeer.PeerId, g.buildTxProofResponse(txID))
+		if err != nil {
+			return ErrResponseTxProof
+		}
 		break
 	}
 
 	return nil
 }
+
+// getHeaderByHeight 는 pruned mode 에서 transaction 이 지워진 block 의 header 도 보낼 수 있도록
+// repository 가 header 조회를 지원하면 header 를 직접 조회한다.
+func (g *GrpcCommandHandler) getHeaderByHeight(height blockchain.BlockHeight) (blockchain.SignedHeader, error) {
+	if headerQueryApi, ok := g.blockQueryApi.(HeaderQueryApi); ok {
+		return headerQueryApi.GetHeaderByHeight(height)
+	}
+
+	block, err := g.blockQueryApi.GetBlockByHeight(height)
+	if err != nil {
+		return blockchain.SignedHeader{}, err
+	}
+
+	return blockchain.NewSignedHeader(block), nil
+}
+
+// buildTxProofResponse 는 transaction 이 없거나 proof 를 만들 수 없으면 Found 가 false 인 응답을 만든다.
+func (g *GrpcCommandHandler) buildTxProofResponse(txID string) blockchain.TxProofResponse {
+	response := blockchain.TxProofResponse{TxID: txID}
+
+	block, err := g.blockQueryApi.GetBlockByTxID(txID)
+	if err != nil {
+		return response
+	}
+
+	proof, err := g.validator.BuildTxProof(block, txID)
+	if err != nil {
+		return response
+	}
+
+	response.Found = true
+	response.Height = block.GetHeight()
+	response.Seal = block.GetSeal()
+	response.Proof = proof
+
+	return response
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/it-chain/engine/blockchain"
 	"github.com/it-chain/engine/blockchain/infra/adapter"
 	"github.com/it-chain/engine/blockchain/test/mock"
+	"github.com/it-chain/engine/common"
 	"github.com/it-chain/midgard"
 	"github.com/stretchr/testify/assert"
 )
@@ -30,6 +31,7 @@ func TestGrpcCommandHandler_HandleGrpcCommand_SyncCheckRequestProtocol(t *testin
 					CommandModel: midgard.CommandModel{ID: "111"},
 					Body:         nil,
 					Protocol:     "SyncCheckRequestProtocol",
+					FromPeer:     blockchain.Peer{PeerId: blockchain.PeerId{Id: "peer1"}},
 				},
 			},
 			err: nil,
@@ -44,6 +46,7 @@ func TestGrpcCommandHandler_HandleGrpcCommand_SyncCheckRequestProtocol(t *testin
 					CommandModel: midgard.CommandModel{ID: "111"},
 					Body:         nil,
 					Protocol:     "SyncCheckRequestProtocol",
+					FromPeer:     blockchain.Peer{PeerId: blockchain.PeerId{Id: "peer1"}},
 				},
 				getLastBlockErr: errors.New("error occur in ErrGetLastBlock"),
 			},
@@ -59,6 +62,7 @@ func TestGrpcCommandHandler_HandleGrpcCommand_SyncCheckRequestProtocol(t *testin
 					CommandModel: midgard.CommandModel{ID: "111"},
 					Body:         nil,
 					Protocol:     "SyncCheckRequestProtocol",
+					FromPeer:     blockchain.Peer{PeerId: blockchain.PeerId{Id: "peer1"}},
 				},
 				syncCheckErr: errors.New("error occur in SyncCheckResponse"),
 			},
@@ -77,7 +81,8 @@ func TestGrpcCommandHandler_HandleGrpcCommand_SyncCheckRequestProtocol(t *testin
 		}
 
 		grpcCommandService := mock.SyncCheckGrpcCommandService{}
-		grpcCommandService.SyncCheckResponseFunc = func(block blockchain.Block) error {
+		grpcCommandService.SyncCheckResponseFunc = func(peerId blockchain.PeerId, block blockchain.Block) error {
+			assert.Equal(t, peerId.Id, "peer1")
 			assert.Equal(t, block.GetHeight(), uint64(99887))
 			return test.input.syncCheckErr
 		}
@@ -225,3 +230,484 @@ func TestGrpcCommandHandler_HandleGrpcCommand_BlockRequestProtocol(t *testing.T)
 	}
 
 }
+
+func TestGrpcCommandHandler_HandleGrpcCommand_SyncCheckResponseProtocol(t *testing.T) {
+	body, _ := (&blockchain.DefaultBlock{Height: blockchain.BlockHeight(10), Seal: []byte("seal")}).Serialize()
+
+	tests := map[string]struct {
+		input struct {
+			command        blockchain.GrpcReceiveCommand
+			syncedCheckErr error
+		}
+		err error
+	}{
+		"success": {
+			input: struct {
+				command        blockchain.GrpcReceiveCommand
+				syncedCheckErr error
+			}{
+				command: blockchain.GrpcReceiveCommand{
+					CommandModel: midgard.CommandModel{ID: "111"},
+					Body:         body,
+					Protocol:     "SyncCheckResponseProtocol",
+					FromPeer:     blockchain.Peer{PeerId: blockchain.PeerId{Id: "peer1"}},
+				},
+			},
+			err: nil,
+		},
+		"fail: deserialize block": {
+			input: struct {
+				command        blockchain.GrpcReceiveCommand
+				syncedCheckErr error
+			}{
+				command: blockchain.GrpcReceiveCommand{
+					CommandModel: midgard.CommandModel{ID: "111"},
+					Body:         []byte("invalid"),
+					Protocol:     "SyncCheckResponseProtocol",
+					FromPeer:     blockchain.Peer{PeerId: blockchain.PeerId{Id: "peer1"}},
+				},
+			},
+			err: adapter.ErrBlockInfoDeliver,
+		},
+		"fail: synced check": {
+			input: struct {
+				command        blockchain.GrpcReceiveCommand
+				syncedCheckErr error
+			}{
+				command: blockchain.GrpcReceiveCommand{
+					CommandModel: midgard.CommandModel{ID: "111"},
+					Body:         body,
+					Protocol:     "SyncCheckResponseProtocol",
+					FromPeer:     blockchain.Peer{PeerId: blockchain.PeerId{Id: "peer1"}},
+				},
+				syncedCheckErr: errors.New("error when synced check"),
+			},
+			err: adapter.ErrSyncedCheck,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+
+		blockApi := mock.MockSyncBlockApi{}
+		blockApi.SyncedCheckFunc = func(peerId blockchain.PeerId, block blockchain.Block) error {
+			assert.Equal(t, "peer1", peerId.Id)
+			assert.Equal(t, uint64(10), block.GetHeight())
+			assert.Equal(t, []byte("seal"), block.GetSeal())
+			return test.input.syncedCheckErr
+		}
+
+		grpcCommandHandler := adapter.NewGrpcCommandHandler(blockApi, mock.BlockQueryApi{}, mock.SyncCheckGrpcCommandService{})
+
+		err := grpcCommandHandler.HandleGrpcCommand(test.input.command)
+		assert.Equal(t, test.err, err)
+	}
+}
+
+func TestGrpcCommandHandler_HandleGrpcCommand_BlockResponseProtocol(t *testing.T) {
+	body, _ := (&blockchain.DefaultBlock{Height: blockchain.BlockHeight(3), Seal: []byte("seal")}).Serialize()
+
+	tests := map[string]struct {
+		input struct {
+			command      blockchain.GrpcReceiveCommand
+			syncBlockErr error
+		}
+		err error
+	}{
+		"success": {
+			input: struct {
+				command      blockchain.GrpcReceiveCommand
+				syncBlockErr error
+			}{
+				command: blockchain.GrpcReceiveCommand{
+					CommandModel: midgard.CommandModel{ID: "111"},
+					Body:         body,
+					Protocol:     "BlockResponseProtocol",
+					FromPeer:     blockchain.Peer{PeerId: blockchain.PeerId{Id: "peer1"}},
+				},
+			},
+			err: nil,
+		},
+		"fail: deserialize block": {
+			input: struct {
+				command      blockchain.GrpcReceiveCommand
+				syncBlockErr error
+			}{
+				command: blockchain.GrpcReceiveCommand{
+					CommandModel: midgard.CommandModel{ID: "111"},
+					Body:         nil,
+					Protocol:     "BlockResponseProtocol",
+					FromPeer:     blockchain.Peer{PeerId: blockchain.PeerId{Id: "peer1"}},
+				},
+			},
+			err: adapter.ErrBlockInfoDeliver,
+		},
+		"fail: sync block": {
+			input: struct {
+				command      blockchain.GrpcReceiveCommand
+				syncBlockErr error
+			}{
+				command: blockchain.GrpcReceiveCommand{
+					CommandModel: midgard.CommandModel{ID: "111"},
+					Body:         body,
+					Protocol:     "BlockResponseProtocol",
+					FromPeer:     blockchain.Peer{PeerId: blockchain.PeerId{Id: "peer1"}},
+				},
+				syncBlockErr: errors.New("error when sync block"),
+			},
+			err: adapter.ErrSyncBlock,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+
+		blockApi := mock.MockSyncBlockApi{}
+		blockApi.SyncBlockFunc = func(peerId blockchain.PeerId, block blockchain.Block) error {
+			assert.Equal(t, "peer1", peerId.Id)
+			assert.Equal(t, uint64(3), block.GetHeight())
+			return test.input.syncBlockErr
+		}
+
+		grpcCommandHandler := adapter.NewGrpcCommandHandler(blockApi, mock.BlockQueryApi{}, mock.SyncCheckGrpcCommandService{})
+
+		err := grpcCommandHandler.HandleGrpcCommand(test.input.command)
+		assert.Equal(t, test.err, err)
+	}
+}
+
+func TestGrpcCommandHandler_HandleGrpcCommand_BlockRangeRequestProtocol(t *testing.T) {
+	validBody, _ := common.Serialize(blockchain.BlockRangeRequest{From: 1, To: 5})
+	invalidBody, _ := common.Serialize(blockchain.BlockRangeRequest{From: 5, To: 1})
+	largeBody, _ := common.Serialize(blockchain.BlockRangeRequest{From: 0, To: 1000})
+
+	tests := map[string]struct {
+		input struct {
+			body             []byte
+			responseBlockErr error
+		}
+		responseHeights []uint64
+		err             error
+	}{
+		"success: send blocks which exist": {
+			input: struct {
+				body             []byte
+				responseBlockErr error
+			}{body: validBody},
+			responseHeights: []uint64{1, 2, 3},
+			err:             nil,
+		},
+		"success: range is limited": {
+			input: struct {
+				body             []byte
+				responseBlockErr error
+			}{body: largeBody},
+			responseHeights: []uint64{0, 1, 2, 3},
+			err:             nil,
+		},
+		"fail: invalid range": {
+			input: struct {
+				body             []byte
+				responseBlockErr error
+			}{body: invalidBody},
+			err: adapter.ErrBlockInfoDeliver,
+		},
+		"fail: response block range": {
+			input: struct {
+				body             []byte
+				responseBlockErr error
+			}{body: validBody, responseBlockErr: errors.New("error when response block range")},
+			responseHeights: []uint64{1, 2, 3},
+			err:             adapter.ErrResponseBlock,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+
+		requestedHeights := make([]uint64, 0)
+		blockQueryApi := mock.BlockQueryApi{}
+		blockQueryApi.GetBlockByHeightFunc = func(height uint64) (blockchain.Block, error) {
+			requestedHeights = append(requestedHeights, height)
+			if height > 3 {
+				return nil, blockchain.ErrBlockNotFound
+			}
+			return &blockchain.DefaultBlock{Height: height, Seal: []byte("seal")}, nil
+		}
+
+		grpcCommandService := mock.SyncCheckGrpcCommandService{}
+		grpcCommandService.ResponseBlockRangeFunc = func(peerId blockchain.PeerId, response blockchain.BlockRangeResponse) error {
+			assert.Equal(t, "peer1", peerId.Id)
+			assert.True(t, response.To-response.From < adapter.MaxBlockRangeSize)
+
+			heights := make([]uint64, 0)
+			for _, block := range response.Blocks {
+				heights = append(heights, block.GetHeight())
+			}
+			assert.Equal(t, test.responseHeights, heights)
+
+			return test.input.responseBlockErr
+		}
+
+		grpcCommandHandler := adapter.NewGrpcCommandHandler(mock.MockSyncBlockApi{}, blockQueryApi, grpcCommandService)
+
+		err := grpcCommandHandler.HandleGrpcCommand(blockchain.GrpcReceiveCommand{
+			CommandModel: midgard.CommandModel{ID: "111"},
+			Body:         test.input.body,
+			Protocol:     "BlockRangeRequestProtocol",
+			FromPeer:     blockchain.Peer{PeerId: blockchain.PeerId{Id: "peer1"}},
+		})
+		assert.Equal(t, test.err, err)
+		assert.True(t, len(requestedHeights) <= adapter.MaxBlockRangeSize)
+	}
+}
+
+func TestGrpcCommandHandler_HandleGrpcCommand_BlockRangeResponseProtocol(t *testing.T) {
+	body, _ := blockchain.EncodeBlockRangeResponse(blockchain.BlockRangeResponse{
+		From: 1,
+		To:   4,
+		Blocks: []blockchain.Block{
+			&blockchain.DefaultBlock{Height: blockchain.BlockHeight(1), Seal: []byte("seal1")},
+			&blockchain.DefaultBlock{Height: blockchain.BlockHeight(2), Seal: []byte("seal2")},
+		},
+	})
+
+	tests := map[string]struct {
+		input struct {
+			body          []byte
+			syncBlocksErr error
+		}
+		err error
+	}{
+		"success": {
+			input: struct {
+				body          []byte
+				syncBlocksErr error
+			}{body: body},
+			err: nil,
+		},
+		"fail: deserialize blocks": {
+			input: struct {
+				body          []byte
+				syncBlocksErr error
+			}{body: []byte("invalid")},
+			err: adapter.ErrBlockInfoDeliver,
+		},
+		"fail: sync blocks": {
+			input: struct {
+				body          []byte
+				syncBlocksErr error
+			}{body: body, syncBlocksErr: errors.New("error when sync blocks")},
+			err: adapter.ErrSyncBlock,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+
+		blockApi := mock.MockSyncBlockApi{}
+		blockApi.SyncBlocksFunc = func(peerId blockchain.PeerId, response blockchain.BlockRangeResponse) error {
+			assert.Equal(t, "peer1", peerId.Id)
+			assert.Equal(t, blockchain.BlockHeight(1), response.From)
+			assert.Equal(t, blockchain.BlockHeight(4), response.To)
+			assert.Equal(t, 2, len(response.Blocks))
+			assert.Equal(t, []byte("seal2"), response.Blocks[1].GetSeal())
+			return test.input.syncBlocksErr
+		}
+
+		grpcCommandHandler := adapter.NewGrpcCommandHandler(blockApi, mock.BlockQueryApi{}, mock.SyncCheckGrpcCommandService{})
+
+		err := grpcCommandHandler.HandleGrpcCommand(blockchain.GrpcReceiveCommand{
+			CommandModel: midgard.CommandModel{ID: "111"},
+			Body:         test.input.body,
+			Protocol:     "BlockRangeResponseProtocol",
+			FromPeer:     blockchain.Peer{PeerId: blockchain.PeerId{Id: "peer1"}},
+		})
+		assert.Equal(t, test.err, err)
+	}
+}
+
+func TestGrpcCommandHandler_HandleGrpcCommand_HeaderRangeRequestProtocol(t *testing.T) {
+	validBody, _ := common.Serialize(blockchain.BlockRangeRequest{From: 1, To: 5})
+	invalidBody, _ := common.Serialize(blockchain.BlockRangeRequest{From: 5, To: 1})
+
+	tests := map[string]struct {
+		input struct {
+			body              []byte
+			responseHeaderErr error
+		}
+		responseHeights []uint64
+		err             error
+	}{
+		"success: send headers which exist": {
+			input: struct {
+				body              []byte
+				responseHeaderErr error
+			}{body: validBody},
+			responseHeights: []uint64{1, 2, 3},
+			err:             nil,
+		},
+		"fail: invalid range": {
+			input: struct {
+				body              []byte
+				responseHeaderErr error
+			}{body: invalidBody},
+			err: adapter.ErrBlockInfoDeliver,
+		},
+		"fail: response header range": {
+			input: struct {
+				body              []byte
+				responseHeaderErr error
+			}{body: validBody, responseHeaderErr: errors.New("error when response header range")},
+			responseHeights: []uint64{1, 2, 3},
+			err:             adapter.ErrResponseHeader,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+
+		blockQueryApi := mock.BlockQueryApi{}
+		blockQueryApi.GetBlockByHeightFunc = func(height uint64) (blockchain.Block, error) {
+			if height > 3 {
+				return nil, blockchain.ErrBlockNotFound
+			}
+			return &blockchain.DefaultBlock{Height: height, Seal: []byte("seal"), Signature: []byte("signature")}, nil
+		}
+
+		grpcCommandService := mock.SyncCheckGrpcCommandService{}
+		grpcCommandService.ResponseHeaderRangeFunc = func(peerId blockchain.PeerId, headers []blockchain.SignedHeader) error {
+			assert.Equal(t, "peer1", peerId.Id)
+
+			heights := make([]uint64, 0)
+			for _, header := range headers {
+				heights = append(heights, header.Height)
+				assert.Equal(t, []byte("signature"), header.Signature)
+			}
+			assert.Equal(t, test.responseHeights, heights)
+
+			return test.input.responseHeaderErr
+		}
+
+		grpcCommandHandler := adapter.NewGrpcCommandHandler(mock.MockSyncBlockApi{}, blockQueryApi, grpcCommandService)
+
+		err := grpcCommandHandler.HandleGrpcCommand(blockchain.GrpcReceiveCommand{
+			CommandModel: midgard.CommandModel{ID: "111"},
+			Body:         test.input.body,
+			Protocol:     "HeaderRangeRequestProtocol",
+			FromPeer:     blockchain.Peer{PeerId: blockchain.PeerId{Id: "peer1"}},
+		})
+		assert.Equal(t, test.err, err)
+	}
+}
+
+// pruned mode 의 repository 처럼 header 를 조회할 수 있으면 transaction 이 지워진 block 의 header 도 보낸다.
+type prunedBlockQueryApi struct {
+	mock.BlockQueryApi
+}
+
+func (api prunedBlockQueryApi) GetHeaderByHeight(height blockchain.BlockHeight) (blockchain.SignedHeader, error) {
+	if height > 3 {
+		return blockchain.SignedHeader{}, blockchain.ErrHeaderNotFound
+	}
+	return blockchain.SignedHeader{BlockHeader: blockchain.BlockHeader{Height: height}, Seal: []byte("seal")}, nil
+}
+
+func TestGrpcCommandHandler_HandleGrpcCommand_HeaderRangeRequestProtocol_Pruned(t *testing.T) {
+	// given
+	body, _ := common.Serialize(blockchain.BlockRangeRequest{From: 1, To: 5})
+
+	blockQueryApi := prunedBlockQueryApi{}
+	blockQueryApi.GetBlockByHeightFunc = func(height uint64) (blockchain.Block, error) {
+		return nil, blockchain.ErrBlockPruned
+	}
+
+	heights := make([]uint64, 0)
+	grpcCommandService := mock.SyncCheckGrpcCommandService{}
+	grpcCommandService.ResponseHeaderRangeFunc = func(peerId blockchain.PeerId, headers []blockchain.SignedHeader) error {
+		for _, header := range headers {
+			heights = append(heights, header.Height)
+		}
+		return nil
+	}
+
+	grpcCommandHandler := adapter.NewGrpcCommandHandler(mock.MockSyncBlockApi{}, blockQueryApi, grpcCommandService)
+
+	// when
+	err := grpcCommandHandler.HandleGrpcCommand(blockchain.GrpcReceiveCommand{
+		CommandModel: midgard.CommandModel{ID: "111"},
+		Body:         body,
+		Protocol:     "HeaderRangeRequestProtocol",
+		FromPeer:     blockchain.Peer{PeerId: blockchain.PeerId{Id: "peer1"}},
+	})
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{1, 2, 3}, heights)
+}
+
+func TestGrpcCommandHandler_HandleGrpcCommand_TxProofRequestProtocol(t *testing.T) {
+	validator := blockchain.DefaultValidator{}
+	txList := []blockchain.Transaction{&blockchain.DefaultTransaction{ID: "tx1"}, &blockchain.DefaultTransaction{ID: "tx2"}}
+	txSeal, _ := validator.BuildTxSeal(txList)
+	block := &blockchain.DefaultBlock{Height: 4, Seal: []byte("seal4"), TxSeal: txSeal, Version: blockchain.CurrentBlockVersion}
+	for _, tx := range txList {
+		block.PutTx(tx)
+	}
+
+	tx2Body, _ := common.Serialize("tx2")
+	unknownBody, _ := common.Serialize("tx3")
+
+	tests := map[string]struct {
+		input []byte
+		found bool
+		err   error
+	}{
+		"success: proof of committed tx": {
+			input: tx2Body,
+			found: true,
+			err:   nil,
+		},
+		"success: unknown tx": {
+			input: unknownBody,
+			found: false,
+			err:   nil,
+		},
+		"fail: invalid body": {
+			input: []byte("tx2"),
+			err:   adapter.ErrBlockInfoDeliver,
+		},
+	}
+
+	blockQueryApi := mock.BlockQueryApi{}
+	blockQueryApi.GetBlockByTxIDFunc = func(txid string) (blockchain.Block, error) {
+		if txid == "tx3" {
+			return nil, blockchain.ErrBlockNotFound
+		}
+		return block, nil
+	}
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+
+		grpcCommandService := mock.SyncCheckGrpcCommandService{}
+		grpcCommandService.ResponseTxProofFunc = func(peerId blockchain.PeerId, response blockchain.TxProofResponse) error {
+			assert.Equal(t, test.found, response.Found)
+			if response.Found {
+				assert.Equal(t, []byte("seal4"), response.Seal)
+				assert.True(t, validator.VerifyTxProof(response.Proof, blockchain.NewBlockHeader(block)))
+			}
+			return nil
+		}
+
+		grpcCommandHandler := adapter.NewGrpcCommandHandler(mock.MockSyncBlockApi{}, blockQueryApi, grpcCommandService)
+
+		err := grpcCommandHandler.HandleGrpcCommand(blockchain.GrpcReceiveCommand{
+			CommandModel: midgard.CommandModel{ID: "111"},
+			Body:         test.input,
+			Protocol:     "TxProofRequestProtocol",
+			FromPeer:     blockchain.Peer{PeerId: blockchain.PeerId{Id: "peer1"}},
+		})
+		assert.Equal(t, test.err, err)
+	}
+}
@@ -46,9 +46,37 @@ func (gcs *GrpcCommandService) ResponseBlock(peerId blockchain.PeerId, block blo
 		return ErrEmptyBlockSeal
 	}
 
-	body := block
+	body, err := block.Serialize()
+	if err != nil {
+		return err
+	}
+
+	deliverCommand := createGrpcDeliverCommandWithData("BlockResponseProtocol", body)
+	if err != nil {
+		return err
+	}
+
+	deliverCommand.Recipients = append(deliverCommand.Recipients, peerId.ToString())
+
+	return gcs.publish("Command", "message.deliver", deliverCommand)
+}
+
+// "BlockRangeRequestProtocol"을 통해서 from 부터 to 까지의 block 을 요청한다.
+func (gcs *GrpcCommandService) RequestBlockRange(peerId blockchain.PeerId, from blockchain.BlockHeight, to blockchain.BlockHeight) error {
+	if peerId.Id == "" {
+		return ErrEmptyNodeId
+	}
+
+	if from > to {
+		return ErrInvalidBlockRange
+	}
+
+	body := blockchain.BlockRangeRequest{
+		From: from,
+		To:   to,
+	}
 
-	deliverCommand, err := createGrpcDeliverCommand("BlockResponseProtocol", body)
+	deliverCommand, err := createGrpcDeliverCommand("BlockRangeRequestProtocol", body)
 	if err != nil {
 		return err
 	}
@@ -58,9 +86,185 @@ func (gcs *GrpcCommandService) ResponseBlock(peerId blockchain.PeerId, block blo
 	return gcs.publish("Command", "message.deliver", deliverCommand)
 }
 
-// TODO "SyncCheckResponseProtocol"을 통해서 last block을 전달한다.
-func (gcs *GrpcCommandService) SyncCheckResponse(block blockchain.Block) error {
-	return nil
+// "BlockRangeResponseProtocol"을 통해서 요청받은 block 들을 전달한다.
+func (gcs *GrpcCommandService) ResponseBlockRange(peerId blockchain.PeerId, response blockchain.BlockRangeResponse) error {
+	if peerId.Id == "" {
+		return ErrEmptyNodeId
+	}
+
+	for _, block := range response.Blocks {
+		if block.GetSeal() == nil {
+			return ErrEmptyBlockSeal
+		}
+	}
+
+	body, err := blockchain.EncodeBlockRangeResponse(response)
+	if err != nil {
+		return err
+	}
+
+	deliverCommand := createGrpcDeliverCommandWithData("BlockRangeResponseProtocol", body)
+
+	deliverCommand.Recipients = append(deliverCommand.Recipients, peerId.ToString())
+
+	return gcs.publish("Command", "message.deliver", deliverCommand)
+}
+
+// "SyncCheckRequestProtocol"을 통해서 상대방의 last block을 요청한다.
+func (gcs *GrpcCommandService) SyncCheckRequest(peerId blockchain.PeerId) error {
+	if peerId.Id == "" {
+		return ErrEmptyNodeId
+	}
+
+	deliverCommand, err := createGrpcDeliverCommand("SyncCheckRequestProtocol", nil)
+	if err != nil {
+		return err
+	}
+
+	deliverCommand.Recipients = append(deliverCommand.Recipients, peerId.ToString())
+
+	return gcs.publish("Command", "message.deliver", deliverCommand)
+}
+
+// "SyncCheckResponseProtocol"을 통해서 last block을 전달한다.
+func (gcs *GrpcCommandService) SyncCheckResponse(peerId blockchain.PeerId, block blockchain.Block) error {
+	if peerId.Id == "" {
+		return ErrEmptyNodeId
+	}
+
+	if block.GetSeal() == nil {
+		return ErrEmptyBlockSeal
+	}
+
+	body, err := block.Serialize()
+	if err != nil {
+		return err
+	}
+
+	deliverCommand := createGrpcDeliverCommandWithData("SyncCheckResponseProtocol", body)
+
+	deliverCommand.Recipients = append(deliverCommand.Recipients, peerId.ToString())
+
+	return gcs.publish("Command", "message.deliver", deliverCommand)
+}
+
+// "HeaderRangeRequestProtocol"을 통해서 from 부터 to 까지의 header 를 요청한다.
+func (gcs *GrpcCommandService) RequestHeaderRange(peerId blockchain.PeerId, from blockchain.BlockHeight, to blockchain.BlockHeight) error {
+	if peerId.Id == "" {
+		return ErrEmptyNodeId
+	}
+
+	if from > to {
+		return ErrInvalidBlockRange
+	}
+
+	body := blockchain.BlockRangeRequest{
+		From: from,
+		To:   to,
+	}
+
+	deliverCommand, err := createGrpcDeliverCommand("HeaderRangeRequestProtocol", body)
+	if err != nil {
+		return err
+	}
+
+	deliverCommand.Recipients = append(deliverCommand.Recipients, peerId.ToString())
+
+	return gcs.publish("Command", "message.deliver", deliverCommand)
+}
+
+// "HeaderRangeResponseProtocol"을 통해서 요청받은 header 들을 전달한다.
+func (gcs *GrpcCommandService) ResponseHeaderRange(peerId blockchain.PeerId, headers []blockchain.SignedHeader) error {
+	if peerId.Id == "" {
+		return ErrEmptyNodeId
+	}
+
+	for _, header := range headers {
+		if header.Seal == nil {
+			return ErrEmptyBlockSeal
+		}
+	}
+
+	deliverCommand, err := createGrpcDeliverCommand("HeaderRangeResponseProtocol", headers)
+	if err != nil {
+		return err
+	}
+
+	deliverCommand.Recipients = append(deliverCommand.Recipients, peerId.ToString())
+
+	return gcs.publish("Command", "message.deliver", deliverCommand)
+}
+
+// "TxProofRequestProtocol"을 통해서 txID 에 해당하는 transaction 의 Merkle proof 를 요청한다.
+func (gcs *GrpcCommandService) RequestTxProof(peerId blockchain.PeerId, txID string) error {
+	if peerId.Id == "" {
+		return ErrEmptyNodeId
+	}
+
+	if txID == "" {
+		return ErrEmptyTxID
+	}
+
+	deliverCommand, err := createGrpcDeliverCommand("TxProofRequestProtocol", txID)
+	if err != nil {
+		return err
+	}
+
+	deliverCommand.Recipients = append(deliverCommand.Recipients, peerId.ToString())
+
+	return gcs.publish("Command", "message.deliver", deliverCommand)
+}
+
+// "TxProofResponseProtocol"을 통해서 transaction 의 Merkle proof 를 전달한다.
+func (gcs *GrpcCommandService) ResponseTxProof(peerId blockchain.PeerId, response blockchain.TxProofResponse) error {
+	if peerId.Id == "" {
+		return ErrEmptyNodeId
+	}
+
+	deliverCommand, err := createGrpcDeliverCommand("TxProofResponseProtocol", response)
+	if err != nil {
+		return err
+	}
+
+	deliverCommand.Recipients = append(deliverCommand.Recipients, peerId.ToString())
+
+	return gcs.publish("Command", "message.deliver", deliverCommand)
+}
+
+// "SnapshotRequestProtocol"을 통해서 상대방의 마지막 snapshot 을 요청한다.
+func (gcs *GrpcCommandService) RequestSnapshot(peerId blockchain.PeerId) error {
+	if peerId.Id == "" {
+		return ErrEmptyNodeId
+	}
+
+	deliverCommand, err := createGrpcDeliverCommand("SnapshotRequestProtocol", nil)
+	if err != nil {
+		return err
+	}
+
+	deliverCommand.Recipients = append(deliverCommand.Recipients, peerId.ToString())
+
+	return gcs.publish("Command", "message.deliver", deliverCommand)
+}
+
+// "SnapshotResponseProtocol"을 통해서 snapshot 을 전달한다.
+func (gcs *GrpcCommandService) ResponseSnapshot(peerId blockchain.PeerId, snapshot blockchain.Snapshot) error {
+	if peerId.Id == "" {
+		return ErrEmptyNodeId
+	}
+
+	if snapshot.Header.Seal == nil {
+		return ErrEmptyBlockSeal
+	}
+
+	deliverCommand, err := createGrpcDeliverCommand("SnapshotResponseProtocol", snapshot)
+	if err != nil {
+		return err
+	}
+
+	deliverCommand.Recipients = append(deliverCommand.Recipients, peerId.ToString())
+
+	return gcs.publish("Command", "message.deliver", deliverCommand)
 }
 
 func createGrpcDeliverCommand(protocol string, body interface{}) (blockchain.GrpcDeliverCommand, error) {
@@ -70,6 +274,11 @@ func createGrpcDeliverCommand(protocol string, body interface{}) (blockchain.Grp
 		return blockchain.GrpcDeliverCommand{}, err
 	}
 
+	return createGrpcDeliverCommandWithData(protocol, data), nil
+}
+
+// block 은 canonical binary encoding 으로 이미 직렬화된 data 를 그대로 body 로 보낸다.
+func createGrpcDeliverCommandWithData(protocol string, data []byte) blockchain.GrpcDeliverCommand {
 	return blockchain.GrpcDeliverCommand{
 		CommandModel: midgard.CommandModel{
 			ID: xid.New().String(),
@@ -77,5 +286,5 @@ func createGrpcDeliverCommand(protocol string, body interface{}) (blockchain.Grp
 		Recipients: make([]string, 0),
 		Body:       data,
 		Protocol:   protocol,
-	}, err
+	}
 }
@@ -127,3 +127,292 @@ func TestGrpcCommandService_ResponseBlock(t *testing.T) {
 	}
 
 }
+
+func TestGrpcCommandService_SyncCheckRequest(t *testing.T) {
+
+	tests := map[string]struct {
+		input struct {
+			peerId blockchain.PeerId
+		}
+		err error
+	}{
+		"success: sync check request": {
+			input: struct {
+				peerId blockchain.PeerId
+			}{
+				peerId: blockchain.PeerId{
+					Id: "1",
+				},
+			},
+			err: nil,
+		},
+		"fail: empty node id": {
+			input: struct {
+				peerId blockchain.PeerId
+			}{
+				peerId: blockchain.PeerId{},
+			},
+			err: adapter.ErrEmptyNodeId,
+		},
+	}
+
+	publish := func(exchange string, topic string, data interface{}) error {
+		assert.Equal(t, exchange, "Command")
+		assert.Equal(t, topic, "message.deliver")
+
+		command := data.(blockchain.GrpcDeliverCommand)
+		assert.Equal(t, "SyncCheckRequestProtocol", command.Protocol)
+		assert.Equal(t, []string{"1"}, command.Recipients)
+
+		return nil
+	}
+
+	GrpcCommandService := adapter.NewGrpcCommandService(publish)
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+		err := GrpcCommandService.SyncCheckRequest(test.input.peerId)
+		assert.Equal(t, err, test.err)
+	}
+}
+
+func TestGrpcCommandService_SyncCheckResponse(t *testing.T) {
+
+	tests := map[string]struct {
+		input struct {
+			peerId blockchain.PeerId
+			block  blockchain.DefaultBlock
+		}
+		err error
+	}{
+		"success: sync check response": {
+			input: struct {
+				peerId blockchain.PeerId
+				block  blockchain.DefaultBlock
+			}{
+				peerId: blockchain.PeerId{
+					Id: "1",
+				},
+				block: blockchain.DefaultBlock{
+					Seal: []byte("seal"),
+				},
+			},
+			err: nil,
+		},
+		"fail: empty node id": {
+			input: struct {
+				peerId blockchain.PeerId
+				block  blockchain.DefaultBlock
+			}{
+				peerId: blockchain.PeerId{},
+				block: blockchain.DefaultBlock{
+					Seal: []byte("seal"),
+				},
+			},
+			err: adapter.ErrEmptyNodeId,
+		},
+		"fail: empty block seal": {
+			input: struct {
+				peerId blockchain.PeerId
+				block  blockchain.DefaultBlock
+			}{
+				peerId: blockchain.PeerId{
+					Id: "1",
+				},
+				block: blockchain.DefaultBlock{
+					Seal: nil,
+				},
+			},
+			err: adapter.ErrEmptyBlockSeal,
+		},
+	}
+
+	publish := func(exchange string, topic string, data interface{}) error {
+		assert.Equal(t, exchange, "Command")
+		assert.Equal(t, topic, "message.deliver")
+
+		command := data.(blockchain.GrpcDeliverCommand)
+		assert.Equal(t, "SyncCheckResponseProtocol", command.Protocol)
+		assert.Equal(t, []string{"1"}, command.Recipients)
+
+		return nil
+	}
+
+	GrpcCommandService := adapter.NewGrpcCommandService(publish)
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+		err := GrpcCommandService.SyncCheckResponse(test.input.peerId, &test.input.block)
+		assert.Equal(t, err, test.err)
+	}
+}
+
+func TestGrpcCommandService_RequestBlockRange(t *testing.T) {
+
+	tests := map[string]struct {
+		input struct {
+			peerId blockchain.PeerId
+			from   uint64
+			to     uint64
+		}
+		err error
+	}{
+		"success: request block range": {
+			input: struct {
+				peerId blockchain.PeerId
+				from   uint64
+				to     uint64
+			}{peerId: blockchain.PeerId{Id: "1"}, from: 1, to: 10},
+			err: nil,
+		},
+		"fail: empty node id": {
+			input: struct {
+				peerId blockchain.PeerId
+				from   uint64
+				to     uint64
+			}{peerId: blockchain.PeerId{}, from: 1, to: 10},
+			err: adapter.ErrEmptyNodeId,
+		},
+		"fail: invalid range": {
+			input: struct {
+				peerId blockchain.PeerId
+				from   uint64
+				to     uint64
+			}{peerId: blockchain.PeerId{Id: "1"}, from: 10, to: 1},
+			err: adapter.ErrInvalidBlockRange,
+		},
+	}
+
+	publish := func(exchange string, topic string, data interface{}) error {
+		assert.Equal(t, exchange, "Command")
+		assert.Equal(t, topic, "message.deliver")
+
+		command := data.(blockchain.GrpcDeliverCommand)
+		assert.Equal(t, "BlockRangeRequestProtocol", command.Protocol)
+		assert.Equal(t, []string{"1"}, command.Recipients)
+
+		return nil
+	}
+
+	GrpcCommandService := adapter.NewGrpcCommandService(publish)
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+		err := GrpcCommandService.RequestBlockRange(test.input.peerId, test.input.from, test.input.to)
+		assert.Equal(t, err, test.err)
+	}
+}
+
+func TestGrpcCommandService_ResponseBlockRange(t *testing.T) {
+
+	tests := map[string]struct {
+		input struct {
+			peerId blockchain.PeerId
+			blocks []blockchain.Block
+		}
+		err error
+	}{
+		"success: response block range": {
+			input: struct {
+				peerId blockchain.PeerId
+				blocks []blockchain.Block
+			}{
+				peerId: blockchain.PeerId{Id: "1"},
+				blocks: []blockchain.Block{&blockchain.DefaultBlock{Seal: []byte("seal")}},
+			},
+			err: nil,
+		},
+		"success: empty response": {
+			input: struct {
+				peerId blockchain.PeerId
+				blocks []blockchain.Block
+			}{
+				peerId: blockchain.PeerId{Id: "1"},
+				blocks: []blockchain.Block{},
+			},
+			err: nil,
+		},
+		"fail: empty block seal": {
+			input: struct {
+				peerId blockchain.PeerId
+				blocks []blockchain.Block
+			}{
+				peerId: blockchain.PeerId{Id: "1"},
+				blocks: []blockchain.Block{&blockchain.DefaultBlock{}},
+			},
+			err: adapter.ErrEmptyBlockSeal,
+		},
+	}
+
+	publish := func(exchange string, topic string, data interface{}) error {
+		command := data.(blockchain.GrpcDeliverCommand)
+		assert.Equal(t, "BlockRangeResponseProtocol", command.Protocol)
+
+		response, err := blockchain.DecodeBlockRangeResponse(command.Body)
+		assert.NoError(t, err)
+		assert.Equal(t, blockchain.BlockHeight(1), response.From)
+		assert.Equal(t, blockchain.BlockHeight(3), response.To)
+
+		return nil
+	}
+
+	GrpcCommandService := adapter.NewGrpcCommandService(publish)
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+		err := GrpcCommandService.ResponseBlockRange(test.input.peerId, blockchain.BlockRangeResponse{From: 1, To: 3, Blocks: test.input.blocks})
+		assert.Equal(t, err, test.err)
+	}
+}
+
+func TestGrpcCommandService_RequestTxProof(t *testing.T) {
+
+	tests := map[string]struct {
+		input struct {
+			peerId blockchain.PeerId
+			txID   string
+		}
+		err error
+	}{
+		"success: request tx proof": {
+			input: struct {
+				peerId blockchain.PeerId
+				txID   string
+			}{peerId: blockchain.PeerId{Id: "1"}, txID: "tx1"},
+			err: nil,
+		},
+		"fail: empty node id": {
+			input: struct {
+				peerId blockchain.PeerId
+				txID   string
+			}{peerId: blockchain.PeerId{}, txID: "tx1"},
+			err: adapter.ErrEmptyNodeId,
+		},
+		"fail: empty tx id": {
+			input: struct {
+				peerId blockchain.PeerId
+				txID   string
+			}{peerId: blockchain.PeerId{Id: "1"}, txID: ""},
+			err: adapter.ErrEmptyTxID,
+		},
+	}
+
+	publish := func(exchange string, topic string, data interface{}) error {
+		assert.Equal(t, exchange, "Command")
+		assert.Equal(t, topic, "message.deliver")
+
+		command := data.(blockchain.GrpcDeliverCommand)
+		assert.Equal(t, "TxProofRequestProtocol", command.Protocol)
+		assert.Equal(t, []string{"1"}, command.Recipients)
+
+		return nil
+	}
+
+	GrpcCommandService := adapter.NewGrpcCommandService(publish)
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+		err := GrpcCommandService.RequestTxProof(test.input.peerId, test.input.txID)
+		assert.Equal(t, err, test.err)
+	}
+}
@@ -0,0 +1,69 @@
+package adapter
+
+import (
+	"crypto/sha256"
+
+	"github.com/it-chain/engine/blockchain"
+	"github.com/it-chain/heimdall/auth"
+	"github.com/it-chain/heimdall/key"
+)
+
+// HeimdallSigner 는 node 의 heimdall private key 로 block seal 에 서명한다.
+type HeimdallSigner struct {
+	priKey key.PriKey
+	auth   auth.Auth
+}
+
+func NewHeimdallSigner(priKey key.PriKey) (*HeimdallSigner, error) {
+	a, err := auth.NewAuth()
+	if err != nil {
+		return nil, err
+	}
+
+	return &HeimdallSigner{
+		priKey: priKey,
+		auth:   a,
+	}, nil
+}
+
+func (s *HeimdallSigner) Sign(data []byte) ([]byte, error) {
+	digest := sha256.Sum256(data)
+
+	return s.auth.Sign(s.priKey, digest[:], auth.EQUAL_SHA256.SignerOptsToPSSOptions())
+}
+
+// HeimdallSignatureVerifier 는 PublicKeyRepository 에 등록된 peer 의 public key 로 서명을 검증한다.
+type HeimdallSignatureVerifier struct {
+	publicKeyRepository blockchain.PublicKeyRepository
+	keyGenOpts          key.KeyGenOpts
+	auth                auth.Auth
+}
+
+func NewHeimdallSignatureVerifier(publicKeyRepository blockchain.PublicKeyRepository, keyGenOpts key.KeyGenOpts) (*HeimdallSignatureVerifier, error) {
+	a, err := auth.NewAuth()
+	if err != nil {
+		return nil, err
+	}
+
+	return &HeimdallSignatureVerifier{
+		publicKeyRepository: publicKeyRepository,
+		keyGenOpts:          keyGenOpts,
+		auth:                a,
+	}, nil
+}
+
+func (v *HeimdallSignatureVerifier) Verify(peerId blockchain.PeerId, data []byte, signature []byte) (bool, error) {
+	pem, err := v.publicKeyRepository.FindByPeerId(peerId)
+	if err != nil {
+		return false, err
+	}
+
+	pubKey, err := key.PEMToPublicKey(pem, v.keyGenOpts)
+	if err != nil {
+		return false, err
+	}
+
+	digest := sha256.Sum256(data)
+
+	return v.auth.Verify(pubKey, signature, digest[:], auth.EQUAL_SHA256.SignerOptsToPSSOptions())
+}
@@ -0,0 +1,52 @@
+package adapter
+
+import (
+	"encoding/json"
+
+	"github.com/it-chain/engine/blockchain"
+)
+
+type LightSyncApi interface {
+	SyncHeaderRange(peerId blockchain.PeerId, headers []blockchain.SignedHeader) error
+	ReceiveTxProof(response blockchain.TxProofResponse) error
+}
+
+// LightGrpcCommandHandler 는 light node 가 full node 에게서 받은 header 와 transaction proof 를 처리한다.
+// block 을 저장하지 않으므로 다른 protocol 은 무시한다.
+type LightGrpcCommandHandler struct {
+	lightApi LightSyncApi
+}
+
+func NewLightGrpcCommandHandler(lightApi LightSyncApi) *LightGrpcCommandHandler {
+	return &LightGrpcCommandHandler{
+		lightApi: lightApi,
+	}
+}
+
+func (g *LightGrpcCommandHandler) HandleGrpcCommand(command blockchain.GrpcReceiveCommand) error {
+	switch command.Protocol {
+	case "HeaderRangeResponseProtocol":
+		headers := make([]blockchain.SignedHeader, 0)
+		err := json.Unmarshal(command.Body, &headers)
+		if err != nil {
+			return ErrBlockInfoDeliver
+		}
+
+		err = g.lightApi.SyncHeaderRange(command.FromPeer.PeerId, headers)
+		if err != nil {
+			return ErrSyncHeader
+		}
+		break
+
+	case "TxProofResponseProtocol":
+		var response blockchain.TxProofResponse
+		err := json.Unmarshal(command.Body, &response)
+		if err != nil {
+			return ErrBlockInfoDeliver
+		}
+
+		return g.lightApi.ReceiveTxProof(response)
+	}
+
+	return nil
+}
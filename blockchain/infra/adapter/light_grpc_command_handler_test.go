@@ -0,0 +1,98 @@
+package adapter_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/it-chain/engine/blockchain"
+	"github.com/it-chain/engine/blockchain/infra/adapter"
+	"github.com/it-chain/engine/blockchain/test/mock"
+	"github.com/it-chain/engine/common"
+	"github.com/it-chain/midgard"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLightGrpcCommandHandler_HandleGrpcCommand(t *testing.T) {
+	headerBody, _ := common.Serialize([]blockchain.SignedHeader{
+		{BlockHeader: blockchain.BlockHeader{Height: 1}, Seal: []byte("seal1")},
+		{BlockHeader: blockchain.BlockHeader{Height: 2}, Seal: []byte("seal2")},
+	})
+	proofBody, _ := common.Serialize(blockchain.TxProofResponse{TxID: "tx1", Found: true, Height: 2})
+
+	tests := map[string]struct {
+		input struct {
+			protocol string
+			body     []byte
+			syncErr  error
+		}
+		err error
+	}{
+		"success: header range response": {
+			input: struct {
+				protocol string
+				body     []byte
+				syncErr  error
+			}{protocol: "HeaderRangeResponseProtocol", body: headerBody},
+			err: nil,
+		},
+		"fail: invalid header": {
+			input: struct {
+				protocol string
+				body     []byte
+				syncErr  error
+			}{protocol: "HeaderRangeResponseProtocol", body: headerBody, syncErr: errors.New("invalid seal")},
+			err: adapter.ErrSyncHeader,
+		},
+		"fail: header range body": {
+			input: struct {
+				protocol string
+				body     []byte
+				syncErr  error
+			}{protocol: "HeaderRangeResponseProtocol", body: []byte("header")},
+			err: adapter.ErrBlockInfoDeliver,
+		},
+		"success: tx proof response": {
+			input: struct {
+				protocol string
+				body     []byte
+				syncErr  error
+			}{protocol: "TxProofResponseProtocol", body: proofBody},
+			err: nil,
+		},
+		"success: ignore block protocol": {
+			input: struct {
+				protocol string
+				body     []byte
+				syncErr  error
+			}{protocol: "BlockRangeRequestProtocol", body: []byte("range")},
+			err: nil,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+
+		lightApi := mock.LightSyncApi{}
+		lightApi.SyncHeaderRangeFunc = func(peerId blockchain.PeerId, headers []blockchain.SignedHeader) error {
+			assert.Equal(t, "peer1", peerId.Id)
+			assert.Equal(t, 2, len(headers))
+			assert.Equal(t, []byte("seal2"), headers[1].Seal)
+			return test.input.syncErr
+		}
+		lightApi.ReceiveTxProofFunc = func(response blockchain.TxProofResponse) error {
+			assert.Equal(t, "tx1", response.TxID)
+			assert.True(t, response.Found)
+			return nil
+		}
+
+		handler := adapter.NewLightGrpcCommandHandler(lightApi)
+
+		err := handler.HandleGrpcCommand(blockchain.GrpcReceiveCommand{
+			CommandModel: midgard.CommandModel{ID: "111"},
+			Body:         test.input.body,
+			Protocol:     test.input.protocol,
+			FromPeer:     blockchain.Peer{PeerId: blockchain.PeerId{Id: "peer1"}},
+		})
+		assert.Equal(t, test.err, err)
+	}
+}
@@ -0,0 +1,30 @@
+package adapter
+
+import (
+	"github.com/it-chain/engine/blockchain"
+)
+
+type NodeCommandHandler struct {
+	peerRepository      blockchain.PeerRepository
+	publicKeyRepository blockchain.PublicKeyRepository
+}
+
+func NewNodeCommandHandler(peerRepository blockchain.PeerRepository, publicKeyRepository blockchain.PublicKeyRepository) *NodeCommandHandler {
+	return &NodeCommandHandler{
+		peerRepository:      peerRepository,
+		publicKeyRepository: publicKeyRepository,
+	}
+}
+
+// p2p 에서 새로운 node 가 연결되면 동기화 대상 peer 로 저장하고, block 서명 검증을 위해 public key 를 등록한다.
+func (h *NodeCommandHandler) HandleNodeUpdateCommand(command blockchain.NodeUpdateCommand) error {
+	if err := h.peerRepository.Add(command.Peer); err != nil {
+		return err
+	}
+
+	if len(command.Peer.PubKey) == 0 {
+		return nil
+	}
+
+	return h.publicKeyRepository.Save(command.Peer.PeerId, command.Peer.PubKey)
+}
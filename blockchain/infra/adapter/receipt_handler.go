@@ -0,0 +1,45 @@
+package adapter
+
+import (
+	"github.com/it-chain/engine/blockchain"
+)
+
+type ReceiptApi interface {
+	ExecuteBlock(seal []byte) error
+	SaveReceipts(blockID string, results []blockchain.TxResult) error
+	RollbackBlock(height blockchain.BlockHeight, txIDs []string) error
+}
+
+// ReceiptHandler 는 commit 된 block 을 icode 에 실행 요청하고, icode 가 돌려준 결과를 receipt 로 저장한다.
+// reorg 로 되돌린 block 은 commit 된 block 보다 먼저 처리해야 하므로 block.committed 와 block.rolledback 을 같은 구독으로 받는다.
+type ReceiptHandler struct {
+	receiptApi ReceiptApi
+}
+
+func NewReceiptHandler(receiptApi ReceiptApi) *ReceiptHandler {
+	return &ReceiptHandler{
+		receiptApi: receiptApi,
+	}
+}
+
+func (h *ReceiptHandler) HandleBlockCommittedEvent(event blockchain.BlockCommittedEvent) error {
+	if len(event.Seal) == 0 {
+		return ErrEmptyBlockSeal
+	}
+
+	return h.receiptApi.ExecuteBlock(event.Seal)
+}
+
+// HandleBlockRolledBackEvent 는 reorg 로 되돌린 block 의 receipt 를 지우고,
+// 다음 block 을 실행할 때 icode 의 state 를 처음부터 다시 만들도록 한다.
+func (h *ReceiptHandler) HandleBlockRolledBackEvent(event blockchain.BlockRolledBackEvent) error {
+	if len(event.Seal) == 0 {
+		return ErrEmptyBlockSeal
+	}
+
+	return h.receiptApi.RollbackBlock(event.Height, event.TxIDs)
+}
+
+func (h *ReceiptHandler) HandleBlockResultCommand(command blockchain.BlockResultCommand) error {
+	return h.receiptApi.SaveReceipts(command.GetID(), command.TxResults)
+}
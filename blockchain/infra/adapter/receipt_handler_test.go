@@ -0,0 +1,147 @@
+package adapter_test
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/it-chain/engine/blockchain"
+	"github.com/it-chain/engine/blockchain/api"
+	"github.com/it-chain/engine/blockchain/infra/adapter"
+	"github.com/it-chain/engine/blockchain/infra/repository/leveldb"
+	"github.com/it-chain/engine/blockchain/test/mock"
+	"github.com/it-chain/engine/core/eventstore"
+	"github.com/it-chain/engine/txpool"
+	txpoolApi "github.com/it-chain/engine/txpool/api"
+	txpoolAdapter "github.com/it-chain/engine/txpool/infra/adapter"
+	"github.com/it-chain/midgard"
+	"github.com/stretchr/testify/assert"
+)
+
+// reorg 로 block 을 되돌리면 receipt 를 지우고, icode state 를 처음부터 다시 만들고,
+// 새 chain 에 commit 되지 않은 transaction 은 txpool 에 다시 넣는다.
+func TestReceiptHandler_Reorg(t *testing.T) {
+	// given
+	blockPath := "./.test_reorg_block"
+	receiptPath := "./.test_reorg_receipt"
+	blockRepository := leveldb.NewBlockRepository(blockPath)
+	receiptRepository := leveldb.NewReceiptRepository(receiptPath)
+	defer func() {
+		blockRepository.Close()
+		receiptRepository.Close()
+		os.RemoveAll(blockPath)
+		os.RemoveAll(receiptPath)
+	}()
+
+	published := make([]midgard.Event, 0)
+	eventLog := mock.NewEventLog()
+	eventRepository := mock.EventRepository{}
+	eventRepository.LoadFunc = eventLog.Load
+	eventRepository.SaveFunc = func(aggregateID string, events ...midgard.Event) error {
+		published = append(published, events...)
+		return eventLog.Save(aggregateID, events...)
+	}
+	eventRepository.CloseFunc = func() {}
+
+	eventstore.InitForMock(eventRepository)
+	defer eventstore.Close()
+
+	genesis := &blockchain.DefaultBlock{Seal: []byte("seal0"), Height: 0}
+	a1 := mock.NewLegacyBlock(t, genesis.Seal, 1, newReorgTx("txA"), newReorgTx("txShared"))
+	assert.NoError(t, blockRepository.AddBlock(genesis))
+	assert.NoError(t, blockRepository.AddBlock(a1))
+	assert.NoError(t, receiptRepository.Save(1, []blockchain.Receipt{
+		{TxID: "txA", Success: true, BlockHeight: 1},
+		{TxID: "txShared", Success: true, BlockHeight: 1},
+	}, []byte("root1")))
+
+	peerRepository := mock.PeerRepository{}
+	peerRepository.FindAllFunc = func() ([]blockchain.Peer, error) {
+		return []blockchain.Peer{}, nil
+	}
+	blockApi, err := api.NewBlockApi("zf", blockRepository, mock.GrpcCommandService{}, peerRepository)
+	assert.NoError(t, err)
+
+	executed := make([]string, 0)
+	commandService := mock.CommandService{}
+	commandService.SendBlockExecuteCommandFunc = func(block blockchain.Block) error {
+		executed = append(executed, string(block.GetSeal()))
+		return nil
+	}
+	commandService.SendResetBlockExecuteCommandFunc = func(block blockchain.Block) error {
+		executed = append(executed, "reset:"+string(block.GetSeal()))
+		return nil
+	}
+	receiptHandler := adapter.NewReceiptHandler(api.NewReceiptApi(blockRepository, receiptRepository, commandService))
+	txHandler := txpoolAdapter.NewBlockCommittedEventHandler(txpoolApi.NewTransactionApi("zf", nil))
+
+	b1 := mock.NewLegacyBlock(t, genesis.Seal, 1, newReorgTx("txShared"))
+	b2 := mock.NewLegacyBlock(t, b1.Seal, 2, newReorgTx("txB"))
+
+	// when: 더 긴 branch 로 reorg 한다.
+	assert.NoError(t, blockApi.AddBlockToPool(b1))
+	assert.NoError(t, blockApi.AddBlockToPool(b2))
+	assert.NoError(t, blockApi.CheckAndSaveBlockFromPool(2))
+
+	// when: 각 component 가 발행된 순서대로 event 를 받는다.
+	for i := 0; i < len(published); i++ {
+		switch event := published[i].(type) {
+		case blockchain.BlockRolledBackEvent:
+			assert.NoError(t, receiptHandler.HandleBlockRolledBackEvent(event))
+
+			txpoolEvent := txpool.BlockRolledBackEvent{}
+			convertEvent(t, event, &txpoolEvent)
+			assert.NoError(t, txHandler.HandleBlockRolledBackEvent(txpoolEvent))
+
+		case blockchain.BlockCommittedEvent:
+			assert.NoError(t, receiptHandler.HandleBlockCommittedEvent(event))
+
+			txpoolEvent := txpool.BlockCommittedEvent{}
+			convertEvent(t, event, &txpoolEvent)
+			assert.NoError(t, txHandler.HandleBlockCommittedEvent(txpoolEvent))
+		}
+	}
+
+	// then: chain 은 branch 로 바뀌었다.
+	lastBlock, err := blockRepository.GetLastBlock()
+	assert.NoError(t, err)
+	assert.Equal(t, b2.Seal, lastBlock.GetSeal())
+
+	// then: 되돌린 block 의 receipt 는 지워졌다.
+	_, err = receiptRepository.GetReceipt("txA")
+	assert.Equal(t, blockchain.ErrReceiptNotFound, err)
+	_, err = receiptRepository.GetReceiptsRoot(1)
+	assert.Equal(t, blockchain.ErrReceiptNotFound, err)
+
+	// then: icode 의 state 를 비우고 새 chain 을 처음부터 실행한다.
+	assert.Equal(t, []string{"reset:" + string(b1.Seal), string(b2.Seal)}, executed)
+
+	// then: 새 chain 에 없는 transaction 만 서명을 유지한 채 pool 에 남는다.
+	tx := &txpool.Transaction{}
+	assert.NoError(t, eventstore.Load(tx, "txA"))
+	assert.Equal(t, "txA", tx.TxId)
+	assert.Equal(t, []byte("signature:txA"), tx.Signature)
+
+	tx = &txpool.Transaction{}
+	assert.NoError(t, eventstore.Load(tx, "txShared"))
+	assert.Equal(t, "", tx.TxId)
+}
+
+// convertEvent 는 message queue 를 거치는 것처럼 blockchain 의 event 를 json 으로 다른 component 의 event 로 바꾼다.
+func convertEvent(t *testing.T, event interface{}, converted interface{}) {
+	serialized, err := json.Marshal(event)
+	assert.NoError(t, err)
+	assert.NoError(t, json.Unmarshal(serialized, converted))
+}
+
+func newReorgTx(txID string) *blockchain.DefaultTransaction {
+	return &blockchain.DefaultTransaction{
+		ID:        txID,
+		Status:    blockchain.StatusTransactionValid,
+		PeerID:    "zf",
+		Timestamp: time.Now().Round(0),
+		TxData:    blockchain.NewTxData("2.0", blockchain.Invoke, blockchain.NewParams(0, "set", []string{txID}), "icode1"),
+		Signature: []byte("signature:" + txID),
+	}
+}
@@ -0,0 +1,106 @@
+package adapter
+
+import (
+	"encoding/json"
+
+	"github.com/it-chain/engine/blockchain"
+)
+
+type SnapshotApi interface {
+	TakeSnapshot(height blockchain.BlockHeight) error
+	SaveICodeState(state blockchain.ICodeState) error
+	RemoveICodeState(id string) error
+	GetLastSnapshot() (blockchain.Snapshot, error)
+	ReceiveSnapshot(peerId blockchain.PeerId, snapshot blockchain.Snapshot) (bool, error)
+}
+
+type Synchronizer interface {
+	Synchronize() error
+}
+
+// SnapshotGrpcCommandHandler 는 peer 의 snapshot 요청에 마지막 snapshot 으로 응답하고,
+// 받은 snapshot 을 가져왔다면 snapshot 다음 block 부터 동기화를 시작한다.
+type SnapshotGrpcCommandHandler struct {
+	snapshotApi        SnapshotApi
+	synchronizer       Synchronizer
+	grpcCommandService blockchain.SnapshotGrpcCommandService
+}
+
+func NewSnapshotGrpcCommandHandler(snapshotApi SnapshotApi, synchronizer Synchronizer, grpcCommandService blockchain.SnapshotGrpcCommandService) *SnapshotGrpcCommandHandler {
+	return &SnapshotGrpcCommandHandler{
+		snapshotApi:        snapshotApi,
+		synchronizer:       synchronizer,
+		grpcCommandService: grpcCommandService,
+	}
+}
+
+func (g *SnapshotGrpcCommandHandler) HandleGrpcCommand(command blockchain.GrpcReceiveCommand) error {
+	switch command.Protocol {
+	case "SnapshotRequestProtocol":
+		// snapshot 이 없으면 응답하지 않는다. 요청한 node 는 다른 peer 의 snapshot 을 기다린다.
+		snapshot, err := g.snapshotApi.GetLastSnapshot()
+		if err == blockchain.ErrSnapshotNotFound {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		err = g.grpcCommandService.ResponseSnapshot(command.FromPeer.PeerId, snapshot)
+		if err != nil {
+			return ErrResponseSnapshot
+		}
+		break
+
+	case "SnapshotResponseProtocol":
+		var snapshot blockchain.Snapshot
+		err := json.Unmarshal(command.Body, &snapshot)
+		if err != nil {
+			return ErrBlockInfoDeliver
+		}
+
+		imported, err := g.snapshotApi.ReceiveSnapshot(command.FromPeer.PeerId, snapshot)
+		if err != nil {
+			return ErrImportSnapshot
+		}
+
+		if imported {
+			return g.synchronizer.Synchronize()
+		}
+		break
+	}
+
+	return nil
+}
+
+// SnapshotEventHandler 는 commit 된 block 으로 snapshot 을 만들고, icode 의 배포와 삭제를 icode state 로 기록한다.
+type SnapshotEventHandler struct {
+	snapshotApi SnapshotApi
+}
+
+func NewSnapshotEventHandler(snapshotApi SnapshotApi) *SnapshotEventHandler {
+	return &SnapshotEventHandler{
+		snapshotApi: snapshotApi,
+	}
+}
+
+func (h *SnapshotEventHandler) HandleBlockCommittedEvent(event blockchain.BlockCommittedEvent) error {
+	if len(event.Seal) == 0 {
+		return ErrEmptyBlockSeal
+	}
+
+	return h.snapshotApi.TakeSnapshot(event.Height)
+}
+
+func (h *SnapshotEventHandler) HandleMetaCreatedEvent(event blockchain.MetaCreatedEvent) error {
+	return h.snapshotApi.SaveICodeState(blockchain.ICodeState{
+		ID:         event.GetID(),
+		GitUrl:     event.GitUrl,
+		CommitHash: event.CommitHash,
+	})
+}
+
+func (h *SnapshotEventHandler) HandleMetaDeletedEvent(event blockchain.MetaDeletedEvent) error {
+	return h.snapshotApi.RemoveICodeState(event.GetID())
+}
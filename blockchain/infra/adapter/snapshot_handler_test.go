@@ -0,0 +1,189 @@
+package adapter_test
+
+import (
+	"testing"
+
+	"github.com/it-chain/engine/blockchain"
+	"github.com/it-chain/engine/blockchain/infra/adapter"
+	"github.com/it-chain/engine/blockchain/test/mock"
+	"github.com/it-chain/engine/common"
+	"github.com/it-chain/midgard"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnapshotGrpcCommandHandler_HandleGrpcCommand(t *testing.T) {
+	snapshot := blockchain.Snapshot{
+		Header: blockchain.SignedHeader{BlockHeader: blockchain.BlockHeader{Height: 10}, Seal: []byte("seal10")},
+	}
+	snapshotBody, _ := common.Serialize(snapshot)
+
+	tests := map[string]struct {
+		input struct {
+			protocol    string
+			body        []byte
+			snapshotErr error
+			imported    bool
+		}
+		responded    bool
+		synchronized bool
+		err          error
+	}{
+		"success: snapshot request": {
+			input: struct {
+				protocol    string
+				body        []byte
+				snapshotErr error
+				imported    bool
+			}{protocol: "SnapshotRequestProtocol"},
+			responded: true,
+			err:       nil,
+		},
+		"success: no snapshot to response": {
+			input: struct {
+				protocol    string
+				body        []byte
+				snapshotErr error
+				imported    bool
+			}{protocol: "SnapshotRequestProtocol", snapshotErr: blockchain.ErrSnapshotNotFound},
+			responded: false,
+			err:       nil,
+		},
+		"success: import snapshot": {
+			input: struct {
+				protocol    string
+				body        []byte
+				snapshotErr error
+				imported    bool
+			}{protocol: "SnapshotResponseProtocol", body: snapshotBody, imported: true},
+			synchronized: true,
+			err:          nil,
+		},
+		"success: ignore old snapshot": {
+			input: struct {
+				protocol    string
+				body        []byte
+				snapshotErr error
+				imported    bool
+			}{protocol: "SnapshotResponseProtocol", body: snapshotBody},
+			synchronized: false,
+			err:          nil,
+		},
+		"fail: invalid snapshot": {
+			input: struct {
+				protocol    string
+				body        []byte
+				snapshotErr error
+				imported    bool
+			}{protocol: "SnapshotResponseProtocol", body: snapshotBody, snapshotErr: blockchain.ErrSnapshotHeaderMismatch},
+			err: adapter.ErrImportSnapshot,
+		},
+		"fail: snapshot body": {
+			input: struct {
+				protocol    string
+				body        []byte
+				snapshotErr error
+				imported    bool
+			}{protocol: "SnapshotResponseProtocol", body: []byte("snapshot")},
+			err: adapter.ErrBlockInfoDeliver,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+
+		// given
+		responded := false
+		synchronized := false
+
+		snapshotApi := mock.SnapshotApi{}
+		snapshotApi.GetLastSnapshotFunc = func() (blockchain.Snapshot, error) {
+			return snapshot, test.input.snapshotErr
+		}
+		snapshotApi.ReceiveSnapshotFunc = func(peerId blockchain.PeerId, received blockchain.Snapshot) (bool, error) {
+			assert.Equal(t, "peer1", peerId.Id)
+			assert.Equal(t, snapshot.Header.Seal, received.Header.Seal)
+			return test.input.imported, test.input.snapshotErr
+		}
+
+		synchronizer := mock.Synchronizer{}
+		synchronizer.SynchronizeFunc = func() error {
+			synchronized = true
+			return nil
+		}
+
+		grpcCommandService := mock.SnapshotGrpcCommandService{}
+		grpcCommandService.ResponseSnapshotFunc = func(peerId blockchain.PeerId, response blockchain.Snapshot) error {
+			assert.Equal(t, "peer1", peerId.Id)
+			responded = true
+			return nil
+		}
+
+		handler := adapter.NewSnapshotGrpcCommandHandler(snapshotApi, synchronizer, grpcCommandService)
+
+		// when
+		err := handler.HandleGrpcCommand(blockchain.GrpcReceiveCommand{
+			CommandModel: midgard.CommandModel{ID: "111"},
+			Body:         test.input.body,
+			Protocol:     test.input.protocol,
+			FromPeer:     blockchain.Peer{PeerId: blockchain.PeerId{Id: "peer1"}},
+		})
+
+		// then
+		assert.Equal(t, test.err, err)
+		assert.Equal(t, test.responded, responded)
+		assert.Equal(t, test.synchronized, synchronized)
+	}
+}
+
+func TestSnapshotEventHandler(t *testing.T) {
+	// given
+	heights := make([]blockchain.BlockHeight, 0)
+	states := make(map[string]blockchain.ICodeState)
+
+	snapshotApi := mock.SnapshotApi{}
+	snapshotApi.TakeSnapshotFunc = func(height blockchain.BlockHeight) error {
+		heights = append(heights, height)
+		return nil
+	}
+	snapshotApi.SaveICodeStateFunc = func(state blockchain.ICodeState) error {
+		states[state.ID] = state
+		return nil
+	}
+	snapshotApi.RemoveICodeStateFunc = func(id string) error {
+		delete(states, id)
+		return nil
+	}
+
+	handler := adapter.NewSnapshotEventHandler(snapshotApi)
+
+	// when
+	err := handler.HandleBlockCommittedEvent(blockchain.BlockCommittedEvent{Seal: []byte("seal10"), Height: 10})
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, []blockchain.BlockHeight{10}, heights)
+
+	// when
+	err = handler.HandleBlockCommittedEvent(blockchain.BlockCommittedEvent{Height: 11})
+
+	// then
+	assert.Equal(t, adapter.ErrEmptyBlockSeal, err)
+
+	// when
+	assert.NoError(t, handler.HandleMetaCreatedEvent(blockchain.MetaCreatedEvent{
+		EventModel: midgard.EventModel{ID: "icode1", Type: "meta.created"},
+		GitUrl:     "git@github.com:it-chain/icode.git",
+		CommitHash: "hash",
+	}))
+	assert.NoError(t, handler.HandleMetaCreatedEvent(blockchain.MetaCreatedEvent{
+		EventModel: midgard.EventModel{ID: "icode2", Type: "meta.created"},
+	}))
+	assert.NoError(t, handler.HandleMetaDeletedEvent(blockchain.MetaDeletedEvent{
+		EventModel: midgard.EventModel{ID: "icode2", Type: "meta.deleted"},
+	}))
+
+	// then
+	assert.Equal(t, map[string]blockchain.ICodeState{
+		"icode1": {ID: "icode1", GitUrl: "git@github.com:it-chain/icode.git", CommitHash: "hash"},
+	}, states)
+}
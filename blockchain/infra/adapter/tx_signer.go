@@ -0,0 +1,27 @@
+package adapter
+
+import (
+	"github.com/it-chain/engine/blockchain"
+	"github.com/it-chain/engine/txpool"
+)
+
+// TxSigner 는 txpool 의 transaction 을 block 에 담기는 DefaultTransaction 의 내용으로 서명한다.
+// 다른 node 는 같은 내용을 제출자의 public key 로 검증한다.
+type TxSigner struct {
+	signer blockchain.Signer
+}
+
+func NewTxSigner(signer blockchain.Signer) *TxSigner {
+	return &TxSigner{
+		signer: signer,
+	}
+}
+
+func (s *TxSigner) SignTx(tx txpool.Transaction) ([]byte, error) {
+	content, err := convertTx(tx).GetContent()
+	if err != nil {
+		return nil, err
+	}
+
+	return s.signer.Sign(content)
+}
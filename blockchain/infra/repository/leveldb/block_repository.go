@@ -0,0 +1,532 @@
+package leveldb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+
+	"github.com/it-chain/engine/blockchain"
+	"github.com/it-chain/leveldb-wrapper"
+)
+
+// key prefix of each index stored in leveldb
+//
+// | key                      | value            |
+// | ------------------------ | ---------------- |
+// | block_{height}           | serialized block |
+// | seal_{hex(seal)}         | height           |
+// | txid_{transaction id}    | height           |
+// | last_block_height        | height           |
+// | pruned_height            | height           |
+// | snapshot_height          | height           |
+// | prune_stats              | json prune stats |
+// | final_{hex(seal)}        | 1                |
+//
+// height 는 big endian 으로 저장하므로 block_ prefix 로 순회하면 height 순서대로 block 을 얻는다.
+// pruned_height 이하의 block_ 에는 transaction 을 지운 header 만 남아 있고, txid_ index 는 ErrBlockPruned 를 반환하기 위해 남겨둔다.
+// final_ 은 합의가 commit 한 block 의 기록으로, block 이 commit 되기 전에 기록되고 block 을 지워도 남는다.
+var (
+	blockKeyPrefix     = []byte("block_")
+	sealKeyPrefix      = []byte("seal_")
+	txIDKeyPrefix      = []byte("txid_")
+	lastBlockHeightKey = []byte("last_block_height")
+	prunedHeightKey    = []byte("pruned_height")
+	pruneStatsKey      = []byte("prune_stats")
+	snapshotHeightKey  = []byte("snapshot_height")
+	finalKeyPrefix     = []byte("final_")
+)
+
+// 한 번의 batch 로 transaction 을 지우는 최대 block 수
+const pruneBatchSize = 1000
+
+// BlockRepository 는 commit 된 block 을 leveldb 에 저장하고 height, seal, transaction ID 로 조회한다.
+type BlockRepository struct {
+	mux         *sync.RWMutex
+	leveldb     *leveldbwrapper.DB
+	pruneConfig blockchain.PruneConfig
+}
+
+// pruneStats 는 지금까지 지운 block 과 transaction 의 수, 줄어든 byte 이다.
+type pruneStats struct {
+	Blocks uint64
+	Txs    uint64
+	Bytes  uint64
+}
+
+func NewBlockRepository(path string) *BlockRepository {
+	db := leveldbwrapper.CreateNewDB(path)
+	db.Open()
+
+	return &BlockRepository{
+		mux:     &sync.RWMutex{},
+		leveldb: db,
+	}
+}
+
+// AddBlock 은 block 과 seal, transaction ID index 를 한 번의 batch 로 저장한다.
+// 이미 block 이 있다면 마지막 block 의 다음 height 이고 prev seal 이 마지막 block 의 seal 이어야 한다.
+func (r *BlockRepository) AddBlock(block blockchain.Block) error {
+	if block == nil {
+		return ErrNilBlock
+	}
+
+	if len(block.GetSeal()) == 0 {
+		return ErrEmptySeal
+	}
+
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	lastBlock, err := r.getLastBlock()
+	if err != nil && err != blockchain.ErrBlockNotFound {
+		return err
+	}
+
+	if lastBlock != nil {
+		if block.GetHeight() != lastBlock.GetHeight()+1 {
+			return ErrInvalidHeight
+		}
+
+		if !bytes.Equal(block.GetPrevSeal(), lastBlock.GetSeal()) {
+			return ErrInvalidPrevSeal
+		}
+	}
+
+	return r.writeBlock(block, map[string][]byte{})
+}
+
+// AddSnapshotBlock 은 snapshot 의 block 을 중간 block 없이 마지막 block 으로 저장한다.
+// snapshot 으로 시작하는 node 는 snapshot 이전의 block 을 갖지 않으므로 prev seal 은 검사하지 않고,
+// 마지막 block 보다 높은 height 이기만 하면 된다. chain 을 검증할 수 있도록 snapshot block 의 height 를 기록한다.
+func (r *BlockRepository) AddSnapshotBlock(block blockchain.Block) error {
+	if block == nil {
+		return ErrNilBlock
+	}
+
+	if len(block.GetSeal()) == 0 {
+		return ErrEmptySeal
+	}
+
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	lastBlock, err := r.getLastBlock()
+	if err != nil && err != blockchain.ErrBlockNotFound {
+		return err
+	}
+
+	if lastBlock != nil && block.GetHeight() <= lastBlock.GetHeight() {
+		return ErrInvalidHeight
+	}
+
+	return r.writeBlock(block, map[string][]byte{
+		string(snapshotHeightKey): encodeHeight(block.GetHeight()),
+	})
+}
+
+// GetSnapshotHeight 는 마지막으로 저장한 snapshot block 의 height 를 반환한다. snapshot 으로 시작하지 않았으면 0 이다.
+func (r *BlockRepository) GetSnapshotHeight() (blockchain.BlockHeight, error) {
+	r.mux.RLock()
+	defer r.mux.RUnlock()
+
+	height, err := r.leveldb.Get(snapshotHeightKey)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(height) == 0 {
+		return 0, nil
+	}
+
+	return decodeHeight(height), nil
+}
+
+// SaveFinalSeal 은 합의가 commit 한 block 의 seal 을 기록한다.
+func (r *BlockRepository) SaveFinalSeal(seal []byte) error {
+	if len(seal) == 0 {
+		return ErrEmptySeal
+	}
+
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	return r.leveldb.Put(finalKey(seal), []byte{1}, true)
+}
+
+// IsFinalSeal 은 seal 의 block 을 합의가 commit 했는지 확인한다.
+func (r *BlockRepository) IsFinalSeal(seal []byte) (bool, error) {
+	r.mux.RLock()
+	defer r.mux.RUnlock()
+
+	value, err := r.leveldb.Get(finalKey(seal))
+	if err != nil {
+		return false, err
+	}
+
+	return len(value) != 0, nil
+}
+
+// RemoveBlock 은 마지막 block 과 그 index 들을 한 번의 batch 로 지우고 last block height 를 이전 block 으로 되돌린다.
+// 마지막 block 이 아니면 ErrNotLastBlock 을 반환한다.
+func (r *BlockRepository) RemoveBlock(block blockchain.Block) error {
+	if block == nil {
+		return ErrNilBlock
+	}
+
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	lastBlock, err := r.getLastBlock()
+	if err != nil {
+		return err
+	}
+
+	if !bytes.Equal(block.GetSeal(), lastBlock.GetSeal()) {
+		return ErrNotLastBlock
+	}
+
+	// value 가 nil 인 key 는 batch 에서 삭제된다.
+	batch := map[string][]byte{
+		string(blockKey(lastBlock.GetHeight())): nil,
+		string(sealKey(lastBlock.GetSeal())):    nil,
+		string(lastBlockHeightKey):              nil,
+	}
+
+	if lastBlock.GetHeight() > 0 {
+		batch[string(lastBlockHeightKey)] = encodeHeight(lastBlock.GetHeight() - 1)
+	}
+
+	for _, tx := range lastBlock.GetTxList() {
+		batch[string(txIDKey(tx.GetID()))] = nil
+	}
+
+	return r.leveldb.WriteBatch(batch, true)
+}
+
+// SetPruneConfig 는 block 보관 방식을 설정한다. pruned mode 라면 AddBlock 할 때마다 오래된 block 의 transaction 을 지운다.
+func (r *BlockRepository) SetPruneConfig(config blockchain.PruneConfig) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	r.pruneConfig = config
+}
+
+// Prune 은 pruned mode 에서 마지막 KeepBlocks 개를 제외한 block 의 transaction 을 지운다.
+// archive mode 에서 pruned mode 로 바꾸고 시작했을 때 밀린 block 을 정리하기 위해 사용한다.
+func (r *BlockRepository) Prune() error {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	lastBlock, err := r.getLastBlock()
+	if err == blockchain.ErrBlockNotFound {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	pruneHeight := r.pruneConfig.PruneHeight(lastBlock.GetHeight())
+
+	for {
+		prunedHeight, err := r.getPrunedHeight()
+		if err != nil {
+			return err
+		}
+
+		if prunedHeight >= pruneHeight {
+			return nil
+		}
+
+		to := pruneHeight
+		if to-prunedHeight > pruneBatchSize {
+			to = prunedHeight + pruneBatchSize
+		}
+
+		batch := make(map[string][]byte)
+		if err := r.addPruneBatch(batch, to); err != nil {
+			return err
+		}
+
+		if err := r.leveldb.WriteBatch(batch, true); err != nil {
+			return err
+		}
+	}
+}
+
+// RetentionStats 는 block 보관 방식과 지금까지 지운 block 의 통계를 반환한다.
+func (r *BlockRepository) RetentionStats() (blockchain.RetentionStats, error) {
+	r.mux.RLock()
+	defer r.mux.RUnlock()
+
+	prunedHeight, stats, err := r.getPruneState()
+	if err != nil {
+		return blockchain.RetentionStats{}, err
+	}
+
+	retention := blockchain.RetentionStats{
+		Mode:         r.pruneConfig.Mode,
+		KeepBlocks:   r.pruneConfig.KeepBlocks,
+		PrunedHeight: prunedHeight,
+		PrunedBlocks: stats.Blocks,
+		PrunedTxs:    stats.Txs,
+		PrunedBytes:  stats.Bytes,
+	}
+
+	if retention.Mode == "" {
+		retention.Mode = blockchain.ArchiveMode
+	}
+
+	lastBlock, err := r.getLastBlock()
+	if err != nil && err != blockchain.ErrBlockNotFound {
+		return blockchain.RetentionStats{}, err
+	}
+
+	if lastBlock != nil {
+		retention.LastHeight = lastBlock.GetHeight()
+	}
+
+	return retention, nil
+}
+
+// GetHeaderByHeight 는 transaction 이 지워진 block 도 header 를 반환한다.
+func (r *BlockRepository) GetHeaderByHeight(height blockchain.BlockHeight) (blockchain.SignedHeader, error) {
+	r.mux.RLock()
+	defer r.mux.RUnlock()
+
+	block, err := r.readBlock(height)
+	if err == blockchain.ErrBlockNotFound {
+		return blockchain.SignedHeader{}, blockchain.ErrHeaderNotFound
+	}
+
+	if err != nil {
+		return blockchain.SignedHeader{}, err
+	}
+
+	return blockchain.NewSignedHeader(block), nil
+}
+
+func (r *BlockRepository) GetBlockByHeight(blockHeight uint64) (blockchain.Block, error) {
+	r.mux.RLock()
+	defer r.mux.RUnlock()
+
+	return r.getBlockByHeight(blockHeight)
+}
+
+func (r *BlockRepository) GetBlockBySeal(seal []byte) (blockchain.Block, error) {
+	r.mux.RLock()
+	defer r.mux.RUnlock()
+
+	return r.getBlockByIndex(sealKey(seal))
+}
+
+func (r *BlockRepository) GetBlockByTxID(txid string) (blockchain.Block, error) {
+	r.mux.RLock()
+	defer r.mux.RUnlock()
+
+	return r.getBlockByIndex(txIDKey(txid))
+}
+
+func (r *BlockRepository) GetLastBlock() (blockchain.Block, error) {
+	r.mux.RLock()
+	defer r.mux.RUnlock()
+
+	return r.getLastBlock()
+}
+
+func (r *BlockRepository) Close() {
+	r.leveldb.Close()
+}
+
+// writeBlock 은 block 과 seal, transaction ID index, last block height 를 batch 에 더해서 한 번에 저장한다.
+func (r *BlockRepository) writeBlock(block blockchain.Block, batch map[string][]byte) error {
+	serializedBlock, err := block.Serialize()
+	if err != nil {
+		return err
+	}
+
+	height := encodeHeight(block.GetHeight())
+
+	batch[string(blockKey(block.GetHeight()))] = serializedBlock
+	batch[string(sealKey(block.GetSeal()))] = height
+	batch[string(lastBlockHeightKey)] = height
+
+	for _, tx := range block.GetTxList() {
+		batch[string(txIDKey(tx.GetID()))] = height
+	}
+
+	if r.pruneConfig.Mode == blockchain.PrunedMode {
+		if err := r.addPruneBatch(batch, r.pruneConfig.PruneHeight(block.GetHeight())); err != nil {
+			return err
+		}
+	}
+
+	return r.leveldb.WriteBatch(batch, true)
+}
+
+// addPruneBatch 는 마지막으로 지운 height 다음부터 to 까지의 block 을 header 만 남긴 block 으로 바꾸는 내용을 batch 에 추가한다.
+// snapshot 으로 시작한 node 처럼 없는 block 은 건너뛴다.
+func (r *BlockRepository) addPruneBatch(batch map[string][]byte, to blockchain.BlockHeight) error {
+	prunedHeight, stats, err := r.getPruneState()
+	if err != nil {
+		return err
+	}
+
+	if to <= prunedHeight {
+		return nil
+	}
+
+	for height := prunedHeight + 1; height <= to; height++ {
+		serializedBlock, err := r.leveldb.Get(blockKey(height))
+		if err != nil {
+			return err
+		}
+
+		if len(serializedBlock) == 0 {
+			continue
+		}
+
+		block := &blockchain.DefaultBlock{}
+		if err := block.Deserialize(serializedBlock); err != nil {
+			return err
+		}
+
+		serializedHeader, err := blockchain.NewBlockFromHeader(blockchain.NewSignedHeader(block)).Serialize()
+		if err != nil {
+			return err
+		}
+
+		batch[string(blockKey(height))] = serializedHeader
+
+		stats.Blocks++
+		stats.Txs += uint64(len(block.GetTxList()))
+		if len(serializedHeader) < len(serializedBlock) {
+			stats.Bytes += uint64(len(serializedBlock) - len(serializedHeader))
+		}
+	}
+
+	serializedStats, err := json.Marshal(stats)
+	if err != nil {
+		return err
+	}
+
+	batch[string(prunedHeightKey)] = encodeHeight(to)
+	batch[string(pruneStatsKey)] = serializedStats
+
+	return nil
+}
+
+// getPrunedHeight 는 transaction 을 지운 가장 높은 height 를 반환한다. 지운 block 이 없으면 0 이다.
+func (r *BlockRepository) getPrunedHeight() (blockchain.BlockHeight, error) {
+	height, err := r.leveldb.Get(prunedHeightKey)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(height) == 0 {
+		return 0, nil
+	}
+
+	return decodeHeight(height), nil
+}
+
+// getPruneState 는 transaction 을 지운 가장 높은 height 와 통계를 반환한다.
+func (r *BlockRepository) getPruneState() (blockchain.BlockHeight, pruneStats, error) {
+	stats := pruneStats{}
+
+	height, err := r.getPrunedHeight()
+	if err != nil || height == 0 {
+		return height, stats, err
+	}
+
+	serializedStats, err := r.leveldb.Get(pruneStatsKey)
+	if err != nil {
+		return 0, stats, err
+	}
+
+	if len(serializedStats) != 0 {
+		if err := json.Unmarshal(serializedStats, &stats); err != nil {
+			return 0, stats, err
+		}
+	}
+
+	return height, stats, nil
+}
+
+func (r *BlockRepository) getLastBlock() (blockchain.Block, error) {
+	return r.getBlockByIndex(lastBlockHeightKey)
+}
+
+// getBlockByIndex 는 index key 로 height 를 찾은 다음 해당 height 의 block 을 반환한다.
+func (r *BlockRepository) getBlockByIndex(key []byte) (blockchain.Block, error) {
+	height, err := r.leveldb.Get(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(height) == 0 {
+		return nil, blockchain.ErrBlockNotFound
+	}
+
+	return r.getBlockByHeight(decodeHeight(height))
+}
+
+// getBlockByHeight 는 transaction 이 지워진 block 이면 ErrBlockPruned 를 반환한다.
+func (r *BlockRepository) getBlockByHeight(height uint64) (blockchain.Block, error) {
+	prunedHeight, err := r.getPrunedHeight()
+	if err != nil {
+		return nil, err
+	}
+
+	if height != 0 && height <= prunedHeight {
+		return nil, blockchain.ErrBlockPruned
+	}
+
+	return r.readBlock(height)
+}
+
+func (r *BlockRepository) readBlock(height uint64) (blockchain.Block, error) {
+	serializedBlock, err := r.leveldb.Get(blockKey(height))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(serializedBlock) == 0 {
+		return nil, blockchain.ErrBlockNotFound
+	}
+
+	block := &blockchain.DefaultBlock{}
+	if err := block.Deserialize(serializedBlock); err != nil {
+		return nil, err
+	}
+
+	return block, nil
+}
+
+func blockKey(height uint64) []byte {
+	return append(append([]byte{}, blockKeyPrefix...), encodeHeight(height)...)
+}
+
+func sealKey(seal []byte) []byte {
+	return append(append([]byte{}, sealKeyPrefix...), hex.EncodeToString(seal)...)
+}
+
+func finalKey(seal []byte) []byte {
+	return append(append([]byte{}, finalKeyPrefix...), hex.EncodeToString(seal)...)
+}
+
+func txIDKey(txid string) []byte {
+	return append(append([]byte{}, txIDKeyPrefix...), txid...)
+}
+
+func encodeHeight(height uint64) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, height)
+	return b
+}
+
+func decodeHeight(b []byte) uint64 {
+	return binary.BigEndian.Uint64(b)
+}
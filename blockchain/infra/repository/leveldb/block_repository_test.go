@@ -0,0 +1,422 @@
+package leveldb_test
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/it-chain/engine/blockchain"
+	"github.com/it-chain/engine/blockchain/infra/repository/leveldb"
+	"github.com/it-chain/engine/blockchain/test/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlockRepository_AddBlock(t *testing.T) {
+	tests := map[string]struct {
+		input blockchain.Block
+		err   error
+	}{
+		"success: next block": {
+			input: &blockchain.DefaultBlock{
+				Seal:     []byte("seal1"),
+				PrevSeal: []byte("seal0"),
+				Height:   1,
+			},
+			err: nil,
+		},
+		"fail: nil block": {
+			input: nil,
+			err:   leveldb.ErrNilBlock,
+		},
+		"fail: empty seal": {
+			input: &blockchain.DefaultBlock{
+				PrevSeal: []byte("seal0"),
+				Height:   1,
+			},
+			err: leveldb.ErrEmptySeal,
+		},
+		"fail: height gap": {
+			input: &blockchain.DefaultBlock{
+				Seal:     []byte("seal2"),
+				PrevSeal: []byte("seal0"),
+				Height:   2,
+			},
+			err: leveldb.ErrInvalidHeight,
+		},
+		"fail: prev seal mismatch": {
+			input: &blockchain.DefaultBlock{
+				Seal:     []byte("seal1"),
+				PrevSeal: []byte("other"),
+				Height:   1,
+			},
+			err: leveldb.ErrInvalidPrevSeal,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+
+		dbPath := "./.test"
+		br := leveldb.NewBlockRepository(dbPath)
+
+		err := br.AddBlock(&blockchain.DefaultBlock{
+			Seal:     []byte("seal0"),
+			PrevSeal: []byte(""),
+			Height:   0,
+		})
+		assert.NoError(t, err)
+
+		err = br.AddBlock(test.input)
+		assert.Equal(t, test.err, err)
+
+		br.Close()
+		os.RemoveAll(dbPath)
+	}
+}
+
+func TestBlockRepository_GetBlock(t *testing.T) {
+	// given
+	dbPath := "./.test"
+	br := leveldb.NewBlockRepository(dbPath)
+
+	defer func() {
+		br.Close()
+		os.RemoveAll(dbPath)
+	}()
+
+	_, err := br.GetLastBlock()
+	assert.Equal(t, blockchain.ErrBlockNotFound, err)
+
+	block0 := &blockchain.DefaultBlock{
+		Seal:      []byte("seal0"),
+		PrevSeal:  []byte(""),
+		Height:    0,
+		Timestamp: time.Now().Round(0),
+	}
+	block1 := &blockchain.DefaultBlock{
+		Seal:     []byte("seal1"),
+		PrevSeal: []byte("seal0"),
+		Height:   1,
+		TxList: []*blockchain.DefaultTransaction{
+			{ID: "tx1"},
+			{ID: "tx2"},
+		},
+		Timestamp: time.Now().Round(0),
+	}
+
+	// when
+	assert.NoError(t, br.AddBlock(block0))
+	assert.NoError(t, br.AddBlock(block1))
+
+	// then
+	lastBlock, err := br.GetLastBlock()
+	assert.NoError(t, err)
+	assert.Equal(t, block1.GetSeal(), lastBlock.GetSeal())
+
+	block, err := br.GetBlockByHeight(0)
+	assert.NoError(t, err)
+	assert.Equal(t, block0.GetSeal(), block.GetSeal())
+
+	block, err = br.GetBlockBySeal([]byte("seal1"))
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), block.GetHeight())
+
+	block, err = br.GetBlockByTxID("tx2")
+	assert.NoError(t, err)
+	assert.Equal(t, block1.GetSeal(), block.GetSeal())
+	assert.Equal(t, 2, len(block.GetTxList()))
+
+	_, err = br.GetBlockByHeight(2)
+	assert.Equal(t, blockchain.ErrBlockNotFound, err)
+
+	_, err = br.GetBlockBySeal([]byte("unknown"))
+	assert.Equal(t, blockchain.ErrBlockNotFound, err)
+
+	_, err = br.GetBlockByTxID("unknown")
+	assert.Equal(t, blockchain.ErrBlockNotFound, err)
+}
+
+func TestBlockRepository_Reopen(t *testing.T) {
+	// given
+	dbPath := "./.test"
+	defer os.RemoveAll(dbPath)
+
+	br := leveldb.NewBlockRepository(dbPath)
+	assert.NoError(t, br.AddBlock(&blockchain.DefaultBlock{
+		Seal:     []byte("seal0"),
+		PrevSeal: []byte(""),
+		Height:   0,
+	}))
+	br.Close()
+
+	// when
+	br = leveldb.NewBlockRepository(dbPath)
+	defer br.Close()
+
+	// then
+	lastBlock, err := br.GetLastBlock()
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("seal0"), lastBlock.GetSeal())
+}
+
+func TestBlockRepository_RemoveBlock(t *testing.T) {
+	// given
+	dbPath := "./.test"
+	br := leveldb.NewBlockRepository(dbPath)
+
+	defer func() {
+		br.Close()
+		os.RemoveAll(dbPath)
+	}()
+
+	block0 := &blockchain.DefaultBlock{Seal: []byte("seal0"), PrevSeal: []byte(""), Height: 0}
+	block1 := &blockchain.DefaultBlock{
+		Seal:     []byte("seal1"),
+		PrevSeal: []byte("seal0"),
+		Height:   1,
+		TxList: []*blockchain.DefaultTransaction{
+			{ID: "tx1"},
+		},
+	}
+	assert.NoError(t, br.AddBlock(block0))
+	assert.NoError(t, br.AddBlock(block1))
+
+	// when
+	err := br.RemoveBlock(block0)
+
+	// then
+	assert.Equal(t, leveldb.ErrNotLastBlock, err)
+
+	// when
+	err = br.RemoveBlock(block1)
+
+	// then
+	assert.NoError(t, err)
+
+	lastBlock, err := br.GetLastBlock()
+	assert.NoError(t, err)
+	assert.Equal(t, block0.GetSeal(), lastBlock.GetSeal())
+
+	_, err = br.GetBlockByHeight(1)
+	assert.Equal(t, blockchain.ErrBlockNotFound, err)
+
+	_, err = br.GetBlockBySeal([]byte("seal1"))
+	assert.Equal(t, blockchain.ErrBlockNotFound, err)
+
+	_, err = br.GetBlockByTxID("tx1")
+	assert.Equal(t, blockchain.ErrBlockNotFound, err)
+
+	// when: fork 된 block 을 같은 height 에 다시 저장
+	err = br.AddBlock(&blockchain.DefaultBlock{Seal: []byte("fork1"), PrevSeal: []byte("seal0"), Height: 1})
+
+	// then
+	assert.NoError(t, err)
+
+	// when: genesis 까지 되돌림
+	assert.NoError(t, br.RemoveBlock(&blockchain.DefaultBlock{Seal: []byte("fork1")}))
+	assert.NoError(t, br.RemoveBlock(block0))
+
+	// then
+	_, err = br.GetLastBlock()
+	assert.Equal(t, blockchain.ErrBlockNotFound, err)
+}
+
+func TestBlockRepository_FinalSeal(t *testing.T) {
+	// given
+	dbPath := "./.test"
+	defer os.RemoveAll(dbPath)
+
+	br := leveldb.NewBlockRepository(dbPath)
+
+	// when
+	assert.NoError(t, br.SaveFinalSeal([]byte("seal1")))
+	br.Close()
+
+	br = leveldb.NewBlockRepository(dbPath)
+	defer br.Close()
+
+	// then: commit 되기 전에 기록한 finality 가 재시작 후에도 남아 있다.
+	final, err := br.IsFinalSeal([]byte("seal1"))
+	assert.NoError(t, err)
+	assert.True(t, final)
+
+	final, err = br.IsFinalSeal([]byte("seal2"))
+	assert.NoError(t, err)
+	assert.False(t, final)
+
+	assert.Equal(t, leveldb.ErrEmptySeal, br.SaveFinalSeal(nil))
+}
+
+func TestBlockRepository_AddSnapshotBlock(t *testing.T) {
+	tests := map[string]struct {
+		input blockchain.Block
+		err   error
+	}{
+		"success: height gap": {
+			input: &blockchain.DefaultBlock{
+				Seal:     []byte("seal10"),
+				PrevSeal: []byte("seal9"),
+				Height:   10,
+				TxSeal:   [][]byte{[]byte("root10")},
+			},
+			err: nil,
+		},
+		"fail: nil block": {
+			input: nil,
+			err:   leveldb.ErrNilBlock,
+		},
+		"fail: empty seal": {
+			input: &blockchain.DefaultBlock{
+				PrevSeal: []byte("seal9"),
+				Height:   10,
+			},
+			err: leveldb.ErrEmptySeal,
+		},
+		"fail: not higher than last block": {
+			input: &blockchain.DefaultBlock{
+				Seal:     []byte("other0"),
+				PrevSeal: []byte(""),
+				Height:   0,
+			},
+			err: leveldb.ErrInvalidHeight,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+
+		// given
+		dbPath := "./.test"
+		br := leveldb.NewBlockRepository(dbPath)
+
+		assert.NoError(t, br.AddBlock(&blockchain.DefaultBlock{Seal: []byte("seal0"), PrevSeal: []byte(""), Height: 0}))
+
+		// when
+		err := br.AddSnapshotBlock(test.input)
+
+		// then
+		assert.Equal(t, test.err, err)
+
+		snapshotHeight, heightErr := br.GetSnapshotHeight()
+		assert.NoError(t, heightErr)
+
+		if err == nil {
+			lastBlock, err := br.GetLastBlock()
+			assert.NoError(t, err)
+			assert.Equal(t, test.input.GetSeal(), lastBlock.GetSeal())
+			assert.Equal(t, test.input.GetHeight(), snapshotHeight)
+
+			// snapshot block 다음 block 은 AddBlock 으로 이어서 저장할 수 있다.
+			assert.NoError(t, br.AddBlock(&blockchain.DefaultBlock{Seal: []byte("seal11"), PrevSeal: []byte("seal10"), Height: 11}))
+		} else {
+			assert.Equal(t, blockchain.BlockHeight(0), snapshotHeight)
+		}
+
+		br.Close()
+		os.RemoveAll(dbPath)
+	}
+}
+
+func TestBlockRepository_PrunedMode(t *testing.T) {
+	// given
+	dbPath := "./.test"
+	br := leveldb.NewBlockRepository(dbPath)
+
+	defer func() {
+		br.Close()
+		os.RemoveAll(dbPath)
+	}()
+
+	br.SetPruneConfig(blockchain.PruneConfig{Mode: blockchain.PrunedMode, KeepBlocks: 2})
+
+	// when
+	for height := uint64(0); height <= 5; height++ {
+		assert.NoError(t, br.AddBlock(mock.NewStoredBlock(height)))
+	}
+
+	// then: 마지막 2 개의 block 과 genesis 만 transaction 을 가진다.
+	for height := uint64(1); height <= 3; height++ {
+		_, err := br.GetBlockByHeight(height)
+		assert.Equal(t, blockchain.ErrBlockPruned, err)
+
+		_, err = br.GetBlockBySeal([]byte(fmt.Sprintf("seal%d", height)))
+		assert.Equal(t, blockchain.ErrBlockPruned, err)
+
+		_, err = br.GetBlockByTxID(fmt.Sprintf("tx%d", height))
+		assert.Equal(t, blockchain.ErrBlockPruned, err)
+
+		header, err := br.GetHeaderByHeight(height)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte(fmt.Sprintf("seal%d", height)), header.Seal)
+		assert.Equal(t, []byte(fmt.Sprintf("root%d", height)), header.TxRoot)
+	}
+
+	for _, height := range []uint64{0, 4, 5} {
+		block, err := br.GetBlockByHeight(height)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(block.GetTxList()))
+	}
+
+	_, err := br.GetBlockByTxID("unknown")
+	assert.Equal(t, blockchain.ErrBlockNotFound, err)
+
+	_, err = br.GetHeaderByHeight(6)
+	assert.Equal(t, blockchain.ErrHeaderNotFound, err)
+
+	stats, err := br.RetentionStats()
+	assert.NoError(t, err)
+	assert.Equal(t, blockchain.PrunedMode, stats.Mode)
+	assert.Equal(t, uint64(5), stats.LastHeight)
+	assert.Equal(t, uint64(3), stats.PrunedHeight)
+	assert.Equal(t, uint64(3), stats.PrunedBlocks)
+	assert.Equal(t, uint64(3), stats.PrunedTxs)
+	assert.True(t, stats.PrunedBytes > 0)
+}
+
+func TestBlockRepository_Prune(t *testing.T) {
+	// given: archive mode 로 쌓인 chain
+	dbPath := "./.test"
+	br := leveldb.NewBlockRepository(dbPath)
+
+	defer func() {
+		br.Close()
+		os.RemoveAll(dbPath)
+	}()
+
+	for height := uint64(0); height <= 5; height++ {
+		assert.NoError(t, br.AddBlock(mock.NewStoredBlock(height)))
+	}
+
+	stats, err := br.RetentionStats()
+	assert.NoError(t, err)
+	assert.Equal(t, blockchain.ArchiveMode, stats.Mode)
+	assert.Equal(t, uint64(0), stats.PrunedHeight)
+
+	// when
+	br.SetPruneConfig(blockchain.PruneConfig{Mode: blockchain.PrunedMode, KeepBlocks: 1})
+	err = br.Prune()
+
+	// then
+	assert.NoError(t, err)
+
+	_, err = br.GetBlockByTxID("tx4")
+	assert.Equal(t, blockchain.ErrBlockPruned, err)
+
+	block, err := br.GetBlockByTxID("tx5")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(5), block.GetHeight())
+
+	stats, err = br.RetentionStats()
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(4), stats.PrunedHeight)
+	assert.Equal(t, uint64(4), stats.PrunedTxs)
+
+	// when: 다시 열어도 pruned 상태가 유지된다.
+	br.Close()
+	br = leveldb.NewBlockRepository(dbPath)
+
+	// then
+	_, err = br.GetBlockByHeight(4)
+	assert.Equal(t, blockchain.ErrBlockPruned, err)
+}
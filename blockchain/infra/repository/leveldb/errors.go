@@ -0,0 +1,9 @@
+package leveldb
+
+import "errors"
+
+var ErrNilBlock = errors.New("block is nil")
+var ErrEmptySeal = errors.New("block seal is empty")
+var ErrInvalidHeight = errors.New("block height is not the next height of last block")
+var ErrInvalidPrevSeal = errors.New("block prev seal does not match last block seal")
+var ErrNotLastBlock = errors.New("block is not the last block")
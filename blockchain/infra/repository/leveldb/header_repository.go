@@ -0,0 +1,127 @@
+package leveldb
+
+import (
+	"bytes"
+	"encoding/json"
+	"sync"
+
+	"github.com/it-chain/engine/blockchain"
+	"github.com/it-chain/leveldb-wrapper"
+)
+
+// key prefix of each header index stored in leveldb
+//
+// | key                | value       |
+// | ------------------ | ----------- |
+// | header_{height}    | json header |
+// | last_header_height | height      |
+var (
+	headerKeyPrefix     = []byte("header_")
+	lastHeaderHeightKey = []byte("last_header_height")
+)
+
+// HeaderRepository 는 light node 가 검증한 signed header 를 height 순서대로 leveldb 에 저장한다.
+type HeaderRepository struct {
+	mux     *sync.RWMutex
+	leveldb *leveldbwrapper.DB
+}
+
+func NewHeaderRepository(path string) *HeaderRepository {
+	db := leveldbwrapper.CreateNewDB(path)
+	db.Open()
+
+	return &HeaderRepository{
+		mux:     &sync.RWMutex{},
+		leveldb: db,
+	}
+}
+
+// AddHeader 는 마지막 header 의 다음 header 만 저장한다. BlockRepository.AddBlock 과 같은 조건이다.
+func (r *HeaderRepository) AddHeader(header blockchain.SignedHeader) error {
+	if len(header.Seal) == 0 {
+		return ErrEmptySeal
+	}
+
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	lastHeader, err := r.getLastHeader()
+	if err != nil && err != blockchain.ErrHeaderNotFound {
+		return err
+	}
+
+	if err == nil {
+		if header.Height != lastHeader.Height+1 {
+			return ErrInvalidHeight
+		}
+
+		if !bytes.Equal(header.PrevSeal, lastHeader.Seal) {
+			return ErrInvalidPrevSeal
+		}
+	}
+
+	serialized, err := json.Marshal(header)
+	if err != nil {
+		return err
+	}
+
+	batch := map[string][]byte{
+		string(headerKey(header.Height)): serialized,
+		string(lastHeaderHeightKey):      encodeHeight(header.Height),
+	}
+
+	return r.leveldb.WriteBatch(batch, true)
+}
+
+func (r *HeaderRepository) GetHeaderByHeight(height blockchain.BlockHeight) (blockchain.SignedHeader, error) {
+	r.mux.RLock()
+	defer r.mux.RUnlock()
+
+	return r.getHeaderByHeight(height)
+}
+
+func (r *HeaderRepository) GetLastHeader() (blockchain.SignedHeader, error) {
+	r.mux.RLock()
+	defer r.mux.RUnlock()
+
+	return r.getLastHeader()
+}
+
+func (r *HeaderRepository) Close() {
+	r.leveldb.Close()
+}
+
+func (r *HeaderRepository) getHeaderByHeight(height blockchain.BlockHeight) (blockchain.SignedHeader, error) {
+	serialized, err := r.leveldb.Get(headerKey(height))
+	if err != nil {
+		return blockchain.SignedHeader{}, err
+	}
+
+	if len(serialized) == 0 {
+		return blockchain.SignedHeader{}, blockchain.ErrHeaderNotFound
+	}
+
+	header := blockchain.SignedHeader{}
+	if err := json.Unmarshal(serialized, &header); err != nil {
+		return blockchain.SignedHeader{}, err
+	}
+
+	return header, nil
+}
+
+func (r *HeaderRepository) getLastHeader() (blockchain.SignedHeader, error) {
+	height, err := r.leveldb.Get(lastHeaderHeightKey)
+	if err != nil {
+		return blockchain.SignedHeader{}, err
+	}
+
+	if len(height) == 0 {
+		return blockchain.SignedHeader{}, blockchain.ErrHeaderNotFound
+	}
+
+	return r.getHeaderByHeight(decodeHeight(height))
+}
+
+func headerKey(height blockchain.BlockHeight) []byte {
+	return append(append([]byte{}, headerKeyPrefix...), encodeHeight(height)...)
+}
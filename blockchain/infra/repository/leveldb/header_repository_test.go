@@ -0,0 +1,63 @@
+package leveldb_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/it-chain/engine/blockchain"
+	"github.com/it-chain/engine/blockchain/infra/repository/leveldb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHeaderRepository_AddHeader(t *testing.T) {
+	// given
+	dbPath := "./.test_header"
+	hr := leveldb.NewHeaderRepository(dbPath)
+	defer func() {
+		hr.Close()
+		os.RemoveAll(dbPath)
+	}()
+
+	_, err := hr.GetLastHeader()
+	assert.Equal(t, blockchain.ErrHeaderNotFound, err)
+
+	genesis := blockchain.SignedHeader{
+		BlockHeader: blockchain.BlockHeader{Height: 0, PrevSeal: []byte("genesis"), TxRoot: []byte("root0")},
+		Seal:        []byte("seal0"),
+	}
+	next := blockchain.SignedHeader{
+		BlockHeader: blockchain.BlockHeader{Height: 1, PrevSeal: []byte("seal0"), TxRoot: []byte("root1")},
+		Seal:        []byte("seal1"),
+		Signature:   []byte("signature"),
+	}
+
+	// when
+	err = hr.AddHeader(genesis)
+	assert.NoError(t, err)
+
+	err = hr.AddHeader(next)
+	assert.NoError(t, err)
+
+	// then
+	err = hr.AddHeader(blockchain.SignedHeader{BlockHeader: blockchain.BlockHeader{Height: 3, PrevSeal: []byte("seal1")}, Seal: []byte("seal3")})
+	assert.Equal(t, leveldb.ErrInvalidHeight, err)
+
+	err = hr.AddHeader(blockchain.SignedHeader{BlockHeader: blockchain.BlockHeader{Height: 2, PrevSeal: []byte("other")}, Seal: []byte("seal2")})
+	assert.Equal(t, leveldb.ErrInvalidPrevSeal, err)
+
+	err = hr.AddHeader(blockchain.SignedHeader{BlockHeader: blockchain.BlockHeader{Height: 2, PrevSeal: []byte("seal1")}})
+	assert.Equal(t, leveldb.ErrEmptySeal, err)
+
+	lastHeader, err := hr.GetLastHeader()
+	assert.NoError(t, err)
+	assert.Equal(t, next.Seal, lastHeader.Seal)
+	assert.Equal(t, next.Signature, lastHeader.Signature)
+	assert.Equal(t, next.TxRoot, lastHeader.TxRoot)
+
+	header, err := hr.GetHeaderByHeight(0)
+	assert.NoError(t, err)
+	assert.Equal(t, genesis.Seal, header.Seal)
+
+	_, err = hr.GetHeaderByHeight(2)
+	assert.Equal(t, blockchain.ErrHeaderNotFound, err)
+}
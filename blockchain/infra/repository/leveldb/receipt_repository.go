@@ -0,0 +1,125 @@
+package leveldb
+
+import (
+	"encoding/json"
+	"sync"
+
+	"github.com/it-chain/engine/blockchain"
+	"github.com/it-chain/leveldb-wrapper"
+)
+
+// key prefix of each receipt index stored in leveldb
+//
+// | key                      | value           |
+// | ------------------------ | --------------- |
+// | receipt_{transaction id} | json receipt    |
+// | receipts_root_{height}   | receipts root   |
+var (
+	receiptKeyPrefix      = []byte("receipt_")
+	receiptsRootKeyPrefix = []byte("receipts_root_")
+)
+
+// ReceiptRepository 는 icode 가 실행한 transaction 의 receipt 와 block 별 receipts root 를 leveldb 에 저장한다.
+type ReceiptRepository struct {
+	mux     *sync.RWMutex
+	leveldb *leveldbwrapper.DB
+}
+
+func NewReceiptRepository(path string) *ReceiptRepository {
+	db := leveldbwrapper.CreateNewDB(path)
+	db.Open()
+
+	return &ReceiptRepository{
+		mux:     &sync.RWMutex{},
+		leveldb: db,
+	}
+}
+
+// Save 는 block 의 receipt 들과 receipts root 를 한 번의 batch 로 저장한다.
+// 같은 transaction 의 receipt 가 이미 있으면 덮어쓴다. reorg 로 block 이 다시 실행된 경우이다.
+func (r *ReceiptRepository) Save(height blockchain.BlockHeight, receipts []blockchain.Receipt, receiptsRoot []byte) error {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	batch := map[string][]byte{
+		string(receiptsRootKey(height)): receiptsRoot,
+	}
+
+	for _, receipt := range receipts {
+		serialized, err := json.Marshal(receipt)
+		if err != nil {
+			return err
+		}
+
+		batch[string(receiptKey(receipt.TxID))] = serialized
+	}
+
+	return r.leveldb.WriteBatch(batch, true)
+}
+
+// Remove 는 reorg 로 되돌린 block 의 receipt 와 receipts root 를 한 번의 batch 로 지운다.
+func (r *ReceiptRepository) Remove(height blockchain.BlockHeight, txIDs []string) error {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	// value 가 nil 인 key 는 batch 에서 삭제된다.
+	batch := map[string][]byte{
+		string(receiptsRootKey(height)): nil,
+	}
+
+	for _, txID := range txIDs {
+		batch[string(receiptKey(txID))] = nil
+	}
+
+	return r.leveldb.WriteBatch(batch, true)
+}
+
+func (r *ReceiptRepository) GetReceipt(txID string) (blockchain.Receipt, error) {
+	r.mux.RLock()
+	defer r.mux.RUnlock()
+
+	serialized, err := r.leveldb.Get(receiptKey(txID))
+	if err != nil {
+		return blockchain.Receipt{}, err
+	}
+
+	if len(serialized) == 0 {
+		return blockchain.Receipt{}, blockchain.ErrReceiptNotFound
+	}
+
+	receipt := blockchain.Receipt{}
+	if err := json.Unmarshal(serialized, &receipt); err != nil {
+		return blockchain.Receipt{}, err
+	}
+
+	return receipt, nil
+}
+
+// GetReceiptsRoot 는 height 의 block 을 실행한 receipts root 를 반환한다. 아직 실행 결과가 없으면 ErrReceiptNotFound 를 반환한다.
+func (r *ReceiptRepository) GetReceiptsRoot(height blockchain.BlockHeight) ([]byte, error) {
+	r.mux.RLock()
+	defer r.mux.RUnlock()
+
+	root, err := r.leveldb.Get(receiptsRootKey(height))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(root) == 0 {
+		return nil, blockchain.ErrReceiptNotFound
+	}
+
+	return root, nil
+}
+
+func (r *ReceiptRepository) Close() {
+	r.leveldb.Close()
+}
+
+func receiptKey(txID string) []byte {
+	return append(append([]byte{}, receiptKeyPrefix...), txID...)
+}
+
+func receiptsRootKey(height blockchain.BlockHeight) []byte {
+	return append(append([]byte{}, receiptsRootKeyPrefix...), encodeHeight(height)...)
+}
@@ -0,0 +1,82 @@
+package leveldb_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/it-chain/engine/blockchain"
+	"github.com/it-chain/engine/blockchain/infra/repository/leveldb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReceiptRepository_Save(t *testing.T) {
+	// given
+	dbPath := "./.test_receipt"
+	rr := leveldb.NewReceiptRepository(dbPath)
+	defer func() {
+		rr.Close()
+		os.RemoveAll(dbPath)
+	}()
+
+	receipts := []blockchain.Receipt{
+		{TxID: "tx1", Success: true, Data: map[string]string{"a": "1"}, BlockHeight: 3},
+		{TxID: "tx2", Success: false, BlockHeight: 3},
+	}
+
+	// when
+	err := rr.Save(3, receipts, []byte("root"))
+
+	// then
+	assert.NoError(t, err)
+
+	receipt, err := rr.GetReceipt("tx1")
+	assert.NoError(t, err)
+	assert.Equal(t, receipts[0], receipt)
+
+	receipt, err = rr.GetReceipt("tx2")
+	assert.NoError(t, err)
+	assert.False(t, receipt.Success)
+	assert.Equal(t, uint64(3), receipt.BlockHeight)
+
+	root, err := rr.GetReceiptsRoot(3)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("root"), root)
+
+	_, err = rr.GetReceipt("tx3")
+	assert.Equal(t, blockchain.ErrReceiptNotFound, err)
+
+	_, err = rr.GetReceiptsRoot(4)
+	assert.Equal(t, blockchain.ErrReceiptNotFound, err)
+}
+
+func TestReceiptRepository_Remove(t *testing.T) {
+	// given
+	dbPath := "./.test_receipt"
+	rr := leveldb.NewReceiptRepository(dbPath)
+	defer func() {
+		rr.Close()
+		os.RemoveAll(dbPath)
+	}()
+
+	assert.NoError(t, rr.Save(3, []blockchain.Receipt{{TxID: "tx1", BlockHeight: 3}}, []byte("root3")))
+	assert.NoError(t, rr.Save(4, []blockchain.Receipt{{TxID: "tx2", BlockHeight: 4}}, []byte("root4")))
+
+	// when
+	err := rr.Remove(4, []string{"tx2"})
+
+	// then
+	assert.NoError(t, err)
+
+	_, err = rr.GetReceipt("tx2")
+	assert.Equal(t, blockchain.ErrReceiptNotFound, err)
+
+	_, err = rr.GetReceiptsRoot(4)
+	assert.Equal(t, blockchain.ErrReceiptNotFound, err)
+
+	_, err = rr.GetReceipt("tx1")
+	assert.NoError(t, err)
+
+	root, err := rr.GetReceiptsRoot(3)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("root3"), root)
+}
@@ -0,0 +1,168 @@
+package leveldb
+
+import (
+	"encoding/json"
+	"sort"
+	"sync"
+
+	"github.com/it-chain/engine/blockchain"
+	"github.com/it-chain/leveldb-wrapper"
+)
+
+// key prefix of each snapshot index stored in leveldb
+//
+// | key                  | value             |
+// | -------------------- | ----------------- |
+// | snapshot_{height}    | json snapshot     |
+// | last_snapshot_height | height            |
+// | icode_{icode id}     | json icode state  |
+var (
+	snapshotKeyPrefix     = []byte("snapshot_")
+	lastSnapshotHeightKey = []byte("last_snapshot_height")
+	icodeStateKeyPrefix   = []byte("icode_")
+)
+
+// SnapshotRepository 는 snapshot 과 snapshot 에 담을 현재 icode state 를 leveldb 에 저장한다.
+type SnapshotRepository struct {
+	mux     *sync.RWMutex
+	leveldb *leveldbwrapper.DB
+}
+
+func NewSnapshotRepository(path string) *SnapshotRepository {
+	db := leveldbwrapper.CreateNewDB(path)
+	db.Open()
+
+	return &SnapshotRepository{
+		mux:     &sync.RWMutex{},
+		leveldb: db,
+	}
+}
+
+// Save 는 snapshot 을 저장한다. 마지막 snapshot 보다 높은 snapshot 이면 마지막 snapshot 으로 기록한다.
+func (r *SnapshotRepository) Save(snapshot blockchain.Snapshot) error {
+	if len(snapshot.Header.Seal) == 0 {
+		return ErrEmptySeal
+	}
+
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	serialized, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+
+	batch := map[string][]byte{
+		string(snapshotKey(snapshot.Height())): serialized,
+	}
+
+	last, err := r.getLastSnapshot()
+	if err == blockchain.ErrSnapshotNotFound || (err == nil && last.Height() < snapshot.Height()) {
+		batch[string(lastSnapshotHeightKey)] = encodeHeight(snapshot.Height())
+	} else if err != nil {
+		return err
+	}
+
+	return r.leveldb.WriteBatch(batch, true)
+}
+
+func (r *SnapshotRepository) GetSnapshot(height blockchain.BlockHeight) (blockchain.Snapshot, error) {
+	r.mux.RLock()
+	defer r.mux.RUnlock()
+
+	return r.getSnapshot(height)
+}
+
+func (r *SnapshotRepository) GetLastSnapshot() (blockchain.Snapshot, error) {
+	r.mux.RLock()
+	defer r.mux.RUnlock()
+
+	return r.getLastSnapshot()
+}
+
+func (r *SnapshotRepository) SaveICodeState(state blockchain.ICodeState) error {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	serialized, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+
+	return r.leveldb.Put(icodeStateKey(state.ID), serialized, true)
+}
+
+func (r *SnapshotRepository) RemoveICodeState(id string) error {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	return r.leveldb.Delete(icodeStateKey(id), true)
+}
+
+// FindAllICodeStates 는 현재 배포되어 있는 icode 들을 ID 순서로 반환한다.
+func (r *SnapshotRepository) FindAllICodeStates() ([]blockchain.ICodeState, error) {
+	r.mux.RLock()
+	defer r.mux.RUnlock()
+
+	states := make([]blockchain.ICodeState, 0)
+
+	iter := r.leveldb.GetIteratorWithPrefix(icodeStateKeyPrefix)
+	defer iter.Release()
+
+	for iter.Next() {
+		state := blockchain.ICodeState{}
+		if err := json.Unmarshal(iter.Value(), &state); err != nil {
+			return nil, err
+		}
+		states = append(states, state)
+	}
+
+	sort.Slice(states, func(i, j int) bool {
+		return states[i].ID < states[j].ID
+	})
+
+	return states, iter.Error()
+}
+
+func (r *SnapshotRepository) Close() {
+	r.leveldb.Close()
+}
+
+func (r *SnapshotRepository) getSnapshot(height blockchain.BlockHeight) (blockchain.Snapshot, error) {
+	serialized, err := r.leveldb.Get(snapshotKey(height))
+	if err != nil {
+		return blockchain.Snapshot{}, err
+	}
+
+	if len(serialized) == 0 {
+		return blockchain.Snapshot{}, blockchain.ErrSnapshotNotFound
+	}
+
+	snapshot := blockchain.Snapshot{}
+	if err := json.Unmarshal(serialized, &snapshot); err != nil {
+		return blockchain.Snapshot{}, err
+	}
+
+	return snapshot, nil
+}
+
+func (r *SnapshotRepository) getLastSnapshot() (blockchain.Snapshot, error) {
+	height, err := r.leveldb.Get(lastSnapshotHeightKey)
+	if err != nil {
+		return blockchain.Snapshot{}, err
+	}
+
+	if len(height) == 0 {
+		return blockchain.Snapshot{}, blockchain.ErrSnapshotNotFound
+	}
+
+	return r.getSnapshot(decodeHeight(height))
+}
+
+func snapshotKey(height blockchain.BlockHeight) []byte {
+	return append(append([]byte{}, snapshotKeyPrefix...), encodeHeight(height)...)
+}
+
+func icodeStateKey(id string) []byte {
+	return append(append([]byte{}, icodeStateKeyPrefix...), id...)
+}
@@ -0,0 +1,77 @@
+package leveldb_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/it-chain/engine/blockchain"
+	"github.com/it-chain/engine/blockchain/infra/repository/leveldb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnapshotRepository_Save(t *testing.T) {
+	// given
+	dbPath := "./.test_snapshot"
+	sr := leveldb.NewSnapshotRepository(dbPath)
+	defer func() {
+		sr.Close()
+		os.RemoveAll(dbPath)
+	}()
+
+	_, err := sr.GetLastSnapshot()
+	assert.Equal(t, blockchain.ErrSnapshotNotFound, err)
+
+	snapshot10 := blockchain.Snapshot{
+		Header:     blockchain.SignedHeader{BlockHeader: blockchain.BlockHeader{Height: 10}, Seal: []byte("seal10")},
+		ICodes:     []blockchain.ICodeState{{ID: "icode1", GitUrl: "git@github.com:it-chain/icode.git", CommitHash: "hash"}},
+		Parliament: []string{"peer1"},
+		Creator:    []byte("peer1"),
+		Signature:  []byte("signature"),
+	}
+	snapshot5 := blockchain.Snapshot{
+		Header: blockchain.SignedHeader{BlockHeader: blockchain.BlockHeader{Height: 5}, Seal: []byte("seal5")},
+	}
+
+	// when
+	assert.NoError(t, sr.Save(snapshot10))
+	assert.NoError(t, sr.Save(snapshot5))
+
+	// then
+	err = sr.Save(blockchain.Snapshot{Header: blockchain.SignedHeader{BlockHeader: blockchain.BlockHeader{Height: 20}}})
+	assert.Equal(t, leveldb.ErrEmptySeal, err)
+
+	lastSnapshot, err := sr.GetLastSnapshot()
+	assert.NoError(t, err)
+	assert.Equal(t, snapshot10, lastSnapshot)
+
+	snapshot, err := sr.GetSnapshot(5)
+	assert.NoError(t, err)
+	assert.Equal(t, snapshot5.Header.Seal, snapshot.Header.Seal)
+
+	_, err = sr.GetSnapshot(15)
+	assert.Equal(t, blockchain.ErrSnapshotNotFound, err)
+}
+
+func TestSnapshotRepository_ICodeState(t *testing.T) {
+	// given
+	dbPath := "./.test_snapshot"
+	sr := leveldb.NewSnapshotRepository(dbPath)
+	defer func() {
+		sr.Close()
+		os.RemoveAll(dbPath)
+	}()
+
+	// when
+	assert.NoError(t, sr.SaveICodeState(blockchain.ICodeState{ID: "icode2", CommitHash: "hash2"}))
+	assert.NoError(t, sr.SaveICodeState(blockchain.ICodeState{ID: "icode1", CommitHash: "hash1"}))
+	assert.NoError(t, sr.SaveICodeState(blockchain.ICodeState{ID: "icode3", CommitHash: "hash3"}))
+	assert.NoError(t, sr.RemoveICodeState("icode3"))
+
+	// then
+	states, err := sr.FindAllICodeStates()
+	assert.NoError(t, err)
+	assert.Equal(t, []blockchain.ICodeState{
+		{ID: "icode1", CommitHash: "hash1"},
+		{ID: "icode2", CommitHash: "hash2"},
+	}, states)
+}
@@ -0,0 +1,56 @@
+package memory
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/it-chain/engine/blockchain"
+)
+
+var ErrEmptyPeerId = errors.New("empty peer id")
+
+type PeerRepository struct {
+	mux   *sync.RWMutex
+	peers map[string]blockchain.Peer
+}
+
+func NewPeerRepository() *PeerRepository {
+	return &PeerRepository{
+		mux:   &sync.RWMutex{},
+		peers: make(map[string]blockchain.Peer),
+	}
+}
+
+func (r *PeerRepository) Add(peer blockchain.Peer) error {
+	if peer.PeerId.Id == "" {
+		return ErrEmptyPeerId
+	}
+
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	r.peers[peer.PeerId.Id] = peer
+
+	return nil
+}
+
+func (r *PeerRepository) Remove(id blockchain.PeerId) error {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	delete(r.peers, id.Id)
+
+	return nil
+}
+
+func (r *PeerRepository) FindAll() ([]blockchain.Peer, error) {
+	r.mux.RLock()
+	defer r.mux.RUnlock()
+
+	peers := make([]blockchain.Peer, 0, len(r.peers))
+	for _, peer := range r.peers {
+		peers = append(peers, peer)
+	}
+
+	return peers, nil
+}
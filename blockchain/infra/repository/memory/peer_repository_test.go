@@ -0,0 +1,58 @@
+package memory_test
+
+import (
+	"testing"
+
+	"github.com/it-chain/engine/blockchain"
+	"github.com/it-chain/engine/blockchain/infra/repository/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPeerRepository_Add(t *testing.T) {
+	tests := map[string]struct {
+		input blockchain.Peer
+		err   error
+	}{
+		"success": {
+			input: blockchain.Peer{IpAddress: "127.0.0.1:5000", PeerId: blockchain.PeerId{Id: "1"}},
+			err:   nil,
+		},
+		"empty peer id": {
+			input: blockchain.Peer{IpAddress: "127.0.0.1:5000"},
+			err:   memory.ErrEmptyPeerId,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+
+		repository := memory.NewPeerRepository()
+
+		err := repository.Add(test.input)
+		assert.Equal(t, test.err, err)
+	}
+}
+
+func TestPeerRepository_FindAllAndRemove(t *testing.T) {
+	// given
+	repository := memory.NewPeerRepository()
+	repository.Add(blockchain.Peer{PeerId: blockchain.PeerId{Id: "1"}})
+	repository.Add(blockchain.Peer{PeerId: blockchain.PeerId{Id: "2"}})
+	repository.Add(blockchain.Peer{PeerId: blockchain.PeerId{Id: "2"}})
+
+	// when
+	peers, err := repository.FindAll()
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(peers))
+
+	// when
+	err = repository.Remove(blockchain.PeerId{Id: "1"})
+	peers, _ = repository.FindAll()
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(peers))
+	assert.Equal(t, "2", peers[0].PeerId.Id)
+}
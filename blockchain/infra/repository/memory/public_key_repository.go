@@ -0,0 +1,44 @@
+package memory
+
+import (
+	"sync"
+
+	"github.com/it-chain/engine/blockchain"
+)
+
+type PublicKeyRepository struct {
+	mux  *sync.RWMutex
+	keys map[string][]byte
+}
+
+func NewPublicKeyRepository() *PublicKeyRepository {
+	return &PublicKeyRepository{
+		mux:  &sync.RWMutex{},
+		keys: make(map[string][]byte),
+	}
+}
+
+func (r *PublicKeyRepository) Save(peerId blockchain.PeerId, pubKey []byte) error {
+	if peerId.Id == "" {
+		return ErrEmptyPeerId
+	}
+
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	r.keys[peerId.Id] = pubKey
+
+	return nil
+}
+
+func (r *PublicKeyRepository) FindByPeerId(peerId blockchain.PeerId) ([]byte, error) {
+	r.mux.RLock()
+	defer r.mux.RUnlock()
+
+	pubKey, ok := r.keys[peerId.Id]
+	if !ok {
+		return nil, blockchain.ErrPublicKeyNotFound
+	}
+
+	return pubKey, nil
+}
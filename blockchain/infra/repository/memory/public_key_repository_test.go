@@ -0,0 +1,64 @@
+package memory_test
+
+import (
+	"testing"
+
+	"github.com/it-chain/engine/blockchain"
+	"github.com/it-chain/engine/blockchain/infra/repository/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPublicKeyRepository_SaveAndFind(t *testing.T) {
+	tests := map[string]struct {
+		input struct {
+			peerId blockchain.PeerId
+			pubKey []byte
+		}
+		err error
+	}{
+		"success": {
+			input: struct {
+				peerId blockchain.PeerId
+				pubKey []byte
+			}{peerId: blockchain.PeerId{Id: "1"}, pubKey: []byte("pubkey")},
+			err: nil,
+		},
+		"empty peer id": {
+			input: struct {
+				peerId blockchain.PeerId
+				pubKey []byte
+			}{peerId: blockchain.PeerId{}, pubKey: []byte("pubkey")},
+			err: memory.ErrEmptyPeerId,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+
+		repository := memory.NewPublicKeyRepository()
+
+		// when
+		err := repository.Save(test.input.peerId, test.input.pubKey)
+
+		// then
+		assert.Equal(t, test.err, err)
+		if err != nil {
+			continue
+		}
+
+		pubKey, err := repository.FindByPeerId(test.input.peerId)
+		assert.NoError(t, err)
+		assert.Equal(t, test.input.pubKey, pubKey)
+	}
+}
+
+func TestPublicKeyRepository_FindByPeerId_NotFound(t *testing.T) {
+	// given
+	repository := memory.NewPublicKeyRepository()
+
+	// when
+	_, err := repository.FindByPeerId(blockchain.PeerId{Id: "1"})
+
+	// then
+	assert.Equal(t, blockchain.ErrPublicKeyNotFound, err)
+}
@@ -0,0 +1,71 @@
+package blockchain
+
+import (
+	"errors"
+)
+
+var ErrTxNotInBlock = errors.New("transaction is not in the block")
+
+// MerkleProof 는 transaction 이 TxSeal 의 root(TxSeal[0]) 에 포함되어 있음을 증명한다.
+// Path 는 leaf 에서 root 방향으로의 sibling hash 목록이다.
+// Version 은 proof 를 만든 block 의 version 이다. 검증할 tree 는 proof 가 아니라 검증하는 header 의 version 으로 정한다.
+type MerkleProof struct {
+	TxID    string
+	TxHash  []byte
+	Path    []MerklePathNode
+	Version BlockVersion
+}
+
+// MerklePathNode 는 proof 경로의 sibling hash 이다. Left 가 true 이면 sibling 이 왼쪽 node 이다.
+type MerklePathNode struct {
+	Hash []byte
+	Left bool
+}
+
+// BuildTxProof 함수는 block 에서 txID 에 해당하는 transaction 의 Merkle proof 를 만든다.
+// proof 의 크기는 transaction 수에 대해 O(log n) 이다.
+func (t *DefaultValidator) BuildTxProof(block Block, txID string) (MerkleProof, error) {
+	var transaction Transaction
+	index := -1
+	for i, tx := range block.GetTxList() {
+		if tx.GetID() == txID {
+			transaction = tx
+			index = i
+			break
+		}
+	}
+
+	if transaction == nil {
+		return MerkleProof{}, ErrTxNotInBlock
+	}
+
+	version := blockVersion(block)
+
+	hash, err := calculateTxLeaf(version, transaction)
+	if err != nil {
+		return MerkleProof{}, ErrHashCalculationFailed
+	}
+
+	path, err := MerkleTreeOf(version).Proof(block.GetTxSeal(), len(block.GetTxList()), index)
+	if err != nil {
+		return MerkleProof{}, err
+	}
+
+	return MerkleProof{
+		TxID:    txID,
+		TxHash:  hash,
+		Path:    path,
+		Version: version,
+	}, nil
+}
+
+// VerifyTxProof 함수는 block 전체 없이 header 의 tx root 만으로 proof 를 검증한다.
+// tree 는 신뢰할 수 있는 header 의 version 으로 정하므로, proof 의 version 이 header 와 다르면 거부한다.
+// 그렇지 않으면 proof 가 legacy version 을 주장하여 domain separation 이 없는 tree 로 검증받을 수 있다.
+func (t *DefaultValidator) VerifyTxProof(proof MerkleProof, header BlockHeader) bool {
+	if len(proof.TxHash) == 0 || proof.Version != header.Version {
+		return false
+	}
+
+	return MerkleTreeOf(header.Version).Verify(proof.TxHash, proof.Path, header.TxRoot)
+}
@@ -0,0 +1,144 @@
+package blockchain_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/it-chain/engine/blockchain"
+	"github.com/stretchr/testify/assert"
+)
+
+func newBlockWithTxs(t *testing.T, txCount int) *blockchain.DefaultBlock {
+	validator := blockchain.DefaultValidator{}
+
+	block := &blockchain.DefaultBlock{Version: blockchain.CurrentBlockVersion}
+	txList := make([]blockchain.Transaction, 0)
+	for i := 0; i < txCount; i++ {
+		tx := &blockchain.DefaultTransaction{ID: fmt.Sprintf("tx%d", i)}
+		txList = append(txList, tx)
+		block.PutTx(tx)
+	}
+
+	txSeal, err := validator.BuildTxSeal(txList)
+	assert.NoError(t, err)
+	block.SetTxSeal(txSeal)
+
+	return block
+}
+
+func TestDefaultValidator_BuildTxProof(t *testing.T) {
+	validator := blockchain.DefaultValidator{}
+
+	// transaction 수 : proof 경로의 길이
+	tests := map[int]int{1: 0, 2: 1, 3: 2, 4: 2, 5: 3, 6: 3, 7: 3, 8: 3, 9: 4}
+
+	for txCount, pathLength := range tests {
+		t.Logf("running test case %d transactions", txCount)
+
+		// given
+		block := newBlockWithTxs(t, txCount)
+		header := blockchain.NewBlockHeader(block)
+
+		for i := 0; i < txCount; i++ {
+			// when
+			proof, err := validator.BuildTxProof(block, fmt.Sprintf("tx%d", i))
+
+			// then
+			assert.NoError(t, err)
+			assert.True(t, validator.VerifyTxProof(proof, header))
+			assert.True(t, len(proof.Path) <= pathLength)
+		}
+	}
+}
+
+func TestDefaultValidator_BuildTxProof_NotInBlock(t *testing.T) {
+	validator := blockchain.DefaultValidator{}
+	block := newBlockWithTxs(t, 4)
+
+	_, err := validator.BuildTxProof(block, "unknown")
+
+	assert.Equal(t, blockchain.ErrTxNotInBlock, err)
+}
+
+func TestDefaultValidator_VerifyTxProof(t *testing.T) {
+	validator := blockchain.DefaultValidator{}
+	block := newBlockWithTxs(t, 4)
+	header := blockchain.NewBlockHeader(block)
+
+	proof, err := validator.BuildTxProof(block, "tx2")
+	assert.NoError(t, err)
+
+	emptyRoot := header
+	emptyRoot.TxRoot = nil
+
+	tests := map[string]struct {
+		input struct {
+			proof  func() blockchain.MerkleProof
+			header blockchain.BlockHeader
+		}
+		output bool
+	}{
+		"valid proof": {
+			input: struct {
+				proof  func() blockchain.MerkleProof
+				header blockchain.BlockHeader
+			}{proof: func() blockchain.MerkleProof { return proof }, header: header},
+			output: true,
+		},
+		"other root": {
+			input: struct {
+				proof  func() blockchain.MerkleProof
+				header blockchain.BlockHeader
+			}{proof: func() blockchain.MerkleProof { return proof }, header: blockchain.NewBlockHeader(newBlockWithTxs(t, 2))},
+			output: false,
+		},
+		"tampered tx hash": {
+			input: struct {
+				proof  func() blockchain.MerkleProof
+				header blockchain.BlockHeader
+			}{proof: func() blockchain.MerkleProof {
+				tampered := proof
+				tampered.TxHash = []byte("tampered")
+				return tampered
+			}, header: header},
+			output: false,
+		},
+		"flipped path": {
+			input: struct {
+				proof  func() blockchain.MerkleProof
+				header blockchain.BlockHeader
+			}{proof: func() blockchain.MerkleProof {
+				tampered := proof
+				tampered.Path = make([]blockchain.MerklePathNode, len(proof.Path))
+				copy(tampered.Path, proof.Path)
+				tampered.Path[0].Left = !tampered.Path[0].Left
+				return tampered
+			}, header: header},
+			output: false,
+		},
+		"legacy version claimed for non-legacy header": {
+			input: struct {
+				proof  func() blockchain.MerkleProof
+				header blockchain.BlockHeader
+			}{proof: func() blockchain.MerkleProof {
+				tampered := proof
+				tampered.Version = blockchain.LegacyBlockVersion
+				return tampered
+			}, header: header},
+			output: false,
+		},
+		"empty root": {
+			input: struct {
+				proof  func() blockchain.MerkleProof
+				header blockchain.BlockHeader
+			}{proof: func() blockchain.MerkleProof { return proof }, header: emptyRoot},
+			output: false,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+
+		assert.Equal(t, test.output, validator.VerifyTxProof(test.input.proof(), test.input.header))
+	}
+}
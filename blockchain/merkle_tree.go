@@ -0,0 +1,297 @@
+package blockchain
+
+import (
+	"bytes"
+	"errors"
+)
+
+var ErrUnknownValidatorType = errors.New("unknown validator type")
+var ErrInvalidMerkleTree = errors.New("merkle tree does not match the leaf count")
+var ErrLeafIndexOutOfRange = errors.New("leaf index is out of range")
+
+const (
+	// block version 에 맞는 tree 를 사용한다. (기본값)
+	VersionValidatorType = "version"
+	// leaf 와 intermediate node 를 다른 prefix 로 hash 하는 tree (second-preimage 공격 방지)
+	DefaultValidatorType = "default"
+	// 이전 버전의 tree. 이전 버전으로 만들어진 chain 을 검증할 때 사용한다.
+	LegacyValidatorType = "legacy"
+)
+
+var leafNodePrefix = []byte{0x00}
+var intermediateNodePrefix = []byte{0x01}
+
+// MerkleTree 는 transaction seal(leaf) 들로 TxSeal 을 만들고, inclusion proof 를 만들고 검증한다.
+// TxSeal 은 root 가 있는 level 부터 leaf level 까지 차례대로 이어 붙인 배열이며, TxSeal[0] 이 root 이다.
+type MerkleTree interface {
+	Build(leaves [][]byte) ([][]byte, error)
+	Proof(tree [][]byte, leafCount int, index int) ([]MerklePathNode, error)
+	Verify(leaf []byte, path []MerklePathNode, root []byte) bool
+	LeafCount(nodeCount int) (int, error)
+}
+
+// 설정으로 고정한 tree. nil 이면 block version 으로 tree 를 정한다.
+var configuredMerkleTree MerkleTree
+
+// InitDefaultValidator 는 DefaultValidator 가 사용하는 tree 를 설정에 따라 정한다.
+// VersionValidatorType(또는 빈 값)이면 block version 으로 tree 를 정하고,
+// 그 외의 값은 모든 block 에 해당 tree 를 사용한다.
+func InitDefaultValidator(validatorType string) error {
+	if validatorType == "" || validatorType == VersionValidatorType {
+		configuredMerkleTree = nil
+		return nil
+	}
+
+	tree, err := NewMerkleTree(validatorType)
+	if err != nil {
+		return err
+	}
+
+	configuredMerkleTree = tree
+
+	return nil
+}
+
+func NewMerkleTree(validatorType string) (MerkleTree, error) {
+	switch validatorType {
+	case DefaultValidatorType:
+		return DefaultMerkleTree{}, nil
+	case LegacyValidatorType:
+		return LegacyMerkleTree{}, nil
+	default:
+		return nil, ErrUnknownValidatorType
+	}
+}
+
+// MerkleTreeOf 함수는 block version 에 맞는 tree 를 반환한다.
+// LegacyBlockVersion 은 이전 버전의 tree 를, 그 이후 version 은 DefaultMerkleTree 를 사용한다.
+// InitDefaultValidator 로 tree 를 고정했다면 version 과 관계없이 그 tree 를 반환한다.
+func MerkleTreeOf(version BlockVersion) MerkleTree {
+	if configuredMerkleTree != nil {
+		return configuredMerkleTree
+	}
+
+	if version == LegacyBlockVersion {
+		return LegacyMerkleTree{}
+	}
+
+	return DefaultMerkleTree{}
+}
+
+// DefaultMerkleTree 는 leaf 를 H(0x00 || seal), intermediate node 를 H(0x01 || left || right) 로 hash 한다.
+// level 의 node 수가 홀수이면 마지막 node 는 hash 하지 않고 그대로 윗 level 로 올린다.
+type DefaultMerkleTree struct{}
+
+func (DefaultMerkleTree) Build(leaves [][]byte) ([][]byte, error) {
+	if len(leaves) == 0 {
+		return nil, ErrEmptyTxList
+	}
+
+	level := make([][]byte, 0, len(leaves))
+	for _, leaf := range leaves {
+		level = append(level, hashWithPrefix(leafNodePrefix, leaf))
+	}
+
+	levels := [][][]byte{level}
+	for len(level) > 1 {
+		parent := make([][]byte, 0, (len(level)+1)/2)
+		for i := 0; i < len(level); i += 2 {
+			if i+1 == len(level) {
+				parent = append(parent, level[i])
+				continue
+			}
+			parent = append(parent, hashWithPrefix(intermediateNodePrefix, level[i], level[i+1]))
+		}
+
+		levels = append(levels, parent)
+		level = parent
+	}
+
+	return flattenLevels(levels), nil
+}
+
+func (DefaultMerkleTree) Proof(tree [][]byte, leafCount int, index int) ([]MerklePathNode, error) {
+	return buildPath(tree, defaultLevelSizes(leafCount), index)
+}
+
+func (DefaultMerkleTree) Verify(leaf []byte, path []MerklePathNode, root []byte) bool {
+	hash := hashWithPrefix(leafNodePrefix, leaf)
+	for _, node := range path {
+		if node.Left {
+			hash = hashWithPrefix(intermediateNodePrefix, node.Hash, hash)
+		} else {
+			hash = hashWithPrefix(intermediateNodePrefix, hash, node.Hash)
+		}
+	}
+
+	return len(root) != 0 && bytes.Equal(hash, root)
+}
+
+func (DefaultMerkleTree) LeafCount(nodeCount int) (int, error) {
+	return findLeafCount(nodeCount, defaultLevelSizes)
+}
+
+// LegacyMerkleTree 는 이전 버전의 tree 이다. leaf 는 seal 을 그대로 사용하고 node 는 H(left || right) 로 hash 한다.
+// level 의 node 수가 홀수이면 마지막 node 를 복사하여 짝수로 맞춘다.
+// node 수가 2의 거듭제곱인 경우 이전 버전과 같은 TxSeal 을 만든다.
+type LegacyMerkleTree struct{}
+
+func (LegacyMerkleTree) Build(leaves [][]byte) ([][]byte, error) {
+	if len(leaves) == 0 {
+		return nil, ErrEmptyTxList
+	}
+
+	level := padLevel(leaves)
+	levels := [][][]byte{level}
+	for len(level) > 1 {
+		parent := make([][]byte, 0, len(level)/2)
+		for i := 0; i < len(level); i += 2 {
+			parent = append(parent, hashWithPrefix(nil, level[i], level[i+1]))
+		}
+
+		if len(parent) > 1 {
+			parent = padLevel(parent)
+		}
+
+		levels = append(levels, parent)
+		level = parent
+	}
+
+	return flattenLevels(levels), nil
+}
+
+func (LegacyMerkleTree) Proof(tree [][]byte, leafCount int, index int) ([]MerklePathNode, error) {
+	return buildPath(tree, legacyLevelSizes(leafCount), index)
+}
+
+func (LegacyMerkleTree) Verify(leaf []byte, path []MerklePathNode, root []byte) bool {
+	hash := leaf
+	for _, node := range path {
+		if node.Left {
+			hash = hashWithPrefix(nil, node.Hash, hash)
+		} else {
+			hash = hashWithPrefix(nil, hash, node.Hash)
+		}
+	}
+
+	return len(root) != 0 && bytes.Equal(hash, root)
+}
+
+func (LegacyMerkleTree) LeafCount(nodeCount int) (int, error) {
+	return findLeafCount(nodeCount, legacyLevelSizes)
+}
+
+// defaultLevelSizes 는 leaf level 부터 root 까지 각 level 의 node 수이다.
+func defaultLevelSizes(leafCount int) []int {
+	sizes := []int{leafCount}
+	for size := leafCount; size > 1; {
+		size = (size + 1) / 2
+		sizes = append(sizes, size)
+	}
+
+	return sizes
+}
+
+func legacyLevelSizes(leafCount int) []int {
+	size := leafCount + leafCount%2
+	sizes := []int{size}
+	for size > 1 {
+		size = size / 2
+		if size > 1 {
+			size += size % 2
+		}
+		sizes = append(sizes, size)
+	}
+
+	return sizes
+}
+
+// findLeafCount 는 node 수가 nodeCount 인 tree 의 leaf level 의 node 수를 찾는다.
+// 여러 leaf 수가 같은 모양의 tree 를 만들면 (legacy 의 padding) 가장 큰 값을 반환한다.
+func findLeafCount(nodeCount int, levelSizes func(int) []int) (int, error) {
+	found := 0
+	for leafCount := 1; leafCount <= nodeCount; leafCount++ {
+		total := 0
+		for _, size := range levelSizes(leafCount) {
+			total += size
+		}
+
+		if total == nodeCount {
+			found = leafCount
+		}
+
+		if total > nodeCount {
+			break
+		}
+	}
+
+	if found == 0 {
+		return 0, ErrInvalidMerkleTree
+	}
+
+	return found, nil
+}
+
+// buildPath 는 leaf level 의 index 번째 node 에서 root 까지의 sibling 목록을 만든다.
+// sibling 이 없는 node (홀수 level 의 마지막 node) 는 경로에 추가하지 않는다.
+func buildPath(tree [][]byte, sizes []int, index int) ([]MerklePathNode, error) {
+	total := 0
+	for _, size := range sizes {
+		total += size
+	}
+
+	if total != len(tree) {
+		return nil, ErrInvalidMerkleTree
+	}
+
+	if index < 0 || index >= sizes[0] {
+		return nil, ErrLeafIndexOutOfRange
+	}
+
+	path := make([]MerklePathNode, 0)
+
+	// leaf level 은 tree 의 마지막에 있다.
+	offset := total
+	for _, size := range sizes[:len(sizes)-1] {
+		offset -= size
+
+		sibling := index ^ 1
+		if sibling < size {
+			path = append(path, MerklePathNode{
+				Hash: tree[offset+sibling],
+				Left: sibling < index,
+			})
+		}
+
+		index = index / 2
+	}
+
+	return path, nil
+}
+
+func flattenLevels(levels [][][]byte) [][]byte {
+	tree := make([][]byte, 0)
+	for i := len(levels) - 1; i >= 0; i-- {
+		tree = append(tree, levels[i]...)
+	}
+
+	return tree
+}
+
+func padLevel(level [][]byte) [][]byte {
+	padded := append([][]byte{}, level...)
+	if len(padded)%2 != 0 {
+		padded = append(padded, padded[len(padded)-1])
+	}
+
+	return padded
+}
+
+func hashWithPrefix(prefix []byte, data ...[]byte) []byte {
+	combined := append([]byte{}, prefix...)
+	for _, d := range data {
+		combined = append(combined, d...)
+	}
+
+	return calculateHash(combined)
+}
@@ -0,0 +1,286 @@
+package blockchain_test
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"testing"
+
+	"github.com/it-chain/engine/blockchain"
+	"github.com/stretchr/testify/assert"
+)
+
+func leavesOf(count int) [][]byte {
+	leaves := make([][]byte, 0)
+	for i := 0; i < count; i++ {
+		hash := sha256.Sum256([]byte(fmt.Sprintf("leaf%d", i)))
+		leaves = append(leaves, hash[:])
+	}
+
+	return leaves
+}
+
+func TestMerkleTree_BuildAndProof(t *testing.T) {
+	trees := map[string]blockchain.MerkleTree{
+		"default": blockchain.DefaultMerkleTree{},
+		"legacy":  blockchain.LegacyMerkleTree{},
+	}
+
+	for treeName, tree := range trees {
+		for leafCount := 1; leafCount <= 17; leafCount++ {
+			t.Logf("running test case %s tree with %d leaves", treeName, leafCount)
+
+			// given
+			leaves := leavesOf(leafCount)
+
+			// when
+			txSeal, err := tree.Build(leaves)
+
+			// then
+			assert.NoError(t, err)
+
+			// when: 같은 leaf 로 만든 tree 는 항상 같다.
+			again, _ := tree.Build(leaves)
+
+			// then
+			assert.Equal(t, txSeal, again)
+
+			// when
+			count, err := tree.LeafCount(len(txSeal))
+
+			// then
+			assert.NoError(t, err)
+			assert.True(t, count == leafCount || count == leafCount+1)
+
+			for index, leaf := range leaves {
+				// when
+				path, err := tree.Proof(txSeal, leafCount, index)
+
+				// then
+				assert.NoError(t, err)
+				assert.True(t, tree.Verify(leaf, path, txSeal[0]))
+				assert.False(t, tree.Verify(leaves[(index+1)%leafCount], path, txSeal[0]) && leafCount > 1)
+			}
+		}
+	}
+}
+
+func TestMerkleTree_Build_EmptyLeaves(t *testing.T) {
+	_, err := blockchain.DefaultMerkleTree{}.Build([][]byte{})
+	assert.Equal(t, blockchain.ErrEmptyTxList, err)
+
+	_, err = blockchain.LegacyMerkleTree{}.Build(nil)
+	assert.Equal(t, blockchain.ErrEmptyTxList, err)
+}
+
+func TestMerkleTree_Proof_Fail(t *testing.T) {
+	tree := blockchain.DefaultMerkleTree{}
+	txSeal, _ := tree.Build(leavesOf(5))
+
+	_, err := tree.Proof(txSeal, 5, 5)
+	assert.Equal(t, blockchain.ErrLeafIndexOutOfRange, err)
+
+	_, err = tree.Proof(txSeal, 4, 0)
+	assert.Equal(t, blockchain.ErrInvalidMerkleTree, err)
+}
+
+func TestLegacyMerkleTree_Compatible(t *testing.T) {
+	// 이전 버전: leaf 를 짝수로 맞추고 H(left || right) 로 root 까지 hash 한다.
+	leaves := leavesOf(4)
+	hash := func(left []byte, right []byte) []byte {
+		h := sha256.Sum256(append(append([]byte{}, left...), right...))
+		return h[:]
+	}
+	h01 := hash(leaves[0], leaves[1])
+	h23 := hash(leaves[2], leaves[3])
+	expected := [][]byte{hash(h01, h23), h01, h23, leaves[0], leaves[1], leaves[2], leaves[3]}
+
+	txSeal, err := blockchain.LegacyMerkleTree{}.Build(leaves)
+
+	assert.NoError(t, err)
+	assert.Equal(t, expected, txSeal)
+}
+
+func TestDefaultMerkleTree_DomainSeparation(t *testing.T) {
+	// given
+	leaves := leavesOf(2)
+	txSeal, _ := blockchain.DefaultMerkleTree{}.Build(leaves)
+	legacySeal, _ := blockchain.LegacyMerkleTree{}.Build(leaves)
+
+	// when: intermediate node 의 자식들을 하나의 leaf 로 위조
+	forgedLeaf := append(append([]byte{}, txSeal[1]...), txSeal[2]...)
+	forgedLegacyLeaf := append(append([]byte{}, legacySeal[1]...), legacySeal[2]...)
+
+	// then: legacy tree 는 intermediate node 를 leaf 로 속일 수 있지만, default tree 는 속일 수 없다.
+	legacyRoot := sha256.Sum256(forgedLegacyLeaf)
+	assert.Equal(t, legacySeal[0], legacyRoot[:])
+	assert.False(t, blockchain.DefaultMerkleTree{}.Verify(forgedLeaf, []blockchain.MerklePathNode{}, txSeal[0]))
+}
+
+func TestMerkleTreeOf(t *testing.T) {
+	tests := map[string]struct {
+		input  blockchain.BlockVersion
+		output blockchain.MerkleTree
+	}{
+		"legacy":  {input: blockchain.LegacyBlockVersion, output: blockchain.LegacyMerkleTree{}},
+		"header":  {input: blockchain.HeaderBlockVersion, output: blockchain.DefaultMerkleTree{}},
+		"receipt": {input: blockchain.ReceiptBlockVersion, output: blockchain.DefaultMerkleTree{}},
+	}
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+
+		assert.Equal(t, test.output, blockchain.MerkleTreeOf(test.input))
+	}
+}
+
+func TestInitDefaultValidator(t *testing.T) {
+	defer blockchain.InitDefaultValidator(blockchain.VersionValidatorType)
+
+	tests := map[string]struct {
+		input struct {
+			validatorType string
+			version       blockchain.BlockVersion
+		}
+		output blockchain.MerkleTree
+		err    error
+	}{
+		"version-legacy": {
+			input: struct {
+				validatorType string
+				version       blockchain.BlockVersion
+			}{validatorType: blockchain.VersionValidatorType, version: blockchain.LegacyBlockVersion},
+			output: blockchain.LegacyMerkleTree{},
+		},
+		"empty-current": {
+			input: struct {
+				validatorType string
+				version       blockchain.BlockVersion
+			}{validatorType: "", version: blockchain.CurrentBlockVersion},
+			output: blockchain.DefaultMerkleTree{},
+		},
+		"default-legacy": {
+			input: struct {
+				validatorType string
+				version       blockchain.BlockVersion
+			}{validatorType: blockchain.DefaultValidatorType, version: blockchain.LegacyBlockVersion},
+			output: blockchain.DefaultMerkleTree{},
+		},
+		"legacy-current": {
+			input: struct {
+				validatorType string
+				version       blockchain.BlockVersion
+			}{validatorType: blockchain.LegacyValidatorType, version: blockchain.CurrentBlockVersion},
+			output: blockchain.LegacyMerkleTree{},
+		},
+		"unknown": {
+			input: struct {
+				validatorType string
+				version       blockchain.BlockVersion
+			}{validatorType: "sha3", version: blockchain.CurrentBlockVersion},
+			err: blockchain.ErrUnknownValidatorType,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+
+		// given
+		blockchain.InitDefaultValidator(blockchain.VersionValidatorType)
+
+		// when
+		err := blockchain.InitDefaultValidator(test.input.validatorType)
+
+		// then
+		assert.Equal(t, test.err, err)
+		if err != nil {
+			continue
+		}
+		assert.Equal(t, test.output, blockchain.MerkleTreeOf(test.input.version))
+	}
+}
+
+func TestDefaultValidator_ValidateTxSeal(t *testing.T) {
+	validator := blockchain.NewDefaultValidator()
+
+	for txCount := 1; txCount <= 9; txCount++ {
+		t.Logf("running test case with %d transactions", txCount)
+
+		// given
+		txList := make([]blockchain.Transaction, 0)
+		for i := 0; i < txCount; i++ {
+			txList = append(txList, &blockchain.DefaultTransaction{ID: fmt.Sprintf("tx%d", i)})
+		}
+
+		// when
+		txSeal, err := validator.BuildTxSeal(txList)
+
+		// then
+		assert.NoError(t, err)
+
+		valid, err := validator.ValidateTxSeal(txSeal, txList)
+		assert.NoError(t, err)
+		assert.True(t, valid)
+
+		valid, _ = validator.ValidateTransaction(txSeal, txList[txCount-1])
+		assert.True(t, valid)
+
+		valid, _ = validator.ValidateTransaction(txSeal, &blockchain.DefaultTransaction{ID: "other"})
+		assert.False(t, valid)
+
+		// when: transaction 순서가 바뀜
+		if txCount > 1 {
+			swapped := append([]blockchain.Transaction{txList[1], txList[0]}, txList[2:]...)
+			valid, _ = validator.ValidateTxSeal(txSeal, swapped)
+			assert.False(t, valid)
+		}
+	}
+}
+
+func TestDefaultValidator_ValidateBlockTxSeal(t *testing.T) {
+	// given: 6 개의 transaction 은 두 tree 의 TxSeal 이 다르다.
+	validator := blockchain.NewDefaultValidator()
+
+	txList := make([]*blockchain.DefaultTransaction, 0)
+	transactions := make([]blockchain.Transaction, 0)
+	for i := 0; i < 6; i++ {
+		tx := &blockchain.DefaultTransaction{ID: fmt.Sprintf("tx%d", i)}
+		txList = append(txList, tx)
+		transactions = append(transactions, tx)
+	}
+
+	defaultSeal, _ := validator.BuildTxSealOf(blockchain.HeaderBlockVersion, transactions)
+	legacySeal, _ := validator.BuildTxSealOf(blockchain.LegacyBlockVersion, transactions)
+
+	tests := map[string]struct {
+		input  *blockchain.DefaultBlock
+		output bool
+	}{
+		"header block with default tree": {
+			input:  &blockchain.DefaultBlock{Version: blockchain.HeaderBlockVersion, TxList: txList, TxSeal: defaultSeal},
+			output: true,
+		},
+		"header block with legacy tree": {
+			input:  &blockchain.DefaultBlock{Version: blockchain.HeaderBlockVersion, TxList: txList, TxSeal: legacySeal},
+			output: false,
+		},
+		"legacy block with legacy tree": {
+			input:  &blockchain.DefaultBlock{Version: blockchain.LegacyBlockVersion, TxList: txList, TxSeal: legacySeal},
+			output: true,
+		},
+		"legacy block with default tree": {
+			input:  &blockchain.DefaultBlock{Version: blockchain.LegacyBlockVersion, TxList: txList, TxSeal: defaultSeal},
+			output: false,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+
+		// when
+		valid, err := validator.ValidateBlockTxSeal(test.input)
+
+		// then
+		assert.NoError(t, err)
+		assert.Equal(t, test.output, valid)
+	}
+}
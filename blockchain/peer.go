@@ -8,12 +8,15 @@ func (peerId PeerId) ToString() string {
 	return string(peerId.Id)
 }
 
+// PubKey 는 PEM 형식의 public key 이며, peer 가 만든 block 의 서명을 검증하는 데 사용한다.
 type Peer struct {
 	IpAddress string
 	PeerId    PeerId
+	PubKey    []byte
 }
 
 type PeerRepository interface {
 	Add(peer Peer) error
 	Remove(id PeerId) error
+	FindAll() ([]Peer, error)
 }
@@ -0,0 +1,63 @@
+package blockchain
+
+import "errors"
+
+var ErrBlockPruned = errors.New("block body is pruned")
+var ErrInvalidPruneConfig = errors.New("blockchain mode must be archive, or pruned with at least one full block to keep")
+
+// blockchain 이 commit 된 block 을 보관하는 방식
+const (
+	// 모든 block 을 그대로 보관한다.
+	ArchiveMode = "archive"
+
+	// 모든 header 와 마지막 KeepBlocks 개의 block 만 보관하고, 그 이전 block 의 transaction 은 지운다.
+	PrunedMode = "pruned"
+)
+
+type PruneConfig struct {
+	Mode       string
+	KeepBlocks uint64
+}
+
+func NewPruneConfig(mode string, keepBlocks uint64) (PruneConfig, error) {
+	switch mode {
+	case ArchiveMode:
+		return PruneConfig{Mode: ArchiveMode}, nil
+	case PrunedMode:
+		if keepBlocks == 0 {
+			return PruneConfig{}, ErrInvalidPruneConfig
+		}
+		return PruneConfig{Mode: PrunedMode, KeepBlocks: keepBlocks}, nil
+	default:
+		return PruneConfig{}, ErrInvalidPruneConfig
+	}
+}
+
+// PruneHeight 는 마지막 block 이 lastHeight 일 때 transaction 을 지울 수 있는 가장 높은 height 이다.
+// genesis block 은 genesis 검사에 사용하므로 지우지 않는다. 0 이면 지울 block 이 없다.
+func (c PruneConfig) PruneHeight(lastHeight BlockHeight) BlockHeight {
+	if c.Mode != PrunedMode || lastHeight < c.KeepBlocks {
+		return 0
+	}
+
+	return lastHeight - c.KeepBlocks
+}
+
+// RetentionStats 는 block 보관 상태이다. PrunedHeight 이하의 block 은 header 만 남아 있다.
+type RetentionStats struct {
+	Mode         string
+	KeepBlocks   uint64
+	LastHeight   BlockHeight
+	PrunedHeight BlockHeight
+	PrunedBlocks uint64
+	PrunedTxs    uint64
+	PrunedBytes  uint64
+}
+
+// PrunableBlockRepository 는 오래된 block 의 transaction 을 지울 수 있는 BlockRepository 이다.
+// 지워진 block 을 조회하면 ErrBlockPruned 를 반환하고, header 는 GetHeaderByHeight 로 계속 조회할 수 있다.
+type PrunableBlockRepository interface {
+	GetHeaderByHeight(height BlockHeight) (SignedHeader, error)
+	Prune() error
+	RetentionStats() (RetentionStats, error)
+}
@@ -0,0 +1,88 @@
+package blockchain_test
+
+import (
+	"testing"
+
+	"github.com/it-chain/engine/blockchain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewPruneConfig(t *testing.T) {
+	tests := map[string]struct {
+		input struct {
+			mode       string
+			keepBlocks uint64
+		}
+		output blockchain.PruneConfig
+		err    error
+	}{
+		"archive": {
+			input: struct {
+				mode       string
+				keepBlocks uint64
+			}{mode: "archive", keepBlocks: 100},
+			output: blockchain.PruneConfig{Mode: blockchain.ArchiveMode},
+			err:    nil,
+		},
+		"pruned": {
+			input: struct {
+				mode       string
+				keepBlocks uint64
+			}{mode: "pruned", keepBlocks: 100},
+			output: blockchain.PruneConfig{Mode: blockchain.PrunedMode, KeepBlocks: 100},
+			err:    nil,
+		},
+		"pruned without block to keep": {
+			input: struct {
+				mode       string
+				keepBlocks uint64
+			}{mode: "pruned", keepBlocks: 0},
+			err: blockchain.ErrInvalidPruneConfig,
+		},
+		"unknown mode": {
+			input: struct {
+				mode       string
+				keepBlocks uint64
+			}{mode: "light", keepBlocks: 100},
+			err: blockchain.ErrInvalidPruneConfig,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+
+		config, err := blockchain.NewPruneConfig(test.input.mode, test.input.keepBlocks)
+		assert.Equal(t, test.err, err)
+		assert.Equal(t, test.output, config)
+	}
+}
+
+func TestPruneConfig_PruneHeight(t *testing.T) {
+	tests := map[string]struct {
+		config blockchain.PruneConfig
+		input  blockchain.BlockHeight
+		output blockchain.BlockHeight
+	}{
+		"archive": {
+			config: blockchain.PruneConfig{Mode: blockchain.ArchiveMode},
+			input:  100,
+			output: 0,
+		},
+		"keep last blocks": {
+			config: blockchain.PruneConfig{Mode: blockchain.PrunedMode, KeepBlocks: 10},
+			input:  100,
+			output: 90,
+		},
+		"short chain": {
+			config: blockchain.PruneConfig{Mode: blockchain.PrunedMode, KeepBlocks: 10},
+			input:  10,
+			output: 0,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+
+		assert.Equal(t, test.output, test.config.PruneHeight(test.input))
+	}
+}
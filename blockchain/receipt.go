@@ -0,0 +1,108 @@
+package blockchain
+
+import (
+	"crypto/sha256"
+	"errors"
+	"sort"
+)
+
+var ErrReceiptNotFound = errors.New("receipt not found")
+var ErrResultBlockMismatch = errors.New("tx results do not belong to the block")
+
+// TxResult 는 icode 가 transaction 을 실행한 결과이다. icode.Result 와 같은 형태이다.
+type TxResult struct {
+	TxId    string
+	Data    map[string]string
+	Success bool
+}
+
+// Receipt 는 commit 된 block 의 transaction 실행 결과이다.
+type Receipt struct {
+	TxID        string
+	Success     bool
+	Data        map[string]string
+	BlockHeight BlockHeight
+}
+
+// ReceiptRepository 는 receipt 를 transaction ID 로, receipts root 를 block height 로 저장한다.
+// Remove 는 reorg 로 되돌린 block 의 receipt 와 receipts root 를 지운다.
+type ReceiptRepository interface {
+	Save(height BlockHeight, receipts []Receipt, receiptsRoot []byte) error
+	Remove(height BlockHeight, txIDs []string) error
+	GetReceipt(txID string) (Receipt, error)
+	GetReceiptsRoot(height BlockHeight) ([]byte, error)
+	Close()
+}
+
+// NewReceipts 함수는 block 의 transaction 순서대로 실행 결과를 receipt 로 만든다.
+// 결과가 없는 transaction 은 실행되지 않은 것이므로 실패한 receipt 가 된다.
+func NewReceipts(block Block, results []TxResult) ([]Receipt, error) {
+	resultByTxID := make(map[string]TxResult, len(results))
+	for _, result := range results {
+		resultByTxID[result.TxId] = result
+	}
+
+	receipts := make([]Receipt, 0, len(block.GetTxList()))
+	for _, tx := range block.GetTxList() {
+		result, ok := resultByTxID[tx.GetID()]
+		delete(resultByTxID, tx.GetID())
+
+		receipts = append(receipts, Receipt{
+			TxID:        tx.GetID(),
+			Success:     ok && result.Success,
+			Data:        result.Data,
+			BlockHeight: block.GetHeight(),
+		})
+	}
+
+	if len(resultByTxID) != 0 {
+		return nil, ErrResultBlockMismatch
+	}
+
+	return receipts, nil
+}
+
+// Bytes 함수는 receipt 의 hash 대상을 고정된 순서의 binary 로 encoding 한다.
+// Data 는 key 순서로 정렬한다. BlockHeight 는 receipts root 가 담기는 header 가 이미 commit 하므로 제외한다.
+func (r Receipt) Bytes() []byte {
+	keys := make([]string, 0, len(r.Data))
+	for key := range r.Data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	buf := appendString(nil, r.TxID)
+	if r.Success {
+		buf = appendUint8(buf, 1)
+	} else {
+		buf = appendUint8(buf, 0)
+	}
+
+	buf = appendUint32(buf, uint32(len(keys)))
+	for _, key := range keys {
+		buf = appendString(buf, key)
+		buf = appendString(buf, r.Data[key])
+	}
+
+	return buf
+}
+
+// BuildReceiptsRoot 함수는 receipt hash 들의 merkle root 를 반환한다. receipt 가 없으면 빈 root 를 반환한다.
+func (t *DefaultValidator) BuildReceiptsRoot(receipts []Receipt) ([]byte, error) {
+	if len(receipts) == 0 {
+		return make([]byte, 0), nil
+	}
+
+	leaves := make([][]byte, 0, len(receipts))
+	for _, receipt := range receipts {
+		hash := sha256.Sum256(receipt.Bytes())
+		leaves = append(leaves, hash[:])
+	}
+
+	tree, err := MerkleTreeOf(ReceiptBlockVersion).Build(leaves)
+	if err != nil {
+		return nil, err
+	}
+
+	return tree[0], nil
+}
@@ -0,0 +1,131 @@
+package blockchain_test
+
+import (
+	"testing"
+
+	"github.com/it-chain/engine/blockchain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewReceipts(t *testing.T) {
+	block := &blockchain.DefaultBlock{
+		Height: 5,
+		TxList: []*blockchain.DefaultTransaction{{ID: "tx1"}, {ID: "tx2"}, {ID: "tx3"}},
+	}
+
+	tests := map[string]struct {
+		input  []blockchain.TxResult
+		output []blockchain.Receipt
+		err    error
+	}{
+		"block order": {
+			input: []blockchain.TxResult{
+				{TxId: "tx3", Success: true},
+				{TxId: "tx1", Success: true, Data: map[string]string{"a": "1"}},
+				{TxId: "tx2", Success: false},
+			},
+			output: []blockchain.Receipt{
+				{TxID: "tx1", Success: true, Data: map[string]string{"a": "1"}, BlockHeight: 5},
+				{TxID: "tx2", Success: false, BlockHeight: 5},
+				{TxID: "tx3", Success: true, BlockHeight: 5},
+			},
+			err: nil,
+		},
+		"missing result": {
+			input: []blockchain.TxResult{
+				{TxId: "tx1", Success: true},
+			},
+			output: []blockchain.Receipt{
+				{TxID: "tx1", Success: true, BlockHeight: 5},
+				{TxID: "tx2", Success: false, BlockHeight: 5},
+				{TxID: "tx3", Success: false, BlockHeight: 5},
+			},
+			err: nil,
+		},
+		"result of other block": {
+			input: []blockchain.TxResult{
+				{TxId: "tx4", Success: true},
+			},
+			output: nil,
+			err:    blockchain.ErrResultBlockMismatch,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+
+		// when
+		receipts, err := blockchain.NewReceipts(block, test.input)
+
+		// then
+		assert.Equal(t, test.err, err)
+		assert.Equal(t, test.output, receipts)
+	}
+}
+
+func TestDefaultValidator_BuildReceiptsRoot(t *testing.T) {
+	// given
+	validator := blockchain.DefaultValidator{}
+	receipts := []blockchain.Receipt{
+		{TxID: "tx1", Success: true, Data: map[string]string{"a": "1", "b": "2"}},
+		{TxID: "tx2", Success: false},
+	}
+
+	// when
+	root, err := validator.BuildReceiptsRoot(receipts)
+
+	// then
+	assert.NoError(t, err)
+	assert.NotEmpty(t, root)
+
+	// map 순서와 height 는 root 에 영향을 주지 않는다.
+	same := []blockchain.Receipt{
+		{TxID: "tx1", Success: true, Data: map[string]string{"b": "2", "a": "1"}, BlockHeight: 9},
+		{TxID: "tx2", Success: false, BlockHeight: 9},
+	}
+	sameRoot, err := validator.BuildReceiptsRoot(same)
+	assert.NoError(t, err)
+	assert.Equal(t, root, sameRoot)
+
+	// 실행 결과가 바뀌면 root 도 바뀐다.
+	changed := []blockchain.Receipt{
+		{TxID: "tx1", Success: true, Data: map[string]string{"a": "1", "b": "3"}},
+		{TxID: "tx2", Success: false},
+	}
+	changedRoot, err := validator.BuildReceiptsRoot(changed)
+	assert.NoError(t, err)
+	assert.NotEqual(t, root, changedRoot)
+
+	emptyRoot, err := validator.BuildReceiptsRoot(nil)
+	assert.NoError(t, err)
+	assert.Empty(t, emptyRoot)
+}
+
+func TestBlockHeader_ReceiptsRoot(t *testing.T) {
+	// given
+	validator := blockchain.DefaultValidator{}
+	header := blockchain.BlockHeader{
+		Version:  blockchain.ReceiptBlockVersion,
+		Height:   1,
+		PrevSeal: []byte("prevseal"),
+		TxRoot:   []byte("txroot"),
+		Creator:  []byte("creator"),
+	}
+
+	withReceipts := header
+	withReceipts.ReceiptsRoot = []byte("receiptsroot")
+
+	// when
+	seal, err := validator.BuildHeaderSeal(header)
+	assert.NoError(t, err)
+	receiptSeal, err := validator.BuildHeaderSeal(withReceipts)
+	assert.NoError(t, err)
+
+	// then
+	assert.NotEqual(t, seal, receiptSeal)
+
+	// HeaderBlockVersion 의 seal 은 receipts root 를 포함하지 않는다.
+	header.Version = blockchain.HeaderBlockVersion
+	withReceipts.Version = blockchain.HeaderBlockVersion
+	assert.Equal(t, header.Bytes(), withReceipts.Bytes())
+}
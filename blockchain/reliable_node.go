@@ -0,0 +1,99 @@
+package blockchain
+
+import (
+	"bytes"
+	"errors"
+	"sort"
+)
+
+var ErrNoReliableTip = errors.New("no chain tip is agreed by a quorum of peers")
+
+// SyncCheckResponse 는 SyncCheck 과정에서 peer 가 알려준 last block 이다.
+type SyncCheckResponse struct {
+	PeerId PeerId
+	Block  Block
+}
+
+// ReliableTip 은 quorum 이상의 peer 가 동의한 chain 의 마지막 block 정보와 동의한 peer 목록이다.
+type ReliableTip struct {
+	Height BlockHeight
+	Seal   []byte
+	Peers  []PeerId
+}
+
+// Quorum 은 peerCount 개의 peer 중 신뢰할 수 있다고 판단하기 위해 필요한 peer 수이다.
+// 과반수와 2f+1 (n = 3f+1) 중 더 큰 값을 사용한다.
+func Quorum(peerCount int) int {
+	if peerCount <= 0 {
+		return 1
+	}
+
+	f := (peerCount - 1) / 3
+	quorum := 2*f + 1
+
+	if majority := peerCount/2 + 1; majority > quorum {
+		quorum = majority
+	}
+
+	return quorum
+}
+
+// SelectReliableTip 은 peer 들이 보낸 last block 을 (height, seal) 로 묶어
+// peerCount 에 대한 quorum 이상이 동의한 tip 을 반환한다.
+// 동의한 peer 가 quorum 에 미치지 못하면 ErrNoReliableTip 을 반환한다.
+func SelectReliableTip(responses []SyncCheckResponse, peerCount int) (ReliableTip, error) {
+	tips := make([]*ReliableTip, 0)
+
+	for _, response := range responses {
+		if response.Block == nil || response.Block.GetSeal() == nil {
+			continue
+		}
+
+		tip := findTip(tips, response.Block)
+		if tip == nil {
+			tip = &ReliableTip{
+				Height: response.Block.GetHeight(),
+				Seal:   response.Block.GetSeal(),
+				Peers:  make([]PeerId, 0),
+			}
+			tips = append(tips, tip)
+		}
+
+		if !containsPeer(tip.Peers, response.PeerId) {
+			tip.Peers = append(tip.Peers, response.PeerId)
+		}
+	}
+
+	quorum := Quorum(peerCount)
+	for _, tip := range tips {
+		if len(tip.Peers) >= quorum {
+			sort.Slice(tip.Peers, func(i, j int) bool {
+				return tip.Peers[i].Id < tip.Peers[j].Id
+			})
+
+			return *tip, nil
+		}
+	}
+
+	return ReliableTip{}, ErrNoReliableTip
+}
+
+func findTip(tips []*ReliableTip, block Block) *ReliableTip {
+	for _, tip := range tips {
+		if tip.Height == block.GetHeight() && bytes.Equal(tip.Seal, block.GetSeal()) {
+			return tip
+		}
+	}
+
+	return nil
+}
+
+func containsPeer(peers []PeerId, peerId PeerId) bool {
+	for _, peer := range peers {
+		if peer.Id == peerId.Id {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,118 @@
+package blockchain_test
+
+import (
+	"testing"
+
+	"github.com/it-chain/engine/blockchain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuorum(t *testing.T) {
+	tests := map[string]struct {
+		input  int
+		output int
+	}{
+		"no peer":     {input: 0, output: 1},
+		"single peer": {input: 1, output: 1},
+		"two peers":   {input: 2, output: 2},
+		"three peers": {input: 3, output: 2},
+		"four peers":  {input: 4, output: 3},
+		"seven peers": {input: 7, output: 5},
+	}
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+
+		assert.Equal(t, test.output, blockchain.Quorum(test.input))
+	}
+}
+
+func TestSelectReliableTip(t *testing.T) {
+	honest := &blockchain.DefaultBlock{Seal: []byte("seal10"), Height: 10}
+	divergent := &blockchain.DefaultBlock{Seal: []byte("fake20"), Height: 20}
+
+	response := func(id string, block blockchain.Block) blockchain.SyncCheckResponse {
+		return blockchain.SyncCheckResponse{PeerId: blockchain.PeerId{Id: id}, Block: block}
+	}
+
+	tests := map[string]struct {
+		input struct {
+			responses []blockchain.SyncCheckResponse
+			peerCount int
+		}
+		output blockchain.ReliableTip
+		err    error
+	}{
+		"majority agrees": {
+			input: struct {
+				responses []blockchain.SyncCheckResponse
+				peerCount int
+			}{
+				responses: []blockchain.SyncCheckResponse{
+					response("peer3", honest),
+					response("peer4", divergent),
+					response("peer1", honest),
+					response("peer2", honest),
+				},
+				peerCount: 4,
+			},
+			output: blockchain.ReliableTip{
+				Height: 10,
+				Seal:   []byte("seal10"),
+				Peers: []blockchain.PeerId{
+					{Id: "peer1"}, {Id: "peer2"}, {Id: "peer3"},
+				},
+			},
+			err: nil,
+		},
+		"single divergent peer": {
+			input: struct {
+				responses []blockchain.SyncCheckResponse
+				peerCount int
+			}{
+				responses: []blockchain.SyncCheckResponse{
+					response("peer1", divergent),
+				},
+				peerCount: 4,
+			},
+			output: blockchain.ReliableTip{},
+			err:    blockchain.ErrNoReliableTip,
+		},
+		"split vote": {
+			input: struct {
+				responses []blockchain.SyncCheckResponse
+				peerCount int
+			}{
+				responses: []blockchain.SyncCheckResponse{
+					response("peer1", honest),
+					response("peer2", divergent),
+				},
+				peerCount: 2,
+			},
+			output: blockchain.ReliableTip{},
+			err:    blockchain.ErrNoReliableTip,
+		},
+		"duplicated response is counted once": {
+			input: struct {
+				responses []blockchain.SyncCheckResponse
+				peerCount int
+			}{
+				responses: []blockchain.SyncCheckResponse{
+					response("peer1", divergent),
+					response("peer1", divergent),
+				},
+				peerCount: 3,
+			},
+			output: blockchain.ReliableTip{},
+			err:    blockchain.ErrNoReliableTip,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+
+		tip, err := blockchain.SelectReliableTip(test.input.responses, test.input.peerCount)
+		assert.Equal(t, test.err, err)
+		assert.Equal(t, test.output, tip)
+	}
+}
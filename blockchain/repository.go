@@ -0,0 +1,12 @@
+package blockchain
+
+// BlockRepository 는 commit 된 block 들을 저장하고 조회한다.
+// BlockQueryApi 를 구현하므로 api gateway 와 다른 peer 의 block 요청에도 그대로 사용된다.
+type BlockRepository interface {
+	BlockQueryApi
+	AddBlock(block Block) error
+
+	// RemoveBlock 은 마지막 block 을 제거한다. reorg 에서 block 을 되돌릴 때 사용한다.
+	RemoveBlock(block Block) error
+	Close()
+}
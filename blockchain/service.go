@@ -10,3 +10,8 @@ type BlockQueryInnerService interface {
 	GetLastCommitedBlock() (Block, error)
 	GetCommitedBlockByHeight(height BlockHeight) (Block, error)
 }
+
+// SyncService 는 다른 peer 의 blockchain 으로부터 동기화를 시작한다.
+type SyncService interface {
+	Synchronize() error
+}
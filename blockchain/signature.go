@@ -0,0 +1,108 @@
+package blockchain
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrEmptyBlockSignature = errors.New("block signature is empty")
+var ErrInvalidBlockSignature = errors.New("block signature is invalid")
+var ErrPublicKeyNotFound = errors.New("public key of peer is not registered")
+var ErrSigningBlock = errors.New("Error in signing block")
+var ErrEmptyTxSignature = errors.New("transaction signature is empty")
+var ErrInvalidTxSignature = errors.New("transaction signature is invalid")
+
+// Signer 는 node 의 private key 로 data 에 서명한다.
+type Signer interface {
+	Sign(data []byte) ([]byte, error)
+}
+
+// SignatureVerifier 는 peer 의 등록된 public key 로 data 의 서명을 검증한다.
+// 등록된 public key 가 없으면 ErrPublicKeyNotFound 를 반환한다.
+type SignatureVerifier interface {
+	Verify(peerId PeerId, data []byte, signature []byte) (bool, error)
+}
+
+// PublicKeyRepository 는 peer 의 public key 를 PEM 형식으로 저장한다.
+type PublicKeyRepository interface {
+	Save(peerId PeerId, pubKey []byte) error
+	FindByPeerId(peerId PeerId) ([]byte, error)
+}
+
+// SignedBlock 은 creator 가 seal 에 서명한 block 이다.
+type SignedBlock interface {
+	GetSignature() []byte
+}
+
+// ValidateBlockSignature 함수는 block 의 서명이 creator 의 public key 로 검증되는지 확인한다.
+func ValidateBlockSignature(block Block, verifier SignatureVerifier) error {
+	signed, ok := block.(SignedBlock)
+	if !ok || len(signed.GetSignature()) == 0 {
+		return ErrEmptyBlockSignature
+	}
+
+	valid, err := verifier.Verify(PeerId{Id: string(block.GetCreator())}, block.GetSeal(), signed.GetSignature())
+	if err != nil {
+		return err
+	}
+
+	if !valid {
+		return ErrInvalidBlockSignature
+	}
+
+	return nil
+}
+
+// TxSignatureError 는 서명 검증에 실패한 transaction 과 그 원인을 알려준다.
+type TxSignatureError struct {
+	TxID string
+	Err  error
+}
+
+func (e *TxSignatureError) Error() string {
+	return fmt.Sprintf("transaction [%s]: %s", e.TxID, e.Err.Error())
+}
+
+// SubmittedTransaction 은 transaction 을 제출한 peer 또는 client 를 알려준다.
+type SubmittedTransaction interface {
+	GetPeerID() string
+}
+
+// ValidateTxSignatures 함수는 모든 transaction 의 서명이 제출자의 public key 로 검증되는지 확인한다.
+// 하나라도 실패하면 처음 실패한 transaction 의 TxSignatureError 를 반환한다.
+func ValidateTxSignatures(txList []Transaction, verifier SignatureVerifier) error {
+	for _, tx := range txList {
+		if err := validateTxSignature(tx, verifier); err != nil {
+			return &TxSignatureError{TxID: tx.GetID(), Err: err}
+		}
+	}
+
+	return nil
+}
+
+func validateTxSignature(tx Transaction, verifier SignatureVerifier) error {
+	submitted, ok := tx.(SubmittedTransaction)
+	if !ok {
+		return ErrTransactionType
+	}
+
+	if len(tx.GetSignature()) == 0 {
+		return ErrEmptyTxSignature
+	}
+
+	content, err := tx.GetContent()
+	if err != nil {
+		return err
+	}
+
+	valid, err := verifier.Verify(PeerId{Id: submitted.GetPeerID()}, content, tx.GetSignature())
+	if err != nil {
+		return err
+	}
+
+	if !valid {
+		return ErrInvalidTxSignature
+	}
+
+	return nil
+}
@@ -0,0 +1,254 @@
+package blockchain
+
+import (
+	"bytes"
+	"errors"
+	"sort"
+)
+
+var ErrSnapshotNotFound = errors.New("snapshot not found")
+var ErrSnapshotHeaderMismatch = errors.New("snapshot header does not match the header chain")
+var ErrEmptySnapshotSignature = errors.New("snapshot signature is empty")
+var ErrInvalidSnapshotSignature = errors.New("snapshot signature is invalid")
+var ErrSnapshotStateRootMismatch = errors.New("snapshot state does not match the state root of the header")
+var ErrSnapshotCreatorNotMember = errors.New("snapshot creator is not a parliament member")
+var ErrNoSnapshotVerifier = errors.New("signature verifier is not set to validate snapshot")
+var ErrStateRootMismatch = errors.New("block state root does not match the local state")
+
+// SnapshotConfig 는 snapshot 을 만드는 block height 의 간격이다. 0 이면 snapshot 을 만들지 않는다.
+type SnapshotConfig struct {
+	Interval BlockHeight
+}
+
+// IsSnapshotHeight 는 height 의 block 이 commit 되었을 때 snapshot 을 만들어야 하는지 확인한다.
+// genesis block 은 누구나 genesis 파일로 만들 수 있으므로 snapshot 을 만들지 않는다.
+func (c SnapshotConfig) IsSnapshotHeight(height BlockHeight) bool {
+	return c.Interval > 0 && height > 0 && height%c.Interval == 0
+}
+
+// ICodeState 는 snapshot 시점에 배포되어 있던 icode 이다.
+// snapshot 으로 시작한 node 는 같은 commit 의 icode 를 배포해서 같은 state 로 시작한다.
+type ICodeState struct {
+	ID         string
+	GitUrl     string
+	CommitHash string
+}
+
+// Snapshot 은 새 node 가 genesis 부터 block 을 다시 실행하지 않고 시작할 수 있도록
+// 한 height 의 signed header 와 icode state, parliament 를 담고 snapshot 을 만든 node 가 서명한 것이다.
+type Snapshot struct {
+	Header     SignedHeader
+	ICodes     []ICodeState
+	Parliament []string
+	Creator    []byte
+	Signature  []byte
+}
+
+// SnapshotRepository 는 node 가 만들거나 받은 snapshot 을 height 로 저장한다.
+type SnapshotRepository interface {
+	Save(snapshot Snapshot) error
+	GetSnapshot(height BlockHeight) (Snapshot, error)
+	GetLastSnapshot() (Snapshot, error)
+	Close()
+}
+
+// ICodeStateRepository 는 icode 의 meta event 로 현재 배포되어 있는 icode 들을 기록한다.
+type ICodeStateRepository interface {
+	SaveICodeState(state ICodeState) error
+	RemoveICodeState(id string) error
+	FindAllICodeStates() ([]ICodeState, error)
+}
+
+// SnapshotBlockRepository 는 snapshot 의 block 을 중간 block 없이 chain 의 마지막 block 으로 저장할 수 있는 BlockRepository 이다.
+type SnapshotBlockRepository interface {
+	GetBlockByHeight(height BlockHeight) (Block, error)
+	GetLastBlock() (Block, error)
+	AddSnapshotBlock(block Block) error
+}
+
+// NewSnapshot 함수는 block 의 header 와 icode state, parliament 로 snapshot 을 만들고 creator 의 key 로 서명한다.
+// icode 는 ID 순서로 정렬해서 같은 state 면 같은 hash 가 되도록 한다.
+func NewSnapshot(block Block, icodes []ICodeState, parliament []string, creator []byte, signer Signer) (Snapshot, error) {
+	snapshot := Snapshot{
+		Header:     NewSignedHeader(block),
+		ICodes:     sortICodeStates(icodes),
+		Parliament: append([]string{}, parliament...),
+		Creator:    creator,
+	}
+
+	signature, err := signer.Sign(snapshot.Hash())
+	if err != nil {
+		return Snapshot{}, err
+	}
+
+	snapshot.Signature = signature
+
+	return snapshot, nil
+}
+
+// Height 는 snapshot 이 담고 있는 block 의 height 이다.
+func (s Snapshot) Height() BlockHeight {
+	return s.Header.Height
+}
+
+// Bytes 함수는 snapshot 의 서명 대상을 고정된 순서의 binary 로 encoding 한다. Signature 는 제외한다.
+func (s Snapshot) Bytes() []byte {
+	buf := appendLengthPrefixed(nil, s.Header.Bytes())
+	buf = appendLengthPrefixed(buf, s.Header.Seal)
+	buf = appendLengthPrefixed(buf, s.Header.Signature)
+	buf = appendSnapshotState(buf, s.ICodes, s.Parliament)
+
+	return appendLengthPrefixed(buf, s.Creator)
+}
+
+func (s Snapshot) Hash() []byte {
+	return calculateHash(s.Bytes())
+}
+
+// StateRoot 는 snapshot 이 담고 있는 icode state 와 parliament 의 state root 이다.
+func (s Snapshot) StateRoot() []byte {
+	return SnapshotStateRoot(s.ICodes, s.Parliament)
+}
+
+// SnapshotStateRoot 함수는 icode state 와 parliament 의 hash 를 반환한다.
+// snapshot height 의 block 은 이 값을 header 의 StateRoot 에 담아서 consensus 가 합의한 state 로 만든다.
+// LegacyBlockVersion 의 seal 은 StateRoot 를 포함하지 않으므로 legacy block 으로는 snapshot 을 검증할 수 없다.
+func SnapshotStateRoot(icodes []ICodeState, parliament []string) []byte {
+	return calculateHash(appendSnapshotState(nil, sortICodeStates(icodes), parliament))
+}
+
+// StateRootValidator 는 snapshot height 의 block 을 commit 하기 전에 header 의 StateRoot 가
+// local icode state 와 parliament 로 계산한 state root 와 같은지 검증한다.
+// 동기화로 받는 지난 block 은 지금의 local state 와 비교할 수 없으므로 합의한 block 을 commit 할 때만 사용한다.
+type StateRootValidator struct {
+	config               SnapshotConfig
+	icodeStateRepository ICodeStateRepository
+	parliament           []string
+}
+
+func NewStateRootValidator(config SnapshotConfig, icodeStateRepository ICodeStateRepository, parliament []string) *StateRootValidator {
+	return &StateRootValidator{
+		config:               config,
+		icodeStateRepository: icodeStateRepository,
+		parliament:           append([]string{}, parliament...),
+	}
+}
+
+// ValidateStateRoot 는 snapshot height 가 아니거나 StateRoot 가 없는 LegacyBlockVersion 의 block 은 검증하지 않는다.
+func (v *StateRootValidator) ValidateStateRoot(block Block) error {
+	if !v.config.IsSnapshotHeight(block.GetHeight()) || blockVersion(block) == LegacyBlockVersion {
+		return nil
+	}
+
+	icodes, err := v.icodeStateRepository.FindAllICodeStates()
+	if err != nil {
+		return err
+	}
+
+	if !bytes.Equal(NewBlockHeader(block).StateRoot, SnapshotStateRoot(icodes, v.parliament)) {
+		return ErrStateRootMismatch
+	}
+
+	return nil
+}
+
+func appendSnapshotState(buf []byte, icodes []ICodeState, parliament []string) []byte {
+	buf = appendUint32(buf, uint32(len(icodes)))
+	for _, icode := range icodes {
+		buf = appendString(buf, icode.ID)
+		buf = appendString(buf, icode.GitUrl)
+		buf = appendString(buf, icode.CommitHash)
+	}
+
+	buf = appendUint32(buf, uint32(len(parliament)))
+	for _, member := range parliament {
+		buf = appendString(buf, member)
+	}
+
+	return buf
+}
+
+func sortICodeStates(icodes []ICodeState) []ICodeState {
+	sorted := append([]ICodeState{}, icodes...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].ID < sorted[j].ID
+	})
+
+	return sorted
+}
+
+// ValidateSnapshot 함수는 snapshot 의 header 가 node 가 검증한 header chain 의 header 와 같은지,
+// snapshot 의 icode state 와 parliament 가 그 header 의 StateRoot 와 같은지 확인하고 snapshot 을 만든 node 의 서명을 검증한다.
+// snapshot 을 만든 node 는 parliament 의 구성원이어야 하며, snapshot 이 스스로 담은 parliament 가 아니라 node 가 알고 있는 parliament 로 확인한다.
+// 서명 없이는 snapshot 을 믿을 수 없으므로 verifier 가 설정되지 않았으면 ErrNoSnapshotVerifier 를 반환한다.
+func (t *DefaultValidator) ValidateSnapshot(snapshot Snapshot, header SignedHeader, parliament []string) error {
+	if snapshot.Header.Height != header.Height || !bytes.Equal(snapshot.Header.Seal, header.Seal) {
+		return ErrSnapshotHeaderMismatch
+	}
+
+	// seal 이 같아도 header 의 다른 field 가 바뀌었을 수 있다.
+	seal, err := t.buildSignedHeaderSeal(snapshot.Header.BlockHeader)
+	if err != nil {
+		return err
+	}
+
+	if !bytes.Equal(seal, header.Seal) {
+		return ErrSnapshotHeaderMismatch
+	}
+
+	if !bytes.Equal(snapshot.StateRoot(), header.StateRoot) {
+		return ErrSnapshotStateRootMismatch
+	}
+
+	if !isParliamentMember(parliament, string(snapshot.Creator)) {
+		return ErrSnapshotCreatorNotMember
+	}
+
+	if t.verifier == nil {
+		return ErrNoSnapshotVerifier
+	}
+
+	if len(snapshot.Signature) == 0 {
+		return ErrEmptySnapshotSignature
+	}
+
+	valid, err := t.verifier.Verify(PeerId{Id: string(snapshot.Creator)}, snapshot.Hash(), snapshot.Signature)
+	if err != nil {
+		return err
+	}
+
+	if !valid {
+		return ErrInvalidSnapshotSignature
+	}
+
+	return nil
+}
+
+func isParliamentMember(parliament []string, peerId string) bool {
+	for _, member := range parliament {
+		if member == peerId {
+			return true
+		}
+	}
+
+	return false
+}
+
+// NewBlockFromHeader 함수는 snapshot 의 header 로 transaction 이 없는 block 을 만든다.
+// snapshot 으로 시작한 node 는 이 block 을 chain 의 시작으로 저장하고 다음 block 부터 동기화한다.
+// TxSeal 은 tx root 하나만 가지므로 seal 은 원래 block 과 같다.
+func NewBlockFromHeader(header SignedHeader) *DefaultBlock {
+	return &DefaultBlock{
+		Seal:         header.Seal,
+		PrevSeal:     header.PrevSeal,
+		Height:       header.Height,
+		TxList:       make([]*DefaultTransaction, 0),
+		TxSeal:       [][]byte{header.TxRoot},
+		Timestamp:    header.Timestamp,
+		Creator:      header.Creator,
+		Version:      header.Version,
+		StateRoot:    header.StateRoot,
+		ReceiptsRoot: header.ReceiptsRoot,
+		Signature:    header.Signature,
+	}
+}
@@ -0,0 +1,237 @@
+package blockchain_test
+
+import (
+	"testing"
+
+	"github.com/it-chain/engine/blockchain"
+	"github.com/it-chain/engine/blockchain/test/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnapshotConfig_IsSnapshotHeight(t *testing.T) {
+	tests := map[string]struct {
+		interval blockchain.BlockHeight
+		height   blockchain.BlockHeight
+		output   bool
+	}{
+		"snapshot height":     {interval: 10, height: 20, output: true},
+		"not snapshot height": {interval: 10, height: 21, output: false},
+		"genesis":             {interval: 10, height: 0, output: false},
+		"disabled":            {interval: 0, height: 20, output: false},
+	}
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+
+		config := blockchain.SnapshotConfig{Interval: test.interval}
+		assert.Equal(t, test.output, config.IsSnapshotHeight(test.height))
+	}
+}
+
+func TestStateRootValidator_ValidateStateRoot(t *testing.T) {
+	icodes := []blockchain.ICodeState{{ID: "icode1", GitUrl: "url", CommitHash: "hash"}}
+	parliament := []string{"peer1", "peer2"}
+
+	icodeStateRepository := mock.ICodeStateRepository{}
+	icodeStateRepository.FindAllICodeStatesFunc = func() ([]blockchain.ICodeState, error) {
+		return icodes, nil
+	}
+
+	tests := map[string]struct {
+		input blockchain.Block
+		err   error
+	}{
+		"same state": {
+			input: mock.NewSnapshotBlock(t, []byte("prevseal"), 10, blockchain.SnapshotStateRoot(icodes, parliament)),
+			err:   nil,
+		},
+		"different state": {
+			input: mock.NewSnapshotBlock(t, []byte("prevseal"), 10, blockchain.SnapshotStateRoot(nil, parliament)),
+			err:   blockchain.ErrStateRootMismatch,
+		},
+		"not snapshot height": {
+			input: mock.NewSnapshotBlock(t, []byte("prevseal"), 11, blockchain.SnapshotStateRoot(nil, parliament)),
+			err:   nil,
+		},
+		"legacy block": {
+			input: mock.NewBlock(t, []byte("prevseal"), 10, blockchain.LegacyBlockVersion),
+			err:   nil,
+		},
+	}
+
+	validator := blockchain.NewStateRootValidator(blockchain.SnapshotConfig{Interval: 10}, icodeStateRepository, parliament)
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+
+		// when
+		err := validator.ValidateStateRoot(test.input)
+
+		// then
+		assert.Equal(t, test.err, err)
+	}
+}
+
+func TestNewSnapshot(t *testing.T) {
+	// given
+	block := mock.NewBlock(t, []byte("prevseal"), 10, blockchain.CurrentBlockVersion)
+	icodes := []blockchain.ICodeState{{ID: "icode2"}, {ID: "icode1"}}
+
+	// when
+	snapshot, err := blockchain.NewSnapshot(block, icodes, []string{"peer1", "peer2"}, []byte("creator"), mock.NewSigner())
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(10), snapshot.Height())
+	assert.Equal(t, block.Seal, snapshot.Header.Seal)
+	assert.Equal(t, "icode1", snapshot.ICodes[0].ID)
+	assert.Equal(t, append([]byte("signed:"), snapshot.Hash()...), snapshot.Signature)
+	assert.Equal(t, blockchain.SnapshotStateRoot(icodes, []string{"peer1", "peer2"}), snapshot.StateRoot())
+
+	// icode 의 순서가 달라도 같은 snapshot 이다.
+	other, err := blockchain.NewSnapshot(block, []blockchain.ICodeState{{ID: "icode1"}, {ID: "icode2"}}, []string{"peer1", "peer2"}, []byte("creator"), mock.NewSigner())
+	assert.NoError(t, err)
+	assert.Equal(t, snapshot.Hash(), other.Hash())
+}
+
+func TestDefaultValidator_ValidateSnapshot(t *testing.T) {
+	icodes := []blockchain.ICodeState{{ID: "icode1", CommitHash: "hash"}}
+	parliament := []string{"creator", "peer1"}
+	block := mock.NewSnapshotBlock(t, []byte("prevseal"), 10, blockchain.SnapshotStateRoot(icodes, parliament))
+	header := blockchain.NewSignedHeader(block)
+
+	newSnapshot := func(block blockchain.Block, creator string) blockchain.Snapshot {
+		snapshot, err := blockchain.NewSnapshot(block, icodes, parliament, []byte(creator), mock.NewSigner())
+		assert.NoError(t, err)
+		return snapshot
+	}
+
+	tests := map[string]struct {
+		input func() blockchain.Snapshot
+		err   error
+	}{
+		"success": {
+			input: func() blockchain.Snapshot {
+				return newSnapshot(block, "creator")
+			},
+			err: nil,
+		},
+		"other block": {
+			input: func() blockchain.Snapshot {
+				return newSnapshot(mock.NewSnapshotBlock(t, []byte("otherseal"), 10, blockchain.SnapshotStateRoot(icodes, parliament)), "creator")
+			},
+			err: blockchain.ErrSnapshotHeaderMismatch,
+		},
+		"header changed": {
+			input: func() blockchain.Snapshot {
+				snapshot := newSnapshot(block, "creator")
+				snapshot.Header.StateRoot = []byte("otherroot")
+				return snapshot
+			},
+			err: blockchain.ErrSnapshotHeaderMismatch,
+		},
+		"icode changed": {
+			input: func() blockchain.Snapshot {
+				snapshot := newSnapshot(block, "creator")
+				snapshot.ICodes = []blockchain.ICodeState{{ID: "icode1", CommitHash: "forged"}}
+				return snapshot
+			},
+			err: blockchain.ErrSnapshotStateRootMismatch,
+		},
+		"parliament changed": {
+			input: func() blockchain.Snapshot {
+				snapshot := newSnapshot(block, "creator")
+				snapshot.Parliament = []string{"attacker"}
+				return snapshot
+			},
+			err: blockchain.ErrSnapshotStateRootMismatch,
+		},
+		"creator not parliament member": {
+			input: func() blockchain.Snapshot {
+				return newSnapshot(block, "attacker")
+			},
+			err: blockchain.ErrSnapshotCreatorNotMember,
+		},
+		"creator key not registered": {
+			input: func() blockchain.Snapshot {
+				return newSnapshot(block, "peer1")
+			},
+			err: blockchain.ErrPublicKeyNotFound,
+		},
+		"empty signature": {
+			input: func() blockchain.Snapshot {
+				snapshot := newSnapshot(block, "creator")
+				snapshot.Signature = nil
+				return snapshot
+			},
+			err: blockchain.ErrEmptySnapshotSignature,
+		},
+		"invalid signature": {
+			input: func() blockchain.Snapshot {
+				snapshot := newSnapshot(block, "creator")
+				snapshot.Signature = []byte("forged")
+				return snapshot
+			},
+			err: blockchain.ErrInvalidSnapshotSignature,
+		},
+	}
+
+	verifier := mock.SignatureVerifier{}
+	verifier.VerifyFunc = func(peerId blockchain.PeerId, data []byte, signature []byte) (bool, error) {
+		if peerId.Id != "creator" {
+			return false, blockchain.ErrPublicKeyNotFound
+		}
+		return string(signature) == "signed:"+string(data), nil
+	}
+
+	validator := blockchain.DefaultValidator{}
+	validator.SetSignatureVerifier(verifier)
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+
+		err := validator.ValidateSnapshot(test.input(), header, parliament)
+		assert.Equal(t, test.err, err)
+	}
+}
+
+func TestDefaultValidator_ValidateSnapshot_NoVerifier(t *testing.T) {
+	// given
+	parliament := []string{"creator"}
+	block := mock.NewSnapshotBlock(t, []byte("prevseal"), 10, blockchain.SnapshotStateRoot(nil, parliament))
+	snapshot, err := blockchain.NewSnapshot(block, nil, parliament, []byte("creator"), mock.NewSigner())
+	assert.NoError(t, err)
+
+	validator := blockchain.DefaultValidator{}
+
+	// when
+	err = validator.ValidateSnapshot(snapshot, blockchain.NewSignedHeader(block), parliament)
+
+	// then: 서명을 검증할 수 없으면 snapshot 을 받지 않는다.
+	assert.Equal(t, blockchain.ErrNoSnapshotVerifier, err)
+}
+
+func TestNewBlockFromHeader(t *testing.T) {
+	validator := blockchain.DefaultValidator{}
+
+	tests := map[string]blockchain.BlockVersion{
+		"legacy":  blockchain.LegacyBlockVersion,
+		"current": blockchain.CurrentBlockVersion,
+	}
+
+	for testName, version := range tests {
+		t.Logf("running test case %s", testName)
+
+		// given
+		block := mock.NewBlock(t, []byte("prevseal"), 10, version)
+
+		// when
+		headerBlock := blockchain.NewBlockFromHeader(blockchain.NewSignedHeader(block))
+
+		// then
+		seal, err := validator.BuildBlockSeal(headerBlock)
+		assert.NoError(t, err)
+		assert.Equal(t, block.Seal, seal)
+		assert.Equal(t, 0, len(headerBlock.GetTxList()))
+	}
+}
@@ -15,10 +15,77 @@ func (api BlockApi) CheckAndSaveBlockFromPool(height blockchain.BlockHeight) err
 	return api.CheckAndSaveBlockFromPoolFunc(height)
 }
 
+type BlockProposeApi struct {
+	ProposeBlockFunc func(txList []blockchain.Transaction) error
+}
+
+func (api BlockProposeApi) ProposeBlock(txList []blockchain.Transaction) error {
+	return api.ProposeBlockFunc(txList)
+}
+
 type MockSyncBlockApi struct {
-	SyncedCheckFunc func(block blockchain.Block) error
+	SyncedCheckFunc func(peerId blockchain.PeerId, block blockchain.Block) error
+	SyncBlockFunc   func(peerId blockchain.PeerId, block blockchain.Block) error
+	SyncBlocksFunc  func(peerId blockchain.PeerId, response blockchain.BlockRangeResponse) error
+}
+
+func (ba MockSyncBlockApi) SyncedCheck(peerId blockchain.PeerId, block blockchain.Block) error {
+	return ba.SyncedCheckFunc(peerId, block)
+}
+
+func (ba MockSyncBlockApi) SyncBlock(peerId blockchain.PeerId, block blockchain.Block) error {
+	return ba.SyncBlockFunc(peerId, block)
+}
+
+func (ba MockSyncBlockApi) SyncBlocks(peerId blockchain.PeerId, response blockchain.BlockRangeResponse) error {
+	return ba.SyncBlocksFunc(peerId, response)
+}
+
+type LightSyncApi struct {
+	SyncHeaderRangeFunc func(peerId blockchain.PeerId, headers []blockchain.SignedHeader) error
+	ReceiveTxProofFunc  func(response blockchain.TxProofResponse) error
+}
+
+func (api LightSyncApi) SyncHeaderRange(peerId blockchain.PeerId, headers []blockchain.SignedHeader) error {
+	return api.SyncHeaderRangeFunc(peerId, headers)
+}
+
+func (api LightSyncApi) ReceiveTxProof(response blockchain.TxProofResponse) error {
+	return api.ReceiveTxProofFunc(response)
+}
+
+type SnapshotApi struct {
+	TakeSnapshotFunc     func(height blockchain.BlockHeight) error
+	SaveICodeStateFunc   func(state blockchain.ICodeState) error
+	RemoveICodeStateFunc func(id string) error
+	GetLastSnapshotFunc  func() (blockchain.Snapshot, error)
+	ReceiveSnapshotFunc  func(peerId blockchain.PeerId, snapshot blockchain.Snapshot) (bool, error)
+}
+
+func (api SnapshotApi) TakeSnapshot(height blockchain.BlockHeight) error {
+	return api.TakeSnapshotFunc(height)
+}
+
+func (api SnapshotApi) SaveICodeState(state blockchain.ICodeState) error {
+	return api.SaveICodeStateFunc(state)
+}
+
+func (api SnapshotApi) RemoveICodeState(id string) error {
+	return api.RemoveICodeStateFunc(id)
+}
+
+func (api SnapshotApi) GetLastSnapshot() (blockchain.Snapshot, error) {
+	return api.GetLastSnapshotFunc()
+}
+
+func (api SnapshotApi) ReceiveSnapshot(peerId blockchain.PeerId, snapshot blockchain.Snapshot) (bool, error) {
+	return api.ReceiveSnapshotFunc(peerId, snapshot)
+}
+
+type Synchronizer struct {
+	SynchronizeFunc func() error
 }
 
-func (ba MockSyncBlockApi) SyncedCheck(block blockchain.Block) error {
-	return ba.SyncedCheckFunc(block)
+func (s Synchronizer) Synchronize() error {
+	return s.SynchronizeFunc()
 }
@@ -0,0 +1,133 @@
+package mock
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/it-chain/engine/blockchain"
+)
+
+// NewSigner 는 data 앞에 "signed:" 를 붙인 값을 서명으로 반환한다.
+func NewSigner() Signer {
+	signer := Signer{}
+	signer.SignFunc = func(data []byte) ([]byte, error) {
+		return append([]byte("signed:"), data...), nil
+	}
+
+	return signer
+}
+
+// NewBlock 은 transaction "tx1" 을 담고 version 으로 tx seal 과 seal 을 계산한 block 을 만든다.
+func NewBlock(t *testing.T, prevSeal []byte, height uint64, version blockchain.BlockVersion) *blockchain.DefaultBlock {
+	validator := blockchain.DefaultValidator{}
+
+	tx := &blockchain.DefaultTransaction{ID: "tx1"}
+	txSeal, err := validator.BuildTxSealOf(version, []blockchain.Transaction{tx})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	block := &blockchain.DefaultBlock{
+		PrevSeal:  prevSeal,
+		Height:    height,
+		TxList:    []*blockchain.DefaultTransaction{tx},
+		TxSeal:    txSeal,
+		Timestamp: time.Now().Round(0),
+		Creator:   []byte("creator"),
+		Version:   version,
+	}
+
+	seal, err := validator.BuildBlockSeal(block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	block.Seal = seal
+
+	return block
+}
+
+// NewSnapshotBlock 은 header 의 StateRoot 에 stateRoot 를 담은 CurrentBlockVersion 의 block 을 만든다.
+func NewSnapshotBlock(t *testing.T, prevSeal []byte, height uint64, stateRoot []byte) *blockchain.DefaultBlock {
+	block := NewBlock(t, prevSeal, height, blockchain.CurrentBlockVersion)
+	block.StateRoot = stateRoot
+
+	validator := blockchain.DefaultValidator{}
+	seal, err := validator.BuildBlockSeal(block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	block.Seal = seal
+
+	return block
+}
+
+// NewLegacyBlock 은 txList 를 담은 LegacyBlockVersion 의 block 을 만든다. txList 가 없으면 transaction "tx" 하나를 담는다.
+func NewLegacyBlock(t *testing.T, prevSeal []byte, height uint64, txList ...*blockchain.DefaultTransaction) *blockchain.DefaultBlock {
+	validator := blockchain.DefaultValidator{}
+
+	if len(txList) == 0 {
+		txList = []*blockchain.DefaultTransaction{{ID: "tx"}}
+	}
+
+	txs := make([]blockchain.Transaction, 0, len(txList))
+	for _, tx := range txList {
+		txs = append(txs, tx)
+	}
+
+	txSeal, err := validator.BuildTxSealOf(blockchain.LegacyBlockVersion, txs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	timestamp := time.Now().Round(0)
+	seal, err := validator.BuildSeal(timestamp, append([]byte{}, prevSeal...), txSeal, []byte("creator"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &blockchain.DefaultBlock{
+		Seal:      seal,
+		PrevSeal:  prevSeal,
+		Height:    height,
+		TxList:    txList,
+		TxSeal:    txSeal,
+		Timestamp: timestamp,
+		Creator:   []byte("creator"),
+	}
+}
+
+// NewChain 은 config 의 genesis block 으로 시작하는 length 개의 block 으로 된 chain 을 만든다.
+func NewChain(t *testing.T, config blockchain.GenesisConfig, length int) []blockchain.Block {
+	genesis, err := blockchain.NewGenesisBlock(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	chain := []blockchain.Block{genesis}
+	for height := 1; height < length; height++ {
+		prev := chain[len(chain)-1]
+		chain = append(chain, NewBlock(t, prev.GetSeal(), uint64(height), blockchain.CurrentBlockVersion))
+	}
+
+	return chain
+}
+
+// NewStoredBlock 은 seal 을 계산하지 않고 "seal{height-1}" 에 이어지는 "seal{height}" block 을 만든다.
+// block 을 검증하지 않는 repository 에 저장할 때 사용한다.
+func NewStoredBlock(height uint64) *blockchain.DefaultBlock {
+	prevSeal := []byte("")
+	if height > 0 {
+		prevSeal = []byte(fmt.Sprintf("seal%d", height-1))
+	}
+
+	return &blockchain.DefaultBlock{
+		Seal:     []byte(fmt.Sprintf("seal%d", height)),
+		PrevSeal: prevSeal,
+		Height:   height,
+		TxList: []*blockchain.DefaultTransaction{
+			{ID: fmt.Sprintf("tx%d", height), PeerID: "peer1", Signature: []byte("signature")},
+		},
+		TxSeal: [][]byte{[]byte(fmt.Sprintf("root%d", height))},
+	}
+}
@@ -0,0 +1,26 @@
+package mock
+
+import "github.com/it-chain/engine/blockchain"
+
+type CommandService struct {
+	SendBlockValidateCommandFunc     func(block blockchain.Block) error
+	SendBlockExecuteCommandFunc      func(block blockchain.Block) error
+	SendResetBlockExecuteCommandFunc func(block blockchain.Block) error
+	SendICodeDeployCommandFunc       func(icode blockchain.ICodeState) error
+}
+
+func (cs CommandService) SendBlockValidateCommand(block blockchain.Block) error {
+	return cs.SendBlockValidateCommandFunc(block)
+}
+
+func (cs CommandService) SendBlockExecuteCommand(block blockchain.Block) error {
+	return cs.SendBlockExecuteCommandFunc(block)
+}
+
+func (cs CommandService) SendResetBlockExecuteCommand(block blockchain.Block) error {
+	return cs.SendResetBlockExecuteCommandFunc(block)
+}
+
+func (cs CommandService) SendICodeDeployCommand(icode blockchain.ICodeState) error {
+	return cs.SendICodeDeployCommandFunc(icode)
+}
@@ -1,11 +1,17 @@
 package mock
 
-import "github.com/it-chain/midgard"
+import (
+	"reflect"
+	"sync"
+
+	"github.com/it-chain/midgard"
+)
 
 type EventRepository struct {
-	LoadFunc  func(aggregate midgard.Aggregate, aggregateID string) error
-	SaveFunc  func(aggregateID string, events ...midgard.Event) error
-	CloseFunc func()
+	LoadFunc   func(aggregate midgard.Aggregate, aggregateID string) error
+	SaveFunc   func(aggregateID string, events ...midgard.Event) error
+	DeleteFunc func(aggregateID string) error
+	CloseFunc  func()
 }
 
 func (er EventRepository) Load(aggregate midgard.Aggregate, aggregateID string) error {
@@ -14,6 +20,71 @@ func (er EventRepository) Load(aggregate midgard.Aggregate, aggregateID string)
 func (er EventRepository) Save(aggregateID string, events ...midgard.Event) error {
 	return er.SaveFunc(aggregateID, events...)
 }
+
+// Delete 는 DeleteFunc 가 없으면 아무것도 지우지 않는다.
+func (er EventRepository) Delete(aggregateID string) error {
+	if er.DeleteFunc == nil {
+		return nil
+	}
+	return er.DeleteFunc(aggregateID)
+}
+
 func (er EventRepository) Close() {
 	er.CloseFunc()
 }
+
+// EventLog 는 저장된 event 를 aggregate 별로 기억했다가 Load 에서 순서대로 다시 적용한다.
+// eventstore 의 serializer 처럼 event 를 pointer 로 복원하므로 재시작을 흉내낼 수 있다.
+type EventLog struct {
+	mutex  *sync.Mutex
+	events map[string][]midgard.Event
+}
+
+func NewEventLog() *EventLog {
+	return &EventLog{
+		mutex:  &sync.Mutex{},
+		events: make(map[string][]midgard.Event),
+	}
+}
+
+func (l *EventLog) Load(aggregate midgard.Aggregate, aggregateID string) error {
+	l.mutex.Lock()
+	events := append([]midgard.Event{}, l.events[aggregateID]...)
+	l.mutex.Unlock()
+
+	for _, event := range events {
+		if err := aggregate.On(event); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (l *EventLog) Save(aggregateID string, events ...midgard.Event) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	for _, event := range events {
+		value := reflect.ValueOf(event)
+		if value.Kind() != reflect.Ptr {
+			pointer := reflect.New(value.Type())
+			pointer.Elem().Set(value)
+			event = pointer.Interface().(midgard.Event)
+		}
+
+		l.events[aggregateID] = append(l.events[aggregateID], event)
+	}
+
+	return nil
+}
+
+func (l *EventLog) Delete(aggregateID string) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	delete(l.events, aggregateID)
+	return nil
+}
+
+func (l *EventLog) Close() {}
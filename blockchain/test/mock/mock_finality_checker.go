@@ -0,0 +1,24 @@
+package mock
+
+import "github.com/it-chain/engine/blockchain"
+
+type FinalityChecker struct {
+	IsFinalFunc func(block blockchain.Block) bool
+}
+
+func (c FinalityChecker) IsFinal(block blockchain.Block) bool {
+	return c.IsFinalFunc(block)
+}
+
+type FinalityRecord struct {
+	SaveFinalSealFunc func(seal []byte) error
+	IsFinalSealFunc   func(seal []byte) (bool, error)
+}
+
+func (r FinalityRecord) SaveFinalSeal(seal []byte) error {
+	return r.SaveFinalSealFunc(seal)
+}
+
+func (r FinalityRecord) IsFinalSeal(seal []byte) (bool, error) {
+	return r.IsFinalSealFunc(seal)
+}
@@ -3,13 +3,87 @@ package mock
 import "github.com/it-chain/engine/blockchain"
 
 type SyncCheckGrpcCommandService struct {
-	SyncCheckResponseFunc func(block blockchain.Block) error
-	ResponseBlockFunc     func(peerId blockchain.PeerId, block blockchain.Block) error
+	SyncCheckResponseFunc   func(peerId blockchain.PeerId, block blockchain.Block) error
+	ResponseBlockFunc       func(peerId blockchain.PeerId, block blockchain.Block) error
+	ResponseBlockRangeFunc  func(peerId blockchain.PeerId, response blockchain.BlockRangeResponse) error
+	ResponseHeaderRangeFunc func(peerId blockchain.PeerId, headers []blockchain.SignedHeader) error
+	ResponseTxProofFunc     func(peerId blockchain.PeerId, response blockchain.TxProofResponse) error
 }
 
-func (cs SyncCheckGrpcCommandService) SyncCheckResponse(block blockchain.Block) error {
-	return cs.SyncCheckResponseFunc(block)
+func (cs SyncCheckGrpcCommandService) SyncCheckResponse(peerId blockchain.PeerId, block blockchain.Block) error {
+	return cs.SyncCheckResponseFunc(peerId, block)
 }
 func (cs SyncCheckGrpcCommandService) ResponseBlock(peerId blockchain.PeerId, block blockchain.Block) error {
 	return cs.ResponseBlockFunc(peerId, block)
 }
+
+func (cs SyncCheckGrpcCommandService) ResponseBlockRange(peerId blockchain.PeerId, response blockchain.BlockRangeResponse) error {
+	return cs.ResponseBlockRangeFunc(peerId, response)
+}
+
+func (cs SyncCheckGrpcCommandService) ResponseHeaderRange(peerId blockchain.PeerId, headers []blockchain.SignedHeader) error {
+	return cs.ResponseHeaderRangeFunc(peerId, headers)
+}
+
+func (cs SyncCheckGrpcCommandService) ResponseTxProof(peerId blockchain.PeerId, response blockchain.TxProofResponse) error {
+	return cs.ResponseTxProofFunc(peerId, response)
+}
+
+type LightGrpcCommandService struct {
+	RequestHeaderRangeFunc func(peerId blockchain.PeerId, from blockchain.BlockHeight, to blockchain.BlockHeight) error
+	RequestTxProofFunc     func(peerId blockchain.PeerId, txID string) error
+}
+
+func (cs LightGrpcCommandService) RequestHeaderRange(peerId blockchain.PeerId, from blockchain.BlockHeight, to blockchain.BlockHeight) error {
+	return cs.RequestHeaderRangeFunc(peerId, from, to)
+}
+
+func (cs LightGrpcCommandService) RequestTxProof(peerId blockchain.PeerId, txID string) error {
+	return cs.RequestTxProofFunc(peerId, txID)
+}
+
+type GrpcCommandService struct {
+	RequestBlockFunc       func(peerId blockchain.PeerId, height uint64) error
+	ResponseBlockFunc      func(peerId blockchain.PeerId, block blockchain.Block) error
+	RequestBlockRangeFunc  func(peerId blockchain.PeerId, from blockchain.BlockHeight, to blockchain.BlockHeight) error
+	ResponseBlockRangeFunc func(peerId blockchain.PeerId, response blockchain.BlockRangeResponse) error
+	SyncCheckRequestFunc   func(peerId blockchain.PeerId) error
+	SyncCheckResponseFunc  func(peerId blockchain.PeerId, block blockchain.Block) error
+}
+
+func (cs GrpcCommandService) RequestBlock(peerId blockchain.PeerId, height uint64) error {
+	return cs.RequestBlockFunc(peerId, height)
+}
+
+func (cs GrpcCommandService) ResponseBlock(peerId blockchain.PeerId, block blockchain.Block) error {
+	return cs.ResponseBlockFunc(peerId, block)
+}
+
+func (cs GrpcCommandService) RequestBlockRange(peerId blockchain.PeerId, from blockchain.BlockHeight, to blockchain.BlockHeight) error {
+	return cs.RequestBlockRangeFunc(peerId, from, to)
+}
+
+func (cs GrpcCommandService) ResponseBlockRange(peerId blockchain.PeerId, response blockchain.BlockRangeResponse) error {
+	return cs.ResponseBlockRangeFunc(peerId, response)
+}
+
+func (cs GrpcCommandService) SyncCheckRequest(peerId blockchain.PeerId) error {
+	return cs.SyncCheckRequestFunc(peerId)
+}
+
+func (cs GrpcCommandService) SyncCheckResponse(peerId blockchain.PeerId, block blockchain.Block) error {
+	return cs.SyncCheckResponseFunc(peerId, block)
+}
+
+type SnapshotGrpcCommandService struct {
+	RequestSnapshotFunc  func(peerId blockchain.PeerId) error
+	ResponseSnapshotFunc func(peerId blockchain.PeerId, snapshot blockchain.Snapshot) error
+}
+
+func (cs SnapshotGrpcCommandService) RequestSnapshot(peerId blockchain.PeerId) error {
+	return cs.RequestSnapshotFunc(peerId)
+}
+
+func (cs SnapshotGrpcCommandService) ResponseSnapshot(peerId blockchain.PeerId, snapshot blockchain.Snapshot) error {
+	return cs.ResponseSnapshotFunc(peerId, snapshot)
+}
@@ -0,0 +1,90 @@
+package mock
+
+import "github.com/it-chain/engine/blockchain"
+
+type BlockRepository struct {
+	BlockQueryApi
+	AddBlockFunc    func(block blockchain.Block) error
+	RemoveBlockFunc func(block blockchain.Block) error
+	CloseFunc       func()
+}
+
+func (br BlockRepository) AddBlock(block blockchain.Block) error {
+	return br.AddBlockFunc(block)
+}
+
+func (br BlockRepository) RemoveBlock(block blockchain.Block) error {
+	return br.RemoveBlockFunc(block)
+}
+
+func (br BlockRepository) Close() {
+	br.CloseFunc()
+}
+
+type PeerRepository struct {
+	AddFunc     func(peer blockchain.Peer) error
+	RemoveFunc  func(id blockchain.PeerId) error
+	FindAllFunc func() ([]blockchain.Peer, error)
+}
+
+func (r PeerRepository) Add(peer blockchain.Peer) error {
+	return r.AddFunc(peer)
+}
+
+func (r PeerRepository) Remove(id blockchain.PeerId) error {
+	return r.RemoveFunc(id)
+}
+
+func (r PeerRepository) FindAll() ([]blockchain.Peer, error) {
+	return r.FindAllFunc()
+}
+
+type SnapshotBlockRepository struct {
+	BlockQueryApi
+	AddSnapshotBlockFunc func(block blockchain.Block) error
+}
+
+func (br SnapshotBlockRepository) AddSnapshotBlock(block blockchain.Block) error {
+	return br.AddSnapshotBlockFunc(block)
+}
+
+type SnapshotRepository struct {
+	SaveFunc            func(snapshot blockchain.Snapshot) error
+	GetSnapshotFunc     func(height blockchain.BlockHeight) (blockchain.Snapshot, error)
+	GetLastSnapshotFunc func() (blockchain.Snapshot, error)
+	CloseFunc           func()
+}
+
+func (r SnapshotRepository) Save(snapshot blockchain.Snapshot) error {
+	return r.SaveFunc(snapshot)
+}
+
+func (r SnapshotRepository) GetSnapshot(height blockchain.BlockHeight) (blockchain.Snapshot, error) {
+	return r.GetSnapshotFunc(height)
+}
+
+func (r SnapshotRepository) GetLastSnapshot() (blockchain.Snapshot, error) {
+	return r.GetLastSnapshotFunc()
+}
+
+func (r SnapshotRepository) Close() {
+	r.CloseFunc()
+}
+
+type ICodeStateRepository struct {
+	SaveICodeStateFunc     func(state blockchain.ICodeState) error
+	RemoveICodeStateFunc   func(id string) error
+	FindAllICodeStatesFunc func() ([]blockchain.ICodeState, error)
+}
+
+func (r ICodeStateRepository) SaveICodeState(state blockchain.ICodeState) error {
+	return r.SaveICodeStateFunc(state)
+}
+
+func (r ICodeStateRepository) RemoveICodeState(id string) error {
+	return r.RemoveICodeStateFunc(id)
+}
+
+func (r ICodeStateRepository) FindAllICodeStates() ([]blockchain.ICodeState, error) {
+	return r.FindAllICodeStatesFunc()
+}
@@ -0,0 +1,19 @@
+package mock
+
+import "github.com/it-chain/engine/blockchain"
+
+type Signer struct {
+	SignFunc func(data []byte) ([]byte, error)
+}
+
+func (s Signer) Sign(data []byte) ([]byte, error) {
+	return s.SignFunc(data)
+}
+
+type SignatureVerifier struct {
+	VerifyFunc func(peerId blockchain.PeerId, data []byte, signature []byte) (bool, error)
+}
+
+func (v SignatureVerifier) Verify(peerId blockchain.PeerId, data []byte, signature []byte) (bool, error) {
+	return v.VerifyFunc(peerId, data, signature)
+}
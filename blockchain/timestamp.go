@@ -0,0 +1,50 @@
+package blockchain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrTimestampNotIncreasing = errors.New("block timestamp is not after the previous block timestamp")
+var ErrTimestampTooFarAhead = errors.New("block timestamp is too far ahead of local time")
+
+// TimestampError 는 timestamp 검증에 실패한 block 과 그 원인을 알려준다.
+// Bound 는 ErrTimestampNotIncreasing 이면 이전 block 의 timestamp, ErrTimestampTooFarAhead 이면 허용하는 가장 늦은 시각이다.
+type TimestampError struct {
+	Height    BlockHeight
+	Timestamp time.Time
+	Bound     time.Time
+	Err       error
+}
+
+func (e *TimestampError) Error() string {
+	return fmt.Sprintf("block [%d] timestamp [%s] (bound [%s]): %s", e.Height, e.Timestamp.Format(time.RFC3339Nano), e.Bound.Format(time.RFC3339Nano), e.Err.Error())
+}
+
+// SetMaxTimestampDrift 는 block timestamp 가 local 시각보다 앞설 수 있는 최대 시간을 설정한다. 0 이면 제한하지 않는다.
+func (t *DefaultValidator) SetMaxTimestampDrift(drift time.Duration) {
+	t.maxDrift = drift
+}
+
+// ValidateTimestamp 함수는 block 의 timestamp 가 prevBlock 의 timestamp 보다 늦고, local 시각보다 최대 drift 이상 앞서지 않는지 검증한다.
+// 실패하면 *TimestampError 를 반환한다.
+func (t *DefaultValidator) ValidateTimestamp(block Block, prevBlock Block) error {
+	return t.validateTimestamp(block.GetHeight(), block.GetTimestamp(), prevBlock.GetTimestamp(), time.Now())
+}
+
+func (t *DefaultValidator) validateTimestamp(height BlockHeight, timestamp time.Time, prevTimestamp time.Time, now time.Time) error {
+	if !timestamp.After(prevTimestamp) {
+		return &TimestampError{Height: height, Timestamp: timestamp, Bound: prevTimestamp, Err: ErrTimestampNotIncreasing}
+	}
+
+	if t.maxDrift <= 0 {
+		return nil
+	}
+
+	if bound := now.Add(t.maxDrift); timestamp.After(bound) {
+		return &TimestampError{Height: height, Timestamp: timestamp, Bound: bound, Err: ErrTimestampTooFarAhead}
+	}
+
+	return nil
+}
@@ -0,0 +1,126 @@
+package blockchain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/it-chain/engine/blockchain"
+	"github.com/it-chain/engine/blockchain/test/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultValidator_ValidateTimestamp(t *testing.T) {
+	now := time.Now().Round(0)
+	prevBlock := &blockchain.DefaultBlock{Height: 3, Timestamp: now.Add(-time.Minute)}
+
+	tests := map[string]struct {
+		input struct {
+			timestamp time.Time
+			maxDrift  time.Duration
+		}
+		err error
+	}{
+		"success": {
+			input: struct {
+				timestamp time.Time
+				maxDrift  time.Duration
+			}{timestamp: now, maxDrift: time.Minute},
+			err: nil,
+		},
+		"same as previous block": {
+			input: struct {
+				timestamp time.Time
+				maxDrift  time.Duration
+			}{timestamp: prevBlock.Timestamp, maxDrift: time.Minute},
+			err: blockchain.ErrTimestampNotIncreasing,
+		},
+		"earlier than previous block": {
+			input: struct {
+				timestamp time.Time
+				maxDrift  time.Duration
+			}{timestamp: prevBlock.Timestamp.Add(-time.Second), maxDrift: time.Minute},
+			err: blockchain.ErrTimestampNotIncreasing,
+		},
+		"too far ahead": {
+			input: struct {
+				timestamp time.Time
+				maxDrift  time.Duration
+			}{timestamp: now.Add(time.Hour), maxDrift: time.Minute},
+			err: blockchain.ErrTimestampTooFarAhead,
+		},
+		"no drift limit": {
+			input: struct {
+				timestamp time.Time
+				maxDrift  time.Duration
+			}{timestamp: now.Add(time.Hour), maxDrift: 0},
+			err: nil,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+
+		// given
+		validator := blockchain.DefaultValidator{}
+		validator.SetMaxTimestampDrift(test.input.maxDrift)
+		block := &blockchain.DefaultBlock{Height: 4, Timestamp: test.input.timestamp}
+
+		// when
+		err := validator.ValidateTimestamp(block, prevBlock)
+
+		// then
+		if test.err == nil {
+			assert.NoError(t, err)
+			continue
+		}
+
+		timestampErr, ok := err.(*blockchain.TimestampError)
+		assert.True(t, ok)
+		assert.Equal(t, test.err, timestampErr.Err)
+		assert.Equal(t, uint64(4), timestampErr.Height)
+		assert.Equal(t, test.input.timestamp, timestampErr.Timestamp)
+	}
+}
+
+func TestDefaultValidator_ValidateBlock_Timestamp(t *testing.T) {
+	// given
+	validator := blockchain.DefaultValidator{}
+	validator.SetMaxTimestampDrift(time.Minute)
+
+	block := mock.NewBlock(t, []byte("prevseal"), 4, blockchain.CurrentBlockVersion)
+	prevBlock := &blockchain.DefaultBlock{Seal: []byte("prevseal"), Height: 3, Timestamp: block.Timestamp.Add(time.Second)}
+
+	// when
+	err := validator.ValidateBlock(block, prevBlock)
+
+	// then
+	timestampErr, ok := err.(*blockchain.TimestampError)
+	assert.True(t, ok)
+	assert.Equal(t, blockchain.ErrTimestampNotIncreasing, timestampErr.Err)
+	assert.Equal(t, prevBlock.Timestamp, timestampErr.Bound)
+}
+
+func TestDefaultValidator_ValidateHeader_Timestamp(t *testing.T) {
+	// given
+	validator := blockchain.DefaultValidator{}
+	validator.SetMaxTimestampDrift(time.Minute)
+
+	prevHeader := blockchain.SignedHeader{
+		BlockHeader: blockchain.BlockHeader{Height: 3, Version: blockchain.HeaderBlockVersion},
+		Seal:        []byte("prevseal"),
+	}
+
+	block := mock.NewBlock(t, prevHeader.Seal, 4, blockchain.CurrentBlockVersion)
+	block.Timestamp = time.Now().Add(time.Hour).Round(0)
+	seal, err := validator.BuildBlockSeal(block)
+	assert.NoError(t, err)
+	block.Seal = seal
+
+	// when
+	err = validator.ValidateHeader(blockchain.NewSignedHeader(block), prevHeader)
+
+	// then
+	timestampErr, ok := err.(*blockchain.TimestampError)
+	assert.True(t, ok)
+	assert.Equal(t, blockchain.ErrTimestampTooFarAhead, timestampErr.Err)
+}
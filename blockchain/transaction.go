@@ -68,21 +68,15 @@ func (t *DefaultTransaction) GetID() string {
 	return t.ID
 }
 
-func (t *DefaultTransaction) GetContent() ([]byte, error) {
-	content := struct {
-		ID        string
-		Status    Status
-		PeerID    string
-		Timestamp time.Time
-		TxData    *TxData
-	}{t.ID, t.Status, t.PeerID, t.Timestamp, t.TxData}
-
-	serialized, err := serialize(content)
-	if err != nil {
-		return nil, err
-	}
+// GetPeerID 함수는 Transaction 을 제출한 peer 또는 client 의 ID 를 반환한다.
+func (t *DefaultTransaction) GetPeerID() string {
+	return t.PeerID
+}
 
-	return serialized, nil
+// GetContent 함수는 서명 대상이 되는 Transaction 내용을 canonical binary 형식으로 반환한다.
+// Status 와 Signature 는 포함하지 않는다.
+func (t *DefaultTransaction) GetContent() ([]byte, error) {
+	return appendTransactionContent(appendUint8(nil, encodingVersion), t), nil
 }
 
 func (t *DefaultTransaction) GetSignature() []byte {
@@ -90,8 +84,32 @@ func (t *DefaultTransaction) GetSignature() []byte {
 }
 
 // CalculateSeal 함수는 Transaction 고유의 Hash 값을 계산하여 반환한다.
+// Status 나 Signature 가 바뀌어도 Hash 값은 바뀌지 않는다.
 func (t *DefaultTransaction) CalculateSeal() ([]byte, error) {
-	serializedTx, err := json.Marshal(t)
+	content, err := t.GetContent()
+	if err != nil {
+		return nil, err
+	}
+
+	return calculateHash(content), nil
+}
+
+// CalculateLegacySeal 함수는 LegacyBlockVersion 의 block 이 사용하던 json 기반 Hash 값을 계산한다.
+// Status 와 Signature 까지 hash 하므로 새 block 에서는 CalculateSeal 을 사용한다.
+// binary encoding 은 nil 과 빈 slice 를 구분하지 않으므로 빈 Signature 와 Args 는 json 의 기본값인 null 로 hash 한다.
+func (t *DefaultTransaction) CalculateLegacySeal() ([]byte, error) {
+	legacyTx := *t
+	if len(legacyTx.Signature) == 0 {
+		legacyTx.Signature = nil
+	}
+
+	if legacyTx.TxData != nil && len(legacyTx.TxData.Params.Args) == 0 {
+		txData := *legacyTx.TxData
+		txData.Params.Args = nil
+		legacyTx.TxData = &txData
+	}
+
+	serializedTx, err := json.Marshal(legacyTx)
 	if err != nil {
 		return nil, err
 	}
@@ -103,9 +121,9 @@ func (t *DefaultTransaction) SetSignature(signature []byte) {
 	t.Signature = signature
 }
 
-// Serialize 함수는 Transaction을 []byte 형태로 변환한다.
+// Serialize 함수는 Transaction을 canonical binary 형식의 []byte 로 변환한다.
 func (t *DefaultTransaction) Serialize() ([]byte, error) {
-	return serialize(t)
+	return EncodeTransaction(t), nil
 }
 
 func (t *DefaultTransaction) Deserialize(serializedBytes []byte) error {
@@ -113,12 +131,13 @@ func (t *DefaultTransaction) Deserialize(serializedBytes []byte) error {
 		return nil
 	}
 
-	err := json.Unmarshal(serializedBytes, t)
-
+	tx, err := DecodeTransaction(serializedBytes)
 	if err != nil {
 		return err
 	}
 
+	*t = *tx
+
 	return nil
 }
 
@@ -152,14 +171,6 @@ func NewParams(paramsType int, function string, args []string) Params {
 	}
 }
 
-func serialize(data interface{}) ([]byte, error) {
-	serialized, err := json.Marshal(data)
-	if err != nil {
-		return nil, err
-	}
-	return serialized, nil
-}
-
 func calculateHash(b []byte) []byte {
 	hashValue := sha256.New()
 	hashValue.Write(b)
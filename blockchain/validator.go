@@ -13,16 +13,46 @@ import (
 var ErrHashCalculationFailed = errors.New("Hash Calculation Failed Error")
 var ErrInsufficientFields = errors.New("Previous seal or transaction list seal is not set")
 var ErrEmptyTxList = errors.New("Empty TxList")
+var ErrInvalidBlockHeight = errors.New("block height is not the next height of previous block")
+var ErrPrevSealMismatch = errors.New("block prev seal does not match previous block seal")
+var ErrInvalidSeal = errors.New("block seal is invalid")
+var ErrInvalidTxSeal = errors.New("block tx seal is invalid")
 
 type Validator = common.Validator
 
+// BlockValidator 는 다른 peer 로부터 받은 block 을 chain 에 추가하기 전에 검증한다.
+type BlockValidator interface {
+	ValidateBlock(block Block, prevBlock Block) error
+}
+
 // DefaultValidator 객체는 Validator interface를 구현한 객체.
-type DefaultValidator struct{}
+// merkle tree 는 block version 으로 정한다 (MerkleTreeOf). block 없이 TxSeal 만 받는 함수는 CurrentBlockVersion 의 tree 를 사용한다.
+// verifier 가 설정되면 ValidateBlock 에서 creator 와 transaction 제출자의 서명도 검증한다.
+// limit 이 설정되면 ValidateBlock 에서 transaction 개수와 크기의 한도를 넘는 block 을 거부한다.
+// maxDrift 가 설정되면 ValidateBlock 에서 local 시각보다 maxDrift 이상 앞선 timestamp 의 block 을 거부한다.
+type DefaultValidator struct {
+	verifier SignatureVerifier
+	limit    BlockLimit
+	maxDrift time.Duration
+}
+
+func NewDefaultValidator() *DefaultValidator {
+	return &DefaultValidator{}
+}
+
+func (t *DefaultValidator) SetSignatureVerifier(verifier SignatureVerifier) {
+	t.verifier = verifier
+}
+
+func (t *DefaultValidator) SetBlockLimit(limit BlockLimit) {
+	t.limit = limit
+}
 
 // ValidateSeal 함수는 원래 Seal 값과 주어진 Seal 값(comparisonSeal)을 비교하여, 올바른지 검증한다.
+// block version 에 따라 legacy seal 또는 header seal 로 검증한다.
 func (t *DefaultValidator) ValidateSeal(seal []byte, comparisonBlock Block) (bool, error) {
 
-	comparisonSeal, error := t.BuildSeal(comparisonBlock.GetTimestamp(), comparisonBlock.GetPrevSeal(), comparisonBlock.GetTxSeal(), comparisonBlock.GetCreator())
+	comparisonSeal, error := t.BuildBlockSeal(comparisonBlock)
 
 	if error != nil {
 		return false, error
@@ -32,27 +62,36 @@ func (t *DefaultValidator) ValidateSeal(seal []byte, comparisonBlock Block) (boo
 
 // ValidateTxSeal 함수는 주어진 Transaction 리스트에 따라 주어진 transaction Seal을 검증함.
 func (t *DefaultValidator) ValidateTxSeal(txSeal [][]byte, txList []Transaction) (bool, error) {
-	leafNodeIndex := 0
-	for i, n := range txSeal {
-		leftIndex, rightIndex := (i+1)*2-1, (i+1)*2
-		if rightIndex >= len(txSeal) {
-			// Check Leaf Node
-			calculatedHash, error := txList[leafNodeIndex].CalculateSeal()
-			if error != nil {
-				return false, ErrHashCalculationFailed
-			}
-
-			if bytes.Compare(n, calculatedHash) != 0 {
-				return false, nil
-			}
-			leafNodeIndex++
-		} else {
-			// Check Intermediate Node
-			leftNode, rightNode := txSeal[leftIndex], txSeal[rightIndex]
-			calculatedHash := calculateIntermediateNodeHash(leftNode, rightNode)
-			if bytes.Compare(n, calculatedHash) != 0 {
-				return false, nil
-			}
+	return t.validateTxSeal(CurrentBlockVersion, txSeal, txList)
+}
+
+// ValidateBlockTxSeal 함수는 block version 의 tree 로 block 의 TxSeal 을 검증한다.
+func (t *DefaultValidator) ValidateBlockTxSeal(block Block) (bool, error) {
+	return t.validateTxSeal(blockVersion(block), block.GetTxSeal(), block.GetTxList())
+}
+
+func (t *DefaultValidator) validateTxSeal(version BlockVersion, txSeal [][]byte, txList []Transaction) (bool, error) {
+	if len(txList) == 0 {
+		return len(txSeal) == 0, nil
+	}
+
+	leaves, err := calculateLeaves(version, txList)
+	if err != nil {
+		return false, err
+	}
+
+	tree, err := MerkleTreeOf(version).Build(leaves)
+	if err != nil {
+		return false, err
+	}
+
+	if len(tree) != len(txSeal) {
+		return false, nil
+	}
+
+	for i := range tree {
+		if !bytes.Equal(tree[i], txSeal[i]) {
+			return false, nil
 		}
 	}
 
@@ -66,49 +105,84 @@ func (t *DefaultValidator) ValidateTransaction(txSeal [][]byte, transaction Tran
 		return false, error
 	}
 
-	index := -1
-	for i, h := range txSeal {
-		if bytes.Compare(h, hash) == 0 {
-			index = i
-		}
+	if len(txSeal) == 0 {
+		return false, nil
 	}
 
-	if index == -1 {
+	tree := MerkleTreeOf(CurrentBlockVersion)
+
+	leafCount, err := tree.LeafCount(len(txSeal))
+	if err != nil {
 		return false, nil
 	}
 
-	var siblingIndex, parentIndex int
-	for index > 0 {
-		var isLeft bool
-		if index%2 == 0 {
-			siblingIndex = index - 1
-			parentIndex = (index - 1) / 2
-			isLeft = false
-		} else {
-			siblingIndex = index + 1
-			parentIndex = index / 2
-			isLeft = true
+	for index := 0; index < leafCount; index++ {
+		path, err := tree.Proof(txSeal, leafCount, index)
+		if err != nil {
+			return false, err
 		}
 
-		var parentHash []byte
-		if isLeft {
-			parentHash = calculateIntermediateNodeHash(txSeal[index], txSeal[siblingIndex])
-		} else {
-			parentHash = calculateIntermediateNodeHash(txSeal[siblingIndex], txSeal[index])
+		if tree.Verify(hash, path, txSeal[0]) {
+			return true, nil
 		}
+	}
 
-		if bytes.Compare(parentHash, txSeal[parentIndex]) != 0 {
-			return false, nil
-		}
+	return false, nil
+}
 
-		index = parentIndex
+// ValidateBlock 함수는 block 이 prevBlock 의 다음 block 으로 올바른지 height, prev seal, timestamp, block 한도, tx seal, seal, block 서명, tx 서명 순서로 검증한다.
+func (t *DefaultValidator) ValidateBlock(block Block, prevBlock Block) error {
+	if block.GetHeight() != prevBlock.GetHeight()+1 {
+		return ErrInvalidBlockHeight
 	}
 
-	return true, nil
+	if !bytes.Equal(block.GetPrevSeal(), prevBlock.GetSeal()) {
+		return ErrPrevSealMismatch
+	}
+
+	if blockVersion(block) < blockVersion(prevBlock) {
+		return ErrBlockVersionDowngrade
+	}
+
+	if err := t.ValidateTimestamp(block, prevBlock); err != nil {
+		return err
+	}
+
+	if err := t.limit.Check(block.GetTxList()); err != nil {
+		return err
+	}
+
+	valid, err := t.ValidateBlockTxSeal(block)
+	if err != nil {
+		return err
+	}
+
+	if !valid {
+		return ErrInvalidTxSeal
+	}
+
+	valid, err = t.ValidateSeal(block.GetSeal(), block)
+	if err != nil {
+		return err
+	}
+
+	if !valid {
+		return ErrInvalidSeal
+	}
+
+	if t.verifier == nil {
+		return nil
+	}
+
+	if err := ValidateBlockSignature(block, t.verifier); err != nil {
+		return err
+	}
+
+	return ValidateTxSignatures(block.GetTxList(), t.verifier)
 }
 
-// BuildSeal 함수는 block 객체를 받아서 Seal 값을 만들고, Seal 값을 반환한다.
-// 인풋 파라미터의 block에 자동으로 할당해주지는 않는다.
+// BuildSeal 함수는 LegacyBlockVersion 의 Seal 값을 만들고, Seal 값을 반환한다.
+// height 와 creator 는 seal 에 포함되지 않으므로 새 block 은 BuildHeaderSeal 을 사용한다.
 func (t *DefaultValidator) BuildSeal(timeStamp time.Time, prevSeal []byte, txSeal [][]byte, creator []byte) ([]byte, error) {
 	timestamp, err := timeStamp.MarshalText()
 	if err != nil {
@@ -118,73 +192,87 @@ func (t *DefaultValidator) BuildSeal(timeStamp time.Time, prevSeal []byte, txSea
 	if prevSeal == nil || txSeal == nil || creator == nil {
 		return nil, ErrInsufficientFields
 	}
-	var rootHash []byte
-	if len(txSeal) == 0 {
-		rootHash = make([]byte, 0)
-	} else {
-		rootHash = txSeal[0]
-	}
-	combined := append(prevSeal, rootHash...)
+	rootHash := txRoot(txSeal)
+
+	combined := make([]byte, 0, len(prevSeal)+len(rootHash)+len(timestamp))
+	combined = append(combined, prevSeal...)
+	combined = append(combined, rootHash...)
 	combined = append(combined, timestamp...)
 
 	seal := calculateHash(combined)
 	return seal, nil
 }
 
-// BuildTxSeal 함수는 Transaction 배열을 받아서 TxSeal을 생성하여 반환한다.
-func (t *DefaultValidator) BuildTxSeal(txList []Transaction) ([][]byte, error) {
-
-	if len(txList) == 0 {
-		return nil, ErrEmptyTxList
+// BuildHeaderSeal 함수는 header 의 모든 field 를 hash 하여 Seal 값을 만든다.
+func (t *DefaultValidator) BuildHeaderSeal(header BlockHeader) ([]byte, error) {
+	if header.Version == LegacyBlockVersion || header.Version > CurrentBlockVersion {
+		return nil, ErrUnsupportedBlockVersion
 	}
 
-	leafNodeList := make([][]byte, 0)
+	if header.PrevSeal == nil || header.TxRoot == nil || header.Creator == nil {
+		return nil, ErrInsufficientFields
+	}
 
-	for _, tx := range txList {
-		leafNode, error := tx.CalculateSeal()
-		if error != nil {
-			return nil, error
-		}
+	return calculateHash(header.Bytes()), nil
+}
 
-		leafNodeList = append(leafNodeList, leafNode)
-	}
+// BuildBlockSeal 함수는 block version 에 맞는 방식으로 Seal 값을 만든다.
+func (t *DefaultValidator) BuildBlockSeal(block Block) ([]byte, error) {
+	version := blockVersion(block)
 
-	// leafNodeList의 개수는 짝수개로 맞춤. (홀수 일 경우 마지막 Tx를 중복 저장.)
-	if len(leafNodeList)%2 != 0 {
-		leafNodeList = append(leafNodeList, leafNodeList[len(leafNodeList)-1])
+	if version == LegacyBlockVersion {
+		return t.BuildSeal(block.GetTimestamp(), block.GetPrevSeal(), block.GetTxSeal(), block.GetCreator())
 	}
 
-	tree, error := buildTree(leafNodeList, leafNodeList)
-	if error != nil {
-		return nil, error
+	if block.GetTxSeal() == nil {
+		return nil, ErrInsufficientFields
 	}
 
-	// DefaultValidator 는 Merkle Tree의 루트노드(tree[0])를 Proof로 간주함
-	return tree, nil
+	return t.BuildHeaderSeal(NewBlockHeader(block))
 }
 
-func buildTree(nodeList [][]byte, fullNodeList [][]byte) ([][]byte, error) {
-	intermediateNodeList := make([][]byte, 0)
-	for i := 0; i < len(nodeList); i += 2 {
-		leftIndex, rightIndex := i, i+1
-		leftNode, rightNode := nodeList[leftIndex], nodeList[rightIndex]
+// BuildTxSeal 함수는 Transaction 배열을 받아서 TxSeal을 생성하여 반환한다.
+// TxSeal[0] 이 Merkle Tree 의 root 이다.
+func (t *DefaultValidator) BuildTxSeal(txList []Transaction) ([][]byte, error) {
+	return t.BuildTxSealOf(CurrentBlockVersion, txList)
+}
 
-		intermediateNode := calculateIntermediateNodeHash(leftNode, rightNode)
+// BuildTxSealOf 함수는 주어진 block version 의 tree 로 TxSeal 을 만든다.
+func (t *DefaultValidator) BuildTxSealOf(version BlockVersion, txList []Transaction) ([][]byte, error) {
 
-		intermediateNodeList = append(intermediateNodeList, intermediateNode)
+	if len(txList) == 0 {
+		return nil, ErrEmptyTxList
+	}
 
-		if len(nodeList) == 2 {
-			return append(intermediateNodeList, fullNodeList...), nil
-		}
+	leaves, err := calculateLeaves(version, txList)
+	if err != nil {
+		return nil, err
 	}
 
-	newFullNodeList := append(intermediateNodeList, fullNodeList...)
+	return MerkleTreeOf(version).Build(leaves)
+}
+
+// calculateTxLeaf 는 block version 에 맞는 transaction hash 를 계산한다.
+// LegacyBlockVersion 의 block 은 binary encoding 이전의 json hash 를 leaf 로 사용한다.
+func calculateTxLeaf(version BlockVersion, tx Transaction) ([]byte, error) {
+	if legacyTx, ok := tx.(*DefaultTransaction); ok && version == LegacyBlockVersion {
+		return legacyTx.CalculateLegacySeal()
+	}
 
-	return buildTree(intermediateNodeList, newFullNodeList)
+	return tx.CalculateSeal()
 }
 
-func calculateIntermediateNodeHash(leftHash []byte, rightHash []byte) []byte {
-	combinedHash := append(leftHash, rightHash...)
+func calculateLeaves(version BlockVersion, txList []Transaction) ([][]byte, error) {
+	leaves := make([][]byte, 0, len(txList))
+
+	for _, tx := range txList {
+		leaf, err := calculateTxLeaf(version, tx)
+		if err != nil {
+			return nil, ErrHashCalculationFailed
+		}
+
+		leaves = append(leaves, leaf)
+	}
 
-	return calculateHash(combinedHash)
+	return leaves, nil
 }
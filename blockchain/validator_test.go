@@ -0,0 +1,429 @@
+package blockchain_test
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/it-chain/engine/blockchain"
+	"github.com/it-chain/engine/blockchain/test/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultValidator_ValidateBlock(t *testing.T) {
+	prevBlock := &blockchain.DefaultBlock{Seal: []byte("prevseal"), Height: 3}
+
+	tests := map[string]struct {
+		input func() *blockchain.DefaultBlock
+		err   error
+	}{
+		"success": {
+			input: func() *blockchain.DefaultBlock {
+				return mock.NewBlock(t, prevBlock.Seal, 4, blockchain.CurrentBlockVersion)
+			},
+			err: nil,
+		},
+		"invalid height": {
+			input: func() *blockchain.DefaultBlock {
+				return mock.NewBlock(t, prevBlock.Seal, 5, blockchain.CurrentBlockVersion)
+			},
+			err: blockchain.ErrInvalidBlockHeight,
+		},
+		"prev seal mismatch": {
+			input: func() *blockchain.DefaultBlock {
+				return mock.NewBlock(t, []byte("otherseal"), 4, blockchain.CurrentBlockVersion)
+			},
+			err: blockchain.ErrPrevSealMismatch,
+		},
+		"invalid tx seal": {
+			input: func() *blockchain.DefaultBlock {
+				block := mock.NewBlock(t, prevBlock.Seal, 4, blockchain.CurrentBlockVersion)
+				block.TxList[0].ID = "tx2"
+				return block
+			},
+			err: blockchain.ErrInvalidTxSeal,
+		},
+		"invalid seal": {
+			input: func() *blockchain.DefaultBlock {
+				block := mock.NewBlock(t, prevBlock.Seal, 4, blockchain.CurrentBlockVersion)
+				block.Timestamp = block.Timestamp.Add(time.Second)
+				return block
+			},
+			err: blockchain.ErrInvalidSeal,
+		},
+		"legacy seal": {
+			input: func() *blockchain.DefaultBlock {
+				return mock.NewBlock(t, prevBlock.Seal, 4, blockchain.LegacyBlockVersion)
+			},
+			err: nil,
+		},
+		"creator changed": {
+			input: func() *blockchain.DefaultBlock {
+				block := mock.NewBlock(t, prevBlock.Seal, 4, blockchain.CurrentBlockVersion)
+				block.Creator = []byte("other creator")
+				return block
+			},
+			err: blockchain.ErrInvalidSeal,
+		},
+		"unsupported version": {
+			input: func() *blockchain.DefaultBlock {
+				block := mock.NewBlock(t, prevBlock.Seal, 4, blockchain.CurrentBlockVersion)
+				block.Version = blockchain.CurrentBlockVersion + 1
+				return block
+			},
+			err: blockchain.ErrUnsupportedBlockVersion,
+		},
+	}
+
+	validator := blockchain.DefaultValidator{}
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+
+		err := validator.ValidateBlock(test.input(), prevBlock)
+		assert.Equal(t, test.err, err)
+	}
+}
+
+func TestDefaultValidator_ValidateBlock_LegacyRoundTrip(t *testing.T) {
+	// given: binary encoding 이전에 UTC 가 아닌 zone 으로 만들어진 legacy block
+	zone := time.FixedZone("KST", 9*60*60)
+	timestamp := time.Date(2018, 5, 1, 10, 0, 0, 123456789, zone)
+	validator := blockchain.DefaultValidator{}
+
+	tx1 := blockchain.NewDefaultTransaction("peer", "tx1", timestamp.Add(-time.Minute), &blockchain.TxData{ID: "icode"})
+	tx1.SetSignature([]byte("signature"))
+	tx2 := blockchain.NewDefaultTransaction("peer", "tx2", timestamp.Add(-time.Second), nil)
+	tx2.Status = blockchain.StatusTransactionValid
+
+	// legacy leaf 는 transaction 의 json hash 이다.
+	leaves := make([][]byte, 0)
+	for _, tx := range []*blockchain.DefaultTransaction{tx1, tx2} {
+		serialized, err := json.Marshal(tx)
+		assert.NoError(t, err)
+		leaf := sha256.Sum256(serialized)
+		leaves = append(leaves, leaf[:])
+	}
+
+	txSeal, err := blockchain.LegacyMerkleTree{}.Build(leaves)
+	assert.NoError(t, err)
+
+	prevBlock := &blockchain.DefaultBlock{Seal: []byte("prevseal"), Height: 3, Timestamp: timestamp.Add(-time.Hour)}
+	block := &blockchain.DefaultBlock{
+		PrevSeal:  prevBlock.Seal,
+		Height:    4,
+		TxList:    []*blockchain.DefaultTransaction{tx1, tx2},
+		TxSeal:    txSeal,
+		Timestamp: timestamp,
+		Creator:   []byte("creator"),
+	}
+
+	seal, err := validator.BuildSeal(timestamp, prevBlock.Seal, txSeal, block.Creator)
+	assert.NoError(t, err)
+	block.Seal = seal
+
+	// when
+	serialized, err := block.Serialize()
+	assert.NoError(t, err)
+
+	decoded := &blockchain.DefaultBlock{}
+	err = decoded.Deserialize(serialized)
+	assert.NoError(t, err)
+
+	// then
+	expectedText, _ := timestamp.MarshalText()
+	decodedText, _ := decoded.Timestamp.MarshalText()
+	assert.Equal(t, expectedText, decodedText)
+	assert.NoError(t, validator.ValidateBlock(decoded, prevBlock))
+}
+
+func TestDefaultValidator_ValidateBlock_VersionDowngrade(t *testing.T) {
+	// given
+	validator := blockchain.DefaultValidator{}
+	prevBlock := mock.NewBlock(t, []byte("genesis"), 3, blockchain.CurrentBlockVersion)
+	block := mock.NewBlock(t, prevBlock.Seal, 4, blockchain.LegacyBlockVersion)
+
+	// when
+	err := validator.ValidateBlock(block, prevBlock)
+
+	// then
+	assert.Equal(t, blockchain.ErrBlockVersionDowngrade, err)
+}
+
+func TestDefaultValidator_ValidateBlock_Limit(t *testing.T) {
+	prevBlock := &blockchain.DefaultBlock{Seal: []byte("prevseal"), Height: 3}
+	txList := []blockchain.Transaction{
+		&blockchain.DefaultTransaction{ID: "tx1"},
+		&blockchain.DefaultTransaction{ID: "tx2"},
+	}
+
+	signer := mock.Signer{}
+	signer.SignFunc = func(data []byte) ([]byte, error) {
+		return data, nil
+	}
+
+	block, err := blockchain.CreateProposedBlock(prevBlock.Seal, 4, txList, []byte("creator"), signer)
+	assert.NoError(t, err)
+
+	txBytes := 0
+	for _, tx := range txList {
+		serialized, err := tx.Serialize()
+		assert.NoError(t, err)
+		txBytes += len(serialized)
+	}
+
+	tests := map[string]struct {
+		input blockchain.BlockLimit
+		err   error
+	}{
+		"no limit": {
+			input: blockchain.BlockLimit{},
+			err:   nil,
+		},
+		"within limit": {
+			input: blockchain.BlockLimit{MaxTransactions: 2, MaxBytes: txBytes},
+			err:   nil,
+		},
+		"too many transactions": {
+			input: blockchain.BlockLimit{MaxTransactions: 1},
+			err:   blockchain.ErrTooManyTransactions,
+		},
+		"too large": {
+			input: blockchain.BlockLimit{MaxBytes: txBytes - 1},
+			err:   blockchain.ErrBlockTooLarge,
+		},
+		"transaction too large": {
+			input: blockchain.BlockLimit{MaxBytes: txBytes, MaxTransactionBytes: 1},
+			err:   blockchain.ErrTransactionTooLarge,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+
+		// given
+		validator := blockchain.DefaultValidator{}
+		validator.SetBlockLimit(test.input)
+
+		// when
+		err := validator.ValidateBlock(block, prevBlock)
+
+		// then
+		assert.Equal(t, test.err, err)
+	}
+}
+
+func TestDefaultValidator_BuildHeaderSeal(t *testing.T) {
+	validator := blockchain.DefaultValidator{}
+	timestamp := time.Now().Round(0)
+
+	base := blockchain.BlockHeader{
+		Version:   blockchain.CurrentBlockVersion,
+		Height:    1,
+		PrevSeal:  []byte("prevseal"),
+		TxRoot:    []byte("txroot"),
+		Timestamp: timestamp,
+		Creator:   []byte("creator"),
+	}
+
+	baseSeal, err := validator.BuildHeaderSeal(base)
+	assert.NoError(t, err)
+
+	tests := map[string]struct {
+		input func() blockchain.BlockHeader
+		err   error
+	}{
+		"height": {
+			input: func() blockchain.BlockHeader {
+				header := base
+				header.Height = 2
+				return header
+			},
+			err: nil,
+		},
+		"creator": {
+			input: func() blockchain.BlockHeader {
+				header := base
+				header.Creator = []byte("other")
+				return header
+			},
+			err: nil,
+		},
+		"state root": {
+			input: func() blockchain.BlockHeader {
+				header := base
+				header.StateRoot = []byte("stateroot")
+				return header
+			},
+			err: nil,
+		},
+		"field boundary": {
+			input: func() blockchain.BlockHeader {
+				header := base
+				header.PrevSeal = []byte("prevsealt")
+				header.TxRoot = []byte("xroot")
+				return header
+			},
+			err: nil,
+		},
+		"legacy version": {
+			input: func() blockchain.BlockHeader {
+				header := base
+				header.Version = blockchain.LegacyBlockVersion
+				return header
+			},
+			err: blockchain.ErrUnsupportedBlockVersion,
+		},
+		"empty creator": {
+			input: func() blockchain.BlockHeader {
+				header := base
+				header.Creator = nil
+				return header
+			},
+			err: blockchain.ErrInsufficientFields,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+
+		// when
+		seal, err := validator.BuildHeaderSeal(test.input())
+
+		// then
+		assert.Equal(t, test.err, err)
+		if err == nil {
+			assert.NotEqual(t, baseSeal, seal)
+		}
+	}
+}
+
+func TestDefaultValidator_ValidateBlock_Signature(t *testing.T) {
+	prevBlock := &blockchain.DefaultBlock{Seal: []byte("prevseal"), Height: 3}
+
+	tests := map[string]struct {
+		input struct {
+			signature   []byte
+			creator     []byte
+			txSignature []byte
+			txPeerID    string
+		}
+		err error
+	}{
+		"success": {
+			input: struct {
+				signature   []byte
+				creator     []byte
+				txSignature []byte
+				txPeerID    string
+			}{signature: []byte("signature"), creator: []byte("creator"), txSignature: []byte("signature"), txPeerID: "submitter"},
+			err: nil,
+		},
+		"empty signature": {
+			input: struct {
+				signature   []byte
+				creator     []byte
+				txSignature []byte
+				txPeerID    string
+			}{signature: nil, creator: []byte("creator"), txSignature: []byte("signature"), txPeerID: "submitter"},
+			err: blockchain.ErrEmptyBlockSignature,
+		},
+		"invalid signature": {
+			input: struct {
+				signature   []byte
+				creator     []byte
+				txSignature []byte
+				txPeerID    string
+			}{signature: []byte("forged"), creator: []byte("creator"), txSignature: []byte("signature"), txPeerID: "submitter"},
+			err: blockchain.ErrInvalidBlockSignature,
+		},
+		"unregistered creator": {
+			input: struct {
+				signature   []byte
+				creator     []byte
+				txSignature []byte
+				txPeerID    string
+			}{signature: []byte("signature"), creator: []byte("unknown"), txSignature: []byte("signature"), txPeerID: "submitter"},
+			err: blockchain.ErrPublicKeyNotFound,
+		},
+		"empty tx signature": {
+			input: struct {
+				signature   []byte
+				creator     []byte
+				txSignature []byte
+				txPeerID    string
+			}{signature: []byte("signature"), creator: []byte("creator"), txSignature: nil, txPeerID: "submitter"},
+			err: &blockchain.TxSignatureError{TxID: "tx1", Err: blockchain.ErrEmptyTxSignature},
+		},
+		"invalid tx signature": {
+			input: struct {
+				signature   []byte
+				creator     []byte
+				txSignature []byte
+				txPeerID    string
+			}{signature: []byte("signature"), creator: []byte("creator"), txSignature: []byte("forged"), txPeerID: "submitter"},
+			err: &blockchain.TxSignatureError{TxID: "tx1", Err: blockchain.ErrInvalidTxSignature},
+		},
+		"unregistered submitter": {
+			input: struct {
+				signature   []byte
+				creator     []byte
+				txSignature []byte
+				txPeerID    string
+			}{signature: []byte("signature"), creator: []byte("creator"), txSignature: []byte("signature"), txPeerID: "unknown"},
+			err: &blockchain.TxSignatureError{TxID: "tx1", Err: blockchain.ErrPublicKeyNotFound},
+		},
+	}
+
+	verifier := mock.SignatureVerifier{}
+	verifier.VerifyFunc = func(peerId blockchain.PeerId, data []byte, signature []byte) (bool, error) {
+		if peerId.Id != "creator" && peerId.Id != "submitter" {
+			return false, blockchain.ErrPublicKeyNotFound
+		}
+		return string(signature) == "signature", nil
+	}
+
+	validator := blockchain.DefaultValidator{}
+	validator.SetSignatureVerifier(verifier)
+
+	for testName, test := range tests {
+		t.Logf("running test case %s", testName)
+
+		// given
+		block := mock.NewBlock(t, prevBlock.Seal, 4, blockchain.CurrentBlockVersion)
+		block.Creator = test.input.creator
+		block.Signature = test.input.signature
+		block.TxList[0].PeerID = test.input.txPeerID
+		block.TxList[0].Signature = test.input.txSignature
+		block.TxSeal, _ = validator.BuildTxSeal(block.GetTxList())
+		block.Seal, _ = validator.BuildBlockSeal(block)
+
+		// when
+		err := validator.ValidateBlock(block, prevBlock)
+
+		// then
+		assert.Equal(t, test.err, err)
+	}
+}
+
+func TestValidateTxSignatures_ReportsFailedTx(t *testing.T) {
+	// given
+	txList := []blockchain.Transaction{
+		&blockchain.DefaultTransaction{ID: "tx1", PeerID: "submitter", Signature: []byte("signature")},
+		&blockchain.DefaultTransaction{ID: "tx2", PeerID: "submitter", Signature: []byte("forged")},
+		&blockchain.DefaultTransaction{ID: "tx3", PeerID: "submitter", Signature: []byte("forged")},
+	}
+
+	verifier := mock.SignatureVerifier{}
+	verifier.VerifyFunc = func(peerId blockchain.PeerId, data []byte, signature []byte) (bool, error) {
+		return string(signature) == "signature", nil
+	}
+
+	// when
+	err := blockchain.ValidateTxSignatures(txList, verifier)
+
+	// then
+	assert.Equal(t, &blockchain.TxSignatureError{TxID: "tx2", Err: blockchain.ErrInvalidTxSignature}, err)
+	assert.Equal(t, "transaction [tx2]: transaction signature is invalid", err.Error())
+}
@@ -0,0 +1,17 @@
+package chain
+
+import "github.com/urfave/cli"
+
+var chainCmd = cli.Command{
+	Name:        "chain",
+	Aliases:     []string{"c"},
+	Usage:       "options for committed blockchain",
+	Subcommands: []cli.Command{},
+}
+
+func ChainCmd() cli.Command {
+	chainCmd.Subcommands = append(chainCmd.Subcommands, VerifyCmd())
+	chainCmd.Subcommands = append(chainCmd.Subcommands, ExportCmd())
+	chainCmd.Subcommands = append(chainCmd.Subcommands, ImportCmd())
+	return chainCmd
+}
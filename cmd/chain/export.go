@@ -0,0 +1,97 @@
+package chain
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/it-chain/engine/blockchain"
+	"github.com/it-chain/engine/blockchain/infra/repository/leveldb"
+	"github.com/it-chain/engine/conf"
+	"github.com/urfave/cli"
+)
+
+var ErrEmptyOutput = errors.New("output file path is empty")
+var ErrInvalidRange = errors.New("from height is higher than to height")
+
+func ExportCmd() cli.Command {
+	return cli.Command{
+		Name:  "export",
+		Usage: "it-chain chain export --from [height] --to [height] --out [file path]",
+		Flags: []cli.Flag{
+			cli.Uint64Flag{
+				Name:  "from",
+				Usage: "first block height to export",
+			},
+			cli.Int64Flag{
+				Name:  "to",
+				Value: -1,
+				Usage: "last block height to export. default is the last committed block",
+			},
+			cli.StringFlag{
+				Name:  "out",
+				Usage: "chain file path to write",
+			},
+			cli.StringFlag{
+				Name:  "repository",
+				Usage: "blockchain repository path. default is the path in configuration",
+			},
+		},
+		Action: func(c *cli.Context) error {
+
+			return export(repositoryPath(c), c.Uint64("from"), c.Int64("to"), c.String("out"))
+		},
+	}
+}
+
+// export 는 repository 의 from 부터 to 까지의 block 을 chain file 로 쓴다.
+// to 가 음수이면 마지막 block 까지 쓴다.
+func export(repositoryPath string, from uint64, to int64, out string) error {
+	if out == "" {
+		return ErrEmptyOutput
+	}
+
+	if _, err := os.Stat(repositoryPath); err != nil {
+		return err
+	}
+
+	blockRepository := leveldb.NewBlockRepository(repositoryPath)
+	defer blockRepository.Close()
+
+	lastHeight := uint64(to)
+	if to < 0 {
+		lastBlock, err := blockRepository.GetLastBlock()
+		if err != nil {
+			return err
+		}
+		lastHeight = lastBlock.GetHeight()
+	}
+
+	if from > lastHeight {
+		return ErrInvalidRange
+	}
+
+	file, err := os.Create(out)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	count, err := blockchain.ExportChain(blockRepository, from, lastHeight, file)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(fmt.Sprintf("exported %d blocks (height %d - %d) to %s", count, from, lastHeight, out))
+
+	return nil
+}
+
+// repositoryPath 는 repository flag 가 없으면 설정 파일의 blockchain repository 경로를 사용한다.
+func repositoryPath(c *cli.Context) string {
+	if path := c.String("repository"); path != "" {
+		return path
+	}
+
+	return conf.GetConfiguration().Blockchain.RepositoryPath
+}
@@ -0,0 +1,87 @@
+package chain
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/it-chain/engine/blockchain"
+	"github.com/it-chain/engine/blockchain/infra/repository/leveldb"
+	"github.com/it-chain/engine/conf"
+	"github.com/urfave/cli"
+)
+
+var ErrEmptyInput = errors.New("chain file path is empty")
+var ErrNoPubKey = errors.New("pubkey flag is required to verify signatures. use --skip-signatures to import without verifying signatures")
+
+func ImportCmd() cli.Command {
+	return cli.Command{
+		Name:  "import",
+		Usage: "it-chain chain import [chain file path] --pubkey [peer id]=[public key PEM file path] [--skip-signatures]",
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  "repository",
+				Usage: "blockchain repository path. default is the path in configuration",
+			},
+			cli.StringSliceFlag{
+				Name:  "pubkey",
+				Usage: "public key of block creator and transaction submitter. required unless --skip-signatures is set",
+			},
+			cli.BoolFlag{
+				Name:  "skip-signatures",
+				Usage: "import without verifying block and transaction signatures",
+			},
+			cli.StringFlag{
+				Name:  "genesis",
+				Usage: "genesis file path. default is the path in configuration",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			config := conf.GetConfiguration()
+
+			genesisPath := c.String("genesis")
+			if genesisPath == "" {
+				genesisPath = config.Blockchain.GenesisConfigPath
+			}
+
+			return importChain(c.Args().Get(0), repositoryPath(c), genesisPath, config.Authentication.KeyType, c.StringSlice("pubkey"), c.Bool("skip-signatures"))
+		},
+	}
+}
+
+// importChain 은 chain file 의 block 들을 검증하면서 repository 에 commit 한다.
+// pubKeys 가 없으면 skipSignatures 가 설정된 경우에만 서명 검증 없이 import 한다.
+func importChain(filePath string, repositoryPath string, genesisPath string, keyType string, pubKeys []string, skipSignatures bool) error {
+	if filePath == "" {
+		return ErrEmptyInput
+	}
+
+	if len(pubKeys) == 0 && !skipSignatures {
+		return ErrNoPubKey
+	}
+
+	validator, err := newValidator(keyType, pubKeys)
+	if err != nil {
+		return err
+	}
+
+	genesisConfig, err := blockchain.LoadGenesisConfig(genesisPath)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	blockRepository := leveldb.NewBlockRepository(repositoryPath)
+	defer blockRepository.Close()
+
+	count, err := blockchain.ImportChain(file, blockRepository, validator, genesisConfig)
+	fmt.Println(fmt.Sprintf("imported blocks : %d", count))
+	fmt.Println(fmt.Sprintf("signatures      : %s", signatureStatus(pubKeys)))
+
+	return err
+}
@@ -0,0 +1,131 @@
+package chain
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/it-chain/engine/blockchain"
+	"github.com/it-chain/engine/blockchain/infra/adapter"
+	"github.com/it-chain/engine/blockchain/infra/repository/leveldb"
+	"github.com/it-chain/engine/blockchain/infra/repository/memory"
+	"github.com/it-chain/engine/conf"
+	grpcGatewayInfra "github.com/it-chain/engine/grpc_gateway/infra"
+	"github.com/urfave/cli"
+)
+
+var ErrBrokenChain = errors.New("chain is broken")
+var ErrInvalidPubKeyFlag = errors.New("pubkey flag must be [peer id]=[public key PEM file path]")
+
+func VerifyCmd() cli.Command {
+	return cli.Command{
+		Name:  "verify",
+		Usage: "it-chain chain verify [blockchain repository path] --pubkey [peer id]=[public key PEM file path]",
+		Flags: []cli.Flag{
+			cli.StringSliceFlag{
+				Name:  "pubkey",
+				Usage: "public key of block creator and transaction submitter. if not set, signatures are not verified",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			config := conf.GetConfiguration()
+
+			repositoryPath := c.Args().Get(0)
+			if repositoryPath == "" {
+				repositoryPath = config.Blockchain.RepositoryPath
+			}
+
+			return verify(repositoryPath, config.Authentication.KeyType, c.StringSlice("pubkey"))
+		},
+	}
+}
+
+// verify 는 repository 의 chain 을 genesis 부터 검증하고 report 를 출력한다.
+// transaction 이 지워진 block 과 snapshot block 은 header 로 검증한다.
+// chain 이 깨져 있으면 ErrBrokenChain 을 반환한다.
+func verify(repositoryPath string, keyType string, pubKeys []string) error {
+	validator, err := newValidator(keyType, pubKeys)
+	if err != nil {
+		return err
+	}
+
+	// 없는 경로에 빈 repository 를 만들지 않는다.
+	if _, err := os.Stat(repositoryPath); err != nil {
+		return err
+	}
+
+	blockRepository := leveldb.NewBlockRepository(repositoryPath)
+	defer blockRepository.Close()
+
+	report, err := blockchain.VerifyChain(blockRepository, validator)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(fmt.Sprintf("last height     : %d", report.LastHeight))
+	if report.SnapshotHeight > 0 {
+		fmt.Println(fmt.Sprintf("snapshot height : %d", report.SnapshotHeight))
+	}
+	fmt.Println(fmt.Sprintf("verified blocks : %d", report.VerifiedBlocks))
+	if report.HeaderOnlyBlocks > 0 {
+		fmt.Println(fmt.Sprintf("header only     : %d", report.HeaderOnlyBlocks))
+	}
+	fmt.Println(fmt.Sprintf("signatures      : %s", signatureStatus(pubKeys)))
+
+	if report.IsBroken() {
+		fmt.Println(fmt.Sprintf("broken height   : %d", report.BrokenHeight))
+		fmt.Println(fmt.Sprintf("reason          : %s", report.Err.Error()))
+		return ErrBrokenChain
+	}
+
+	fmt.Println("result          : valid")
+
+	return nil
+}
+
+// newValidator 는 pubKeys 가 있으면 서명도 검증하는 validator 를 만든다.
+func newValidator(keyType string, pubKeys []string) (*blockchain.DefaultValidator, error) {
+	validator := blockchain.NewDefaultValidator()
+	if len(pubKeys) != 0 {
+		verifier, err := newSignatureVerifier(keyType, pubKeys)
+		if err != nil {
+			return nil, err
+		}
+		validator.SetSignatureVerifier(verifier)
+	}
+
+	return validator, nil
+}
+
+// newSignatureVerifier 는 [peer id]=[public key PEM file path] 형식의 flag 들로 public key 를 등록한 verifier 를 만든다.
+func newSignatureVerifier(keyType string, pubKeys []string) (blockchain.SignatureVerifier, error) {
+	publicKeyRepository := memory.NewPublicKeyRepository()
+
+	for _, pubKey := range pubKeys {
+		pair := strings.SplitN(pubKey, "=", 2)
+		if len(pair) != 2 || pair[0] == "" || pair[1] == "" {
+			return nil, ErrInvalidPubKeyFlag
+		}
+
+		pem, err := ioutil.ReadFile(pair[1])
+		if err != nil {
+			return nil, err
+		}
+
+		if err := publicKeyRepository.Save(blockchain.PeerId{Id: pair[0]}, pem); err != nil {
+			return nil, err
+		}
+	}
+
+	return adapter.NewHeimdallSignatureVerifier(publicKeyRepository, grpcGatewayInfra.ConvertToKeyGenOpts(keyType))
+}
+
+func signatureStatus(pubKeys []string) string {
+	if len(pubKeys) == 0 {
+		return "not verified (no --pubkey)"
+	}
+
+	return fmt.Sprintf("verified with %d public keys", len(pubKeys))
+}
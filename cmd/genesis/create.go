@@ -0,0 +1,75 @@
+package genesis
+
+import (
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/it-chain/engine/blockchain"
+	"github.com/it-chain/engine/conf"
+	"github.com/urfave/cli"
+)
+
+func CreateCmd() cli.Command {
+	return cli.Command{
+		Name:  "create",
+		Usage: "it-chain genesis create --chainid [chain id] --creator [creator] --parliament [member] ...",
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  "chainid",
+				Usage: "id of the chain",
+			},
+			cli.StringFlag{
+				Name:  "creator",
+				Usage: "creator of the genesis block",
+			},
+			cli.StringSliceFlag{
+				Name:  "parliament",
+				Usage: "initial parliament member (repeatable)",
+			},
+			cli.StringFlag{
+				Name:  "timestamp",
+				Usage: "genesis timestamp in RFC3339 format (default: now)",
+			},
+			cli.StringFlag{
+				Name:  "output",
+				Usage: "path of the genesis file (default: blockchain.genesisconfigpath)",
+			},
+		},
+		Action: func(c *cli.Context) error {
+
+			output := c.String("output")
+			if output == "" {
+				output = conf.GetConfiguration().Blockchain.GenesisConfigPath
+			}
+
+			return create(c.String("chainid"), c.String("creator"), c.StringSlice("parliament"), c.String("timestamp"), output)
+		},
+	}
+}
+
+func create(chainID string, creator string, parliament []string, timestamp string, output string) error {
+	genesisTime := time.Now()
+
+	if timestamp != "" {
+		t, err := time.Parse(time.RFC3339Nano, timestamp)
+		if err != nil {
+			return err
+		}
+		genesisTime = t
+	}
+
+	config, err := blockchain.NewGenesisConfig(chainID, creator, parliament, genesisTime)
+	if err != nil {
+		return err
+	}
+
+	if err := blockchain.SaveGenesisConfig(output, config); err != nil {
+		return err
+	}
+
+	fmt.Println(fmt.Sprintf("genesis file : %s", output))
+	fmt.Println(fmt.Sprintf("genesis seal : %s", hex.EncodeToString(config.Seal)))
+
+	return nil
+}
@@ -0,0 +1,17 @@
+package genesis
+
+import "github.com/urfave/cli"
+
+var genesisCmd = cli.Command{
+	Name:        "genesis",
+	Aliases:     []string{"g"},
+	Usage:       "options for genesis block",
+	Subcommands: []cli.Command{},
+}
+
+func GenesisCmd() cli.Command {
+	genesisCmd.Subcommands = append(genesisCmd.Subcommands, CreateCmd())
+	genesisCmd.Subcommands = append(genesisCmd.Subcommands, InspectCmd())
+	genesisCmd.Subcommands = append(genesisCmd.Subcommands, VerifyCmd())
+	return genesisCmd
+}
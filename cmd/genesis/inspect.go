@@ -0,0 +1,47 @@
+package genesis
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/it-chain/engine/blockchain"
+	"github.com/it-chain/engine/conf"
+	"github.com/urfave/cli"
+)
+
+func InspectCmd() cli.Command {
+	return cli.Command{
+		Name:  "inspect",
+		Usage: "it-chain genesis inspect [genesis file path]",
+		Action: func(c *cli.Context) error {
+
+			return inspect(genesisFilePath(c))
+		},
+	}
+}
+
+func inspect(filePath string) error {
+	config, err := blockchain.LoadGenesisConfig(filePath)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(fmt.Sprintf("chain id   : %s", config.ChainID))
+	fmt.Println(fmt.Sprintf("creator    : %s", config.Creator))
+	fmt.Println(fmt.Sprintf("parliament : %s", strings.Join(config.Parliament, ", ")))
+	fmt.Println(fmt.Sprintf("timestamp  : %s", config.Timestamp.Format(time.RFC3339Nano)))
+	fmt.Println(fmt.Sprintf("seal       : %s", hex.EncodeToString(config.Seal)))
+
+	return nil
+}
+
+// genesisFilePath 는 첫번째 인자가 없으면 설정 파일의 genesis 경로를 사용한다.
+func genesisFilePath(c *cli.Context) string {
+	if filePath := c.Args().Get(0); filePath != "" {
+		return filePath
+	}
+
+	return conf.GetConfiguration().Blockchain.GenesisConfigPath
+}
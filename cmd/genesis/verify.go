@@ -0,0 +1,57 @@
+package genesis
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/it-chain/engine/blockchain"
+	"github.com/urfave/cli"
+)
+
+var ErrUnexpectedGenesisSeal = errors.New("genesis seal is not the expected seal")
+
+func VerifyCmd() cli.Command {
+	return cli.Command{
+		Name:  "verify",
+		Usage: "it-chain genesis verify [genesis file path] --seal [expected seal]",
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  "seal",
+				Usage: "expected genesis seal in hex",
+			},
+		},
+		Action: func(c *cli.Context) error {
+
+			return verify(genesisFilePath(c), c.String("seal"))
+		},
+	}
+}
+
+// verify 는 genesis 파일의 seal 이 내용과 맞는지, 그리고 주어진 seal 과 같은지 확인한다.
+func verify(filePath string, expectedSeal string) error {
+	config, err := blockchain.LoadGenesisConfig(filePath)
+	if err != nil {
+		return err
+	}
+
+	if err := blockchain.VerifyGenesisConfig(config); err != nil {
+		return err
+	}
+
+	if expectedSeal != "" {
+		seal, err := hex.DecodeString(expectedSeal)
+		if err != nil {
+			return err
+		}
+
+		if !bytes.Equal(seal, config.Seal) {
+			return ErrUnexpectedGenesisSeal
+		}
+	}
+
+	fmt.Println(fmt.Sprintf("genesis seal : %s (valid)", hex.EncodeToString(config.Seal)))
+
+	return nil
+}
@@ -2,10 +2,58 @@ package model
 
 type BlockChainConfiguration struct {
 	RepositoryPath string
+	// it-chain genesis create 로 만든 genesis 파일의 경로
+	GenesisConfigPath string
+	// block pool 이 보관하는 최대 block 수
+	PoolMaxBlocks int
+	// 마지막 block 과 block pool 의 block 사이의 최대 height 차이
+	PoolMaxHeightDistance uint64
+	// block pool 에 추가된 block 을 보관하는 시간(초)
+	PoolExpirationTime int
+	// block pool 의 event 가 이만큼 쌓이면 snapshot 으로 compaction 한다.
+	PoolCompactThreshold int
+	// transaction 실행 결과(receipt)를 저장하는 leveldb 의 경로
+	ReceiptRepositoryPath string
+	// 동기화 방식 (full, light). light 는 block 대신 header 만 동기화한다.
+	SyncMode string
+	// light 모드에서 header 를 저장하는 leveldb 의 경로
+	HeaderRepositoryPath string
+	// snapshot 을 만드는 block height 간격. 0 이면 snapshot 을 만들지 않는다.
+	SnapshotInterval uint64
+	// snapshot 과 icode state 를 저장하는 leveldb 의 경로
+	SnapshotRepositoryPath string
+	// genesis block 만 가진 node 가 peer 의 snapshot 으로 시작할지 여부
+	SnapshotBootstrap bool
+	// block 보관 방식 (archive, pruned). pruned 는 header 와 마지막 PruneKeepBlocks 개의 block 만 보관한다.
+	Mode string
+	// pruned 모드에서 transaction 까지 보관하는 최근 block 수
+	PruneKeepBlocks uint64
+	// block timestamp 가 local 시각보다 앞설 수 있는 최대 시간(초). 0 이면 제한하지 않는다.
+	MaxTimestampDrift int
+	// block 의 TxSeal 을 만들고 검증하는 validator 종류 (version, default, legacy). version 은 block version 으로 tree 를 정한다.
+	ValidatorType string
+	// block 하나에 담는 transaction 의 byte 합의 한도. transaction 하나의 한도는 txpool.MaxTransactionByte 이다.
+	MaxBlockBytes int
 }
 
 func NewBlockChainConfiguration() BlockChainConfiguration {
 	return BlockChainConfiguration{
-		RepositoryPath: "empty",
+		RepositoryPath:         ".it-chain/blockchain",
+		GenesisConfigPath:      ".it-chain/genesis.json",
+		PoolMaxBlocks:          1000,
+		PoolMaxHeightDistance:  100,
+		PoolExpirationTime:     600,
+		PoolCompactThreshold:   1000,
+		ReceiptRepositoryPath:  ".it-chain/receipt",
+		SyncMode:               "full",
+		HeaderRepositoryPath:   ".it-chain/header",
+		SnapshotInterval:       1000,
+		SnapshotRepositoryPath: ".it-chain/snapshot",
+		SnapshotBootstrap:      false,
+		Mode:                   "archive",
+		PruneKeepBlocks:        10000,
+		MaxTimestampDrift:      15,
+		ValidatorType:          "version",
+		MaxBlockBytes:          1048576,
 	}
 }
@@ -12,12 +12,22 @@ import (
 )
 
 var ErrNilStore = errors.New("event store is nil")
+var ErrDeleteNotSupported = errors.New("event store does not support delete")
+
+//EventDeleter는 aggregate의 event를 모두 지울 수 있는 repository 또는 store이다.
+//midgard의 EventRepository는 Save, Load, Close만 가지므로 Delete는 이 interface를 구현한 경우에만 동작한다.
+type EventDeleter interface {
+	Delete(aggregateID string) error
+}
 
 var Instance *Store
 
 type Store struct {
 	repo midgard.EventRepository
 
+	//store는 repo가 event를 저장하는 곳이다. repo가 Delete를 지원하지 않으면 store에서 지운다.
+	store midgard.EventStore
+
 	//serializer는 event struct의 list를가지고 있고 byte[]로 저장된 event를 deserialize할때 등록된 event로 deserialize한다.
 	//serializer는 store의 내부에서 작동하므로, 동적으로 event를 등록하기 위해 따로 밖에서 instance를 가지고 있다.
 	//db에서 event를 복구하기 위해서는 꼭 event가 등록 되어있어야 하므로, RegisterEvents함수를 이용해 저장하는 event들을 등록해야한다.
@@ -37,6 +47,7 @@ func InitDefault() {
 
 	Instance = &Store{
 		repo:       repo,
+		store:      store,
 		serializer: serializer,
 	}
 }
@@ -93,6 +104,7 @@ func InitLevelDBStore(path string, publisher midgard.Publisher, events ...midgar
 
 	Instance = &Store{
 		repo:       midgard.NewRepo(store, publisher),
+		store:      store,
 		serializer: serializer,
 	}
 }
@@ -128,6 +140,25 @@ func Load(aggregate midgard.Aggregate, aggregateID string) error {
 	return Instance.repo.Load(aggregate, aggregateID)
 }
 
+//Delete는 aggregateID의 event를 모두 지운다. 지운 aggregate를 Load하면 event가 없는 상태가 된다.
+//repo와 store 모두 EventDeleter를 구현하지 않으면 ErrDeleteNotSupported를 반환한다.
+func Delete(aggregateID string) error {
+
+	if Instance == nil {
+		return ErrNilStore
+	}
+
+	if deleter, ok := Instance.repo.(EventDeleter); ok {
+		return deleter.Delete(aggregateID)
+	}
+
+	if deleter, ok := Instance.store.(EventDeleter); ok {
+		return deleter.Delete(aggregateID)
+	}
+
+	return ErrDeleteNotSupported
+}
+
 func Close() {
 
 	if Instance == nil {
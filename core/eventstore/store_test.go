@@ -52,3 +52,31 @@ func InitStore() func() {
 		os.RemoveAll(path)
 	}
 }
+
+func TestDelete(t *testing.T) {
+
+	repository := &DeletableRepository{Repository{events: make(map[string][]midgard.Event)}}
+	eventstore.InitForMock(repository)
+	defer eventstore.Close()
+
+	err := eventstore.Save("123", &UserCreatedEvent{EventModel: midgard.EventModel{ID: "123"}})
+	assert.NoError(t, err)
+
+	err = eventstore.Delete("123")
+	assert.NoError(t, err)
+
+	user := &User{}
+	err = eventstore.Load(user, "123")
+	assert.NoError(t, err)
+
+	assert.Equal(t, "", user.ID)
+}
+
+func TestDeleteNotSupported(t *testing.T) {
+
+	eventstore.InitForMock(&Repository{events: make(map[string][]midgard.Event)})
+	defer eventstore.Close()
+
+	err := eventstore.Delete("123")
+	assert.Equal(t, eventstore.ErrDeleteNotSupported, err)
+}
@@ -49,3 +49,34 @@ type UserNameUpdatedEvent struct {
 	midgard.EventModel
 	Name string
 }
+
+// EventRepository
+type Repository struct {
+	events map[string][]midgard.Event
+}
+
+func (r *Repository) Load(aggregate midgard.Aggregate, aggregateID string) error {
+	for _, event := range r.events[aggregateID] {
+		if err := aggregate.On(event); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *Repository) Save(aggregateID string, events ...midgard.Event) error {
+	r.events[aggregateID] = append(r.events[aggregateID], events...)
+	return nil
+}
+
+func (r *Repository) Close() {}
+
+type DeletableRepository struct {
+	Repository
+}
+
+func (r *DeletableRepository) Delete(aggregateID string) error {
+	delete(r.events, aggregateID)
+	return nil
+}
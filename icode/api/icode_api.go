@@ -58,6 +58,11 @@ func (iApi ICodeApi) UnDeploy(id icode.ID) error {
 	return nil
 }
 
+// ResetState 는 배포된 icode 는 그대로 두고 icode 들의 state 만 비운다.
+func (iApi ICodeApi) ResetState() error {
+	return iApi.ContainerService.ResetContainers()
+}
+
 //todo need asnyc process
 func (iApi ICodeApi) Invoke(tx icode.Transaction) *icode.Result {
 	result, err := iApi.ContainerService.ExecuteTransaction(tx)
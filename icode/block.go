@@ -3,3 +3,10 @@ package icode
 type Block struct {
 	TxList []Transaction
 }
+
+// CommittedBlock 은 blockchain 이 commit 한 block 의 header 와 transaction ID 목록이다.
+type CommittedBlock struct {
+	Height uint64
+	Seal   []byte
+	TxIDs  []string
+}
@@ -18,9 +18,11 @@ type UnDeployCommand struct {
 	midgard.CommandModel
 }
 
+// Reset 이면 block 을 실행하기 전에 모든 icode 의 state 를 비운다. blockchain 이 reorg 뒤에 chain 을 다시 실행할 때 보낸다.
 type BlockExecuteCommand struct {
 	midgard.CommandModel
 	Block []byte
+	Reset bool
 }
 
 type BlockResultCommand struct {
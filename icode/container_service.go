@@ -4,4 +4,6 @@ type ContainerService interface {
 	StartContainer(meta Meta) error
 	StopContainer(id ID) error
 	ExecuteTransaction(tx Transaction) (*Result, error)
+	// 실행 중인 모든 icode 의 container 를 새로 띄워 state 를 비운다.
+	ResetContainers() error
 }
@@ -1,6 +1,10 @@
 package icode
 
-import "github.com/it-chain/midgard"
+import (
+	"time"
+
+	"github.com/it-chain/midgard"
+)
 
 //type : meta.created
 type MetaCreatedEvent struct {
@@ -21,3 +25,14 @@ type MetaStatusChangeEvent struct {
 	midgard.EventModel
 	Status MetaStatus
 }
+
+//type : block.committed
+type BlockCommittedEvent struct {
+	midgard.EventModel
+	Seal      []byte
+	PrevSeal  []byte
+	Height    uint64
+	Timestamp time.Time
+	Creator   []byte
+	TxIDs     []string
+}
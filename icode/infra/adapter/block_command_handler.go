@@ -31,6 +31,16 @@ func (b *BlockCommandHandler) HandleBlockExecuteCommand(command icode.BlockExecu
 		return
 	}
 	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	// reorg 로 되돌린 transaction 의 결과가 남지 않도록 state 를 비운 다음 chain 을 다시 실행한다.
+	if command.Reset {
+		if err := b.icodeApi.ResetState(); err != nil {
+			fmt.Println(fmt.Sprintf("error in reset icode state, err : %s", err.Error()))
+			return
+		}
+	}
+
 	results := make([]icode.Result, 0)
 	for _, tx := range block.TxList {
 		switch tx.TxData.Method {
@@ -43,5 +53,4 @@ func (b *BlockCommandHandler) HandleBlockExecuteCommand(command icode.BlockExecu
 		}
 	}
 	b.commandService.SendBlockExecuteResultCommand(results, command.GetID())
-	b.mutex.Unlock()
 }
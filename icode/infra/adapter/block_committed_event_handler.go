@@ -0,0 +1,53 @@
+package adapter
+
+import (
+	"errors"
+	"log"
+	"sync"
+
+	"github.com/it-chain/engine/icode"
+)
+
+// BlockCommittedEventHandler 는 blockchain 이 마지막으로 commit 한 block 을 기억한다.
+// icode 가 실행한 transaction 이 어느 block 까지 확정되었는지 알 수 있다.
+type BlockCommittedEventHandler struct {
+	lastBlock *icode.CommittedBlock
+	mutex     *sync.Mutex
+}
+
+func NewBlockCommittedEventHandler() *BlockCommittedEventHandler {
+	return &BlockCommittedEventHandler{
+		mutex: &sync.Mutex{},
+	}
+}
+
+func (handler *BlockCommittedEventHandler) HandleBlockCommittedEvent(event icode.BlockCommittedEvent) error {
+	if event.ID == "" {
+		return errors.New("Empty event id err")
+	}
+
+	handler.mutex.Lock()
+	defer handler.mutex.Unlock()
+
+	handler.lastBlock = &icode.CommittedBlock{
+		Height: event.Height,
+		Seal:   event.Seal,
+		TxIDs:  event.TxIDs,
+	}
+
+	log.Printf("block committed [%d] with [%d] transactions", event.Height, len(event.TxIDs))
+
+	return nil
+}
+
+// LastCommittedBlock 은 마지막으로 commit 된 block 을 반환한다. 아직 받은 block 이 없으면 false 를 반환한다.
+func (handler *BlockCommittedEventHandler) LastCommittedBlock() (icode.CommittedBlock, bool) {
+	handler.mutex.Lock()
+	defer handler.mutex.Unlock()
+
+	if handler.lastBlock == nil {
+		return icode.CommittedBlock{}, false
+	}
+
+	return *handler.lastBlock, true
+}
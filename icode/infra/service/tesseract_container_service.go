@@ -15,13 +15,15 @@ import (
 
 type TesseractContainerService struct {
 	tesseract      *tesseract.Tesseract
-	containerIdMap map[icode.ID]string // key : iCodeId, value : containerId
+	containerIdMap map[icode.ID]string     // key : iCodeId, value : containerId
+	metaMap        map[icode.ID]icode.Meta // key : iCodeId, value : container 를 다시 띄울 때 쓰는 meta
 }
 
 func NewTesseractContainerService(config tesseract.Config) *TesseractContainerService {
 	tesseractObj := &TesseractContainerService{
 		tesseract:      tesseract.New(config),
 		containerIdMap: make(map[icode.ID]string, 0),
+		metaMap:        make(map[icode.ID]icode.Meta, 0),
 	}
 	return tesseractObj
 }
@@ -37,10 +39,36 @@ func (cs TesseractContainerService) StartContainer(meta icode.Meta) error {
 		return err
 	}
 	cs.containerIdMap[meta.ICodeID] = containerId
+	cs.metaMap[meta.ICodeID] = meta
 	icode.ChangeMetaStatus(meta.GetID(), icode.DEPLOYED)
 	return nil
 }
 
+// ResetContainers 는 실행 중인 icode 의 container 를 멈추고 같은 icode 로 새 container 를 띄운다.
+// icode 는 계속 배포된 상태이므로 meta event 는 저장하지 않는다.
+func (cs TesseractContainerService) ResetContainers() error {
+	for id, meta := range cs.metaMap {
+		if err := cs.tesseract.StopContainerById(cs.containerIdMap[id]); err != nil {
+			return err
+		}
+
+		containerId, err := cs.tesseract.SetupContainer(tesseract.ICodeInfo{
+			Name:      meta.RepositoryName,
+			Directory: meta.Path,
+		})
+		if err != nil {
+			delete(cs.containerIdMap, id)
+			delete(cs.metaMap, id)
+			icode.ChangeMetaStatus(id, icode.DEPLOY_FAIL)
+			return err
+		}
+
+		cs.containerIdMap[id] = containerId
+	}
+
+	return nil
+}
+
 func (cs TesseractContainerService) ExecuteTransaction(tx icode.Transaction) (*icode.Result, error) {
 	containerId, found := cs.containerIdMap[tx.TxData.ICodeID]
 
@@ -98,6 +126,7 @@ func (cs TesseractContainerService) StopContainer(id icode.ID) error {
 		return err
 	}
 	delete(cs.containerIdMap, id)
+	delete(cs.metaMap, id)
 	deletedEvent := icode.MetaDeletedEvent{
 		EventModel: midgard.EventModel{
 			ID:   id,
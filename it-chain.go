@@ -12,9 +12,17 @@ import (
 
 	kitlog "github.com/go-kit/kit/log"
 	"github.com/it-chain/engine/api_gateway"
+	"github.com/it-chain/engine/blockchain"
+	blockchainApi "github.com/it-chain/engine/blockchain/api"
+	blockchainAdapter "github.com/it-chain/engine/blockchain/infra/adapter"
+	blockchainLeveldb "github.com/it-chain/engine/blockchain/infra/repository/leveldb"
+	blockchainMemory "github.com/it-chain/engine/blockchain/infra/repository/memory"
+	"github.com/it-chain/engine/cmd/chain"
+	"github.com/it-chain/engine/cmd/genesis"
 	"github.com/it-chain/engine/cmd/icode"
 	"github.com/it-chain/engine/conf"
 	"github.com/it-chain/engine/core/eventstore"
+	grpcGatewayInfra "github.com/it-chain/engine/grpc_gateway/infra"
 	icodeApi "github.com/it-chain/engine/icode/api"
 	icodeAdapter "github.com/it-chain/engine/icode/infra/adapter"
 	icodeInfra "github.com/it-chain/engine/icode/infra/api"
@@ -61,10 +69,15 @@ func main() {
 	}
 	app.Commands = []cli.Command{}
 	app.Commands = append(app.Commands, icode.IcodeCmd())
+	app.Commands = append(app.Commands, genesis.GenesisCmd())
+	app.Commands = append(app.Commands, chain.ChainCmd())
 	app.Action = func(c *cli.Context) error {
 		configName := c.String("config")
 		conf.SetConfigName(configName)
 		eventstore.InitDefault()
+		if err := eventstore.RegisterEvents(blockchain.Events()...); err != nil {
+			return err
+		}
 		return start()
 	}
 
@@ -93,10 +106,91 @@ func start() error {
 
 	errs := make(chan error, 2)
 
-	initGateway(errs)
+	if err := blockchain.InitDefaultValidator(configuration.Blockchain.ValidatorType); err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid validator type %q: %s\n", configuration.Blockchain.ValidatorType, err)
+		return err
+	}
+
+	// light node 는 block 을 저장하지 않고 header 만 동기화한다.
+	if configuration.Blockchain.SyncMode == "light" {
+		return startLight(errs)
+	}
+
+	// blockchain repository 는 gateway 와 blockchain 이 같이 사용한다.
+	blockRepository := blockchainLeveldb.NewBlockRepository(configuration.Blockchain.RepositoryPath)
+	defer blockRepository.Close()
+
+	receiptRepository := blockchainLeveldb.NewReceiptRepository(configuration.Blockchain.ReceiptRepositoryPath)
+	defer receiptRepository.Close()
+
+	snapshotRepository := blockchainLeveldb.NewSnapshotRepository(configuration.Blockchain.SnapshotRepositoryPath)
+	defer snapshotRepository.Close()
+
+	// 모든 node 가 같은 genesis block 으로 시작하는지 확인한다.
+	genesisConfig, err := blockchain.LoadGenesisConfig(configuration.Blockchain.GenesisConfigPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Can't load genesis file %q (create it with 'it-chain genesis create'): %s\n", configuration.Blockchain.GenesisConfigPath, err)
+		return err
+	}
+
+	if err := blockchain.CheckGenesisBlock(genesisConfig, blockRepository); err != nil {
+		fmt.Fprintf(os.Stderr, "Genesis block check failed: %s\n", err)
+		return err
+	}
+
+	// pruned 모드라면 archive 모드에서 쌓인 오래된 block 부터 정리한다.
+	pruneConfig, err := blockchain.NewPruneConfig(configuration.Blockchain.Mode, configuration.Blockchain.PruneKeepBlocks)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid blockchain mode %q: %s\n", configuration.Blockchain.Mode, err)
+		return err
+	}
+
+	blockRepository.SetPruneConfig(pruneConfig)
+	if err := blockRepository.Prune(); err != nil {
+		return err
+	}
+
+	if stats, err := blockRepository.RetentionStats(); err == nil {
+		log.Printf("blockchain mode [%s]: last height [%d], pruned height [%d], pruned txs [%d], pruned bytes [%d]", stats.Mode, stats.LastHeight, stats.PrunedHeight, stats.PrunedTxs, stats.PrunedBytes)
+	}
+
+	initGateway(errs, blockRepository, receiptRepository)
 	initTxPool()
 	initIcode()
 	initPeer()
+	initBlockchain(blockRepository, receiptRepository, snapshotRepository, genesisConfig)
+
+	go func() {
+		c := make(chan os.Signal, 1)
+		signal.Notify(c, syscall.SIGINT)
+		errs <- fmt.Errorf("%s", <-c)
+	}()
+
+	log.Println("terminated", <-errs)
+
+	return nil
+}
+
+func startLight(errs chan error) error {
+
+	configuration := conf.GetConfiguration()
+
+	headerRepository := blockchainLeveldb.NewHeaderRepository(configuration.Blockchain.HeaderRepositoryPath)
+	defer headerRepository.Close()
+
+	genesisConfig, err := blockchain.LoadGenesisConfig(configuration.Blockchain.GenesisConfigPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Can't load genesis file %q (create it with 'it-chain genesis create'): %s\n", configuration.Blockchain.GenesisConfigPath, err)
+		return err
+	}
+
+	lightApi, err := initLightBlockchain(headerRepository, genesisConfig)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Genesis header check failed: %s\n", err)
+		return err
+	}
+
+	initLightGateway(errs, lightApi)
 
 	go func() {
 		c := make(chan os.Signal, 1)
@@ -112,7 +206,7 @@ func start() error {
 //todo other way to inject each query Api to component
 var txQueryApi api_gateway.TransactionQueryApi
 
-func initGateway(errs chan error) error {
+func initGateway(errs chan error, blockRepository api_gateway.BlockRepository, receiptRepository api_gateway.ReceiptRepository) error {
 
 	log.Println("gateway is running...")
 
@@ -132,6 +226,8 @@ func initGateway(errs chan error) error {
 
 	txQueryApi = api_gateway.NewTransactionQueryApi(repo)
 	txEventListener := api_gateway.NewTransactionEventListener(repo)
+	blockQueryApi := api_gateway.NewBlockQueryApi(blockRepository)
+	receiptQueryApi := api_gateway.NewReceiptQueryApi(receiptRepository)
 
 	//set mux
 	mux := http.NewServeMux()
@@ -143,7 +239,11 @@ func initGateway(errs chan error) error {
 		panic(err)
 	}
 
-	mux.Handle("/", api_gateway.MakeHandler(txQueryApi, httpLogger))
+	if err := mqClient.Subscribe("Event", "block.committed", &txEventListener); err != nil {
+		panic(err)
+	}
+
+	mux.Handle("/", api_gateway.MakeHandler(txQueryApi, blockQueryApi, receiptQueryApi, httpLogger))
 	http.Handle("/", mux)
 
 	go func() {
@@ -154,6 +254,31 @@ func initGateway(errs chan error) error {
 	return nil
 }
 
+func initLightGateway(errs chan error, checker api_gateway.TxInclusionChecker) {
+
+	log.Println("light gateway is running...")
+
+	config := conf.GetConfiguration()
+	ipAddress := config.Common.NodeIp
+
+	var logger kitlog.Logger
+	logger = kitlog.NewLogfmtLogger(kitlog.NewSyncWriter(os.Stderr))
+	logger = kitlog.With(logger, "ts", kitlog.DefaultTimestampUTC)
+
+	inclusionQueryApi := api_gateway.NewInclusionQueryApi(checker)
+
+	mux := http.NewServeMux()
+	httpLogger := kitlog.With(logger, "component", "http")
+
+	mux.Handle("/", api_gateway.MakeLightHandler(inclusionQueryApi, httpLogger))
+	http.Handle("/", mux)
+
+	go func() {
+		log.Println("transport", "http", "address", ipAddress, "msg", "listening")
+		errs <- http.ListenAndServe(ipAddress, nil)
+	}()
+}
+
 func initIcode() error {
 
 	log.Println("icode is running...")
@@ -178,10 +303,12 @@ func initIcode() error {
 	deployHandler := icodeAdapter.NewDeployCommandHandler(*api)
 	unDeployHandler := icodeAdapter.NewUnDeployCommandHandler(*api)
 	blockCommandHandler := icodeAdapter.NewBlockCommandHandler(*api, commandService)
+	blockCommittedEventHandler := icodeAdapter.NewBlockCommittedEventHandler()
 
 	mqClient.Subscribe("Command", "icode.deploy", deployHandler)
 	mqClient.Subscribe("Command", "icode.undeploy", unDeployHandler)
 	mqClient.Subscribe("Command", "block.excute", blockCommandHandler)
+	mqClient.Subscribe("Event", "block.committed", blockCommittedEventHandler)
 
 	return nil
 
@@ -205,21 +332,299 @@ func initTxPool() error {
 	blockService := txpoolAdapter.NewBlockService(mqClient.Publish)
 	blockProposalService := txpool.NewBlockProposalService(txQueryApi, blockService)
 
+	//key
+	priKey, _ := grpcGatewayInfra.LoadKeyPair(config.Authentication.KeyPath, config.Authentication.KeyType)
+	signer, err := blockchainAdapter.NewHeimdallSigner(priKey)
+	if err != nil {
+		return err
+	}
+
 	//infra
-	txApi := txpoolApi.NewTransactionApi(tmpPeerID)
+	txApi := txpoolApi.NewTransactionApi(tmpPeerID, blockchainAdapter.NewTxSigner(signer))
 	txCommandHandler := txpoolAdapter.NewTxCommandHandler(txApi)
+	blockCommittedEventHandler := txpoolAdapter.NewBlockCommittedEventHandler(txApi)
 
 	//10초마다 block propose
 	txpoolBatch.GetTimeOutBatcherInstance().Run(blockProposalService.ProposeBlock, time.Second*10)
 
-	err := mqClient.Subscribe("Command", "transaction.create", txCommandHandler)
+	err = mqClient.Subscribe("Command", "transaction.create", txCommandHandler)
+
+	if err != nil {
+		panic(err)
+	}
+
+	// 되돌린 block 의 transaction 을 다시 넣은 다음 새 chain 에 commit 된 transaction 을 지우도록 하나의 구독으로 받는다.
+	if err := mqClient.Subscribe("Event", "block.*", blockCommittedEventHandler); err != nil {
+		panic(err)
+	}
+
+	return nil
+}
+func initBlockchain(blockRepository *blockchainLeveldb.BlockRepository, receiptRepository *blockchainLeveldb.ReceiptRepository, snapshotRepository *blockchainLeveldb.SnapshotRepository, genesisConfig blockchain.GenesisConfig) error {
+
+	log.Println("blockchain is running...")
+
+	config := conf.GetConfiguration()
+	mqClient := rabbitmq.Connect(config.Common.Messaging.Url)
+
+	//todo get id from pubkey
+	tmpPeerID := "tmp peer 1"
+
+	//key
+	priKey, pubKey := grpcGatewayInfra.LoadKeyPair(config.Authentication.KeyPath, config.Authentication.KeyType)
+	pubKeyPEM, err := pubKey.ToPEM()
+	if err != nil {
+		return err
+	}
+
+	//infra
+	peerRepository := blockchainMemory.NewPeerRepository()
+	publicKeyRepository := blockchainMemory.NewPublicKeyRepository()
+	grpcCommandService := blockchainAdapter.NewGrpcCommandService(mqClient.Publish)
+	commandService := blockchainAdapter.NewCommandService(mqClient.Publish)
+
+	signer, err := blockchainAdapter.NewHeimdallSigner(priKey)
+	if err != nil {
+		return err
+	}
+
+	// 자신이 만든 block 도 다른 peer 와 같은 방식으로 검증한다.
+	if err := publicKeyRepository.Save(blockchain.PeerId{Id: tmpPeerID}, pubKeyPEM); err != nil {
+		return err
+	}
+
+	signatureVerifier, err := blockchainAdapter.NewHeimdallSignatureVerifier(publicKeyRepository, grpcGatewayInfra.ConvertToKeyGenOpts(config.Authentication.KeyType))
+	if err != nil {
+		return err
+	}
 
+	//api
+	blockApi, err := blockchainApi.NewBlockApi(tmpPeerID, blockRepository, grpcCommandService, peerRepository)
 	if err != nil {
+		return err
+	}
+	blockApi.SetSignatureVerifier(signatureVerifier)
+	// 합의가 commit 한 block 은 되돌리지 않는다.
+	blockApi.SetReorgRule(blockchain.NewFinalityRule(blockchain.NewConsensusFinalityChecker(blockRepository), blockchain.LongestChainRule{}))
+	blockApi.SetMaxTimestampDrift(time.Duration(config.Blockchain.MaxTimestampDrift) * time.Second)
+	// 합의한 snapshot height 의 block 은 local state 와 state root 가 같아야 commit 한다.
+	blockApi.SetStateRootValidator(blockchain.NewStateRootValidator(blockchain.SnapshotConfig{Interval: config.Blockchain.SnapshotInterval}, snapshotRepository, genesisConfig.Parliament))
+	blockLimit := blockchain.BlockLimit{
+		MaxTransactions:     config.Consensus.MaxTransactions,
+		MaxBytes:            config.Blockchain.MaxBlockBytes,
+		MaxTransactionBytes: config.Txpool.MaxTransactionByte,
+	}
+	blockApi.SetBlockLimit(blockLimit)
+	blockApi.SetBlockPoolConfig(blockchain.PoolConfig{
+		MaxBlocks:         config.Blockchain.PoolMaxBlocks,
+		MaxHeightDistance: config.Blockchain.PoolMaxHeightDistance,
+		Expiration:        time.Duration(config.Blockchain.PoolExpirationTime) * time.Second,
+		CompactThreshold:  config.Blockchain.PoolCompactThreshold,
+	})
+	// 재시작 전의 block pool 과 동기화 상태를 복원한다.
+	if err := blockApi.LoadState(); err != nil {
+		return err
+	}
+	blockProposeApi := blockchainApi.NewBlockProposeApi(tmpPeerID, blockRepository, commandService, signer)
+	blockProposeApi.SetBlockLimit(blockLimit)
+	blockProposeApi.SetReceiptRepository(receiptRepository)
+	blockProposeApi.SetSnapshotState(blockchain.SnapshotConfig{Interval: config.Blockchain.SnapshotInterval}, snapshotRepository, genesisConfig.Parliament)
+	receiptApi := blockchainApi.NewReceiptApi(blockRepository, receiptRepository, commandService)
+	snapshotApi := blockchainApi.NewSnapshotApi(tmpPeerID, blockRepository, snapshotRepository, snapshotRepository, grpcCommandService, peerRepository, signer)
+	snapshotApi.SetSnapshotConfig(blockchain.SnapshotConfig{Interval: config.Blockchain.SnapshotInterval})
+	snapshotApi.SetParliament(genesisConfig.Parliament)
+	snapshotApi.SetSignatureVerifier(signatureVerifier)
+	snapshotApi.SetCommandService(commandService)
+
+	//handler
+	commandHandler := blockchainAdapter.NewCommandHandler(&blockApi, blockProposeApi, blockRepository, signatureVerifier, blockRepository)
+	commandHandler.SetMaxTimestampDrift(time.Duration(config.Blockchain.MaxTimestampDrift) * time.Second)
+	eventHandler := blockchainAdapter.NewEventHandler(&blockApi)
+	grpcCommandHandler := blockchainAdapter.NewGrpcCommandHandler(&blockApi, blockRepository, grpcCommandService)
+	nodeCommandHandler := blockchainAdapter.NewNodeCommandHandler(peerRepository, publicKeyRepository)
+	receiptHandler := blockchainAdapter.NewReceiptHandler(receiptApi)
+	snapshotEventHandler := blockchainAdapter.NewSnapshotEventHandler(snapshotApi)
+	snapshotGrpcCommandHandler := blockchainAdapter.NewSnapshotGrpcCommandHandler(snapshotApi, &blockApi, grpcCommandService)
+
+	if err := mqClient.Subscribe("Command", "block.confirm", commandHandler); err != nil {
+		panic(err)
+	}
+
+	if err := mqClient.Subscribe("Command", "block.propose", commandHandler); err != nil {
 		panic(err)
 	}
 
+	if err := mqClient.Subscribe("Event", "blockpool.*", eventHandler); err != nil {
+		panic(err)
+	}
+
+	// reorg 로 되돌린 block 의 receipt 를 지우고 icode state 를 다시 만드는 것은 commit 된 block 을 실행하기 전에 해야 하므로
+	// block.committed 와 block.rolledback 을 하나의 구독으로 받아 순서를 지킨다.
+	if err := mqClient.Subscribe("Event", "block.*", receiptHandler); err != nil {
+		panic(err)
+	}
+
+	if err := mqClient.Subscribe("Command", "blockResult", receiptHandler); err != nil {
+		panic(err)
+	}
+
+	if err := mqClient.Subscribe("Event", "block.committed", snapshotEventHandler); err != nil {
+		panic(err)
+	}
+
+	if err := mqClient.Subscribe("Event", "meta.created", snapshotEventHandler); err != nil {
+		panic(err)
+	}
+
+	if err := mqClient.Subscribe("Event", "meta.deleted", snapshotEventHandler); err != nil {
+		panic(err)
+	}
+
+	if err := mqClient.Subscribe("Command", "message.receive", grpcCommandHandler); err != nil {
+		panic(err)
+	}
+
+	if err := mqClient.Subscribe("Command", "message.receive", snapshotGrpcCommandHandler); err != nil {
+		panic(err)
+	}
+
+	if err := mqClient.Subscribe("Command", "node.update", nodeCommandHandler); err != nil {
+		panic(err)
+	}
+
+	// 응답이 없는 block 요청을 다른 peer 에게 다시 요청한다.
+	go func() {
+		for range time.Tick(time.Second) {
+			if err := blockApi.CheckSyncTimeout(); err != nil {
+				log.Printf("block synchronization is aborted: [%v]", err)
+			}
+		}
+	}()
+
+	if config.Blockchain.SnapshotBootstrap {
+		return initSnapshotBootstrap(mqClient, blockRepository, snapshotApi, grpcCommandService, peerRepository, signatureVerifier, genesisConfig)
+	}
+
 	return nil
 }
+
+// initSnapshotBootstrap 은 genesis block 만 가진 node 가 header chain 을 동기화하고,
+// 그 header chain 으로 검증한 peer 의 snapshot 부터 chain 을 시작하도록 한다.
+func initSnapshotBootstrap(mqClient *rabbitmq.Client, blockRepository *blockchainLeveldb.BlockRepository, snapshotApi *blockchainApi.SnapshotApi, grpcCommandService *blockchainAdapter.GrpcCommandService, peerRepository blockchain.PeerRepository, signatureVerifier blockchain.SignatureVerifier, genesisConfig blockchain.GenesisConfig) error {
+
+	config := conf.GetConfiguration()
+
+	lastBlock, err := blockRepository.GetLastBlock()
+	if err != nil {
+		return err
+	}
+
+	if lastBlock.GetHeight() != 0 {
+		return nil
+	}
+
+	log.Println("bootstrapping from snapshot...")
+
+	genesisBlock, err := blockchain.NewGenesisBlock(genesisConfig)
+	if err != nil {
+		return err
+	}
+
+	headerRepository := blockchainLeveldb.NewHeaderRepository(config.Blockchain.HeaderRepositoryPath)
+
+	lightApi := blockchainApi.NewLightApi(headerRepository, grpcCommandService, peerRepository)
+	lightApi.SetSignatureVerifier(signatureVerifier)
+	lightApi.SetMaxTimestampDrift(time.Duration(config.Blockchain.MaxTimestampDrift) * time.Second)
+
+	if err := lightApi.InitGenesis(genesisBlock); err != nil {
+		return err
+	}
+
+	snapshotApi.SetHeaderRepository(headerRepository)
+
+	lightGrpcCommandHandler := blockchainAdapter.NewLightGrpcCommandHandler(lightApi)
+
+	if err := mqClient.Subscribe("Command", "message.receive", lightGrpcCommandHandler); err != nil {
+		panic(err)
+	}
+
+	// snapshot 으로 시작하거나 block 동기화로 genesis 다음 block 을 받을 때까지 header 와 snapshot 을 요청한다.
+	go func() {
+		for range time.Tick(time.Second * 10) {
+			lastBlock, err := blockRepository.GetLastBlock()
+			if err == nil && lastBlock.GetHeight() != 0 {
+				return
+			}
+
+			if err := lightApi.SyncHeaders(); err != nil {
+				log.Printf("header synchronization failed: [%v]", err)
+				continue
+			}
+
+			if err := snapshotApi.RequestSnapshot(); err != nil {
+				log.Printf("snapshot request failed: [%v]", err)
+			}
+		}
+	}()
+
+	return nil
+}
+
+func initLightBlockchain(headerRepository *blockchainLeveldb.HeaderRepository, genesisConfig blockchain.GenesisConfig) (*blockchainApi.LightApi, error) {
+
+	log.Println("light blockchain is running...")
+
+	config := conf.GetConfiguration()
+	mqClient := rabbitmq.Connect(config.Common.Messaging.Url)
+
+	genesisBlock, err := blockchain.NewGenesisBlock(genesisConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	//infra
+	peerRepository := blockchainMemory.NewPeerRepository()
+	publicKeyRepository := blockchainMemory.NewPublicKeyRepository()
+	grpcCommandService := blockchainAdapter.NewGrpcCommandService(mqClient.Publish)
+
+	signatureVerifier, err := blockchainAdapter.NewHeimdallSignatureVerifier(publicKeyRepository, grpcGatewayInfra.ConvertToKeyGenOpts(config.Authentication.KeyType))
+	if err != nil {
+		return nil, err
+	}
+
+	//api
+	lightApi := blockchainApi.NewLightApi(headerRepository, grpcCommandService, peerRepository)
+	lightApi.SetSignatureVerifier(signatureVerifier)
+	lightApi.SetMaxTimestampDrift(time.Duration(config.Blockchain.MaxTimestampDrift) * time.Second)
+
+	if err := lightApi.InitGenesis(genesisBlock); err != nil {
+		return nil, err
+	}
+
+	//handler
+	lightGrpcCommandHandler := blockchainAdapter.NewLightGrpcCommandHandler(lightApi)
+	nodeCommandHandler := blockchainAdapter.NewNodeCommandHandler(peerRepository, publicKeyRepository)
+
+	if err := mqClient.Subscribe("Command", "message.receive", lightGrpcCommandHandler); err != nil {
+		panic(err)
+	}
+
+	if err := mqClient.Subscribe("Command", "node.update", nodeCommandHandler); err != nil {
+		panic(err)
+	}
+
+	// full node 가 새로 commit 한 block 의 header 를 주기적으로 받아온다.
+	go func() {
+		for range time.Tick(time.Second * 10) {
+			if err := lightApi.SyncHeaders(); err != nil {
+				log.Printf("header synchronization failed: [%v]", err)
+			}
+		}
+	}()
+
+	return lightApi, nil
+}
+
 func initConsensus() error {
 	return nil
 }
@@ -9,11 +9,13 @@ import (
 
 type TransactionApi struct {
 	publisherId string
+	signer      txpool.TxSigner
 }
 
-func NewTransactionApi(publisherId string) TransactionApi {
+func NewTransactionApi(publisherId string, signer txpool.TxSigner) TransactionApi {
 	return TransactionApi{
 		publisherId: publisherId,
+		signer:      signer,
 	}
 }
 
@@ -21,7 +23,7 @@ func (t TransactionApi) CreateTransaction(txData txpool.TxData) (txpool.Transact
 
 	log.Printf("create transaction: [%v]", txData)
 
-	tx, err := txpool.CreateTransaction(t.publisherId, txData)
+	tx, err := txpool.CreateTransaction(t.publisherId, txData, t.signer)
 
 	if err != nil {
 		log.Printf("fail to transaction: [%v]", err)
@@ -43,5 +45,32 @@ func (t TransactionApi) DeleteTransaction(id txpool.TransactionId) error {
 		return err
 	}
 
+	// block 을 제안할 때 이미 지웠거나 다른 node 의 pool 에만 있던 transaction 이다.
+	if tx.TxId == "" {
+		log.Printf("transaction not in pool: [%v]", id)
+		return nil
+	}
+
 	return txpool.DeleteTransaction(*tx)
 }
+
+// RequeueTransaction 은 reorg 로 되돌린 block 의 transaction 을 pool 에 다시 넣는다.
+// 이미 pool 에 있는 transaction 은 그대로 둔다.
+func (t TransactionApi) RequeueTransaction(transaction txpool.Transaction) error {
+
+	log.Printf("requeue transaction: [%v]", transaction.TxId)
+
+	tx := &txpool.Transaction{}
+
+	if err := eventstore.Load(tx, transaction.TxId); err != nil {
+		log.Printf("fail to requeue transaction: [%v]", transaction.TxId)
+		return err
+	}
+
+	if tx.TxId != "" {
+		log.Printf("transaction already in pool: [%v]", transaction.TxId)
+		return nil
+	}
+
+	return txpool.RequeueTransaction(transaction)
+}
@@ -1,6 +1,7 @@
 package api_test
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/it-chain/engine/core/eventstore"
@@ -10,6 +11,8 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
+var ErrSignTx = errors.New("sign error")
+
 type MockEventRepository struct {
 	SaveFunc func(aggregateID string, events ...midgard.Event) error
 	LoadFunc func(aggregate midgard.Aggregate, aggregateID string) error
@@ -25,6 +28,14 @@ func (rp MockEventRepository) Save(aggregateID string, events ...midgard.Event)
 
 func (rp MockEventRepository) Close() {}
 
+type MockTxSigner struct {
+	SignTxFunc func(tx txpool.Transaction) ([]byte, error)
+}
+
+func (s MockTxSigner) SignTx(tx txpool.Transaction) ([]byte, error) {
+	return s.SignTxFunc(tx)
+}
+
 func TestTransactionApi_CreateTransaction(t *testing.T) {
 
 	tests := map[string]struct {
@@ -39,24 +50,45 @@ func TestTransactionApi_CreateTransaction(t *testing.T) {
 			}{txData: txpool.TxData{ICodeID: "gg"}},
 			err: nil,
 		},
+		"sign error": {
+			input: struct {
+				txData txpool.TxData
+			}{txData: txpool.TxData{ICodeID: "unsigned"}},
+			err: ErrSignTx,
+		},
 	}
 
 	eventRepository := MockEventRepository{}
 	eventRepository.SaveFunc = func(aggregateID string, events ...midgard.Event) error {
-		assert.Equal(t, "gg", events[0].(*txpool.TxCreatedEvent).ICodeID)
+		event := events[0].(*txpool.TxCreatedEvent)
+		assert.Equal(t, "gg", event.ICodeID)
+		assert.Equal(t, []byte("signed:"+aggregateID), event.Signature)
 		return nil
 	}
 
 	eventstore.InitForMock(eventRepository)
 
-	transactionApi := api.NewTransactionApi("zf")
+	signer := MockTxSigner{}
+	signer.SignTxFunc = func(tx txpool.Transaction) ([]byte, error) {
+		if tx.TxData.ICodeID == "unsigned" {
+			return nil, ErrSignTx
+		}
+
+		assert.Equal(t, "zf", tx.PublishPeerId)
+		return []byte("signed:" + tx.TxId), nil
+	}
+
+	transactionApi := api.NewTransactionApi("zf", signer)
 
 	for testName, test := range tests {
 		t.Logf("running test case %s", testName)
 
-		_, err := transactionApi.CreateTransaction(test.input.txData)
+		tx, err := transactionApi.CreateTransaction(test.input.txData)
 
 		assert.Equal(t, test.err, err)
+		if err == nil {
+			assert.Equal(t, []byte("signed:"+tx.TxId), tx.Signature)
+		}
 	}
 }
 
@@ -70,12 +102,18 @@ func TestTransactionApi_DeleteTransaction(t *testing.T) {
 			input: "transactionID",
 			err:   nil,
 		},
+		"already deleted": {
+			input: "deletedID",
+			err:   nil,
+		},
 	}
 
 	eventRepository := MockEventRepository{}
 	eventRepository.LoadFunc = func(aggregate midgard.Aggregate, aggregateID string) error {
 
-		aggregate.(*txpool.Transaction).TxId = "transactionID"
+		if aggregateID == "transactionID" {
+			aggregate.(*txpool.Transaction).TxId = "transactionID"
+		}
 		return nil
 	}
 
@@ -87,7 +125,7 @@ func TestTransactionApi_DeleteTransaction(t *testing.T) {
 
 	eventstore.InitForMock(eventRepository)
 
-	transactionApi := api.NewTransactionApi("zf")
+	transactionApi := api.NewTransactionApi("zf", MockTxSigner{})
 
 	for testName, test := range tests {
 		t.Logf("running test case %s", testName)
@@ -16,6 +16,7 @@ type TxCreatedEvent struct {
 	Method        string
 	Params        Param
 	ICodeID       string
+	Signature     []byte
 }
 
 func (tx TxCreatedEvent) GetTransaction() Transaction {
@@ -32,6 +33,7 @@ func (tx TxCreatedEvent) GetTransaction() Transaction {
 			Params:  tx.Params,
 		},
 		TimeStamp: tx.TimeStamp,
+		Signature: tx.Signature,
 	}
 }
 
@@ -40,7 +42,23 @@ type TxDeletedEvent struct {
 	midgard.EventModel
 }
 
+// blockchain 이 block 을 commit 하면 발행한다. TxIDs 의 transaction 은 pool 에서 지운다.
 type BlockCommittedEvent struct {
 	midgard.EventModel
-	Transactions []Transaction
+	Seal      []byte
+	PrevSeal  []byte
+	Height    uint64
+	Timestamp time.Time
+	Creator   []byte
+	TxIDs     []string
+}
+
+// blockchain 이 reorg 로 block 을 되돌리면 발행한다. TxList 의 transaction 은 pool 에 다시 넣는다.
+// TxList 는 blockchain 의 transaction 목록을 json 으로 encoding 한 것이다.
+type BlockRolledBackEvent struct {
+	midgard.EventModel
+	Seal   string
+	Height uint64
+	TxIDs  []string
+	TxList []byte
 }
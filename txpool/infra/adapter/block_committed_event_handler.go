@@ -1,7 +1,10 @@
 package adapter
 
 import (
+	"encoding/json"
 	"errors"
+	"log"
+	"time"
 
 	"github.com/it-chain/engine/txpool"
 	"github.com/it-chain/engine/txpool/api"
@@ -9,21 +12,108 @@ import (
 
 var ErrNoEventID = errors.New("no event id ")
 
+// BlockCommittedEventHandler 는 commit 된 block 의 transaction 을 pool 에서 지우고, reorg 로 되돌린 block 의 transaction 은 다시 넣는다.
+// 되돌린 block 의 transaction 이 새 chain 에 commit 되었다면 다시 지워야 하므로, 두 event 를 같은 구독으로 받아 순서를 지킨다.
 type BlockCommittedEventHandler struct {
 	transactionApi api.TransactionApi
 }
 
+func NewBlockCommittedEventHandler(transactionApi api.TransactionApi) *BlockCommittedEventHandler {
+	return &BlockCommittedEventHandler{
+		transactionApi: transactionApi,
+	}
+}
+
+// commit 된 block 의 transaction 을 pool 에서 지운다.
+// 하나를 지우지 못해도 나머지는 지우고 처음 발생한 에러를 반환한다.
 func (e BlockCommittedEventHandler) HandleBlockCommittedEvent(event txpool.BlockCommittedEvent) error {
 
-	txs := event.Transactions
+	var firstErr error
+
+	for _, txID := range event.TxIDs {
+		err := e.transactionApi.DeleteTransaction(txID)
+
+		if err != nil {
+			log.Printf("fail to delete committed transaction [%s]: [%v]", txID, err)
+
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+
+	return firstErr
+}
+
+// reorg 로 되돌린 block 의 transaction 을 pool 에 다시 넣는다.
+// 하나를 넣지 못해도 나머지는 넣고 처음 발생한 에러를 반환한다.
+func (e BlockCommittedEventHandler) HandleBlockRolledBackEvent(event txpool.BlockRolledBackEvent) error {
+
+	txList := make([]rolledBackTx, 0)
+
+	if err := json.Unmarshal(event.TxList, &txList); err != nil {
+		return err
+	}
+
+	var firstErr error
 
-	for _, tx := range txs {
-		err := e.transactionApi.DeleteTransaction(tx.TxId)
+	for _, tx := range txList {
+		err := e.transactionApi.RequeueTransaction(tx.toTransaction())
 
 		if err != nil {
-			return err
+			log.Printf("fail to requeue rolled back transaction [%s]: [%v]", tx.ID, err)
+
+			if firstErr == nil {
+				firstErr = err
+			}
 		}
 	}
 
-	return nil
+	return firstErr
+}
+
+// rolledBackTx 는 blockchain.DefaultTransaction 의 json 형태이다.
+type rolledBackTx struct {
+	ID        string
+	Status    int
+	PeerID    string
+	Timestamp time.Time
+	TxData    *rolledBackTxData
+	Signature []byte
+}
+
+type rolledBackTxData struct {
+	Jsonrpc string
+	Method  string
+	Params  txpool.Param
+	ID      string
+}
+
+// blockchain 의 StatusTransactionValid 는 1 이다. txpool 과 blockchain 은 transaction 상태의 상수 값이 반대이다.
+func (tx rolledBackTx) toTransaction() txpool.Transaction {
+
+	status := txpool.INVALID
+	if tx.Status == 1 {
+		status = txpool.VALID
+	}
+
+	txData := txpool.TxData{}
+	if tx.TxData != nil {
+		txData = txpool.TxData{
+			Jsonrpc: tx.TxData.Jsonrpc,
+			Method:  txpool.TxDataType(tx.TxData.Method),
+			Params:  tx.TxData.Params,
+			ICodeID: tx.TxData.ID,
+		}
+	}
+
+	return txpool.Transaction{
+		TxId:          tx.ID,
+		PublishPeerId: tx.PeerID,
+		TxStatus:      status,
+		TxHash:        txpool.CalTxHash(txData, tx.PeerID, tx.ID, tx.Timestamp),
+		TimeStamp:     tx.Timestamp,
+		TxData:        txData,
+		Signature:     tx.Signature,
+	}
 }
@@ -1,5 +1,132 @@
 package adapter_test
 
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/it-chain/engine/core/eventstore"
+	"github.com/it-chain/engine/txpool"
+	"github.com/it-chain/engine/txpool/api"
+	"github.com/it-chain/engine/txpool/infra/adapter"
+	"github.com/it-chain/midgard"
+	"github.com/stretchr/testify/assert"
+)
+
+type MockEventRepository struct {
+	SaveFunc func(aggregateID string, events ...midgard.Event) error
+	LoadFunc func(aggregate midgard.Aggregate, aggregateID string) error
+}
+
+func (rp MockEventRepository) Load(aggregate midgard.Aggregate, aggregateID string) error {
+	return rp.LoadFunc(aggregate, aggregateID)
+}
+
+func (rp MockEventRepository) Save(aggregateID string, events ...midgard.Event) error {
+	return rp.SaveFunc(aggregateID, events...)
+}
+
+func (rp MockEventRepository) Close() {}
+
+func TestBlockCommittedEventHandler_HandleBlockCommittedEvent(t *testing.T) {
+
+	// given
+	pool := map[string]bool{"tx1": true, "tx3": true}
+	deleted := make([]string, 0)
+
+	eventRepository := MockEventRepository{}
+	eventRepository.LoadFunc = func(aggregate midgard.Aggregate, aggregateID string) error {
+		if pool[aggregateID] {
+			aggregate.(*txpool.Transaction).TxId = aggregateID
+		}
+		return nil
+	}
+	eventRepository.SaveFunc = func(aggregateID string, events ...midgard.Event) error {
+		deleted = append(deleted, events[0].(*txpool.TxDeletedEvent).GetID())
+		return nil
+	}
+
+	eventstore.InitForMock(eventRepository)
+	defer eventstore.Close()
+
+	handler := adapter.NewBlockCommittedEventHandler(api.NewTransactionApi("zf", nil))
+
+	// when
+	err := handler.HandleBlockCommittedEvent(txpool.BlockCommittedEvent{
+		EventModel: midgard.EventModel{ID: "seal", Type: "block.committed"},
+		Height:     1,
+		TxIDs:      []string{"tx1", "tx2", "tx3"},
+	})
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"tx1", "tx3"}, deleted)
+}
+
+func TestBlockCommittedEventHandler_HandleBlockRolledBackEvent(t *testing.T) {
+
+	// given
+	pool := map[string]bool{"tx1": true}
+	requeued := make([]*txpool.TxCreatedEvent, 0)
+
+	eventRepository := MockEventRepository{}
+	eventRepository.LoadFunc = func(aggregate midgard.Aggregate, aggregateID string) error {
+		if pool[aggregateID] {
+			aggregate.(*txpool.Transaction).TxId = aggregateID
+		}
+		return nil
+	}
+	eventRepository.SaveFunc = func(aggregateID string, events ...midgard.Event) error {
+		requeued = append(requeued, events[0].(*txpool.TxCreatedEvent))
+		return nil
+	}
+
+	eventstore.InitForMock(eventRepository)
+	defer eventstore.Close()
+
+	timestamp := time.Now().Round(0)
+	txList, err := json.Marshal([]map[string]interface{}{
+		{"ID": "tx1", "Status": 1, "PeerID": "peer1", "Timestamp": timestamp},
+		{
+			"ID":        "tx2",
+			"Status":    1,
+			"PeerID":    "peer2",
+			"Timestamp": timestamp,
+			"TxData": map[string]interface{}{
+				"Jsonrpc": "2.0",
+				"Method":  "invoke",
+				"Params":  map[string]interface{}{"Type": 0, "Function": "set", "Args": []string{"a", "1"}},
+				"ID":      "icode1",
+			},
+			"Signature": []byte("signature"),
+		},
+	})
+	assert.NoError(t, err)
+
+	handler := adapter.NewBlockCommittedEventHandler(api.NewTransactionApi("zf", nil))
+
+	// when
+	err = handler.HandleBlockRolledBackEvent(txpool.BlockRolledBackEvent{
+		EventModel: midgard.EventModel{ID: "seal", Type: "block.rolledback"},
+		Seal:       "seal",
+		Height:     1,
+		TxIDs:      []string{"tx1", "tx2"},
+		TxList:     txList,
+	})
+
+	// then: pool 에 없는 transaction 만 제출자의 서명을 유지한 채 다시 넣는다.
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(requeued))
+
+	tx := requeued[0].GetTransaction()
+	assert.Equal(t, "tx2", tx.TxId)
+	assert.Equal(t, "peer2", tx.PublishPeerId)
+	assert.Equal(t, txpool.VALID, tx.TxStatus)
+	assert.True(t, timestamp.Equal(tx.TimeStamp))
+	assert.Equal(t, txpool.TxData{Jsonrpc: "2.0", Method: txpool.Invoke, Params: txpool.Param{Function: "set", Args: []string{"a", "1"}}, ICodeID: "icode1"}, tx.TxData)
+	assert.Equal(t, []byte("signature"), tx.Signature)
+}
+
 //
 //import (
 //	"testing"
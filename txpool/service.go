@@ -30,8 +30,8 @@ func NewBlockProposalService(queryService TxpoolQueryService, blockService Block
 	}
 }
 
-// todo do not delete transaction immediately
-// todo transaction will be deleted when block are committed
+// 제안한 transaction 은 pool 에서 지우지 않는다. block 한도를 넘어 담기지 않은 transaction 은 다음 제안에 다시 들어가고,
+// block 에 담긴 transaction 은 block 이 commit 되면 BlockCommittedEvent 의 TxIDs 로 지운다.
 func (b BlockProposalService) ProposeBlock() error {
 
 	// todo transaction size, number of tx
@@ -53,17 +53,7 @@ func (b BlockProposalService) ProposeBlock() error {
 		return nil
 	}
 
-	err = b.blockService.ProposeBlock(transactions)
-
-	if err != nil {
-		return err
-	}
-
-	for _, tx := range transactions {
-		DeleteTransaction(tx)
-	}
-
-	return nil
+	return b.blockService.ProposeBlock(transactions)
 }
 
 func filter(vs []Transaction, f func(Transaction) bool) []Transaction {
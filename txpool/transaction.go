@@ -52,6 +52,13 @@ type Transaction struct {
 	TxHash        string
 	TimeStamp     time.Time
 	TxData        TxData
+	Signature     []byte
+}
+
+// TxSigner 는 pool 에 넣는 transaction 에 제출한 node 의 key 로 서명한다.
+// 서명 대상은 block 에 담겨 검증되는 transaction 의 내용이다.
+type TxSigner interface {
+	SignTx(tx Transaction) ([]byte, error)
 }
 
 // must implement id method
@@ -77,6 +84,7 @@ func (t *Transaction) On(event midgard.Event) error {
 			Jsonrpc: v.Jsonrpc,
 			ICodeID: v.ICodeID,
 		}
+		t.Signature = v.Signature
 
 	case *TxDeletedEvent:
 		t.TxId = ""
@@ -122,12 +130,25 @@ func CalTxHash(txData TxData, publishPeerId string, txId TransactionId, timeStam
 	return common.ComputeSHA256(hashArgs)
 }
 
-func CreateTransaction(publisherId string, txData TxData) (Transaction, error) {
+func CreateTransaction(publisherId string, txData TxData, signer TxSigner) (Transaction, error) {
 
 	id := xid.New().String()
 	timeStamp := time.Now()
 	hash := CalTxHash(txData, publisherId, TransactionId(id), timeStamp)
 
+	signature, err := signer.SignTx(Transaction{
+		TxId:          TransactionId(id),
+		PublishPeerId: publisherId,
+		TxStatus:      VALID,
+		TxHash:        hash,
+		TimeStamp:     timeStamp,
+		TxData:        txData,
+	})
+
+	if err != nil {
+		return Transaction{}, err
+	}
+
 	event := &TxCreatedEvent{
 		EventModel: midgard.EventModel{
 			ID:   id,
@@ -141,6 +162,7 @@ func CreateTransaction(publisherId string, txData TxData) (Transaction, error) {
 		Jsonrpc:       txData.Jsonrpc,
 		Method:        string(txData.Method),
 		Params:        txData.Params,
+		Signature:     signature,
 	}
 
 	tx := &Transaction{}
@@ -152,6 +174,30 @@ func CreateTransaction(publisherId string, txData TxData) (Transaction, error) {
 	return *tx, nil
 }
 
+// RequeueTransaction 은 reorg 로 되돌린 block 의 transaction 을 ID 와 제출자의 서명을 그대로 유지한 채 pool 에 다시 넣는다.
+func RequeueTransaction(transaction Transaction) error {
+
+	event := &TxCreatedEvent{
+		EventModel: midgard.EventModel{
+			ID:   transaction.TxId,
+			Type: "transaction.created",
+		},
+		PublishPeerId: transaction.PublishPeerId,
+		TxStatus:      int(transaction.TxStatus),
+		TxHash:        transaction.TxHash,
+		TimeStamp:     transaction.TimeStamp,
+		ICodeID:       transaction.TxData.ICodeID,
+		Jsonrpc:       transaction.TxData.Jsonrpc,
+		Method:        string(transaction.TxData.Method),
+		Params:        transaction.TxData.Params,
+		Signature:     transaction.Signature,
+	}
+
+	tx := &Transaction{}
+
+	return saveAndOn(tx, event)
+}
+
 func DeleteTransaction(transaction Transaction) error {
 
 	event := &TxDeletedEvent{